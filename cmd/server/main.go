@@ -16,12 +16,27 @@ import (
 	"golang-swing-trading-signal/internal/api/handlers"
 	"golang-swing-trading-signal/internal/api/routes"
 	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/events"
+	"golang-swing-trading-signal/internal/models"
 	"golang-swing-trading-signal/internal/repository"
+	"golang-swing-trading-signal/internal/services/digest"
 	"golang-swing-trading-signal/internal/services/gemini_ai"
+	"golang-swing-trading-signal/internal/services/groups"
 	"golang-swing-trading-signal/internal/services/jobs"
+	"golang-swing-trading-signal/internal/services/llm"
+	"golang-swing-trading-signal/internal/services/llm_usage"
+	"golang-swing-trading-signal/internal/services/market_calendar"
+	"golang-swing-trading-signal/internal/services/market_price"
+	"golang-swing-trading-signal/internal/services/notification"
+	"golang-swing-trading-signal/internal/services/outbox"
+	"golang-swing-trading-signal/internal/services/prompt"
+	"golang-swing-trading-signal/internal/services/quota"
+	"golang-swing-trading-signal/internal/services/scheduler"
 	"golang-swing-trading-signal/internal/services/stocks"
+	"golang-swing-trading-signal/internal/services/symbols"
 	"golang-swing-trading-signal/internal/services/telegram_bot"
 	"golang-swing-trading-signal/internal/services/trading_analysis"
+	"golang-swing-trading-signal/internal/services/users"
 	"golang-swing-trading-signal/internal/services/yahoo_finance"
 	"golang-swing-trading-signal/pkg/postgres"
 	"golang-swing-trading-signal/pkg/ratelimit"
@@ -91,12 +106,26 @@ func main() {
 	unitOfWork := repository.NewUnitOfWork(db.DB)
 	stockSignalRepo := repository.NewStockSignalRepository(db.DB)
 	jobsRepository := repository.NewJobsRepository(db.DB)
+	pipelineRepository := repository.NewPipelineRepository(db.DB)
 	stockPositionMonitoringRepo := repository.NewStockPositionMonitoringRepository(db.DB)
-	genClient, err := genai.NewClient(context.Background(), &genai.ClientConfig{
-		APIKey: cfg.Gemini.APIKey,
-	})
-	if err != nil {
-		logger.WithError(err).Fatal("Failed to initialize Gemini client")
+	marketHolidayRepo := repository.NewMarketHolidayRepository(db.DB)
+	llmUsageRepo := repository.NewLLMUsageRepository(db.DB)
+	telegramOutboxRepo := repository.NewTelegramOutboxRepository(db.DB)
+	inviteCodeRepo := repository.NewInviteCodeRepository(db.DB)
+	quotaRepo := repository.NewQuotaRepository(db.DB)
+	digestRepo := repository.NewDigestRepository(db.DB)
+	notificationPreferenceRepo := repository.NewNotificationPreferenceRepository(db.DB)
+	groupRepo := repository.NewGroupRepository(db.DB)
+	// client Gemini hanya dibuat jika provider memakainya agar deployment openai atau fixture
+	// tidak membutuhkan GEMINI_API_KEY
+	var genClient *genai.Client
+	if llm.NeedsGeminiClient(&cfg.LLM) {
+		genClient, err = genai.NewClient(context.Background(), &genai.ClientConfig{
+			APIKey: cfg.Gemini.APIKey,
+		})
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to initialize Gemini client")
+		}
 	}
 	redisClient, err := redis.NewClient(cfg.Redis)
 	if err != nil {
@@ -104,8 +133,34 @@ func main() {
 	}
 
 	// Initialize services
+	marketCalendar, err := market_calendar.NewCalendar(&cfg.MarketCalendar, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize market calendar")
+	}
+	if cfg.MarketCalendar.HolidaySource == market_calendar.HolidaySourceDatabase {
+		if err := marketCalendar.LoadHolidaysFromRepository(ctxCancel, marketHolidayRepo); err != nil {
+			logger.WithError(err).Fatal("Failed to load market holidays from database")
+		}
+	}
+
 	yahooClient := yahoo_finance.NewClient(&cfg.Yahoo, logger)
-	geminiClient := gemini_ai.NewClient(&cfg.Gemini, logger, genClient)
+	priceService := market_price.NewPriceService(&cfg.MarketPrice, logger, redisClient, yahooClient, marketCalendar)
+	llmProvider, err := llm.NewProvider(&cfg.LLM, &cfg.Gemini, logger, genClient)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize LLM provider")
+	}
+	usageService := llm_usage.NewUsageService(&cfg.LLM, logger, llmUsageRepo)
+	llmProvider = llm.WithUsageRecorder(llmProvider, usageService)
+	promptRegistry, err := prompt.NewRegistry(&cfg.Prompt)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load prompt templates")
+	}
+	rateLimiter := ratelimit.NewLimiter(cfg.RateLimit.Backend, redisClient, logger)
+	var geminiTokenLimiter ratelimit.TokenRateLimiter = ratelimit.NewTokenLimiter(cfg.Gemini.MaxTokenPerMinute)
+	if cfg.RateLimit.Backend == ratelimit.BackendRedis {
+		geminiTokenLimiter = ratelimit.NewKeyedTokenLimiter(rateLimiter, gemini_ai.TokenLimiterKey, cfg.Gemini.MaxTokenPerMinute)
+	}
+	geminiClient := gemini_ai.NewClient(&cfg.Gemini, logger, rateLimiter, geminiTokenLimiter, llmProvider, promptRegistry, usageService, marketCalendar, yahooClient, stockNewsSummaryRepo, stockSignalRepo, stockPositionRepo, stockPositionMonitoringRepo)
 	analyzer := trading_analysis.NewAnalyzer(yahooClient, geminiClient, logger, stockNewsSummaryRepo, stockPositionRepo, userRepo, unitOfWork)
 
 	// Initialize Telegram bot service
@@ -127,19 +182,45 @@ func main() {
 	if err != nil {
 		logger.WithError(err).Fatal("failed to create telegram bot")
 	}
-	telegramRateLimiter := ratelimit.NewTelegramRateLimiter(&cfg.Telegram, logger, bot)
+	telegramRateLimiter := ratelimit.NewTelegramRateLimiter(&cfg.Telegram, logger, bot, rateLimiter)
 	telegramRateLimiter.StartCleanupExpired(ctxCancel)
+	outboxService := outbox.NewOutboxService(&cfg.Telegram, logger, telegramOutboxRepo, telegramRateLimiter)
+	outboxService.Start(ctxCancel)
 
-	stockService := stocks.NewStockService(cfg, stockRepo, stockNewsSummaryRepo, stockPositionRepo, userRepo, logger, unitOfWork, stockNewsRepo, stockSignalRepo, stockPositionMonitoringRepo, redisClient)
-	jobService := jobs.NewJobService(cfg, logger, jobsRepository)
-	telegramService := telegram_bot.NewTelegramBotService(&cfg.Telegram, ctxCancel, &cfg.Trading, logger, analyzer, stockService, jobService, redisClient, bot, telegramRateLimiter, router)
+	quotaService := quota.NewQuotaService(cfg, logger, userRepo, stockPositionRepo, quotaRepo, groupRepo)
+	stockService := stocks.NewStockService(cfg, stockRepo, stockNewsSummaryRepo, stockPositionRepo, userRepo, logger, unitOfWork, stockNewsRepo, stockSignalRepo, stockPositionMonitoringRepo, events.NewRedisPublisher(redisClient, logger), quotaService, redisClient)
+	jobService := jobs.NewJobService(cfg, logger, jobsRepository, pipelineRepository)
+	userService := users.NewUserService(&cfg.Telegram, logger, userRepo, inviteCodeRepo, unitOfWork)
+	notificationService := notification.NewNotificationService(logger, notificationPreferenceRepo)
+	symbolResolver := symbols.NewSymbolResolver(logger, stockRepo)
+	groupService := groups.NewGroupService(logger, groupRepo, quotaService, symbolResolver)
+	digestService := digest.NewDigestService(cfg, logger, digestRepo, stockPositionRepo, stockService, priceService, marketCalendar, userService, outboxService, notificationService, groupService)
+	if cfg.Digest.Enabled {
+		digestService.Start(ctxCancel)
+	}
+	var jobScheduler *scheduler.Scheduler
+	if cfg.Scheduler.Enabled {
+		jobScheduler = scheduler.NewScheduler(&cfg.Scheduler, logger, jobsRepository, pipelineRepository, unitOfWork)
+		jobScheduler.Register(models.JobTypeStockAnalyzer, scheduler.NewStockAnalyzerHandler(&cfg.Trading, stockService))
+		jobScheduler.Register(models.JobTypePositionMonitor, scheduler.NewPositionMonitorHandler(stockService, stockPositionRepo))
+		jobScheduler.Register(models.JobTypeFetchPrices, scheduler.NewFetchPricesHandler(&cfg.Trading, priceService))
+		jobScheduler.Register(models.JobTypeNewsSummary, scheduler.NewNewsSummaryHandler(&cfg.Trading, stockService, cfg.Telegram.FeatureNewsMaxAgeInDays))
+		jobScheduler.Register(models.JobTypeSendBuyList, scheduler.NewSendBuyListHandler(&cfg.Telegram, digestService))
+		jobScheduler.RegisterFanOut(models.JobFanOutStockList, func(ctx context.Context) ([]string, error) {
+			return cfg.Trading.StockList, nil
+		})
+		jobScheduler.Start(ctxCancel)
+	}
+	telegramService := telegram_bot.NewTelegramBotService(&cfg.Telegram, ctxCancel, &cfg.Trading, &cfg.StreamConsumer, logger, analyzer, stockService, jobService, redisClient, bot, telegramRateLimiter, marketCalendar, priceService, usageService, outboxService, userService, quotaService, digestService, notificationService, groupService, symbolResolver, router)
 
 	// Initialize handlers
 	tradingHandler := handlers.NewTradingHandler(analyzer, telegramService, logger, cfg)
 	telegramHandler := handlers.NewTelegramHandler(telegramService, logger)
+	analysisHandler := handlers.NewAnalysisHandler(geminiClient, symbolResolver, quotaService, logger)
+	jobsHandler := handlers.NewJobsHandler(jobService, logger)
 
 	// Setup routes
-	routes.SetupRoutes(router, tradingHandler, telegramHandler)
+	routes.SetupRoutes(router, &cfg.API, tradingHandler, telegramHandler, analysisHandler, jobsHandler)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -152,6 +233,7 @@ func main() {
 		go func() {
 			logger.Info("Starting Telegram bot...")
 			telegramService.Start()
+			telegramService.StartStreamConsumers(ctxCancel)
 		}()
 	}
 
@@ -173,6 +255,13 @@ func main() {
 	cancel()
 
 	telegramRateLimiter.StopCleanupExpired()
+	if cfg.Digest.Enabled {
+		digestService.Stop()
+	}
+	outboxService.Stop()
+	if jobScheduler != nil {
+		jobScheduler.Stop()
+	}
 	// Stop Telegram bot if running with timeout
 	if telegramService != nil {
 		logger.Info("Stopping Telegram bot...")
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"golang-swing-trading-signal/internal/api/middleware"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/gemini_ai"
+	"golang-swing-trading-signal/internal/services/llm"
+	"golang-swing-trading-signal/internal/services/llm_usage"
+	"golang-swing-trading-signal/internal/services/quota"
+	"golang-swing-trading-signal/internal/services/symbols"
+)
+
+type AnalysisHandler struct {
+	geminiClient   *gemini_ai.Client
+	symbolResolver symbols.SymbolResolver
+	quotaService   quota.QuotaService
+	logger         *logrus.Logger
+}
+
+func NewAnalysisHandler(geminiClient *gemini_ai.Client, symbolResolver symbols.SymbolResolver, quotaService quota.QuotaService, logger *logrus.Logger) *AnalysisHandler {
+	return &AnalysisHandler{
+		geminiClient:   geminiClient,
+		symbolResolver: symbolResolver,
+		quotaService:   quotaService,
+		logger:         logger,
+	}
+}
+
+// AnalyzeStock handles POST /api/v1/analyze
+func (h *AnalysisHandler) AnalyzeStock(c *gin.Context) {
+	var request models.AnalyzeStockRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	stock, err := h.symbolResolver.Resolve(c.Request.Context(), request.Symbol)
+	if err != nil {
+		h.respondSymbolError(c, request.Symbol, err)
+		return
+	}
+	request.Symbol = stock.Code
+
+	telegramID := middleware.TelegramID(c)
+	if !h.consumeQuota(c, telegramID) {
+		return
+	}
+
+	ctx := llm.WithTrigger(c.Request.Context(), llm.Trigger{Source: llm.TriggerSourceAPI})
+	result, err := h.geminiClient.AnalyzeStock(ctx, request)
+	if err != nil {
+		h.quotaService.RefundAnalysis(c.Request.Context(), telegramID)
+		if errors.Is(err, llm_usage.ErrBudgetExceeded) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "Analysis paused",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		h.logger.WithError(err).WithField("symbol", request.Symbol).Error("Failed to analyze stock")
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":   "Analysis failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// MonitorPosition handles POST /api/v1/positions/:id/monitor
+func (h *AnalysisHandler) MonitorPosition(c *gin.Context) {
+	positionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"message": "position id must be a positive number",
+		})
+		return
+	}
+
+	// body bersifat opsional, default interval dan range dipakai jika kosong
+	var request models.MonitorPositionRequest
+	if err := c.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"message": err.Error(),
+		})
+		return
+	}
+	request.TelegramID = middleware.TelegramID(c)
+
+	if !h.consumeQuota(c, request.TelegramID) {
+		return
+	}
+
+	ctx := llm.WithTrigger(c.Request.Context(), llm.Trigger{Source: llm.TriggerSourceAPI})
+	result, err := h.geminiClient.MonitorPosition(ctx, uint(positionID), request)
+	if err != nil {
+		h.quotaService.RefundAnalysis(c.Request.Context(), request.TelegramID)
+		if errors.Is(err, gemini_ai.ErrPositionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Not found",
+				"message": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, llm_usage.ErrBudgetExceeded) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "Monitoring paused",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		h.logger.WithError(err).WithField("position_id", positionID).Error("Failed to monitor position")
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":   "Monitoring failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// consumeQuota memakai satu kuota analisa milik pemilik API key sebelum LLM dipanggil. Jika
+// false, response sudah dikirim.
+func (h *AnalysisHandler) consumeQuota(c *gin.Context, telegramID int64) bool {
+	err := h.quotaService.ConsumeAnalysis(c.Request.Context(), telegramID)
+	if err == nil {
+		return true
+	}
+
+	if errors.Is(err, quota.ErrQuotaExceeded) {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":   "Quota exceeded",
+			"message": err.Error(),
+		})
+		return false
+	}
+
+	h.logger.WithError(err).WithField("telegram_id", telegramID).Error("Failed to consume analysis quota")
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Failed to check quota",
+		"message": err.Error(),
+	})
+	return false
+}
+
+// respondSymbolError membalas symbol yang tidak valid atau tidak terdaftar, beserta saran kode
+// saham yang mirip jika ada.
+func (h *AnalysisHandler) respondSymbolError(c *gin.Context, symbol string, err error) {
+	var unknown *symbols.UnknownSymbolError
+	switch {
+	case errors.As(err, &unknown):
+		suggestions := make([]gin.H, 0, len(unknown.Suggestions))
+		for _, stock := range unknown.Suggestions {
+			suggestions = append(suggestions, gin.H{"code": stock.Code, "name": stock.Name})
+		}
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":       "Unknown symbol",
+			"message":     err.Error(),
+			"suggestions": suggestions,
+		})
+	case errors.Is(err, symbols.ErrInvalidSymbol):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"message": err.Error(),
+		})
+	default:
+		h.logger.WithError(err).WithField("symbol", symbol).Error("Failed to resolve symbol")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to resolve symbol",
+			"message": err.Error(),
+		})
+	}
+}
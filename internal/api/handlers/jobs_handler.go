@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/jobs"
+	"golang-swing-trading-signal/internal/utils"
+)
+
+const (
+	defaultPipelineRunLimit = 10
+	maxPipelineRunLimit     = 100
+)
+
+type JobsHandler struct {
+	jobService jobs.JobService
+	logger     *logrus.Logger
+}
+
+func NewJobsHandler(jobService jobs.JobService, logger *logrus.Logger) *JobsHandler {
+	return &JobsHandler{
+		jobService: jobService,
+		logger:     logger,
+	}
+}
+
+// GetPipelineRuns handles GET /api/v1/jobs/pipelines/runs?root_job_id=&limit=
+func (h *JobsHandler) GetPipelineRuns(c *gin.Context) {
+	param := &models.GetPipelineRunParam{Limit: utils.ToPointer(defaultPipelineRunLimit)}
+	if rootJobID := c.Query("root_job_id"); rootJobID != "" {
+		id, err := strconv.ParseUint(rootJobID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request",
+				"message": "root_job_id must be a positive number",
+			})
+			return
+		}
+		param.RootJobID = utils.ToPointer(uint(id))
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 || n > maxPipelineRunLimit {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request",
+				"message": "limit must be between 1 and 100",
+			})
+			return
+		}
+		param.Limit = utils.ToPointer(n)
+	}
+
+	runs, err := h.jobService.GetPipelineRuns(c.Request.Context(), param)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get pipeline runs")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get pipeline runs",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"runs":   runs,
+	})
+}
+
+// GetPipelineRun handles GET /api/v1/jobs/pipelines/runs/:id
+func (h *JobsHandler) GetPipelineRun(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"message": "pipeline run id must be a positive number",
+		})
+		return
+	}
+
+	runs, err := h.jobService.GetPipelineRuns(c.Request.Context(), &models.GetPipelineRunParam{IDs: []uint{uint(id)}})
+	if err != nil {
+		h.logger.WithError(err).WithField("pipeline_run_id", id).Error("Failed to get pipeline run")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get pipeline run",
+			"message": err.Error(),
+		})
+		return
+	}
+	if len(runs) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not found",
+			"message": "pipeline run not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"run":    runs[0],
+	})
+}
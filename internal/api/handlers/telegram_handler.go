@@ -92,3 +92,11 @@ func (h *TelegramHandler) GetBotInfo(c *gin.Context) {
 		},
 	})
 }
+
+// GetEventMetrics returns processing stats of worker result events consumed by the bot
+func (h *TelegramHandler) GetEventMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"events": h.telegramService.EventMetrics(),
+	})
+}
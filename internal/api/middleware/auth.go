@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	APIKeyHeader = "X-API-Key"
+
+	telegramIDKey = "telegram_id"
+)
+
+// APIKeyAuth menolak request tanpa header X-API-Key yang terdaftar di keys. Telegram ID pemilik
+// key disimpan di context dan bisa dibaca dengan TelegramID.
+func APIKeyAuth(keys map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := []byte(c.GetHeader(APIKeyHeader))
+
+		var telegramID int64
+		for key, id := range keys {
+			// bandingkan semua key dengan waktu konstan agar key tidak bisa ditebak dari durasi
+			if subtle.ConstantTimeCompare(provided, []byte(key)) == 1 {
+				telegramID = id
+			}
+		}
+		if len(provided) == 0 || telegramID == 0 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":   "Unauthorized",
+				"message": "missing or invalid " + APIKeyHeader + " header",
+			})
+			return
+		}
+
+		c.Set(telegramIDKey, telegramID)
+		c.Next()
+	}
+}
+
+// TelegramID mengembalikan telegram ID pemilik API key dari APIKeyAuth.
+func TelegramID(c *gin.Context) int64 {
+	return c.GetInt64(telegramIDKey)
+}
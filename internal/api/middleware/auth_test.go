@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAPIKeyAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name       string
+		keys       map[string]int64
+		header     string
+		wantStatus int
+		wantID     int64
+	}{
+		{name: "valid key", keys: map[string]int64{"secret": 42}, header: "secret", wantStatus: http.StatusOK, wantID: 42},
+		{name: "invalid key", keys: map[string]int64{"secret": 42}, header: "guess", wantStatus: http.StatusUnauthorized},
+		{name: "missing key", keys: map[string]int64{"secret": 42}, wantStatus: http.StatusUnauthorized},
+		{name: "no keys configured", keys: nil, header: "secret", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/", APIKeyAuth(tt.keys), func(c *gin.Context) {
+				c.String(http.StatusOK, strconv.FormatInt(TelegramID(c), 10))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(APIKeyHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && rec.Body.String() != strconv.FormatInt(tt.wantID, 10) {
+				t.Errorf("telegram id = %s, want %d", rec.Body.String(), tt.wantID)
+			}
+		})
+	}
+}
@@ -4,20 +4,37 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"golang-swing-trading-signal/internal/api/handlers"
+	"golang-swing-trading-signal/internal/api/middleware"
+	"golang-swing-trading-signal/internal/config"
 )
 
-func SetupRoutes(router *gin.Engine, tradingHandler *handlers.TradingHandler, telegramHandler *handlers.TelegramHandler) {
+func SetupRoutes(router *gin.Engine, apiConfig *config.APIConfig, tradingHandler *handlers.TradingHandler, telegramHandler *handlers.TelegramHandler, analysisHandler *handlers.AnalysisHandler, jobsHandler *handlers.JobsHandler) {
 	// Health check
 	router.GET("/health", tradingHandler.HealthCheck)
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
+		// On-demand analysis endpoints, memanggil LLM sehingga wajib API key
+		authorized := v1.Group("", middleware.APIKeyAuth(apiConfig.Keys))
+		{
+			authorized.POST("/analyze", analysisHandler.AnalyzeStock)
+			authorized.POST("/positions/:id/monitor", analysisHandler.MonitorPosition)
+		}
+
 		// Telegram bot endpoints (basic info only, webhook handled by Telegram service)
 		telegram := v1.Group("/telegram")
 		{
 			telegram.GET("/health", telegramHandler.HealthCheck)
 			telegram.GET("/info", telegramHandler.GetBotInfo)
+			telegram.GET("/events/metrics", telegramHandler.GetEventMetrics)
+		}
+
+		// Job pipeline status
+		jobs := v1.Group("/jobs")
+		{
+			jobs.GET("/pipelines/runs", jobsHandler.GetPipelineRuns)
+			jobs.GET("/pipelines/runs/:id", jobsHandler.GetPipelineRun)
 		}
 	}
 }
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"golang-swing-trading-signal/pkg/postgres"
 	"golang-swing-trading-signal/pkg/redis"
 	"log"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,14 +14,55 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig       `mapstructure:"server"`
-	Yahoo    YahooFinanceConfig `mapstructure:"yahoo"`
-	Gemini   GeminiConfig       `mapstructure:"gemini"`
-	Trading  TradingConfig      `mapstructure:"trading"`
-	Telegram TelegramConfig     `mapstructure:"telegram"`
-	Database postgres.Config    `mapstructure:"database"`
-	Log      LogConfig          `mapstructure:"log"`
-	Redis    redis.Config       `mapstructure:"redis"`
+	Server         ServerConfig         `mapstructure:"server"`
+	Yahoo          YahooFinanceConfig   `mapstructure:"yahoo"`
+	Gemini         GeminiConfig         `mapstructure:"gemini"`
+	Trading        TradingConfig        `mapstructure:"trading"`
+	Telegram       TelegramConfig       `mapstructure:"telegram"`
+	Database       postgres.Config      `mapstructure:"database"`
+	Log            LogConfig            `mapstructure:"log"`
+	Redis          redis.Config         `mapstructure:"redis"`
+	MarketCalendar MarketCalendarConfig `mapstructure:"market_calendar"`
+	MarketPrice    MarketPriceConfig    `mapstructure:"market_price"`
+	LLM            LLMConfig            `mapstructure:"llm"`
+	Prompt         PromptConfig         `mapstructure:"prompt"`
+	RateLimit      RateLimitConfig      `mapstructure:"rate_limit"`
+	StreamConsumer StreamConsumerConfig `mapstructure:"stream_consumer"`
+	Scheduler      SchedulerConfig      `mapstructure:"scheduler"`
+	Quota          QuotaConfig          `mapstructure:"quota"`
+	Digest         DigestConfig         `mapstructure:"digest"`
+	API            APIConfig            `mapstructure:"api"`
+}
+
+// APIConfig berisi API key untuk endpoint on-demand. Setiap key terikat ke telegram ID pemilik
+// sehingga kuota, budget dan kepemilikan posisi mengikuti user tersebut.
+type APIConfig struct {
+	Keys map[string]int64
+}
+
+// PlanLimits adalah batas pemakaian satu plan, 0 berarti tidak dibatasi.
+type PlanLimits struct {
+	AnalysesPerDay     int
+	MaxActivePositions int
+	MaxAlerts          int
+	MaxWatchlist       int
+}
+
+type QuotaConfig struct {
+	Free PlanLimits
+	Pro  PlanLimits
+}
+
+// DigestConfig mengatur worker digest. *Time adalah jam kirim default (HH:MM WIB) untuk
+// user yang belum mengatur jam sendiri, digest mingguan dikirim pada WeeklyDay (0 = Minggu).
+type DigestConfig struct {
+	Enabled      bool
+	PollInterval time.Duration
+	MaxDelay     time.Duration
+	MorningTime  string
+	EndOfDayTime string
+	WeeklyTime   string
+	WeeklyDay    int
 }
 
 type LogConfig struct {
@@ -41,6 +85,54 @@ type GeminiConfig struct {
 	MaxRequestPerMinute int
 	MaxTokenPerMinute   int
 	RequestTemperature  float64
+	MaxRepairAttempts   int
+	AnalysisCacheTTL    time.Duration
+}
+
+type LLMConfig struct {
+	Provider      string
+	OpenAIBaseURL string
+	OpenAIAPIKey  string
+	OpenAIModel   string
+	FixtureDir    string
+	FixtureRecord bool
+
+	// biaya dalam USD per 1 juta token, dipakai untuk laporan /usage dan budget
+	InputCostPerMillionTokens  float64
+	OutputCostPerMillionTokens float64
+	// batas biaya harian/bulanan, 0 berarti tidak dibatasi
+	DailyBudget   float64
+	MonthlyBudget float64
+}
+
+type PromptConfig struct {
+	IndividualAnalysisVersion string
+	PositionMonitoringVersion string
+	ExperimentVersion         string
+	ExperimentPercentage      int
+}
+
+type RateLimitConfig struct {
+	// Backend: memory (per proses, untuk development) atau redis (dibagi antar replica)
+	Backend string
+}
+
+// StreamConsumerConfig mengatur consumer group untuk stream hasil analisa dari worker.
+type StreamConsumerConfig struct {
+	Group string
+	// ConsumerName harus unik per replica, default hostname
+	ConsumerName  string
+	MaxDeliveries int64
+	MinIdle       time.Duration
+}
+
+type SchedulerConfig struct {
+	// Enabled menjalankan job dari tabel jobs / task_schedules di proses ini. Jangan
+	// diaktifkan bersamaan dengan scheduler eksternal yang membaca tabel yang sama.
+	Enabled           bool
+	PollInterval      time.Duration
+	BatchSize         int
+	MaxConcurrentJobs int
 }
 
 type TradingConfig struct {
@@ -49,6 +141,19 @@ type TradingConfig struct {
 	StockList                   []string
 	GetLatestSignalBefore       time.Duration
 	GetBuyListSignalBefore      time.Duration
+	// AnalyzerInflightTTL adalah batas tunggu analisa yang sedang berjalan sebelum request
+	// yang sama boleh dikirim ulang ke worker
+	AnalyzerInflightTTL time.Duration
+}
+
+type MarketCalendarConfig struct {
+	HolidaySource string
+	HolidayFile   string
+}
+
+type MarketPriceConfig struct {
+	LiveMaxAge    time.Duration
+	DelayedMaxAge time.Duration
 }
 
 type TelegramConfig struct {
@@ -60,11 +165,17 @@ type TelegramConfig struct {
 	MaxGlobalRequestPerSecond int
 	MaxUserRequestPerSecond   int
 	MaxEditMessagePerSecond   int
-	RatelimitExpireDuration   time.Duration
 	RateLimitCleanupDuration  time.Duration
 	FeatureNewsMaxAgeInDays   int
 	FeatureNewsLimitStockNews int
 	MaxShowHistoryAnalysis    int
+	AdminIDs                  []int64
+	AllowlistIDs              []int64
+	DefaultRole               string
+	OutboxPollInterval        time.Duration
+	OutboxBatchSize           int
+	OutboxMaxAttempts         int
+	OutboxRetryBackoff        time.Duration
 }
 
 func LoadConfig() (*Config, error) {
@@ -77,6 +188,26 @@ func LoadConfig() (*Config, error) {
 		log.Println("Failed to read config file .env config try read from environment variables")
 	}
 
+	// Parse admin & allowlist telegram IDs from comma-separated string
+	adminIDs, err := parseTelegramIDs("TELEGRAM_ADMIN_IDS")
+	if err != nil {
+		return nil, err
+	}
+	allowlistIDs, err := parseTelegramIDs("TELEGRAM_ALLOWLIST_IDS")
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeys, err := parseAPIKeys("API_KEYS")
+	if err != nil {
+		return nil, err
+	}
+
+	consumerName := viper.GetString("STREAM_CONSUMER_NAME")
+	if consumerName == "" {
+		consumerName, _ = os.Hostname()
+	}
+
 	// Parse stock list from comma-separated string
 	stockListStr := viper.GetString("STOCK_LIST")
 	var stockList []string
@@ -103,6 +234,68 @@ func LoadConfig() (*Config, error) {
 			MaxRequestPerMinute: viper.GetInt("GEMINI_MAX_REQUEST_PER_MINUTE"),
 			MaxTokenPerMinute:   viper.GetInt("GEMINI_MAX_TOKEN_PER_MINUTE"),
 			RequestTemperature:  viper.GetFloat64("GEMINI_REQUEST_TEMPERATURE"),
+			MaxRepairAttempts:   viper.GetInt("GEMINI_MAX_REPAIR_ATTEMPTS"),
+			AnalysisCacheTTL:    viper.GetDuration("GEMINI_ANALYSIS_CACHE_TTL"),
+		},
+		LLM: LLMConfig{
+			Provider:      viper.GetString("LLM_PROVIDER"),
+			OpenAIBaseURL: viper.GetString("LLM_OPENAI_BASE_URL"),
+			OpenAIAPIKey:  viper.GetString("LLM_OPENAI_API_KEY"),
+			OpenAIModel:   viper.GetString("LLM_OPENAI_MODEL"),
+			FixtureDir:    viper.GetString("LLM_FIXTURE_DIR"),
+			FixtureRecord: viper.GetBool("LLM_FIXTURE_RECORD"),
+
+			InputCostPerMillionTokens:  viper.GetFloat64("LLM_INPUT_COST_PER_MILLION_TOKENS"),
+			OutputCostPerMillionTokens: viper.GetFloat64("LLM_OUTPUT_COST_PER_MILLION_TOKENS"),
+			DailyBudget:                viper.GetFloat64("LLM_DAILY_BUDGET"),
+			MonthlyBudget:              viper.GetFloat64("LLM_MONTHLY_BUDGET"),
+		},
+		Prompt: PromptConfig{
+			IndividualAnalysisVersion: viper.GetString("PROMPT_INDIVIDUAL_ANALYSIS_VERSION"),
+			PositionMonitoringVersion: viper.GetString("PROMPT_POSITION_MONITORING_VERSION"),
+			ExperimentVersion:         viper.GetString("PROMPT_EXPERIMENT_VERSION"),
+			ExperimentPercentage:      viper.GetInt("PROMPT_EXPERIMENT_PERCENTAGE"),
+		},
+		RateLimit: RateLimitConfig{
+			Backend: viper.GetString("RATE_LIMIT_BACKEND"),
+		},
+		StreamConsumer: StreamConsumerConfig{
+			Group:         viper.GetString("STREAM_CONSUMER_GROUP"),
+			ConsumerName:  consumerName,
+			MaxDeliveries: viper.GetInt64("STREAM_CONSUMER_MAX_DELIVERIES"),
+			MinIdle:       viper.GetDuration("STREAM_CONSUMER_MIN_IDLE"),
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:           viper.GetBool("SCHEDULER_ENABLED"),
+			PollInterval:      viper.GetDuration("SCHEDULER_POLL_INTERVAL"),
+			BatchSize:         viper.GetInt("SCHEDULER_BATCH_SIZE"),
+			MaxConcurrentJobs: viper.GetInt("SCHEDULER_MAX_CONCURRENT_JOBS"),
+		},
+		Quota: QuotaConfig{
+			Free: PlanLimits{
+				AnalysesPerDay:     viper.GetInt("QUOTA_FREE_ANALYSES_PER_DAY"),
+				MaxActivePositions: viper.GetInt("QUOTA_FREE_MAX_ACTIVE_POSITIONS"),
+				MaxAlerts:          viper.GetInt("QUOTA_FREE_MAX_ALERTS"),
+				MaxWatchlist:       viper.GetInt("QUOTA_FREE_MAX_WATCHLIST"),
+			},
+			Pro: PlanLimits{
+				AnalysesPerDay:     viper.GetInt("QUOTA_PRO_ANALYSES_PER_DAY"),
+				MaxActivePositions: viper.GetInt("QUOTA_PRO_MAX_ACTIVE_POSITIONS"),
+				MaxAlerts:          viper.GetInt("QUOTA_PRO_MAX_ALERTS"),
+				MaxWatchlist:       viper.GetInt("QUOTA_PRO_MAX_WATCHLIST"),
+			},
+		},
+		Digest: DigestConfig{
+			Enabled:      viper.GetBool("DIGEST_ENABLED"),
+			PollInterval: viper.GetDuration("DIGEST_POLL_INTERVAL"),
+			MaxDelay:     viper.GetDuration("DIGEST_MAX_DELAY"),
+			MorningTime:  viper.GetString("DIGEST_MORNING_TIME"),
+			EndOfDayTime: viper.GetString("DIGEST_END_OF_DAY_TIME"),
+			WeeklyTime:   viper.GetString("DIGEST_WEEKLY_TIME"),
+			WeeklyDay:    viper.GetInt("DIGEST_WEEKLY_DAY"),
+		},
+		API: APIConfig{
+			Keys: apiKeys,
 		},
 		Trading: TradingConfig{
 			DefaultMaxHoldingPeriodDays: viper.GetInt("DEFAULT_MAX_HOLDING_PERIOD_DAYS"),
@@ -110,6 +303,7 @@ func LoadConfig() (*Config, error) {
 			StockList:                   stockList,
 			GetLatestSignalBefore:       viper.GetDuration("GET_LATEST_SIGNAL_BEFORE"),
 			GetBuyListSignalBefore:      viper.GetDuration("GET_BUY_LIST_SIGNAL_BEFORE"),
+			AnalyzerInflightTTL:         viper.GetDuration("STOCK_ANALYZER_INFLIGHT_TTL"),
 		},
 		Log: LogConfig{
 			Level: viper.GetString("LOG_LEVEL"),
@@ -123,11 +317,17 @@ func LoadConfig() (*Config, error) {
 			MaxGlobalRequestPerSecond: viper.GetInt("TELEGRAM_MAX_GLOBAL_REQUEST_PER_SECOND"),
 			MaxUserRequestPerSecond:   viper.GetInt("TELEGRAM_MAX_USER_REQUEST_PER_SECOND"),
 			MaxEditMessagePerSecond:   viper.GetInt("TELEGRAM_MAX_EDIT_MESSAGE_PER_SECOND"),
-			RatelimitExpireDuration:   viper.GetDuration("TELEGRAM_RATELIMIT_EXPIRE_DURATION"),
 			RateLimitCleanupDuration:  viper.GetDuration("TELEGRAM_RATE_LIMIT_CLEANUP_DURATION"),
 			FeatureNewsMaxAgeInDays:   viper.GetInt("TELEGRAM_FEATURE_NEWS_MAX_AGE_IN_DAYS"),
 			FeatureNewsLimitStockNews: viper.GetInt("TELEGRAM_FEATURE_NEWS_LIMIT_STOCK_NEWS"),
 			MaxShowHistoryAnalysis:    viper.GetInt("TELEGRAM_MAX_SHOW_HISTORY_ANALYSIS"),
+			AdminIDs:                  adminIDs,
+			AllowlistIDs:              allowlistIDs,
+			DefaultRole:               viper.GetString("TELEGRAM_DEFAULT_ROLE"),
+			OutboxPollInterval:        viper.GetDuration("TELEGRAM_OUTBOX_POLL_INTERVAL"),
+			OutboxBatchSize:           viper.GetInt("TELEGRAM_OUTBOX_BATCH_SIZE"),
+			OutboxMaxAttempts:         viper.GetInt("TELEGRAM_OUTBOX_MAX_ATTEMPTS"),
+			OutboxRetryBackoff:        viper.GetDuration("TELEGRAM_OUTBOX_RETRY_BACKOFF"),
 		},
 		Database: postgres.Config{
 			Host:            viper.GetString("DATABASE_HOST"),
@@ -142,6 +342,14 @@ func LoadConfig() (*Config, error) {
 			ConnMaxLifetime: viper.GetString("DATABASE_CONN_MAX_LIFETIME"),
 			LogLevel:        viper.GetString("DATABASE_LOG_LEVEL"),
 		},
+		MarketCalendar: MarketCalendarConfig{
+			HolidaySource: viper.GetString("MARKET_CALENDAR_HOLIDAY_SOURCE"),
+			HolidayFile:   viper.GetString("MARKET_CALENDAR_HOLIDAY_FILE"),
+		},
+		MarketPrice: MarketPriceConfig{
+			LiveMaxAge:    viper.GetDuration("MARKET_PRICE_LIVE_MAX_AGE"),
+			DelayedMaxAge: viper.GetDuration("MARKET_PRICE_DELAYED_MAX_AGE"),
+		},
 		Redis: redis.Config{
 			Host:     viper.GetString("REDIS_HOST"),
 			Port:     viper.GetInt("REDIS_PORT"),
@@ -153,3 +361,40 @@ func LoadConfig() (*Config, error) {
 
 	return config, nil
 }
+
+// parseAPIKeys membaca daftar "key:telegram_id" yang dipisah koma.
+func parseAPIKeys(key string) (map[string]int64, error) {
+	keys := make(map[string]int64)
+	for _, entry := range strings.Split(viper.GetString(key), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		apiKey, id, ok := strings.Cut(entry, ":")
+		if !ok || apiKey == "" {
+			return nil, fmt.Errorf("invalid %s entry, want key:telegram_id", key)
+		}
+		telegramID, err := strconv.ParseInt(strings.TrimSpace(id), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s telegram id %q: %w", key, id, err)
+		}
+		keys[strings.TrimSpace(apiKey)] = telegramID
+	}
+	return keys, nil
+}
+
+func parseTelegramIDs(key string) ([]int64, error) {
+	var ids []int64
+	for _, id := range strings.Split(viper.GetString(key), ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		telegramID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", key, id, err)
+		}
+		ids = append(ids, telegramID)
+	}
+	return ids, nil
+}
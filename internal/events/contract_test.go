@@ -0,0 +1,144 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	goRedis "github.com/redis/go-redis/v9"
+)
+
+// TestContract_Fixtures memastikan setiap tipe dan versi event yang terdaftar punya fixture
+// di testdata dan payload struct saat ini masih bisa membaca dan menulis fixture tersebut
+// tanpa kehilangan field. Jika test ini gagal karena perubahan struct, tambahkan versi
+// baru alih-alih mengubah fixture versi lama.
+func TestContract_Fixtures(t *testing.T) {
+	for key, newPayload := range schemas {
+		name := fmt.Sprintf("%s.v%d", key.eventType, key.version)
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", name+".json"))
+			if err != nil {
+				t.Fatalf("missing contract fixture: %v", err)
+			}
+
+			var envelope Envelope
+			if err := json.Unmarshal(data, &envelope); err != nil {
+				t.Fatalf("invalid fixture envelope: %v", err)
+			}
+			if envelope.Type != key.eventType || envelope.Version != key.version {
+				t.Fatalf("fixture is %s v%d, want %s v%d", envelope.Type, envelope.Version, key.eventType, key.version)
+			}
+			if envelope.ID == "" || envelope.CorrelationID == "" || envelope.OccurredAt.IsZero() {
+				t.Errorf("fixture envelope metadata is incomplete: %+v", envelope)
+			}
+
+			payload := newPayload()
+			decoder := json.NewDecoder(bytes.NewReader(envelope.Payload))
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(payload); err != nil {
+				t.Fatalf("payload struct cannot read fixture: %v", err)
+			}
+
+			encoded, err := json.Marshal(payload)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			var got, want map[string]interface{}
+			_ = json.Unmarshal(encoded, &got)
+			_ = json.Unmarshal(envelope.Payload, &want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("payload round trip mismatch\n got: %s\nwant: %s", encoded, envelope.Payload)
+			}
+		})
+	}
+}
+
+func TestEnvelope_StreamRoundTrip(t *testing.T) {
+	ctx := WithCorrelationID(context.Background(), "corr-1")
+	envelope, err := New(ctx, TypeStockAnalyzerRequested, map[string]interface{}{"stock_code": "BBCA"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// redis mengembalikan semua field sebagai string
+	values := map[string]interface{}{}
+	for key, value := range envelope.Values() {
+		values[key] = fmt.Sprint(value)
+	}
+	got, err := FromMessage(goRedis.XMessage{ID: "1-0", Values: values}, TypeStockAnalyzerRequested)
+	if err != nil {
+		t.Fatalf("FromMessage() error = %v", err)
+	}
+
+	if got.ID != envelope.ID || got.Type != envelope.Type || got.Version != envelope.Version ||
+		got.CorrelationID != "corr-1" || !got.OccurredAt.Equal(envelope.OccurredAt) ||
+		string(got.Payload) != string(envelope.Payload) {
+		t.Errorf("FromMessage() = %+v, want %+v", got, envelope)
+	}
+}
+
+func TestFromMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      map[string]interface{}
+		wantType    string
+		wantVersion int
+		wantErr     bool
+		wantDecode  error
+	}{
+		{
+			name:        "legacy payload only",
+			values:      map[string]interface{}{"payload": `{"stock_code":"BBCA"}`},
+			wantType:    TypeStockAnalyzerCompleted,
+			wantVersion: 1,
+		},
+		{
+			name:        "unsupported version",
+			values:      map[string]interface{}{"type": TypeStockAnalyzerCompleted, "version": "99", "payload": `{}`},
+			wantType:    TypeStockAnalyzerCompleted,
+			wantVersion: 99,
+			wantDecode:  ErrUnsupportedVersion,
+		},
+		{
+			name:    "missing payload",
+			values:  map[string]interface{}{"type": TypeStockAnalyzerCompleted, "version": "1"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid version",
+			values:  map[string]interface{}{"type": TypeStockAnalyzerCompleted, "version": "v1", "payload": `{}`},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envelope, err := FromMessage(goRedis.XMessage{ID: "1-0", Values: tt.values}, TypeStockAnalyzerCompleted)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if envelope.Type != tt.wantType || envelope.Version != tt.wantVersion {
+				t.Errorf("FromMessage() = %s v%d, want %s v%d", envelope.Type, envelope.Version, tt.wantType, tt.wantVersion)
+			}
+
+			var payload map[string]interface{}
+			if err := envelope.DecodeInto(&payload); !errors.Is(err, tt.wantDecode) {
+				t.Errorf("DecodeInto() error = %v, want %v", err, tt.wantDecode)
+			}
+		})
+	}
+}
+
+func TestNew_UnknownEvent(t *testing.T) {
+	if _, err := New(context.Background(), "unknown.event", nil); !errors.Is(err, ErrUnknownEvent) {
+		t.Errorf("New() error = %v, want ErrUnknownEvent", err)
+	}
+}
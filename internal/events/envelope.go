@@ -0,0 +1,143 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	goRedis "github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrUnknownEvent       = errors.New("unknown event type")
+	ErrUnsupportedVersion = errors.New("unsupported event version")
+)
+
+// Field pada pesan redis stream. Payload tetap di field "payload" agar consumer lama yang
+// hanya membaca payload tetap bisa berjalan.
+const (
+	fieldID            = "id"
+	fieldType          = "type"
+	fieldVersion       = "version"
+	fieldOccurredAt    = "occurred_at"
+	fieldCorrelationID = "correlation_id"
+	fieldPayload       = "payload"
+)
+
+// Envelope membungkus payload event dengan metadata yang sama untuk semua event.
+type Envelope struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	Version       int             `json:"version"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	CorrelationID string          `json:"correlation_id"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// New membuat envelope untuk versi terbaru eventType. Correlation ID diambil dari ctx,
+// jika kosong ID event dipakai sebagai correlation ID (awal rantai event).
+func New(ctx context.Context, eventType string, payload interface{}) (*Envelope, error) {
+	version, ok := LatestVersion(eventType)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, eventType)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s payload: %w", eventType, err)
+	}
+
+	id := uuid.NewString()
+	correlationID := CorrelationIDFromContext(ctx)
+	if correlationID == "" {
+		correlationID = id
+	}
+	return &Envelope{
+		ID:            id,
+		Type:          eventType,
+		Version:       version,
+		OccurredAt:    time.Now().UTC(),
+		CorrelationID: correlationID,
+		Payload:       data,
+	}, nil
+}
+
+// DecodeInto membaca payload ke v setelah memastikan tipe dan versi event dikenal.
+func (e *Envelope) DecodeInto(v interface{}) error {
+	if !isRegistered(e.Type, e.Version) {
+		return fmt.Errorf("%w: %s v%d", ErrUnsupportedVersion, e.Type, e.Version)
+	}
+	if err := json.Unmarshal(e.Payload, v); err != nil {
+		return fmt.Errorf("failed to decode %s v%d payload: %w", e.Type, e.Version, err)
+	}
+	return nil
+}
+
+// Values mengubah envelope menjadi field pesan redis stream.
+func (e *Envelope) Values() map[string]interface{} {
+	return map[string]interface{}{
+		fieldID:            e.ID,
+		fieldType:          e.Type,
+		fieldVersion:       e.Version,
+		fieldOccurredAt:    e.OccurredAt.Format(time.RFC3339Nano),
+		fieldCorrelationID: e.CorrelationID,
+		fieldPayload:       string(e.Payload),
+	}
+}
+
+// FromMessage membaca envelope dari pesan redis stream. Pesan lama yang hanya berisi
+// "payload" dianggap legacyType versi 1.
+func FromMessage(message goRedis.XMessage, legacyType string) (*Envelope, error) {
+	payload, ok := message.Values[fieldPayload].(string)
+	if !ok {
+		return nil, fmt.Errorf("stream message %s has no payload", message.ID)
+	}
+
+	eventType, ok := message.Values[fieldType].(string)
+	if !ok {
+		return &Envelope{
+			ID:            message.ID,
+			Type:          legacyType,
+			Version:       1,
+			CorrelationID: message.ID,
+			Payload:       json.RawMessage(payload),
+		}, nil
+	}
+
+	version, err := strconv.Atoi(stringValue(message.Values[fieldVersion]))
+	if err != nil {
+		return nil, fmt.Errorf("stream message %s has invalid version: %w", message.ID, err)
+	}
+	envelope := &Envelope{
+		ID:            stringValue(message.Values[fieldID]),
+		Type:          eventType,
+		Version:       version,
+		CorrelationID: stringValue(message.Values[fieldCorrelationID]),
+		Payload:       json.RawMessage(payload),
+	}
+	if occurredAt := stringValue(message.Values[fieldOccurredAt]); occurredAt != "" {
+		if envelope.OccurredAt, err = time.Parse(time.RFC3339Nano, occurredAt); err != nil {
+			return nil, fmt.Errorf("stream message %s has invalid occurred_at: %w", message.ID, err)
+		}
+	}
+	return envelope, nil
+}
+
+func stringValue(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
+
+type correlationIDKey struct{}
+
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, correlationID)
+}
+
+func CorrelationIDFromContext(ctx context.Context) string {
+	correlationID, _ := ctx.Value(correlationIDKey{}).(string)
+	return correlationID
+}
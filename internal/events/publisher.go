@@ -0,0 +1,50 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"golang-swing-trading-signal/pkg/redis"
+
+	goRedis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+type Publisher interface {
+	// Publish membungkus payload dengan envelope versi terbaru eventType lalu mengirimnya
+	// ke stream.
+	Publish(ctx context.Context, stream string, eventType string, payload interface{}) (*Envelope, error)
+}
+
+type redisPublisher struct {
+	client *redis.Client
+	log    *logrus.Logger
+}
+
+func NewRedisPublisher(client *redis.Client, log *logrus.Logger) Publisher {
+	return &redisPublisher{
+		client: client,
+		log:    log,
+	}
+}
+
+func (p *redisPublisher) Publish(ctx context.Context, stream string, eventType string, payload interface{}) (*Envelope, error) {
+	envelope, err := New(ctx, eventType, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.client.XAdd(ctx, &goRedis.XAddArgs{
+		Stream: stream,
+		Values: envelope.Values(),
+	}).Err(); err != nil {
+		p.log.Error("failed to publish event", logrus.Fields{
+			"stream":         stream,
+			"type":           eventType,
+			"correlation_id": envelope.CorrelationID,
+			"error":          err,
+		})
+		return nil, fmt.Errorf("failed to publish %s to %s: %w", eventType, stream, err)
+	}
+	return envelope, nil
+}
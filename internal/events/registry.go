@@ -0,0 +1,44 @@
+package events
+
+import (
+	"golang-swing-trading-signal/internal/models"
+)
+
+// Tipe event yang dipertukarkan dengan worker eksternal lewat redis stream.
+const (
+	TypeStockAnalyzerRequested        = "stock.analyzer.requested"
+	TypeStockAnalyzerCompleted        = "stock.analyzer.completed"
+	TypeStockPositionMonitorRequested = "stock.position.monitor.requested"
+	TypeStockPositionMonitorCompleted = "stock.position.monitor.completed"
+)
+
+type schemaKey struct {
+	eventType string
+	version   int
+}
+
+// schemas adalah daftar payload per tipe dan versi event. Perubahan payload yang tidak
+// kompatibel harus menambah versi baru, versi lama tetap didaftarkan selama masih ada
+// producer / consumer yang memakainya. Setiap entry wajib punya fixture di testdata.
+var schemas = map[schemaKey]func() interface{}{
+	{TypeStockAnalyzerRequested, 1}:        func() interface{} { return &models.RequestStockAnalyzer{} },
+	{TypeStockAnalyzerCompleted, 1}:        func() interface{} { return &models.StockAnalyzerResult{} },
+	{TypeStockPositionMonitorRequested, 1}: func() interface{} { return &models.RequestStockPositionMonitoring{} },
+	{TypeStockPositionMonitorCompleted, 1}: func() interface{} { return &models.StockPositionMonitorResult{} },
+}
+
+// LatestVersion mengembalikan versi terbaru yang terdaftar untuk eventType.
+func LatestVersion(eventType string) (int, bool) {
+	latest := 0
+	for key := range schemas {
+		if key.eventType == eventType && key.version > latest {
+			latest = key.version
+		}
+	}
+	return latest, latest > 0
+}
+
+func isRegistered(eventType string, version int) bool {
+	_, ok := schemas[schemaKey{eventType, version}]
+	return ok
+}
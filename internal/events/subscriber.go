@@ -0,0 +1,134 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang-swing-trading-signal/pkg/redis"
+
+	goRedis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+type Handler func(ctx context.Context, envelope *Envelope) error
+
+type Middleware func(next Handler) Handler
+
+// Chain membungkus handler dengan middleware, middleware pertama menjadi yang terluar.
+func Chain(handler Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// NewSubscriber membuat consumer group untuk cfg.Stream yang mengubah setiap pesan menjadi
+// Envelope. legacyType dipakai untuk pesan lama tanpa envelope.
+func NewSubscriber(client *redis.Client, log *logrus.Logger, cfg redis.ConsumerConfig, legacyType string, handler Handler, middlewares ...Middleware) *redis.Consumer {
+	handler = Chain(handler, middlewares...)
+	return redis.NewConsumer(client, log, cfg, func(ctx context.Context, message goRedis.XMessage) error {
+		envelope, err := FromMessage(message, legacyType)
+		if err != nil {
+			return err
+		}
+		return handler(WithCorrelationID(ctx, envelope.CorrelationID), envelope)
+	})
+}
+
+// Logging mencatat setiap event yang gagal diproses beserta durasinya.
+func Logging(log *logrus.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, envelope *Envelope) error {
+			start := time.Now()
+			err := next(ctx, envelope)
+			fields := logrus.Fields{
+				"event_id":       envelope.ID,
+				"type":           envelope.Type,
+				"version":        envelope.Version,
+				"correlation_id": envelope.CorrelationID,
+				"duration_ms":    time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				fields["error"] = err
+				log.Error("failed to handle event", fields)
+				return err
+			}
+			log.Debug("event handled", fields)
+			return nil
+		}
+	}
+}
+
+// Retry mengulang handler di dalam proses sebelum menyerah ke mekanisme reclaim stream.
+func Retry(attempts int, backoff time.Duration) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, envelope *Envelope) error {
+			var err error
+			for attempt := 1; attempt <= attempts; attempt++ {
+				if err = next(ctx, envelope); err == nil {
+					return nil
+				}
+				if attempt == attempts {
+					break
+				}
+				select {
+				case <-ctx.Done():
+					return err
+				case <-time.After(backoff * time.Duration(attempt)):
+				}
+			}
+			return err
+		}
+	}
+}
+
+// EventStats adalah statistik pemrosesan satu tipe event.
+type EventStats struct {
+	Handled       int64         `json:"handled"`
+	Failed        int64         `json:"failed"`
+	TotalDuration time.Duration `json:"total_duration"`
+}
+
+// Metrics mengumpulkan statistik event per tipe di memori.
+type Metrics struct {
+	mu    sync.Mutex
+	stats map[string]EventStats
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{stats: make(map[string]EventStats)}
+}
+
+func (m *Metrics) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, envelope *Envelope) error {
+			start := time.Now()
+			err := next(ctx, envelope)
+			m.observe(envelope.Type, time.Since(start), err)
+			return err
+		}
+	}
+}
+
+func (m *Metrics) observe(eventType string, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := m.stats[eventType]
+	stats.Handled++
+	if err != nil {
+		stats.Failed++
+	}
+	stats.TotalDuration += duration
+	m.stats[eventType] = stats
+}
+
+func (m *Metrics) Snapshot() map[string]EventStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make(map[string]EventStats, len(m.stats))
+	for eventType, stats := range m.stats {
+		snapshot[eventType] = stats
+	}
+	return snapshot
+}
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChain_Order(t *testing.T) {
+	var order []string
+	middleware := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, envelope *Envelope) error {
+				order = append(order, name)
+				return next(ctx, envelope)
+			}
+		}
+	}
+
+	handler := Chain(func(ctx context.Context, envelope *Envelope) error {
+		order = append(order, "handler")
+		return nil
+	}, middleware("first"), middleware("second"))
+
+	if err := handler(context.Background(), &Envelope{}); err != nil {
+		t.Fatalf("handler() error = %v", err)
+	}
+	if got := len(order); got != 3 || order[0] != "first" || order[1] != "second" || order[2] != "handler" {
+		t.Errorf("order = %v, want [first second handler]", order)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		attempts  int
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "success first try", failures: 0, attempts: 3, wantCalls: 1},
+		{name: "success after retry", failures: 2, attempts: 3, wantCalls: 3},
+		{name: "give up", failures: 5, attempts: 3, wantCalls: 3, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			handler := Retry(tt.attempts, time.Millisecond)(func(ctx context.Context, envelope *Envelope) error {
+				calls++
+				if calls <= tt.failures {
+					return errors.New("failed")
+				}
+				return nil
+			})
+
+			err := handler(context.Background(), &Envelope{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("handler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	metrics := NewMetrics()
+	handler := metrics.Middleware()(func(ctx context.Context, envelope *Envelope) error {
+		if envelope.ID == "bad" {
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	for _, id := range []string{"a", "b", "bad"} {
+		_ = handler(context.Background(), &Envelope{ID: id, Type: TypeStockAnalyzerCompleted})
+	}
+
+	stats := metrics.Snapshot()[TypeStockAnalyzerCompleted]
+	if stats.Handled != 3 || stats.Failed != 1 {
+		t.Errorf("stats = %+v, want handled 3 failed 1", stats)
+	}
+}
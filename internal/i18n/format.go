@@ -0,0 +1,99 @@
+package i18n
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type numberFormat struct {
+	group   string
+	decimal string
+}
+
+var numberFormats = map[Locale]numberFormat{
+	LocaleID: {group: ".", decimal: ","},
+	LocaleEN: {group: ",", decimal: "."},
+}
+
+var monthNames = map[Locale][12]string{
+	LocaleID: {"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"},
+	LocaleEN: {"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+}
+
+var weekdayNames = map[Locale][7]string{
+	LocaleID: {"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"},
+	LocaleEN: {"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+}
+
+// Number memformat angka dengan pemisah ribuan dan desimal sesuai bahasa,
+// misalnya 1234.5 menjadi "1.234,5" (id) atau "1,234.5" (en).
+func (t Translator) Number(value float64, decimals int) string {
+	nf := numberFormats[t.Locale()]
+
+	text := strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(text, ".")
+
+	var sb strings.Builder
+	if value < 0 && strings.Trim(text, "0.") != "" {
+		sb.WriteString("-")
+	}
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			sb.WriteString(nf.group)
+		}
+		sb.WriteRune(digit)
+	}
+	if fraction != "" {
+		sb.WriteString(nf.decimal)
+		sb.WriteString(fraction)
+	}
+	return sb.String()
+}
+
+// Int memformat bilangan bulat dengan pemisah ribuan, dipakai untuk harga saham.
+func (t Translator) Int(value float64) string {
+	return t.Number(math.Trunc(value), 0)
+}
+
+// Percent memformat persentase dengan decimals angka di belakang koma, misalnya "65,5%".
+func (t Translator) Percent(value float64, decimals int) string {
+	return t.Number(value, decimals) + "%"
+}
+
+// PercentChange memformat perubahan persentase bertanda dengan satu desimal, misalnya "+1,5%".
+func (t Translator) PercentChange(value float64) string {
+	text := t.Percent(value, 1)
+	if !strings.HasPrefix(text, "-") {
+		text = "+" + text
+	}
+	return text
+}
+
+func (t Translator) Month(month time.Month) string {
+	return monthNames[t.Locale()][month-1]
+}
+
+func (t Translator) Weekday(day time.Weekday) string {
+	return weekdayNames[t.Locale()][day]
+}
+
+// Date memformat tanggal panjang, misalnya "02 Januari 2026".
+func (t Translator) Date(date time.Time) string {
+	return fmt.Sprintf("%02d %s %d", date.Day(), t.Month(date.Month()), date.Year())
+}
+
+// ShortDate memformat tanggal ringkas, misalnya "02/01/2026" (id) atau "Jan 02, 2026" (en).
+func (t Translator) ShortDate(date time.Time) string {
+	if t.Locale() == LocaleEN {
+		return date.Format("Jan 02, 2006")
+	}
+	return date.Format("02/01/2006")
+}
+
+// DateTime memformat tanggal dan jam WIB, misalnya "02 Januari 2026 - 15:04 WIB".
+func (t Translator) DateTime(date time.Time) string {
+	return fmt.Sprintf("%s - %02d:%02d WIB", t.Date(date), date.Hour(), date.Minute())
+}
@@ -0,0 +1,172 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Locale adalah bahasa pesan bot.
+type Locale string
+
+const (
+	LocaleID Locale = "id"
+	LocaleEN Locale = "en"
+
+	DefaultLocale = LocaleID
+)
+
+// Locales adalah semua bahasa yang memiliki katalog pesan.
+var Locales = []Locale{LocaleID, LocaleEN}
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// Message adalah satu entry katalog. Pesan biasa hanya mengisi Other, pesan dengan bentuk
+// jamak mengisi bentuk sesuai aturan plural bahasanya (lihat pluralForms).
+type Message struct {
+	One   string `json:"one,omitempty"`
+	Other string `json:"other"`
+}
+
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		m.Other = text
+		return nil
+	}
+
+	type plural Message
+	return json.Unmarshal(data, (*plural)(m))
+}
+
+func (m Message) IsPlural() bool {
+	return m.One != ""
+}
+
+var catalogs = mustLoadCatalogs()
+
+func mustLoadCatalogs() map[Locale]map[string]Message {
+	loaded := make(map[Locale]map[string]Message, len(Locales))
+	for _, locale := range Locales {
+		data, err := localeFiles.ReadFile(path.Join("locales", string(locale)+".json"))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing catalog %s: %v", locale, err))
+		}
+
+		catalog := make(map[string]Message)
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog %s: %v", locale, err))
+		}
+		loaded[locale] = catalog
+	}
+	return loaded
+}
+
+// Parse mengubah kode bahasa seperti "en", "en-US" atau "id_ID" menjadi Locale yang didukung.
+func Parse(value string) (Locale, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if base, _, found := strings.Cut(strings.ReplaceAll(value, "_", "-"), "-"); found {
+		value = base
+	}
+	for _, locale := range Locales {
+		if value == string(locale) {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// Resolve memilih bahasa user: pilihan dari /language lebih dulu, lalu bahasa aplikasi
+// Telegram. Bahasa Telegram yang tidak didukung memakai bahasa Inggris, user tanpa
+// informasi bahasa memakai DefaultLocale.
+func Resolve(preferred string, languageCodes ...string) Locale {
+	if locale, ok := Parse(preferred); ok {
+		return locale
+	}
+	for _, languageCode := range languageCodes {
+		if languageCode == "" {
+			continue
+		}
+		if locale, ok := Parse(languageCode); ok {
+			return locale
+		}
+		return LocaleEN
+	}
+	return DefaultLocale
+}
+
+// Translator menerjemahkan key katalog ke bahasa tertentu.
+type Translator struct {
+	locale Locale
+}
+
+func New(locale Locale) Translator {
+	if _, ok := catalogs[locale]; !ok {
+		locale = DefaultLocale
+	}
+	return Translator{locale: locale}
+}
+
+func (t Translator) Locale() Locale {
+	if t.locale == "" {
+		return DefaultLocale
+	}
+	return t.locale
+}
+
+// T mengembalikan pesan untuk key, args diformat dengan fmt.Sprintf. Key yang tidak ada di
+// bahasa user memakai DefaultLocale, key yang tidak ada sama sekali dikembalikan apa adanya.
+func (t Translator) T(key string, args ...any) string {
+	message, ok := t.lookup(key)
+	if !ok {
+		return key
+	}
+	return format(message.Other, args)
+}
+
+// Plural memilih bentuk pesan berdasarkan n. n selalu menjadi argumen pertama template,
+// diikuti args.
+func (t Translator) Plural(key string, n int, args ...any) string {
+	message, ok := t.lookup(key)
+	if !ok {
+		return key
+	}
+
+	text := message.Other
+	if pluralForm(t.Locale(), n) == "one" && message.One != "" {
+		text = message.One
+	}
+	return format(text, append([]any{n}, args...))
+}
+
+func (t Translator) lookup(key string) (Message, bool) {
+	if message, ok := catalogs[t.Locale()][key]; ok {
+		return message, true
+	}
+	message, ok := catalogs[DefaultLocale][key]
+	return message, ok
+}
+
+func format(text string, args []any) string {
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// pluralForms adalah bentuk plural (CLDR) yang wajib ada di katalog setiap bahasa untuk
+// pesan jamak. Bahasa Indonesia tidak membedakan bentuk tunggal dan jamak.
+var pluralForms = map[Locale][]string{
+	LocaleID: {"other"},
+	LocaleEN: {"one", "other"},
+}
+
+func pluralForm(locale Locale, n int) string {
+	if locale == LocaleEN && (n == 1 || n == -1) {
+		return "one"
+	}
+	return "other"
+}
@@ -0,0 +1,213 @@
+package i18n
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCatalogsComplete gagal jika ada key yang hilang di salah satu bahasa atau pesan jamak
+// yang tidak lengkap bentuk plural-nya.
+func TestCatalogsComplete(t *testing.T) {
+	keys := map[string]bool{}
+	for _, locale := range Locales {
+		for key := range catalogs[locale] {
+			keys[key] = true
+		}
+	}
+	if len(keys) == 0 {
+		t.Fatal("catalogs are empty")
+	}
+
+	for key := range keys {
+		plural := false
+		for _, locale := range Locales {
+			if catalogs[locale][key].IsPlural() {
+				plural = true
+			}
+		}
+
+		for _, locale := range Locales {
+			message, ok := catalogs[locale][key]
+			if !ok {
+				t.Errorf("key %q is missing in locale %s", key, locale)
+				continue
+			}
+			if message.Other == "" {
+				t.Errorf("key %q in locale %s has an empty message", key, locale)
+			}
+			if !plural {
+				continue
+			}
+			for _, form := range pluralForms[locale] {
+				if form == "one" && message.One == "" {
+					t.Errorf("plural key %q in locale %s is missing form %q", key, locale, form)
+				}
+			}
+		}
+	}
+}
+
+var verbPattern = regexp.MustCompile(`%(\[(\d+)\])?[-+# 0]*\d*(\.\d+)?([a-zA-Z%])`)
+
+// formatArgs mengembalikan pasangan "posisi argumen:verb" dari template fmt, mengikuti
+// aturan fmt bahwa verb setelah %[n] memakai argumen n+1.
+func formatArgs(text string) []string {
+	var args []string
+	next := 1
+	for _, match := range verbPattern.FindAllStringSubmatch(text, -1) {
+		if match[4] == "%" {
+			continue
+		}
+		if match[2] != "" {
+			next, _ = strconv.Atoi(match[2])
+		}
+		args = append(args, strconv.Itoa(next)+":"+match[4])
+		next++
+	}
+	slices.Sort(args)
+	return slices.Compact(args)
+}
+
+func TestCatalogsFormatArgs(t *testing.T) {
+	for key, base := range catalogs[DefaultLocale] {
+		want := formatArgs(base.Other)
+		for _, locale := range Locales {
+			message := catalogs[locale][key]
+			for _, text := range []string{message.Other, message.One} {
+				if text == "" {
+					continue
+				}
+				if got := formatArgs(text); !slices.Equal(got, want) {
+					t.Errorf("key %q in locale %s uses args %v, want %v", key, locale, got, want)
+				}
+			}
+		}
+	}
+}
+
+var keyLiteralPattern = regexp.MustCompile(`"([a-z_]+)\.([a-z0-9_]+)"`)
+
+// TestSourceKeysExist memastikan setiap key katalog yang dipakai di kode ada di katalog.
+func TestSourceKeysExist(t *testing.T) {
+	namespaces := map[string]bool{}
+	for key := range catalogs[DefaultLocale] {
+		namespace, _, _ := strings.Cut(key, ".")
+		namespaces[namespace] = true
+	}
+
+	used := 0
+	err := filepath.WalkDir("..", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, match := range keyLiteralPattern.FindAllStringSubmatch(string(source), -1) {
+			if !namespaces[match[1]] {
+				continue
+			}
+			used++
+			key := match[1] + "." + match[2]
+			if _, ok := catalogs[DefaultLocale][key]; !ok {
+				t.Errorf("%s uses unknown key %q", path, key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if used == 0 {
+		t.Error("no catalog keys found in source")
+	}
+}
+
+func TestTranslator(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale Locale
+		got    func(tr Translator) string
+		want   string
+	}{
+		{name: "id message", locale: LocaleID, got: func(tr Translator) string { return tr.T("button.yes") }, want: "✅ Ya"},
+		{name: "en message", locale: LocaleEN, got: func(tr Translator) string { return tr.T("button.yes") }, want: "✅ Yes"},
+		{name: "unknown key", locale: LocaleEN, got: func(tr Translator) string { return tr.T("unknown.key") }, want: "unknown.key"},
+		{name: "en plural one", locale: LocaleEN, got: func(tr Translator) string { return tr.Plural("users.plan_valid_days", 1) }, want: " Valid for 1 day."},
+		{name: "en plural other", locale: LocaleEN, got: func(tr Translator) string { return tr.Plural("users.plan_valid_days", 3) }, want: " Valid for 3 days."},
+		{name: "id plural", locale: LocaleID, got: func(tr Translator) string { return tr.Plural("users.plan_valid_days", 1) }, want: " Berlaku 1 hari."},
+		{name: "unsupported locale", locale: Locale("fr"), got: func(tr Translator) string { return tr.T("button.yes") }, want: "✅ Ya"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got(New(tt.locale)); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name          string
+		preferred     string
+		languageCodes []string
+		want          Locale
+	}{
+		{name: "preferred wins", preferred: "en", languageCodes: []string{"id"}, want: LocaleEN},
+		{name: "telegram language", languageCodes: []string{"en-US"}, want: LocaleEN},
+		{name: "telegram indonesian", languageCodes: []string{"id_ID"}, want: LocaleID},
+		{name: "unsupported telegram language", languageCodes: []string{"de"}, want: LocaleEN},
+		{name: "skip empty language code", languageCodes: []string{"", "en"}, want: LocaleEN},
+		{name: "no information", want: DefaultLocale},
+		{name: "invalid preferred", preferred: "xx", want: DefaultLocale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Resolve(tt.preferred, tt.languageCodes...); got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	date := time.Date(2026, 10, 16, 9, 5, 0, 0, time.UTC)
+	id, en := New(LocaleID), New(LocaleEN)
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "id number", got: id.Number(1234567.891, 2), want: "1.234.567,89"},
+		{name: "en number", got: en.Number(1234567.891, 2), want: "1,234,567.89"},
+		{name: "negative number", got: en.Number(-1500, 0), want: "-1,500"},
+		{name: "negative zero", got: en.Number(-0.001, 1), want: "0.0"},
+		{name: "int truncates", got: id.Int(9875.9), want: "9.875"},
+		{name: "percent", got: id.Percent(65.5, 1), want: "65,5%"},
+		{name: "positive change", got: en.PercentChange(1.25), want: "+1.2%"},
+		{name: "negative change", got: id.PercentChange(-3.5), want: "-3,5%"},
+		{name: "id date", got: id.Date(date), want: "16 Oktober 2026"},
+		{name: "en date", got: en.Date(date), want: "16 October 2026"},
+		{name: "id short date", got: id.ShortDate(date), want: "16/10/2026"},
+		{name: "en short date", got: en.ShortDate(date), want: "Oct 16, 2026"},
+		{name: "date time", got: en.DateTime(date), want: "16 October 2026 - 09:05 WIB"},
+		{name: "weekday", got: id.Weekday(date.Weekday()), want: "Jumat"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Jenis digest yang bisa dilanggan user
+const (
+	DigestMorningBuyList = "morning_buylist"
+	DigestEndOfDay       = "end_of_day"
+	DigestWeeklyRecap    = "weekly_recap"
+)
+
+var DigestTypes = []string{DigestMorningBuyList, DigestEndOfDay, DigestWeeklyRecap}
+
+// DigestSubscriptionEntity menyimpan preferensi digest per user. DeliveryTime dalam
+// format HH:MM WIB.
+type DigestSubscriptionEntity struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	TelegramID   int64      `gorm:"not null" json:"telegram_id"`
+	DigestType   string     `gorm:"type:varchar(30);not null" json:"digest_type"`
+	DeliveryTime string     `gorm:"type:varchar(5);not null" json:"delivery_time"`
+	Enabled      bool       `gorm:"not null" json:"enabled"`
+	LastSentAt   *time.Time `json:"last_sent_at"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (DigestSubscriptionEntity) TableName() string {
+	return "digest_subscriptions"
+}
+
+type GetDigestSubscriptionParam struct {
+	TelegramID *int64
+	Enabled    *bool
+}
+
+// DigestPreference adalah preferensi digest user yang sudah digabung dengan nilai default.
+type DigestPreference struct {
+	DigestType   string     `json:"digest_type"`
+	Enabled      bool       `json:"enabled"`
+	DeliveryTime string     `json:"delivery_time"`
+	LastSentAt   *time.Time `json:"last_sent_at"`
+}
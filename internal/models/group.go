@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// TelegramGroupEntity menyimpan pengaturan bot di grup Telegram. AddedBy adalah admin grup
+// yang pertama mengaktifkan bot, kuota watchlist grup dihitung dari plan user ini.
+type TelegramGroupEntity struct {
+	ChatID       int64     `gorm:"primaryKey;autoIncrement:false" json:"chat_id"`
+	Title        string    `gorm:"type:varchar(255);not null" json:"title"`
+	AddedBy      int64     `gorm:"not null" json:"added_by"`
+	Language     string    `gorm:"type:varchar(10);not null" json:"language"`
+	SignalAlerts bool      `gorm:"not null" json:"signal_alerts"`
+	IsActive     bool      `gorm:"not null" json:"is_active"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (TelegramGroupEntity) TableName() string {
+	return "telegram_groups"
+}
+
+// GroupWatchlistEntity adalah saham yang dipantau bersama oleh sebuah grup.
+type GroupWatchlistEntity struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ChatID    int64     `gorm:"not null" json:"chat_id"`
+	StockCode string    `gorm:"type:varchar(20);not null" json:"stock_code"`
+	AddedBy   int64     `gorm:"not null" json:"added_by"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (GroupWatchlistEntity) TableName() string {
+	return "group_watchlists"
+}
+
+// GroupDigestTypes adalah digest yang bisa dikirim ke grup. Digest posisi bersifat pribadi
+// sehingga tidak pernah dikirim ke grup.
+var GroupDigestTypes = []string{DigestMorningBuyList}
+
+// IsGroupChat mengembalikan apakah chat ID milik grup. ID grup dan supergroup Telegram
+// selalu negatif, ID user selalu positif.
+func IsGroupChat(chatID int64) bool {
+	return chatID < 0
+}
@@ -6,6 +6,15 @@ import (
 	"gorm.io/datatypes"
 )
 
+// Tipe job yang bisa dijalankan scheduler in-process.
+const (
+	JobTypeStockAnalyzer   = "stock_analyzer"
+	JobTypePositionMonitor = "position_monitor"
+	JobTypeFetchPrices     = "fetch_prices"
+	JobTypeNewsSummary     = "news_summary"
+	JobTypeSendBuyList     = "send_buy_list"
+)
+
 type JobEntity struct {
 	ID          uint                         `gorm:"primaryKey"`
 	Name        string                       `gorm:"type:varchar(255);not null"`
@@ -14,10 +23,12 @@ type JobEntity struct {
 	Payload     datatypes.JSON               `gorm:"type:jsonb;not null"`
 	RetryPolicy datatypes.JSON               `gorm:"type:jsonb"`
 	Timeout     int                          `gorm:"default:60"`
+	FanOut      string                       `gorm:"type:varchar(50)"` // kosong atau JobFanOutStockList
 	CreatedAt   time.Time                    `gorm:"autoCreateTime"`
 	UpdatedAt   time.Time                    `gorm:"autoUpdateTime"`
 	Schedules   []TaskScheduleEntity         `gorm:"foreignKey:JobID"`
 	Histories   []TaskExecutionHistoryEntity `gorm:"foreignKey:JobID"`
+	DependsOn   []JobDependencyEntity        `gorm:"foreignKey:JobID"`
 }
 
 func (JobEntity) TableName() string {
@@ -32,5 +43,52 @@ type GetJobParam struct {
 }
 
 type GetTaskExecutionHistoryParam struct {
-	Limit *int `json:"limit"`
+	JobID  *uint                `json:"job_id"`
+	Status *TaskExecutionStatus `json:"status"`
+	Limit  *int                 `json:"limit"`
+	Offset *int                 `json:"offset"`
+}
+
+// StockAnalyzerJobPayload adalah payload job stock_analyzer. StockCodes kosong berarti
+// semua saham di STOCK_LIST.
+type StockAnalyzerJobPayload struct {
+	StockCodes []string `json:"stock_codes"`
+	Interval   string   `json:"interval"`
+	Range      string   `json:"range"`
+}
+
+// StockListJobPayload adalah payload job fetch_prices dan news_summary. StockCodes kosong
+// berarti semua saham di STOCK_LIST.
+type StockListJobPayload struct {
+	StockCodes []string `json:"stock_codes"`
+}
+
+// Kelas error yang bisa di-retry, dipakai di JobRetryPolicy.RetryOn.
+const (
+	RetryOnTimeout   = "timeout"    // attempt melewati Timeout job atau network timeout
+	RetryOnNetwork   = "network"    // koneksi gagal, putus atau DNS error
+	RetryOnRateLimit = "rate_limit" // HTTP 429 dari Yahoo Finance atau LLM
+	RetryOnServer    = "server"     // HTTP 5xx dari Yahoo Finance atau LLM
+	RetryOnAny       = "any"        // semua error kecuali job tanpa handler
+)
+
+// Strategi jeda antar attempt.
+const (
+	BackoffFixed       = "fixed"
+	BackoffLinear      = "linear"
+	BackoffExponential = "exponential"
+)
+
+// JobRetryPolicy adalah isi kolom jobs.retry_policy, contoh:
+//
+//	{"max_attempts": 3, "backoff": "exponential", "initial_interval": "30s", "max_interval": "5m", "retry_on": ["timeout", "rate_limit"]}
+//
+// Interval memakai format time.ParseDuration. RetryOn kosong berarti timeout, network,
+// rate_limit dan server.
+type JobRetryPolicy struct {
+	MaxAttempts     int      `json:"max_attempts"`
+	Backoff         string   `json:"backoff"`
+	InitialInterval string   `json:"initial_interval"`
+	MaxInterval     string   `json:"max_interval"`
+	RetryOn         []string `json:"retry_on"`
 }
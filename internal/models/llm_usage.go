@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+type LLMUsageEntity struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	Provider      string    `gorm:"type:varchar(50);not null" json:"provider"`
+	Model         string    `gorm:"type:varchar(100);not null" json:"model"`
+	PromptName    string    `gorm:"type:varchar(100)" json:"prompt_name"`
+	PromptVersion string    `gorm:"type:varchar(50)" json:"prompt_version"`
+	InputTokens   int       `gorm:"not null" json:"input_tokens"`
+	OutputTokens  int       `gorm:"not null" json:"output_tokens"`
+	LatencyMs     int64     `gorm:"not null" json:"latency_ms"`
+	FinishReason  string    `gorm:"type:varchar(50)" json:"finish_reason"`
+	Error         string    `gorm:"type:text" json:"error"`
+	TriggerSource string    `gorm:"type:varchar(50)" json:"trigger_source"`
+	TelegramID    *int64    `json:"telegram_id"`
+	JobID         *uint     `json:"job_id"`
+	Cost          float64   `gorm:"not null" json:"cost"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (LLMUsageEntity) TableName() string {
+	return "llm_usages"
+}
+
+// LLMUsageSummary adalah agregat pemakaian LLM per grup (model atau tanggal).
+type LLMUsageSummary struct {
+	Group        string  `json:"group"`
+	Requests     int64   `json:"requests"`
+	Errors       int64   `json:"errors"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	Cost         float64 `json:"cost"`
+}
+
+type LLMUsageReport struct {
+	GeneratedAt   time.Time         `json:"generated_at"`
+	TodayByModel  []LLMUsageSummary `json:"today_by_model"`
+	MonthByModel  []LLMUsageSummary `json:"month_by_model"`
+	MonthByDay    []LLMUsageSummary `json:"month_by_day"`
+	TodayCost     float64           `json:"today_cost"`
+	MonthCost     float64           `json:"month_cost"`
+	DailyBudget   float64           `json:"daily_budget"`
+	MonthlyBudget float64           `json:"monthly_budget"`
+}
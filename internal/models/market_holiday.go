@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type MarketHolidayEntity struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Date      time.Time `gorm:"type:date;uniqueIndex;not null" json:"date"`
+	Name      string    `gorm:"type:varchar(255);not null" json:"name"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (MarketHolidayEntity) TableName() string {
+	return "market_holidays"
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+type PriceFreshness string
+
+const (
+	PriceFreshnessLive    PriceFreshness = "LIVE"
+	PriceFreshnessDelayed PriceFreshness = "DELAYED"
+	PriceFreshnessStale   PriceFreshness = "STALE"
+)
+
+const (
+	PriceSourceRedis = "redis"
+	PriceSourceYahoo = "yahoo"
+)
+
+// MarketPrice adalah harga terakhir sebuah saham beserta sumber dan status kesegarannya.
+type MarketPrice struct {
+	StockCode string         `json:"stock_code"`
+	Price     float64        `json:"price"`
+	Time      time.Time      `json:"time"`
+	Source    string         `json:"source"`
+	Freshness PriceFreshness `json:"freshness"`
+}
@@ -132,11 +132,15 @@ type PositionMetrics struct {
 	VolumeSupport           string  `json:"volume_support"`
 }
 
+// IndividualAnalysisResponseMultiTimeframe juga menjadi sumber response schema Gemini.
+// Tag schema:"-" menandai field yang diisi oleh server, enum:"..." membatasi nilai field.
 type IndividualAnalysisResponseMultiTimeframe struct {
-	MarketPrice          float64           `json:"market_price"`
+	MarketPrice          float64           `json:"market_price" schema:"-"`
 	Symbol               string            `json:"symbol"`
-	AnalysisDate         time.Time         `json:"analysis_date"`
-	Action               string            `json:"action"`
+	AnalysisDate         time.Time         `json:"analysis_date" schema:"-"`
+	PromptVersion        string            `json:"prompt_version,omitempty" schema:"-"`
+	Cached               bool              `json:"cached,omitempty" schema:"-"`
+	Action               string            `json:"action" enum:"BUY,HOLD"`
 	BuyPrice             float64           `json:"buy_price,omitempty"`
 	TargetPrice          float64           `json:"target_price,omitempty"`
 	CutLoss              float64           `json:"cut_loss,omitempty"`
@@ -184,24 +188,26 @@ type TechnicalSummary struct {
 // News Summary
 type NewsSummary struct {
 	ConfidenceScore float64  `json:"confidence_score"`
-	Sentiment       string   `json:"sentiment"`
-	Impact          string   `json:"impact"`
+	Sentiment       string   `json:"sentiment" enum:"positive,negative,neutral,mixed"`
+	Impact          string   `json:"impact" enum:"bullish,bearish,sideways"`
 	KeyIssues       []string `json:"key_issues"`
 	Reasoning       string   `json:"reasoning"`
 }
 
+// PositionMonitoringResponseMultiTimeframe juga menjadi sumber response schema Gemini.
 type PositionMonitoringResponseMultiTimeframe struct {
-	MarketPrice          float64           `json:"market_price"`
+	MarketPrice          float64           `json:"market_price" schema:"-"`
 	Symbol               string            `json:"symbol"`
-	AnalysisDate         time.Time         `json:"analysis_date"`
-	Action               string            `json:"action"`
-	BuyPrice             float64           `json:"buy_price,omitempty"`
-	BuyDate              time.Time         `json:"buy_date,omitempty"`
-	MaxHoldingPeriodDays int               `json:"max_holding_period_days,omitempty"`
+	AnalysisDate         time.Time         `json:"analysis_date" schema:"-"`
+	PromptVersion        string            `json:"prompt_version,omitempty" schema:"-"`
+	Action               string            `json:"action" enum:"HOLD,TAKE_PROFIT,CUT_LOSS,TRAIL_STOP"`
+	BuyPrice             float64           `json:"buy_price,omitempty" schema:"-"`
+	BuyDate              time.Time         `json:"buy_date,omitempty" schema:"-"`
+	MaxHoldingPeriodDays int               `json:"max_holding_period_days,omitempty" schema:"-"`
 	TargetPrice          float64           `json:"target_price,omitempty"`
 	CutLoss              float64           `json:"cut_loss,omitempty"`
-	ExitTargetPrice      float64           `json:"exit_target_price,omitempty"`
-	ExitCutLossPrice     float64           `json:"exit_cut_loss_price,omitempty"`
+	ExitTargetPrice      float64           `json:"exit_target_price"`
+	ExitCutLossPrice     float64           `json:"exit_cut_loss_price"`
 	ConfidenceLevel      int               `json:"confidence_level"`
 	Reasoning            string            `json:"reasoning"`
 	RiskRewardRatio      float64           `json:"risk_reward_ratio"`
@@ -224,11 +230,25 @@ type GeminiAIRequest struct {
 }
 
 type GeminiContent struct {
+	Role  string       `json:"role,omitempty"`
 	Parts []GeminiPart `json:"parts"`
 }
 
 type GeminiGenerationConfig struct {
-	Temperature float64 `json:"temperature"`
+	Temperature      float64       `json:"temperature"`
+	ResponseMimeType string        `json:"responseMimeType,omitempty"`
+	ResponseSchema   *GeminiSchema `json:"responseSchema,omitempty"`
+}
+
+// GeminiSchema adalah subset OpenAPI schema yang didukung structured output Gemini
+type GeminiSchema struct {
+	Type             string                   `json:"type"`
+	Format           string                   `json:"format,omitempty"`
+	Enum             []string                 `json:"enum,omitempty"`
+	Items            *GeminiSchema            `json:"items,omitempty"`
+	Properties       map[string]*GeminiSchema `json:"properties,omitempty"`
+	Required         []string                 `json:"required,omitempty"`
+	PropertyOrdering []string                 `json:"propertyOrdering,omitempty"`
 }
 
 type GeminiPart struct {
@@ -237,7 +257,14 @@ type GeminiPart struct {
 
 // Gemini AI Response
 type GeminiAIResponse struct {
-	Candidates []GeminiCandidate `json:"candidates"`
+	Candidates    []GeminiCandidate   `json:"candidates"`
+	UsageMetadata GeminiUsageMetadata `json:"usageMetadata"`
+}
+
+type GeminiUsageMetadata struct {
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
+	TotalTokenCount      int `json:"totalTokenCount"`
 }
 
 type GeminiCandidate struct {
@@ -284,6 +311,21 @@ type SummaryStatistics struct {
 	WorstOpportunity string `json:"worst_opportunity"`
 }
 
+// AnalyzeStockRequest adalah body POST /api/v1/analyze
+type AnalyzeStockRequest struct {
+	Symbol   string `json:"symbol" binding:"required"`
+	Interval string `json:"interval"`
+	Range    string `json:"range"`
+}
+
+// MonitorPositionRequest adalah body (opsional) POST /api/v1/positions/:id/monitor
+type MonitorPositionRequest struct {
+	Interval string `json:"interval"`
+	Range    string `json:"range"`
+	// TelegramID diisi dari API key, hanya posisi milik user ini yang boleh dimonitor
+	TelegramID int64 `json:"-"`
+}
+
 type PositionMonitoringTelegramUserRequest struct {
 	TelegramID int64  `json:"telegram_id" binding:"required"`
 	Symbol     string `json:"symbol" binding:"required"`
@@ -308,7 +350,7 @@ type TimeframeAnalysis struct {
 }
 
 type TimeframeAnalysisData struct {
-	Trend      string  `json:"trend"`
+	Trend      string  `json:"trend" enum:"BULLISH,BEARISH,SIDEWAYS"`
 	KeySignal  string  `json:"key_signal"`
 	RSI        int     `json:"rsi"`
 	Support    float64 `json:"support"`
@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+// Jenis notifikasi otomatis yang bisa diatur user lewat /settings
+const (
+	NotificationTargetHit    = "target_hit"
+	NotificationSignalChange = "signal_change"
+	NotificationDigest       = "digest"
+)
+
+var NotificationTypes = []string{NotificationTargetHit, NotificationSignalChange, NotificationDigest}
+
+// Tingkat detail pesan notifikasi
+const (
+	VerbosityCompact  = "compact"
+	VerbosityDetailed = "detailed"
+)
+
+// NotificationPreferenceEntity menyimpan preferensi notifikasi per user. QuietStart dan
+// QuietEnd dalam format HH:MM WIB, kosong berarti jam tenang tidak aktif.
+type NotificationPreferenceEntity struct {
+	TelegramID    int64     `gorm:"primaryKey;autoIncrement:false" json:"telegram_id"`
+	TargetHit     bool      `gorm:"not null" json:"target_hit"`
+	SignalChange  bool      `gorm:"not null" json:"signal_change"`
+	Digest        bool      `gorm:"not null" json:"digest"`
+	QuietStart    string    `gorm:"type:varchar(5);not null" json:"quiet_start"`
+	QuietEnd      string    `gorm:"type:varchar(5);not null" json:"quiet_end"`
+	MinConfidence int       `gorm:"not null" json:"min_confidence"`
+	Verbosity     string    `gorm:"type:varchar(20);not null" json:"verbosity"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (NotificationPreferenceEntity) TableName() string {
+	return "notification_preferences"
+}
+
+// DefaultNotificationPreference adalah preferensi user yang belum pernah membuka /settings:
+// semua notifikasi aktif, tanpa jam tenang dan pesan lengkap.
+func DefaultNotificationPreference(telegramID int64) NotificationPreferenceEntity {
+	return NotificationPreferenceEntity{
+		TelegramID:   telegramID,
+		TargetHit:    true,
+		SignalChange: true,
+		Digest:       true,
+		Verbosity:    VerbosityDetailed,
+	}
+}
+
+// Wants mengembalikan apakah user ingin menerima notifikasi jenis notificationType.
+func (p NotificationPreferenceEntity) Wants(notificationType string) bool {
+	switch notificationType {
+	case NotificationTargetHit:
+		return p.TargetHit
+	case NotificationSignalChange:
+		return p.SignalChange
+	case NotificationDigest:
+		return p.Digest
+	default:
+		return true
+	}
+}
+
+func (p NotificationPreferenceEntity) HasQuietHours() bool {
+	return p.QuietStart != "" && p.QuietEnd != "" && p.QuietStart != p.QuietEnd
+}
+
+func (p NotificationPreferenceEntity) IsCompact() bool {
+	return p.Verbosity == VerbosityCompact
+}
+
+// Notification adalah notifikasi otomatis yang akan dikirim ke user. Confidence diisi
+// untuk notifikasi perubahan sinyal agar bisa difilter dengan minimum confidence user.
+type Notification struct {
+	Type       string
+	Confidence *int
+}
+
+// NotificationDecision adalah hasil evaluasi preferensi user terhadap sebuah notifikasi.
+// SendAfter diisi jika notifikasi jatuh di jam tenang dan harus ditunda.
+type NotificationDecision struct {
+	Send      bool
+	SendAfter time.Time
+	Compact   bool
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Sumber fan-out job. Job dengan FanOut dijalankan sekali per item dan dianggap selesai
+// (fan-in) setelah semua item berhasil.
+const (
+	JobFanOutStockList = "stock_list" // satu eksekusi per saham di STOCK_LIST
+)
+
+// JobDependencyEntity menyatakan JobID baru dijalankan setelah DependsOnJobID berhasil dalam
+// pipeline run yang sama.
+type JobDependencyEntity struct {
+	ID             uint      `gorm:"primaryKey"`
+	JobID          uint      `gorm:"not null"`
+	DependsOnJobID uint      `gorm:"not null"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+}
+
+func (JobDependencyEntity) TableName() string {
+	return "job_dependencies"
+}
+
+type PipelineRunStatus string
+
+const (
+	PipelineRunRunning   PipelineRunStatus = "running"
+	PipelineRunCompleted PipelineRunStatus = "completed"
+	PipelineRunFailed    PipelineRunStatus = "failed"
+)
+
+// PipelineRunEntity mengikat semua riwayat eksekusi yang dipicu satu eksekusi job root.
+type PipelineRunEntity struct {
+	ID           uint              `gorm:"primaryKey"`
+	RootJobID    uint              `gorm:"not null"`
+	Status       PipelineRunStatus `gorm:"type:varchar(50);not null"`
+	FailedJobID  *uint
+	ErrorMessage sql.NullString `gorm:"type:text"`
+	StartedAt    time.Time      `gorm:"not null"`
+	CompletedAt  sql.NullTime
+	CreatedAt    time.Time                    `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time                    `gorm:"autoUpdateTime"`
+	Histories    []TaskExecutionHistoryEntity `gorm:"foreignKey:PipelineRunID"`
+}
+
+func (PipelineRunEntity) TableName() string {
+	return "pipeline_runs"
+}
+
+type GetPipelineRunParam struct {
+	IDs       []uint             `json:"ids"`
+	RootJobID *uint              `json:"root_job_id"`
+	Status    *PipelineRunStatus `json:"status"`
+	Limit     *int               `json:"limit"`
+}
+
+// Status stage pada ringkasan pipeline run. Stage yang belum dijalankan karena stage
+// sebelumnya gagal berstatus skipped.
+const (
+	PipelineStagePending   = "pending"
+	PipelineStageRunning   = "running"
+	PipelineStageCompleted = "completed"
+	PipelineStageFailed    = "failed"
+	PipelineStageSkipped   = "skipped"
+)
+
+// PipelineRunSummary adalah status pipeline run per stage untuk /scheduler dan API.
+type PipelineRunSummary struct {
+	ID           uint              `json:"id"`
+	RootJobID    uint              `json:"root_job_id"`
+	Status       PipelineRunStatus `json:"status"`
+	FailedJobID  *uint             `json:"failed_job_id,omitempty"`
+	ErrorMessage string            `json:"error_message,omitempty"`
+	StartedAt    time.Time         `json:"started_at"`
+	CompletedAt  *time.Time        `json:"completed_at,omitempty"`
+	Stages       []PipelineStage   `json:"stages"`
+}
+
+type PipelineStage struct {
+	JobID     uint     `json:"job_id"`
+	JobName   string   `json:"job_name"`
+	DependsOn []uint   `json:"depends_on,omitempty"`
+	Status    string   `json:"status"`
+	Total     int      `json:"total"`
+	Completed int      `json:"completed"`
+	Failed    int      `json:"failed"`
+	Running   int      `json:"running"`
+	Errors    []string `json:"errors,omitempty"`
+}
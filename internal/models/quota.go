@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+type Plan string
+
+const (
+	PlanFree Plan = "free"
+	PlanPro  Plan = "pro"
+)
+
+func (p Plan) Valid() bool {
+	return p == PlanFree || p == PlanPro
+}
+
+// Jenis kuota per user
+const (
+	QuotaAnalysis       = "analysis"
+	QuotaActivePosition = "active_position"
+	QuotaAlert          = "alert"
+	QuotaWatchlist      = "watchlist"
+)
+
+// QuotaUsageEntity menghitung pemakaian kuota harian per user, tanggal dalam WIB.
+type QuotaUsageEntity struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	TelegramID int64     `gorm:"not null" json:"telegram_id"`
+	UsageDate  time.Time `gorm:"type:date;not null" json:"usage_date"`
+	Kind       string    `gorm:"type:varchar(30);not null" json:"kind"`
+	Count      int       `gorm:"not null" json:"count"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (QuotaUsageEntity) TableName() string {
+	return "quota_usages"
+}
+
+// QuotaItem adalah pemakaian satu jenis kuota. Limit 0 berarti tidak dibatasi,
+// ResetAt hanya diisi untuk kuota harian.
+type QuotaItem struct {
+	Kind    string     `json:"kind"`
+	Used    int        `json:"used"`
+	Limit   int        `json:"limit"`
+	ResetAt *time.Time `json:"reset_at,omitempty"`
+}
+
+func (q QuotaItem) Unlimited() bool {
+	return q.Limit == 0
+}
+
+func (q QuotaItem) Remaining() int {
+	if q.Unlimited() || q.Used >= q.Limit {
+		return 0
+	}
+	return q.Limit - q.Used
+}
+
+type QuotaStatus struct {
+	Plan          Plan        `json:"plan"`
+	PlanExpiresAt *time.Time  `json:"plan_expires_at,omitempty"`
+	Unlimited     bool        `json:"unlimited"`
+	Items         []QuotaItem `json:"items"`
+}
@@ -12,6 +12,10 @@ var (
 	RedisStreamSchedulerTaskExecution = "schedule.task.execution"
 	RedisStreamStockAnalyzer          = "stock.analyzer"
 	RedisStreamStockPositionMonitor   = "stock.position.monitor"
+
+	// stream hasil yang dipublish worker setelah request di atas selesai diproses
+	RedisStreamStockAnalyzerResult        = "stock.analyzer.result"
+	RedisStreamStockPositionMonitorResult = "stock.position.monitor.result"
 )
 
 type StockEntity struct {
@@ -168,6 +172,8 @@ type StockSignalEntity struct {
 	NewsScore       float64        `json:"news_score"`
 	Interval        string         `json:"interval"`
 	Range           string         `json:"range"`
+	PromptVersion   string         `json:"prompt_version"`
+	CacheKey        string         `json:"cache_key"`
 	Data            datatypes.JSON `gorm:"type:jsonb"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
@@ -182,6 +188,7 @@ type GetStockBuySignalParam struct {
 	Signal      string                `json:"signal"`
 	After       time.Time             `json:"after"`
 	StockCode   string                `json:"stock_code"`
+	StockCodes  []string              `json:"stock_codes"`
 	ReqAnalyzer *RequestStockAnalyzer `json:"request_analyzer"`
 }
 
@@ -191,6 +198,31 @@ type RequestStockAnalyzer struct {
 	Range      string `json:"range"`
 	TelegramID int64  `json:"telegram_id"`
 	NotifyUser bool   `json:"notify_user"`
+	// OnDemand diisi untuk permintaan langsung dari user dan memakai kuota analisa harian
+	OnDemand bool `json:"-"`
+}
+
+// StockAnalyzerResult adalah payload stream stock.analyzer.result. Signal sudah disimpan
+// worker di stock_signals, Error diisi jika analisa gagal.
+type StockAnalyzerResult struct {
+	StockCode  string `json:"stock_code"`
+	TelegramID int64  `json:"telegram_id"`
+	NotifyUser bool   `json:"notify_user"`
+	// Reused diisi saat signal yang masih segar dipakai ulang tanpa menjalankan analisa baru
+	Reused bool   `json:"reused,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// StockPositionMonitorResult adalah payload stream stock.position.monitor.result. Hasil
+// monitoring sudah disimpan worker di stock_position_monitorings.
+type StockPositionMonitorResult struct {
+	TelegramID      int64  `json:"telegram_id"`
+	StockCode       string `json:"stock_code"`
+	StockPositionID uint   `json:"stock_position_id"`
+	SendToTelegram  bool   `json:"send_to_telegram"`
+	// Scheduled disalin worker dari request, notifikasi terjadwal mengikuti preferensi /settings
+	Scheduled bool   `json:"scheduled,omitempty"`
+	Error     string `json:"error,omitempty"`
 }
 
 type StockPositionMonitoringEntity struct {
@@ -229,6 +261,10 @@ type RequestStockPositionMonitoring struct {
 	StockCode       string `json:"stock_code"`
 	StockPositionID uint   `json:"stock_position_id"`
 	SendToTelegram  bool   `json:"send_to_telegram"`
+	// Scheduled diisi untuk monitoring dari scheduler, bukan permintaan langsung user
+	Scheduled bool `json:"scheduled,omitempty"`
+	// OnDemand diisi untuk permintaan langsung dari user dan memakai kuota analisa harian
+	OnDemand bool `json:"-"`
 }
 
 type TopNewsCustomResult struct {
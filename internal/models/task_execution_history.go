@@ -12,19 +12,32 @@ const (
 	StatusCompleted TaskExecutionStatus = "completed"
 	StatusFailed    TaskExecutionStatus = "failed"
 	StatusTimeout   TaskExecutionStatus = "timeout"
+	StatusCancelled TaskExecutionStatus = "cancelled"
+)
+
+// Exit code eksekusi job, mengikuti konvensi shell.
+const (
+	ExitCodeSuccess   = 0
+	ExitCodeFailed    = 1
+	ExitCodeTimeout   = 124
+	ExitCodeNoHandler = 127
+	ExitCodeCancelled = 130
 )
 
 type TaskExecutionHistoryEntity struct {
-	ID           uint      `gorm:"primaryKey"`
-	JobID        uint      `gorm:"not null"`
-	ScheduleID   uint      `gorm:"not null"`
-	StartedAt    time.Time `gorm:"not null"`
-	CompletedAt  sql.NullTime
-	Status       TaskExecutionStatus `gorm:"type:varchar(50);not null"`
-	ExitCode     sql.NullInt32
-	Output       sql.NullString `gorm:"type:text"`
-	ErrorMessage sql.NullString `gorm:"type:text"`
-	CreatedAt    time.Time      `gorm:"autoCreateTime"`
+	ID            uint  `gorm:"primaryKey"`
+	JobID         uint  `gorm:"not null"`
+	ScheduleID    *uint // kosong untuk stage pipeline yang tidak punya schedule sendiri
+	Attempt       int   `gorm:"not null;default:1"` // dimulai dari 1, bertambah setiap retry
+	PipelineRunID *uint
+	Item          string    `gorm:"type:varchar(50)"` // item fan-out, misal kode saham
+	StartedAt     time.Time `gorm:"not null"`
+	CompletedAt   sql.NullTime
+	Status        TaskExecutionStatus `gorm:"type:varchar(50);not null"`
+	ExitCode      sql.NullInt32
+	Output        sql.NullString `gorm:"type:text"`
+	ErrorMessage  sql.NullString `gorm:"type:text"`
+	CreatedAt     time.Time      `gorm:"autoCreateTime"`
 }
 
 func (TaskExecutionHistoryEntity) TableName() string {
@@ -82,6 +82,12 @@ type RequestExitPositionData struct {
 	StockPositionID uint
 }
 
+type RequestSchedulerCronData struct {
+	JobID          uint
+	JobName        string
+	CronExpression string
+}
+
 type RequestAdjustTargetPositionData struct {
 	StockPositionID uint
 	TargetPrice     float64
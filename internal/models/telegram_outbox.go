@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSending = "sending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusFailed  = "failed"
+)
+
+// TelegramOutboxEntity adalah pesan telegram yang menunggu dikirim oleh worker outbox.
+type TelegramOutboxEntity struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	ChatID        int64      `gorm:"not null" json:"chat_id"`
+	Text          string     `gorm:"type:text;not null" json:"text"`
+	ParseMode     string     `gorm:"type:varchar(20)" json:"parse_mode"`
+	DedupKey      *string    `gorm:"type:varchar(255);uniqueIndex" json:"dedup_key"`
+	Status        string     `gorm:"type:varchar(20);not null" json:"status"`
+	Attempts      int        `gorm:"not null" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"not null" json:"next_attempt_at"`
+	LastError     string     `gorm:"type:text" json:"last_error"`
+	MessageID     *int       `json:"message_id"`
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (TelegramOutboxEntity) TableName() string {
+	return "telegram_outbox"
+}
+
+// TelegramOutboxMessage adalah pesan yang akan dimasukkan ke outbox. DedupKey opsional,
+// pesan dengan DedupKey yang sama hanya disimpan sekali. SendAfter opsional untuk menunda
+// pengiriman, misalnya sampai jam tenang user selesai.
+type TelegramOutboxMessage struct {
+	ChatID    int64
+	Text      string
+	ParseMode string
+	DedupKey  string
+	SendAfter time.Time
+}
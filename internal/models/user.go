@@ -4,19 +4,105 @@ import (
 	"time"
 )
 
+// Role menentukan perintah apa saja yang boleh dipakai user di bot.
+type Role string
+
+const (
+	RoleGuest  Role = "guest"
+	RoleMember Role = "member"
+	RoleAdmin  Role = "admin"
+)
+
+var roleLevels = map[Role]int{
+	RoleGuest:  0,
+	RoleMember: 1,
+	RoleAdmin:  2,
+}
+
+// Valid mengembalikan true jika role dikenal.
+func (r Role) Valid() bool {
+	_, ok := roleLevels[r]
+	return ok
+}
+
+// Allows mengembalikan true jika role ini setara atau lebih tinggi dari role yang dibutuhkan.
+func (r Role) Allows(required Role) bool {
+	level, ok := roleLevels[r]
+	if !ok {
+		return false
+	}
+	return level >= roleLevels[required]
+}
+
+// Promote mengembalikan role satu tingkat di atasnya, admin tetap admin.
+func (r Role) Promote() Role {
+	switch r {
+	case RoleGuest:
+		return RoleMember
+	default:
+		return RoleAdmin
+	}
+}
+
+// Demote mengembalikan role satu tingkat di bawahnya, guest tetap guest.
+func (r Role) Demote() Role {
+	switch r {
+	case RoleAdmin:
+		return RoleMember
+	default:
+		return RoleGuest
+	}
+}
+
 type UserEntity struct {
-	ID           uint      `gorm:"primaryKey" json:"id"`
-	TelegramID   int64     `gorm:"not null" json:"telegram_id"`
-	Username     string    `gorm:"not null" json:"username"`
-	FirstName    string    `gorm:"not null" json:"first_name"`
-	LastName     string    `json:"last_name"`
-	LanguageCode string    `json:"language_code"`
-	IsBot        bool      `gorm:"not null" json:"is_bot"`
-	LastActiveAt time.Time `gorm:"not null" json:"last_active_at"`
-	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	TelegramID   int64  `gorm:"not null" json:"telegram_id"`
+	Username     string `gorm:"not null" json:"username"`
+	FirstName    string `gorm:"not null" json:"first_name"`
+	LastName     string `json:"last_name"`
+	LanguageCode string `json:"language_code"`
+	// Language adalah bahasa pilihan user dari /language, kosong berarti mengikuti Telegram
+	Language      string     `gorm:"type:varchar(5);not null;default:''" json:"language"`
+	IsBot         bool       `gorm:"not null" json:"is_bot"`
+	Role          Role       `gorm:"type:varchar(20);not null;default:guest" json:"role"`
+	BannedAt      *time.Time `json:"banned_at"`
+	Plan          Plan       `gorm:"type:varchar(20);not null;default:free" json:"plan"`
+	PlanExpiresAt *time.Time `json:"plan_expires_at"`
+	LastActiveAt  time.Time  `gorm:"not null" json:"last_active_at"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 func (UserEntity) TableName() string {
 	return "users"
 }
+
+func (u *UserEntity) IsBanned() bool {
+	return u.BannedAt != nil
+}
+
+// ActivePlan mengembalikan plan yang berlaku saat now, plan pro yang sudah lewat masa
+// berlakunya kembali menjadi free.
+func (u *UserEntity) ActivePlan(now time.Time) Plan {
+	if u.Plan == PlanPro && (u.PlanExpiresAt == nil || now.Before(*u.PlanExpiresAt)) {
+		return PlanPro
+	}
+	return PlanFree
+}
+
+// InviteCodeEntity adalah kode undangan yang dipakai lewat /start <kode> untuk mendapatkan role.
+// MaxUses 0 berarti tidak dibatasi.
+type InviteCodeEntity struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	Code      string     `gorm:"type:varchar(32);not null;uniqueIndex" json:"code"`
+	Role      Role       `gorm:"type:varchar(20);not null" json:"role"`
+	MaxUses   int        `gorm:"not null" json:"max_uses"`
+	UsedCount int        `gorm:"not null" json:"used_count"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedBy int64      `gorm:"not null" json:"created_by"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (InviteCodeEntity) TableName() string {
+	return "invite_codes"
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DigestRepository interface {
+	GetSubscriptions(ctx context.Context, param models.GetDigestSubscriptionParam, opts ...utils.DBOption) ([]models.DigestSubscriptionEntity, error)
+	// UpsertSubscription membuat atau memperbarui enabled & delivery_time langganan user.
+	UpsertSubscription(ctx context.Context, subscription *models.DigestSubscriptionEntity, opts ...utils.DBOption) error
+	MarkSent(ctx context.Context, id uint, sentAt time.Time, opts ...utils.DBOption) error
+}
+
+type digestRepository struct {
+	db *gorm.DB
+}
+
+func NewDigestRepository(db *gorm.DB) DigestRepository {
+	return &digestRepository{
+		db: db,
+	}
+}
+
+func (r *digestRepository) GetSubscriptions(ctx context.Context, param models.GetDigestSubscriptionParam, opts ...utils.DBOption) ([]models.DigestSubscriptionEntity, error) {
+	var subscriptions []models.DigestSubscriptionEntity
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+
+	if param.TelegramID != nil {
+		tx = tx.Where("telegram_id = ?", *param.TelegramID)
+	}
+	if param.Enabled != nil {
+		tx = tx.Where("enabled = ?", *param.Enabled)
+	}
+
+	if err := tx.Order("id ASC").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (r *digestRepository) UpsertSubscription(ctx context.Context, subscription *models.DigestSubscriptionEntity, opts ...utils.DBOption) error {
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "telegram_id"}, {Name: "digest_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "delivery_time", "updated_at"}),
+	}).Create(subscription).Error
+}
+
+func (r *digestRepository) MarkSent(ctx context.Context, id uint, sentAt time.Time, opts ...utils.DBOption) error {
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	return tx.Model(&models.DigestSubscriptionEntity{}).Where("id = ?", id).Update("last_sent_at", sentAt).Error
+}
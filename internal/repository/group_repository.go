@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GroupRepository interface {
+	// GetGroup mengembalikan nil jika bot belum pernah diaktifkan di grup.
+	GetGroup(ctx context.Context, chatID int64, opts ...utils.DBOption) (*models.TelegramGroupEntity, error)
+	// UpsertGroup membuat grup baru atau mengaktifkan kembali grup lama dengan judul terbaru,
+	// pengaturan grup lama tetap dipertahankan.
+	UpsertGroup(ctx context.Context, group *models.TelegramGroupEntity, opts ...utils.DBOption) error
+	UpdateGroup(ctx context.Context, chatID int64, updates map[string]any, opts ...utils.DBOption) error
+	// GetGroupsWatching mengembalikan grup aktif dengan alert sinyal yang memantau stockCode.
+	GetGroupsWatching(ctx context.Context, stockCode string, opts ...utils.DBOption) ([]models.TelegramGroupEntity, error)
+
+	GetWatchlist(ctx context.Context, chatID int64, opts ...utils.DBOption) ([]models.GroupWatchlistEntity, error)
+	// AddWatchlist mengembalikan false jika saham sudah ada di watchlist grup.
+	AddWatchlist(ctx context.Context, item *models.GroupWatchlistEntity, opts ...utils.DBOption) (bool, error)
+	// DeleteWatchlist mengembalikan false jika saham tidak ada di watchlist grup.
+	DeleteWatchlist(ctx context.Context, chatID int64, stockCode string, opts ...utils.DBOption) (bool, error)
+	// CountWatchlistByOwner menghitung saham di watchlist semua grup aktif milik telegramID.
+	CountWatchlistByOwner(ctx context.Context, telegramID int64, opts ...utils.DBOption) (int, error)
+}
+
+type groupRepository struct {
+	db *gorm.DB
+}
+
+func NewGroupRepository(db *gorm.DB) GroupRepository {
+	return &groupRepository{
+		db: db,
+	}
+}
+
+func (r *groupRepository) GetGroup(ctx context.Context, chatID int64, opts ...utils.DBOption) (*models.TelegramGroupEntity, error) {
+	var group models.TelegramGroupEntity
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+
+	result := tx.Where("chat_id = ?", chatID).First(&group)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &group, nil
+}
+
+func (r *groupRepository) UpsertGroup(ctx context.Context, group *models.TelegramGroupEntity, opts ...utils.DBOption) error {
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chat_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "is_active", "updated_at"}),
+	}).Create(group).Error
+}
+
+func (r *groupRepository) UpdateGroup(ctx context.Context, chatID int64, updates map[string]any, opts ...utils.DBOption) error {
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	return tx.Model(&models.TelegramGroupEntity{}).Where("chat_id = ?", chatID).Updates(updates).Error
+}
+
+func (r *groupRepository) GetGroupsWatching(ctx context.Context, stockCode string, opts ...utils.DBOption) ([]models.TelegramGroupEntity, error) {
+	var groups []models.TelegramGroupEntity
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+
+	err := tx.Joins("JOIN group_watchlists gw ON gw.chat_id = telegram_groups.chat_id").
+		Where("gw.stock_code = ? AND telegram_groups.is_active AND telegram_groups.signal_alerts", stockCode).
+		Find(&groups).Error
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (r *groupRepository) GetWatchlist(ctx context.Context, chatID int64, opts ...utils.DBOption) ([]models.GroupWatchlistEntity, error) {
+	var watchlist []models.GroupWatchlistEntity
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+
+	if err := tx.Where("chat_id = ?", chatID).Order("stock_code ASC").Find(&watchlist).Error; err != nil {
+		return nil, err
+	}
+	return watchlist, nil
+}
+
+func (r *groupRepository) AddWatchlist(ctx context.Context, item *models.GroupWatchlistEntity, opts ...utils.DBOption) (bool, error) {
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	result := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chat_id"}, {Name: "stock_code"}},
+		DoNothing: true,
+	}).Create(item)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *groupRepository) DeleteWatchlist(ctx context.Context, chatID int64, stockCode string, opts ...utils.DBOption) (bool, error) {
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	result := tx.Where("chat_id = ? AND stock_code = ?", chatID, stockCode).Delete(&models.GroupWatchlistEntity{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *groupRepository) CountWatchlistByOwner(ctx context.Context, telegramID int64, opts ...utils.DBOption) (int, error) {
+	var total int64
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+
+	err := tx.Model(&models.GroupWatchlistEntity{}).
+		Joins("JOIN telegram_groups tg ON tg.chat_id = group_watchlists.chat_id").
+		Where("tg.added_by = ? AND tg.is_active", telegramID).
+		Count(&total).Error
+	if err != nil {
+		return 0, err
+	}
+	return int(total), nil
+}
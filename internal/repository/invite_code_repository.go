@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InviteCodeRepository interface {
+	CreateInviteCode(ctx context.Context, invite *models.InviteCodeEntity, opts ...utils.DBOption) error
+	// RedeemInviteCode menambah used_count secara atomik jika kode masih berlaku.
+	// Mengembalikan nil jika kode tidak ada, kedaluwarsa, atau kuotanya habis.
+	RedeemInviteCode(ctx context.Context, code string, now time.Time, opts ...utils.DBOption) (*models.InviteCodeEntity, error)
+}
+
+type inviteCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewInviteCodeRepository(db *gorm.DB) InviteCodeRepository {
+	return &inviteCodeRepository{
+		db: db,
+	}
+}
+
+func (r *inviteCodeRepository) CreateInviteCode(ctx context.Context, invite *models.InviteCodeEntity, opts ...utils.DBOption) error {
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	return tx.Create(invite).Error
+}
+
+func (r *inviteCodeRepository) RedeemInviteCode(ctx context.Context, code string, now time.Time, opts ...utils.DBOption) (*models.InviteCodeEntity, error) {
+	var invites []models.InviteCodeEntity
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+
+	result := tx.Model(&invites).
+		Clauses(clause.Returning{}).
+		Where("code = ?", code).
+		Where("max_uses = 0 OR used_count < max_uses").
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Update("used_count", gorm.Expr("used_count + 1"))
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(invites) == 0 {
+		return nil, nil
+	}
+
+	return &invites[0], nil
+}
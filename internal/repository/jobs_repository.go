@@ -2,15 +2,39 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"golang-swing-trading-signal/internal/models"
 	"golang-swing-trading-signal/internal/utils"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type JobsRepository interface {
 	Get(ctx context.Context, param *models.GetJobParam, opts ...utils.DBOption) ([]models.JobEntity, error)
 	RunJobTask(ctx context.Context, jobID uint, opts ...utils.DBOption) error
+	GetByIDs(ctx context.Context, ids []uint, opts ...utils.DBOption) ([]models.JobEntity, error)
+	// ClaimDueSchedules mengunci schedule aktif yang next_execution <= now dengan
+	// FOR UPDATE SKIP LOCKED. Harus dipanggil di dalam transaksi (utils.WithTx) agar lock
+	// bertahan sampai next_execution diperbarui.
+	ClaimDueSchedules(ctx context.Context, now time.Time, limit int, opts ...utils.DBOption) ([]models.TaskScheduleEntity, error)
+	// GetUnscheduled mengembalikan schedule aktif yang belum punya next_execution.
+	GetUnscheduled(ctx context.Context, opts ...utils.DBOption) ([]models.TaskScheduleEntity, error)
+	UpdateSchedule(ctx context.Context, schedule *models.TaskScheduleEntity, opts ...utils.DBOption) error
+	CreateHistory(ctx context.Context, history *models.TaskExecutionHistoryEntity, opts ...utils.DBOption) error
+	// FinishHistory menyimpan hasil akhir eksekusi hanya jika statusnya masih running. Mengembalikan
+	// false jika eksekusi sudah dibatalkan admin lebih dulu, sehingga pembatalan tidak tertimpa.
+	FinishHistory(ctx context.Context, history *models.TaskExecutionHistoryEntity, opts ...utils.DBOption) (bool, error)
+	// UpdateScheduleByJobID memperbarui kolom schedule milik job, misal is_active atau
+	// cron_expression beserta next_execution.
+	UpdateScheduleByJobID(ctx context.Context, jobID uint, updates map[string]interface{}, opts ...utils.DBOption) error
+	GetHistories(ctx context.Context, param *models.GetTaskExecutionHistoryParam, opts ...utils.DBOption) ([]models.TaskExecutionHistoryEntity, int64, error)
+	GetHistoryByID(ctx context.Context, id uint, opts ...utils.DBOption) (*models.TaskExecutionHistoryEntity, error)
+	GetHistoriesByIDs(ctx context.Context, ids []uint, opts ...utils.DBOption) ([]models.TaskExecutionHistoryEntity, error)
+	// CancelHistory menandai eksekusi yang masih running sebagai cancelled. Mengembalikan
+	// false jika eksekusi sudah selesai.
+	CancelHistory(ctx context.Context, id uint, now time.Time, opts ...utils.DBOption) (bool, error)
 }
 
 type jobsRepository struct {
@@ -58,3 +82,129 @@ func (r *jobsRepository) RunJobTask(ctx context.Context, jobID uint, opts ...uti
 	db = db.Model(&models.TaskScheduleEntity{}).Where("job_id = ?", jobID).Update("next_execution", utils.TimeNowWIB())
 	return db.Error
 }
+
+func (r *jobsRepository) GetByIDs(ctx context.Context, ids []uint, opts ...utils.DBOption) ([]models.JobEntity, error) {
+	var jobs []models.JobEntity
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	if err := db.Where("id IN ?", ids).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *jobsRepository) ClaimDueSchedules(ctx context.Context, now time.Time, limit int, opts ...utils.DBOption) ([]models.TaskScheduleEntity, error) {
+	var schedules []models.TaskScheduleEntity
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	err := db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("is_active = ? AND next_execution <= ?", true, now).
+		Order("next_execution").
+		Limit(limit).
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (r *jobsRepository) GetUnscheduled(ctx context.Context, opts ...utils.DBOption) ([]models.TaskScheduleEntity, error) {
+	var schedules []models.TaskScheduleEntity
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	if err := db.Where("is_active = ? AND next_execution IS NULL", true).Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (r *jobsRepository) UpdateSchedule(ctx context.Context, schedule *models.TaskScheduleEntity, opts ...utils.DBOption) error {
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	return db.Save(schedule).Error
+}
+
+func (r *jobsRepository) CreateHistory(ctx context.Context, history *models.TaskExecutionHistoryEntity, opts ...utils.DBOption) error {
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	return db.Create(history).Error
+}
+
+func (r *jobsRepository) FinishHistory(ctx context.Context, history *models.TaskExecutionHistoryEntity, opts ...utils.DBOption) (bool, error) {
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	result := db.Model(&models.TaskExecutionHistoryEntity{}).
+		Where("id = ? AND status = ?", history.ID, models.StatusRunning).
+		Updates(map[string]interface{}{
+			"status":        history.Status,
+			"exit_code":     history.ExitCode,
+			"completed_at":  history.CompletedAt,
+			"output":        history.Output,
+			"error_message": history.ErrorMessage,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *jobsRepository) UpdateScheduleByJobID(ctx context.Context, jobID uint, updates map[string]interface{}, opts ...utils.DBOption) error {
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	return db.Model(&models.TaskScheduleEntity{}).Where("job_id = ?", jobID).Updates(updates).Error
+}
+
+func (r *jobsRepository) GetHistories(ctx context.Context, param *models.GetTaskExecutionHistoryParam, opts ...utils.DBOption) ([]models.TaskExecutionHistoryEntity, int64, error) {
+	var histories []models.TaskExecutionHistoryEntity
+	var total int64
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	db = db.Model(&models.TaskExecutionHistoryEntity{})
+	if param.JobID != nil {
+		db = db.Where("job_id = ?", *param.JobID)
+	}
+	if param.Status != nil {
+		db = db.Where("status = ?", *param.Status)
+	}
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if param.Limit != nil {
+		db = db.Limit(*param.Limit)
+	}
+	if param.Offset != nil {
+		db = db.Offset(*param.Offset)
+	}
+	if err := db.Order("created_at DESC, id DESC").Find(&histories).Error; err != nil {
+		return nil, 0, err
+	}
+	return histories, total, nil
+}
+
+func (r *jobsRepository) GetHistoryByID(ctx context.Context, id uint, opts ...utils.DBOption) (*models.TaskExecutionHistoryEntity, error) {
+	var history models.TaskExecutionHistoryEntity
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	if err := db.First(&history, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &history, nil
+}
+
+func (r *jobsRepository) GetHistoriesByIDs(ctx context.Context, ids []uint, opts ...utils.DBOption) ([]models.TaskExecutionHistoryEntity, error) {
+	var histories []models.TaskExecutionHistoryEntity
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	if err := db.Where("id IN ?", ids).Find(&histories).Error; err != nil {
+		return nil, err
+	}
+	return histories, nil
+}
+
+func (r *jobsRepository) CancelHistory(ctx context.Context, id uint, now time.Time, opts ...utils.DBOption) (bool, error) {
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	result := db.Model(&models.TaskExecutionHistoryEntity{}).
+		Where("id = ? AND status = ?", id, models.StatusRunning).
+		Updates(map[string]interface{}{
+			"status":       models.StatusCancelled,
+			"exit_code":    models.ExitCodeCancelled,
+			"completed_at": now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+type LLMUsageRepository interface {
+	Create(ctx context.Context, usage *models.LLMUsageEntity, opts ...utils.DBOption) error
+	// SummarizeByModel mengagregasi pemakaian pada rentang [from, to) per model.
+	SummarizeByModel(ctx context.Context, from, to time.Time) ([]models.LLMUsageSummary, error)
+	// SummarizeByDay mengagregasi pemakaian pada rentang [from, to) per tanggal (WIB).
+	SummarizeByDay(ctx context.Context, from, to time.Time) ([]models.LLMUsageSummary, error)
+	TotalCost(ctx context.Context, from, to time.Time) (float64, error)
+}
+
+type llmUsageRepository struct {
+	db *gorm.DB
+}
+
+func NewLLMUsageRepository(db *gorm.DB) LLMUsageRepository {
+	return &llmUsageRepository{db: db}
+}
+
+const llmUsageSummarySelect = `COUNT(*) AS requests,
+	COUNT(*) FILTER (WHERE COALESCE(error, '') <> '') AS errors,
+	COALESCE(SUM(input_tokens), 0) AS input_tokens,
+	COALESCE(SUM(output_tokens), 0) AS output_tokens,
+	COALESCE(AVG(latency_ms), 0) AS avg_latency_ms,
+	COALESCE(SUM(cost), 0) AS cost`
+
+func (r *llmUsageRepository) Create(ctx context.Context, usage *models.LLMUsageEntity, opts ...utils.DBOption) error {
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	return db.Create(usage).Error
+}
+
+func (r *llmUsageRepository) SummarizeByModel(ctx context.Context, from, to time.Time) ([]models.LLMUsageSummary, error) {
+	var summaries []models.LLMUsageSummary
+	err := r.db.WithContext(ctx).Model(&models.LLMUsageEntity{}).
+		Select("model AS \"group\", "+llmUsageSummarySelect).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Group("model").
+		Order("cost DESC").
+		Scan(&summaries).Error
+	if err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+func (r *llmUsageRepository) SummarizeByDay(ctx context.Context, from, to time.Time) ([]models.LLMUsageSummary, error) {
+	var summaries []models.LLMUsageSummary
+	err := r.db.WithContext(ctx).Model(&models.LLMUsageEntity{}).
+		Select("TO_CHAR(created_at AT TIME ZONE 'Asia/Jakarta', 'YYYY-MM-DD') AS \"group\", "+llmUsageSummarySelect).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Group("1").
+		Order("1 ASC").
+		Scan(&summaries).Error
+	if err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+func (r *llmUsageRepository) TotalCost(ctx context.Context, from, to time.Time) (float64, error) {
+	var total float64
+	err := r.db.WithContext(ctx).Model(&models.LLMUsageEntity{}).
+		Select("COALESCE(SUM(cost), 0)").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Scan(&total).Error
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"golang-swing-trading-signal/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type MarketHolidayRepository interface {
+	GetAll(ctx context.Context) ([]models.MarketHolidayEntity, error)
+}
+
+type marketHolidayRepository struct {
+	db *gorm.DB
+}
+
+func NewMarketHolidayRepository(db *gorm.DB) MarketHolidayRepository {
+	return &marketHolidayRepository{db: db}
+}
+
+func (r *marketHolidayRepository) GetAll(ctx context.Context) ([]models.MarketHolidayEntity, error) {
+	var holidays []models.MarketHolidayEntity
+	if err := r.db.WithContext(ctx).Order("date ASC").Find(&holidays).Error; err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationPreferenceRepository interface {
+	// GetPreference mengembalikan nil jika user belum pernah menyimpan preferensi.
+	GetPreference(ctx context.Context, telegramID int64, opts ...utils.DBOption) (*models.NotificationPreferenceEntity, error)
+	UpsertPreference(ctx context.Context, preference *models.NotificationPreferenceEntity, opts ...utils.DBOption) error
+}
+
+type notificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationPreferenceRepository(db *gorm.DB) NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{
+		db: db,
+	}
+}
+
+func (r *notificationPreferenceRepository) GetPreference(ctx context.Context, telegramID int64, opts ...utils.DBOption) (*models.NotificationPreferenceEntity, error) {
+	var preference models.NotificationPreferenceEntity
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+
+	result := tx.Where("telegram_id = ?", telegramID).First(&preference)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &preference, nil
+}
+
+func (r *notificationPreferenceRepository) UpsertPreference(ctx context.Context, preference *models.NotificationPreferenceEntity, opts ...utils.DBOption) error {
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "telegram_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"target_hit", "signal_change", "digest",
+			"quiet_start", "quiet_end", "min_confidence", "verbosity", "updated_at",
+		}),
+	}).Create(preference).Error
+}
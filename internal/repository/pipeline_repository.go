@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+type PipelineRepository interface {
+	GetDependencies(ctx context.Context, opts ...utils.DBOption) ([]models.JobDependencyEntity, error)
+	CreateRun(ctx context.Context, run *models.PipelineRunEntity, opts ...utils.DBOption) error
+	UpdateRun(ctx context.Context, run *models.PipelineRunEntity, opts ...utils.DBOption) error
+	// GetRuns mengembalikan pipeline run terbaru beserta seluruh riwayat eksekusinya.
+	GetRuns(ctx context.Context, param *models.GetPipelineRunParam, opts ...utils.DBOption) ([]models.PipelineRunEntity, error)
+}
+
+type pipelineRepository struct {
+	db *gorm.DB
+}
+
+func NewPipelineRepository(db *gorm.DB) PipelineRepository {
+	return &pipelineRepository{db: db}
+}
+
+func (r *pipelineRepository) GetDependencies(ctx context.Context, opts ...utils.DBOption) ([]models.JobDependencyEntity, error) {
+	var dependencies []models.JobDependencyEntity
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	if err := db.Order("id").Find(&dependencies).Error; err != nil {
+		return nil, err
+	}
+	return dependencies, nil
+}
+
+func (r *pipelineRepository) CreateRun(ctx context.Context, run *models.PipelineRunEntity, opts ...utils.DBOption) error {
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	return db.Omit("Histories").Create(run).Error
+}
+
+func (r *pipelineRepository) UpdateRun(ctx context.Context, run *models.PipelineRunEntity, opts ...utils.DBOption) error {
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	return db.Omit("Histories").Save(run).Error
+}
+
+func (r *pipelineRepository) GetRuns(ctx context.Context, param *models.GetPipelineRunParam, opts ...utils.DBOption) ([]models.PipelineRunEntity, error) {
+	var runs []models.PipelineRunEntity
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	if len(param.IDs) > 0 {
+		db = db.Where("id IN ?", param.IDs)
+	}
+	if param.RootJobID != nil {
+		db = db.Where("root_job_id = ?", *param.RootJobID)
+	}
+	if param.Status != nil {
+		db = db.Where("status = ?", *param.Status)
+	}
+	if param.Limit != nil {
+		db = db.Limit(*param.Limit)
+	}
+	err := db.Preload("Histories", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Order("started_at DESC, id DESC").Find(&runs).Error
+	if err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
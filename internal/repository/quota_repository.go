@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type QuotaRepository interface {
+	// IncrementUsage menambah pemakaian secara atomik selama belum mencapai limit (0 berarti
+	// tidak dibatasi). Mengembalikan false jika kuota sudah habis.
+	IncrementUsage(ctx context.Context, telegramID int64, date time.Time, kind string, limit int, opts ...utils.DBOption) (bool, error)
+	DecrementUsage(ctx context.Context, telegramID int64, date time.Time, kind string, opts ...utils.DBOption) error
+	GetUsage(ctx context.Context, telegramID int64, date time.Time, kind string, opts ...utils.DBOption) (int, error)
+}
+
+type quotaRepository struct {
+	db *gorm.DB
+}
+
+func NewQuotaRepository(db *gorm.DB) QuotaRepository {
+	return &quotaRepository{
+		db: db,
+	}
+}
+
+func (r *quotaRepository) IncrementUsage(ctx context.Context, telegramID int64, date time.Time, kind string, limit int, opts ...utils.DBOption) (bool, error) {
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+
+	// baris tidak dikembalikan jika kondisi WHERE pada DO UPDATE tidak terpenuhi
+	var counts []int
+	err := tx.Raw(`
+		INSERT INTO quota_usages (telegram_id, usage_date, kind, count, created_at, updated_at)
+		VALUES (?, ?, ?, 1, NOW(), NOW())
+		ON CONFLICT (telegram_id, usage_date, kind) DO UPDATE
+		SET count = quota_usages.count + 1, updated_at = NOW()
+		WHERE ? = 0 OR quota_usages.count < ?
+		RETURNING count`,
+		telegramID, date.Format(time.DateOnly), kind, limit, limit,
+	).Scan(&counts).Error
+	if err != nil {
+		return false, err
+	}
+
+	return len(counts) > 0, nil
+}
+
+func (r *quotaRepository) DecrementUsage(ctx context.Context, telegramID int64, date time.Time, kind string, opts ...utils.DBOption) error {
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	return tx.Model(&models.QuotaUsageEntity{}).
+		Where("telegram_id = ? AND usage_date = ? AND kind = ? AND count > 0", telegramID, date.Format(time.DateOnly), kind).
+		Updates(map[string]any{
+			"count":      gorm.Expr("count - 1"),
+			"updated_at": gorm.Expr("NOW()"),
+		}).Error
+}
+
+func (r *quotaRepository) GetUsage(ctx context.Context, telegramID int64, date time.Time, kind string, opts ...utils.DBOption) (int, error) {
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+
+	var usage models.QuotaUsageEntity
+	result := tx.Where("telegram_id = ? AND usage_date = ? AND kind = ?", telegramID, date.Format(time.DateOnly), kind).Limit(1).Find(&usage)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return usage.Count, nil
+}
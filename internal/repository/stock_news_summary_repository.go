@@ -10,6 +10,8 @@ import (
 
 type StockNewsSummaryRepository interface {
 	GetLast(ctx context.Context, before time.Time, stockCode string) (*models.StockNewsSummaryEntity, error)
+	// GetLastByStockCodes mengambil ringkasan terbaru untuk setiap kode saham dalam satu query.
+	GetLastByStockCodes(ctx context.Context, before time.Time, stockCodes []string) ([]models.StockNewsSummaryEntity, error)
 }
 
 type stockNewsSummaryRepository struct {
@@ -34,3 +36,18 @@ func (r *stockNewsSummaryRepository) GetLast(ctx context.Context, before time.Ti
 	}
 	return &summary, nil
 }
+
+func (r *stockNewsSummaryRepository) GetLastByStockCodes(ctx context.Context, before time.Time, stockCodes []string) ([]models.StockNewsSummaryEntity, error) {
+	var summaries []models.StockNewsSummaryEntity
+	if len(stockCodes) == 0 {
+		return summaries, nil
+	}
+
+	query := `SELECT DISTINCT ON (stock_code) * FROM stock_news_summary
+		WHERE created_at >= ? AND stock_code IN ?
+		ORDER BY stock_code, created_at DESC`
+	if err := r.db.WithContext(ctx).Raw(query, before, stockCodes).Scan(&summaries).Error; err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
@@ -12,6 +12,7 @@ import (
 type StockPositionMonitoringRepository interface {
 	GetLatestMonitoring(ctx context.Context, param models.GetStockPositionMonitoringParam) ([]models.StockPositionMonitoringEntity, error)
 	GetRecentDistinctMonitorings(ctx context.Context, param models.StockPositionMonitoringQueryParam, opts ...utils.DBOption) ([]models.StockPositionMonitoringEntity, error)
+	Create(ctx context.Context, monitoring *models.StockPositionMonitoringEntity, opts ...utils.DBOption) error
 }
 
 type stockPositionMonitoringRepository struct {
@@ -108,3 +109,8 @@ func (r *stockPositionMonitoringRepository) GetRecentDistinctMonitorings(ctx con
 	err := utils.ApplyOptions(r.db.WithContext(ctx), opts...).Raw(query, param.StockPositionID, param.Limit).Scan(&results).Error
 	return results, err
 }
+
+func (r *stockPositionMonitoringRepository) Create(ctx context.Context, monitoring *models.StockPositionMonitoringEntity, opts ...utils.DBOption) error {
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	return tx.Omit("StockPosition").Create(monitoring).Error
+}
@@ -3,13 +3,17 @@ package repository
 import (
 	"context"
 	"golang-swing-trading-signal/internal/models"
-	"strings"
+	"golang-swing-trading-signal/internal/utils"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type StocksRepository interface {
 	GetStocks(ctx context.Context, param models.GetStocksParam) ([]models.StockEntity, error)
+	// SearchStocks mencari saham dengan awalan kode atau awalan kata pada nama, kode yang
+	// sama persis ditampilkan paling atas.
+	SearchStocks(ctx context.Context, query string, limit int) ([]models.StockEntity, error)
 }
 
 type stocksRepository struct {
@@ -22,20 +26,28 @@ func NewStocksRepository(db *gorm.DB) StocksRepository {
 
 func (s *stocksRepository) GetStocks(ctx context.Context, param models.GetStocksParam) ([]models.StockEntity, error) {
 	var stocks []models.StockEntity
-	baseQuery := "SELECT * FROM stocks"
-	conditions := []string{}
-	params := []interface{}{}
+	query := s.db.WithContext(ctx)
 
 	if len(param.StockCodes) > 0 {
-		conditions = append(conditions, "stock_code IN ?")
-		params = append(params, param.StockCodes)
+		query = query.Where("code IN ?", param.StockCodes)
 	}
 
-	if len(conditions) > 0 {
-		baseQuery += " WHERE " + strings.Join(conditions, " AND ")
+	if err := query.Order("code").Find(&stocks).Error; err != nil {
+		return nil, err
 	}
+	return stocks, nil
+}
 
-	if err := s.db.WithContext(ctx).Raw(baseQuery, params...).Scan(&stocks).Error; err != nil {
+func (s *stocksRepository) SearchStocks(ctx context.Context, query string, limit int) ([]models.StockEntity, error) {
+	var stocks []models.StockEntity
+	prefix := utils.EscapeLike(query) + "%"
+
+	err := s.db.WithContext(ctx).
+		Where("code ILIKE ? OR name ILIKE ? OR name ILIKE ?", prefix, prefix, "% "+prefix).
+		Order(clause.Expr{SQL: "CASE WHEN code = UPPER(?) THEN 0 WHEN code ILIKE ? THEN 1 ELSE 2 END, code", Vars: []interface{}{query, prefix}}).
+		Limit(limit).
+		Find(&stocks).Error
+	if err != nil {
 		return nil, err
 	}
 	return stocks, nil
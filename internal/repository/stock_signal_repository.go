@@ -3,13 +3,22 @@ package repository
 import (
 	"context"
 	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type StockSignalRepository interface {
 	GetLatestSignal(ctx context.Context, param models.GetStockBuySignalParam) ([]models.StockSignalEntity, error)
+	Create(ctx context.Context, signal *models.StockSignalEntity, opts ...utils.DBOption) error
+	// GetByCacheKey mengembalikan signal terbaru dengan cache key yang sama yang dibuat setelah
+	// after, atau nil jika tidak ada.
+	GetByCacheKey(ctx context.Context, cacheKey string, after time.Time) (*models.StockSignalEntity, error)
+	// GetLatestByInput mengembalikan signal terbaru untuk saham, interval dan range yang sama
+	// yang dibuat setelah after, atau nil jika tidak ada. Interval atau range kosong tidak difilter.
+	GetLatestByInput(ctx context.Context, stockCode, interval, period string, after time.Time) (*models.StockSignalEntity, error)
 }
 
 type stockSignalRepository struct {
@@ -40,6 +49,10 @@ func (s *stockSignalRepository) GetLatestSignal(ctx context.Context, param model
 		filterQuery = append(filterQuery, "ss.stock_code = ?")
 		filterParams = append(filterParams, param.StockCode)
 	}
+	if len(param.StockCodes) > 0 {
+		filterQuery = append(filterQuery, "ss.stock_code IN ?")
+		filterParams = append(filterParams, param.StockCodes)
+	}
 
 	basedQuery += " WHERE ss.deleted_at IS NULL"
 
@@ -52,3 +65,43 @@ func (s *stockSignalRepository) GetLatestSignal(ctx context.Context, param model
 	}
 	return stockSignals, nil
 }
+
+func (s *stockSignalRepository) Create(ctx context.Context, signal *models.StockSignalEntity, opts ...utils.DBOption) error {
+	tx := utils.ApplyOptions(s.db.WithContext(ctx), opts...)
+	return tx.Create(signal).Error
+}
+
+func (s *stockSignalRepository) GetByCacheKey(ctx context.Context, cacheKey string, after time.Time) (*models.StockSignalEntity, error) {
+	var signals []models.StockSignalEntity
+	err := s.db.WithContext(ctx).
+		Where("cache_key = ? AND created_at >= ?", cacheKey, after).
+		Order("created_at DESC").
+		Limit(1).
+		Find(&signals).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(signals) == 0 {
+		return nil, nil
+	}
+	return &signals[0], nil
+}
+
+func (s *stockSignalRepository) GetLatestByInput(ctx context.Context, stockCode, interval, period string, after time.Time) (*models.StockSignalEntity, error) {
+	query := s.db.WithContext(ctx).Where("stock_code = ? AND created_at >= ?", stockCode, after)
+	if interval != "" {
+		query = query.Where(`"interval" = ?`, interval)
+	}
+	if period != "" {
+		query = query.Where(`"range" = ?`, period)
+	}
+
+	var signals []models.StockSignalEntity
+	if err := query.Order("created_at DESC").Limit(1).Find(&signals).Error; err != nil {
+		return nil, err
+	}
+	if len(signals) == 0 {
+		return nil, nil
+	}
+	return &signals[0], nil
+}
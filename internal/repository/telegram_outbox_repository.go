@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TelegramOutboxRepository interface {
+	// Create menyimpan pesan baru. Jika dedup_key sudah ada, pesan yang sudah tersimpan
+	// dikembalikan dan created bernilai false.
+	Create(ctx context.Context, message *models.TelegramOutboxEntity, opts ...utils.DBOption) (created bool, err error)
+	GetByID(ctx context.Context, id uint, opts ...utils.DBOption) (*models.TelegramOutboxEntity, error)
+	// ClaimDue mengambil maksimal limit pesan yang sudah waktunya dikirim, termasuk pesan
+	// berstatus sending yang lease-nya habis (worker mati di tengah pengiriman). Pesan yang
+	// diambil ditandai sending sampai now+lease sehingga tidak diambil replica lain.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.TelegramOutboxEntity, error)
+	Update(ctx context.Context, message *models.TelegramOutboxEntity, opts ...utils.DBOption) error
+}
+
+type telegramOutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewTelegramOutboxRepository(db *gorm.DB) TelegramOutboxRepository {
+	return &telegramOutboxRepository{db: db}
+}
+
+func (r *telegramOutboxRepository) Create(ctx context.Context, message *models.TelegramOutboxEntity, opts ...utils.DBOption) (bool, error) {
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "dedup_key"}},
+		DoNothing: true,
+	}).Create(message)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 || message.DedupKey == nil {
+		return true, nil
+	}
+
+	db = utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	if err := db.Where("dedup_key = ?", *message.DedupKey).First(message).Error; err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+func (r *telegramOutboxRepository) GetByID(ctx context.Context, id uint, opts ...utils.DBOption) (*models.TelegramOutboxEntity, error) {
+	var message models.TelegramOutboxEntity
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	if err := db.First(&message, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &message, nil
+}
+
+func (r *telegramOutboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.TelegramOutboxEntity, error) {
+	var messages []models.TelegramOutboxEntity
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND next_attempt_at <= ?", []string{models.OutboxStatusPending, models.OutboxStatusSending}, now).
+			Order("id").
+			Limit(limit).
+			Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(messages))
+		for i := range messages {
+			ids = append(ids, messages[i].ID)
+			messages[i].Status = models.OutboxStatusSending
+			messages[i].Attempts++
+			messages[i].NextAttemptAt = now.Add(lease)
+		}
+		return tx.Model(&models.TelegramOutboxEntity{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":          models.OutboxStatusSending,
+				"attempts":        gorm.Expr("attempts + 1"),
+				"next_attempt_at": now.Add(lease),
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (r *telegramOutboxRepository) Update(ctx context.Context, message *models.TelegramOutboxEntity, opts ...utils.DBOption) error {
+	db := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	return db.Save(message).Error
+}
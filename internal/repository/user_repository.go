@@ -11,6 +11,9 @@ import (
 type UserRepository interface {
 	GetUserByTelegramID(ctx context.Context, telegramID int64, opts ...utils.DBOption) (*models.UserEntity, error)
 	CreateUser(ctx context.Context, user *models.UserEntity, opts ...utils.DBOption) error
+	GetUserByUsername(ctx context.Context, username string, opts ...utils.DBOption) (*models.UserEntity, error)
+	GetUsers(ctx context.Context, limit int, opts ...utils.DBOption) ([]models.UserEntity, error)
+	UpdateUser(ctx context.Context, telegramID int64, updates map[string]any, opts ...utils.DBOption) error
 }
 
 type userRepository struct {
@@ -43,3 +46,34 @@ func (r *userRepository) CreateUser(ctx context.Context, user *models.UserEntity
 	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
 	return tx.Create(user).Error
 }
+
+func (r *userRepository) GetUserByUsername(ctx context.Context, username string, opts ...utils.DBOption) (*models.UserEntity, error) {
+	var user models.UserEntity
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+
+	result := tx.Where("LOWER(username) = LOWER(?)", username).First(&user)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, result.Error
+	}
+
+	return &user, nil
+}
+
+func (r *userRepository) GetUsers(ctx context.Context, limit int, opts ...utils.DBOption) ([]models.UserEntity, error) {
+	var users []models.UserEntity
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+
+	if err := tx.Order("last_active_at DESC").Limit(limit).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *userRepository) UpdateUser(ctx context.Context, telegramID int64, updates map[string]any, opts ...utils.DBOption) error {
+	tx := utils.ApplyOptions(r.db.WithContext(ctx), opts...)
+	return tx.Model(&models.UserEntity{}).Where("telegram_id = ?", telegramID).Updates(updates).Error
+}
@@ -0,0 +1,549 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/i18n"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/repository"
+	"golang-swing-trading-signal/internal/services/groups"
+	"golang-swing-trading-signal/internal/services/market_calendar"
+	"golang-swing-trading-signal/internal/services/market_price"
+	"golang-swing-trading-signal/internal/services/notification"
+	"golang-swing-trading-signal/internal/services/outbox"
+	"golang-swing-trading-signal/internal/services/stocks"
+	"golang-swing-trading-signal/internal/services/users"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+const (
+	defaultPollInterval = time.Minute
+	defaultMaxDelay     = 2 * time.Hour
+	defaultMorningTime  = "08:00"
+	defaultEndOfDayTime = "16:30"
+	defaultWeeklyTime   = "17:00"
+	weeklyRecapDays     = 7
+)
+
+var (
+	ErrInvalidDigestType   = errors.New("invalid digest type")
+	ErrInvalidDeliveryTime = errors.New("invalid delivery time")
+)
+
+type DigestService interface {
+	// GetPreferences mengembalikan preferensi semua jenis digest milik user.
+	GetPreferences(ctx context.Context, telegramID int64) ([]models.DigestPreference, error)
+	SetEnabled(ctx context.Context, telegramID int64, digestType string, enabled bool) error
+	SetDeliveryTime(ctx context.Context, telegramID int64, digestType string, deliveryTime string) error
+	// SendDue memasukkan digest yang sudah jatuh tempo ke outbox dan mengembalikan jumlahnya.
+	SendDue(ctx context.Context) (int, error)
+	// SendBuyList memasukkan buy-list dari signal terbaru untuk chatID ke outbox, dipakai stage
+	// terakhir pipeline harian. Mengembalikan jumlah signal BUY yang dikirim.
+	SendBuyList(ctx context.Context, chatID int64) (int, error)
+	Start(ctx context.Context)
+	Stop()
+}
+
+type digestService struct {
+	cfg                 *config.Config
+	logger              *logrus.Logger
+	digestRepository    repository.DigestRepository
+	stockPositionRepo   repository.StockPositionRepository
+	stockService        stocks.StockService
+	priceService        market_price.PriceService
+	marketCalendar      *market_calendar.Calendar
+	userService         users.UserService
+	outboxService       outbox.OutboxService
+	notificationService notification.NotificationService
+	groupService        groups.GroupService
+	now                 func() time.Time
+	wg                  sync.WaitGroup
+}
+
+func NewDigestService(
+	cfg *config.Config,
+	logger *logrus.Logger,
+	digestRepository repository.DigestRepository,
+	stockPositionRepo repository.StockPositionRepository,
+	stockService stocks.StockService,
+	priceService market_price.PriceService,
+	marketCalendar *market_calendar.Calendar,
+	userService users.UserService,
+	outboxService outbox.OutboxService,
+	notificationService notification.NotificationService,
+	groupService groups.GroupService,
+) DigestService {
+	return &digestService{
+		cfg:                 cfg,
+		logger:              logger,
+		digestRepository:    digestRepository,
+		stockPositionRepo:   stockPositionRepo,
+		stockService:        stockService,
+		priceService:        priceService,
+		marketCalendar:      marketCalendar,
+		userService:         userService,
+		outboxService:       outboxService,
+		notificationService: notificationService,
+		groupService:        groupService,
+		now:                 utils.TimeNowWIB,
+	}
+}
+
+// NormalizeDeliveryTime menerima jam dalam format H:MM, HH:MM atau HH.MM dan
+// mengembalikannya dalam format HH:MM.
+func NormalizeDeliveryTime(value string) (string, error) {
+	hour, minute, ok := utils.ParseClock(value)
+	if !ok {
+		return "", ErrInvalidDeliveryTime
+	}
+	return fmt.Sprintf("%02d:%02d", hour, minute), nil
+}
+
+// DueSlot menentukan apakah digest harus dikirim pada now. slot adalah jadwal kirim hari
+// ini, digest yang terlambat lebih dari maxDelay dilewati. Buy-list pagi dan ringkasan
+// akhir hari hanya dikirim di hari bursa, rekap mingguan hanya pada weeklyDay.
+func DueSlot(digestType, deliveryTime string, lastSentAt *time.Time, now time.Time, maxDelay time.Duration, weeklyDay time.Weekday, isTradingDay func(time.Time) bool) (time.Time, bool) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(deliveryTime, "%d:%d", &hour, &minute); err != nil {
+		return time.Time{}, false
+	}
+
+	now = utils.TimeToWIB(now)
+	slot := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	if now.Before(slot) || now.Sub(slot) > maxDelay {
+		return slot, false
+	}
+
+	switch digestType {
+	case models.DigestMorningBuyList, models.DigestEndOfDay:
+		if !isTradingDay(now) {
+			return slot, false
+		}
+	case models.DigestWeeklyRecap:
+		if now.Weekday() != weeklyDay {
+			return slot, false
+		}
+	default:
+		return slot, false
+	}
+
+	if lastSentAt != nil && !lastSentAt.Before(slot) {
+		return slot, false
+	}
+	return slot, true
+}
+
+func (s *digestService) defaultDeliveryTime(digestType string) string {
+	value, fallback := s.cfg.Digest.MorningTime, defaultMorningTime
+	switch digestType {
+	case models.DigestEndOfDay:
+		value, fallback = s.cfg.Digest.EndOfDayTime, defaultEndOfDayTime
+	case models.DigestWeeklyRecap:
+		value, fallback = s.cfg.Digest.WeeklyTime, defaultWeeklyTime
+	}
+
+	if normalized, err := NormalizeDeliveryTime(value); err == nil {
+		return normalized
+	}
+	return fallback
+}
+
+func (s *digestService) maxDelay() time.Duration {
+	if s.cfg.Digest.MaxDelay > 0 {
+		return s.cfg.Digest.MaxDelay
+	}
+	return defaultMaxDelay
+}
+
+func (s *digestService) pollInterval() time.Duration {
+	if s.cfg.Digest.PollInterval > 0 {
+		return s.cfg.Digest.PollInterval
+	}
+	return defaultPollInterval
+}
+
+func (s *digestService) GetPreferences(ctx context.Context, telegramID int64) ([]models.DigestPreference, error) {
+	subscriptions, err := s.digestRepository.GetSubscriptions(ctx, models.GetDigestSubscriptionParam{TelegramID: &telegramID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest subscriptions: %w", err)
+	}
+
+	preferences := make([]models.DigestPreference, 0, len(models.DigestTypes))
+	for _, digestType := range models.DigestTypes {
+		preference := models.DigestPreference{
+			DigestType:   digestType,
+			DeliveryTime: s.defaultDeliveryTime(digestType),
+		}
+		for _, subscription := range subscriptions {
+			if subscription.DigestType == digestType {
+				preference.Enabled = subscription.Enabled
+				preference.DeliveryTime = subscription.DeliveryTime
+				preference.LastSentAt = subscription.LastSentAt
+			}
+		}
+		preferences = append(preferences, preference)
+	}
+	return preferences, nil
+}
+
+func (s *digestService) SetEnabled(ctx context.Context, telegramID int64, digestType string, enabled bool) error {
+	return s.updatePreference(ctx, telegramID, digestType, func(preference *models.DigestPreference) {
+		preference.Enabled = enabled
+	})
+}
+
+func (s *digestService) SetDeliveryTime(ctx context.Context, telegramID int64, digestType string, deliveryTime string) error {
+	normalized, err := NormalizeDeliveryTime(deliveryTime)
+	if err != nil {
+		return err
+	}
+	// mengatur jam sekaligus mengaktifkan digest
+	return s.updatePreference(ctx, telegramID, digestType, func(preference *models.DigestPreference) {
+		preference.DeliveryTime = normalized
+		preference.Enabled = true
+	})
+}
+
+func (s *digestService) updatePreference(ctx context.Context, telegramID int64, digestType string, update func(preference *models.DigestPreference)) error {
+	if !slices.Contains(models.DigestTypes, digestType) {
+		return ErrInvalidDigestType
+	}
+
+	preferences, err := s.GetPreferences(ctx, telegramID)
+	if err != nil {
+		return err
+	}
+	preference := preferences[slices.Index(models.DigestTypes, digestType)]
+	update(&preference)
+
+	if err := s.digestRepository.UpsertSubscription(ctx, &models.DigestSubscriptionEntity{
+		TelegramID:   telegramID,
+		DigestType:   digestType,
+		DeliveryTime: preference.DeliveryTime,
+		Enabled:      preference.Enabled,
+	}); err != nil {
+		return fmt.Errorf("failed to save digest subscription: %w", err)
+	}
+	return nil
+}
+
+func (s *digestService) SendDue(ctx context.Context) (int, error) {
+	subscriptions, err := s.digestRepository.GetSubscriptions(ctx, models.GetDigestSubscriptionParam{Enabled: utils.ToPointer(true)})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get digest subscriptions: %w", err)
+	}
+
+	now := s.now()
+	weeklyDay := time.Weekday(s.cfg.Digest.WeeklyDay)
+	// sinyal buy-list pagi sama untuk semua user, cukup diambil sekali per putaran
+	var (
+		morningSignals []models.StockSignalEntity
+		morningLoaded  bool
+		morningErr     error
+	)
+
+	sent := 0
+	for _, subscription := range subscriptions {
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+
+		slot, due := DueSlot(subscription.DigestType, subscription.DeliveryTime, subscription.LastSentAt, now, s.maxDelay(), weeklyDay, s.marketCalendar.IsTradingDay)
+		if !due {
+			continue
+		}
+
+		recipient, ok := s.resolveRecipient(ctx, subscription)
+		if !ok {
+			continue
+		}
+		decision, tr := recipient.decision, recipient.translator
+		if !decision.Send {
+			// digest dimatikan dari /settings, jadwal hari ini dianggap selesai
+			if err := s.digestRepository.MarkSent(ctx, subscription.ID, now); err != nil {
+				s.logger.Error("failed to mark digest as sent", logrus.Fields{
+					"telegram_id": subscription.TelegramID,
+					"digest_type": subscription.DigestType,
+					"error":       err,
+				})
+			}
+			continue
+		}
+
+		var (
+			text string
+			err  error
+		)
+		switch subscription.DigestType {
+		case models.DigestMorningBuyList:
+			if !morningLoaded {
+				morningSignals, morningErr = s.getMorningBuySignals(ctx, now)
+				morningLoaded = true
+			}
+			text, err = formatMorningBuyList(tr, FilterSignals(morningSignals, recipient.watchlist), now, decision.Compact), morningErr
+		case models.DigestEndOfDay:
+			text, err = s.composeEndOfDay(ctx, tr, subscription.TelegramID, now, decision.Compact)
+		case models.DigestWeeklyRecap:
+			text, err = s.composeWeeklyRecap(ctx, tr, subscription.TelegramID, now)
+		}
+		if err != nil {
+			s.logger.Error("failed to compose digest", logrus.Fields{
+				"telegram_id": subscription.TelegramID,
+				"digest_type": subscription.DigestType,
+				"error":       err,
+			})
+			continue
+		}
+
+		// digest kosong (misal tidak ada posisi aktif) tidak dikirim tapi tetap ditandai
+		if text != "" {
+			if _, err := s.outboxService.Enqueue(ctx, models.TelegramOutboxMessage{
+				ChatID:    subscription.TelegramID,
+				Text:      text,
+				ParseMode: telebot.ModeHTML,
+				DedupKey:  fmt.Sprintf("digest:%s:%d:%s", subscription.DigestType, subscription.TelegramID, slot.Format(time.DateOnly)),
+				SendAfter: decision.SendAfter,
+			}); err != nil {
+				s.logger.Error("failed to enqueue digest", logrus.Fields{
+					"telegram_id": subscription.TelegramID,
+					"digest_type": subscription.DigestType,
+					"error":       err,
+				})
+				continue
+			}
+			sent++
+		}
+
+		if err := s.digestRepository.MarkSent(ctx, subscription.ID, now); err != nil {
+			s.logger.Error("failed to mark digest as sent", logrus.Fields{
+				"telegram_id": subscription.TelegramID,
+				"digest_type": subscription.DigestType,
+				"error":       err,
+			})
+		}
+	}
+	return sent, nil
+}
+
+// digestRecipient adalah penerima digest: user di chat pribadi atau grup. Watchlist hanya
+// terisi untuk grup dan membatasi saham yang masuk buy-list pagi.
+type digestRecipient struct {
+	translator i18n.Translator
+	decision   models.NotificationDecision
+	watchlist  []string
+}
+
+// resolveRecipient mengembalikan false jika digest tidak boleh dikirim ke penerima, misal
+// user diblokir atau bot sudah dikeluarkan dari grup.
+func (s *digestService) resolveRecipient(ctx context.Context, subscription models.DigestSubscriptionEntity) (digestRecipient, bool) {
+	if models.IsGroupChat(subscription.TelegramID) {
+		return s.resolveGroupRecipient(ctx, subscription)
+	}
+
+	access, err := s.userService.GetAccess(ctx, subscription.TelegramID)
+	if err != nil {
+		s.logger.Error("failed to get user access for digest", logrus.Fields{
+			"telegram_id": subscription.TelegramID,
+			"error":       err,
+		})
+		return digestRecipient{}, false
+	}
+	if access.Banned || !access.Role.Allows(models.RoleMember) {
+		return digestRecipient{}, false
+	}
+
+	decision, err := s.notificationService.Decide(ctx, subscription.TelegramID, models.Notification{Type: models.NotificationDigest})
+	if err != nil {
+		s.logger.Error("failed to get notification preference for digest", logrus.Fields{
+			"telegram_id": subscription.TelegramID,
+			"digest_type": subscription.DigestType,
+			"error":       err,
+		})
+		return digestRecipient{}, false
+	}
+	return digestRecipient{
+		translator: i18n.New(i18n.Resolve(access.Language, access.LanguageCode)),
+		decision:   decision,
+	}, true
+}
+
+// resolveGroupRecipient tidak memakai preferensi notifikasi karena grup tidak punya
+// jam tenang, digest grup hanya diatur dari /group.
+func (s *digestService) resolveGroupRecipient(ctx context.Context, subscription models.DigestSubscriptionEntity) (digestRecipient, bool) {
+	if !slices.Contains(models.GroupDigestTypes, subscription.DigestType) {
+		return digestRecipient{}, false
+	}
+
+	group, err := s.groupService.GetGroup(ctx, subscription.TelegramID)
+	if err != nil {
+		if !errors.Is(err, groups.ErrGroupNotFound) {
+			s.logger.Error("failed to get group for digest", logrus.Fields{
+				"telegram_id": subscription.TelegramID,
+				"digest_type": subscription.DigestType,
+				"error":       err,
+			})
+		}
+		return digestRecipient{}, false
+	}
+	if !group.IsActive {
+		return digestRecipient{}, false
+	}
+
+	watchlist, err := s.groupService.GetWatchlist(ctx, group.ChatID)
+	if err != nil {
+		s.logger.Error("failed to get group watchlist for digest", logrus.Fields{
+			"telegram_id": subscription.TelegramID,
+			"digest_type": subscription.DigestType,
+			"error":       err,
+		})
+		return digestRecipient{}, false
+	}
+	return digestRecipient{
+		translator: i18n.New(i18n.Resolve(group.Language)),
+		decision:   models.NotificationDecision{Send: true},
+		watchlist:  watchlist,
+	}, true
+}
+
+// FilterSignals mengembalikan sinyal untuk saham di watchlist, watchlist kosong berarti
+// semua sinyal.
+func FilterSignals(signals []models.StockSignalEntity, watchlist []string) []models.StockSignalEntity {
+	if len(watchlist) == 0 {
+		return signals
+	}
+	filtered := make([]models.StockSignalEntity, 0, len(signals))
+	for _, signal := range signals {
+		if slices.Contains(watchlist, signal.StockCode) {
+			filtered = append(filtered, signal)
+		}
+	}
+	return filtered
+}
+
+func (s *digestService) SendBuyList(ctx context.Context, chatID int64) (int, error) {
+	now := s.now()
+	signals, err := s.getMorningBuySignals(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	// chat dari konfigurasi bukan user terdaftar, pakai bahasa default
+	text := formatMorningBuyList(i18n.New(i18n.DefaultLocale), signals, now, false)
+	if _, err := s.outboxService.Enqueue(ctx, models.TelegramOutboxMessage{
+		ChatID:    chatID,
+		Text:      text,
+		ParseMode: telebot.ModeHTML,
+		DedupKey:  fmt.Sprintf("buy_list:%d:%s", chatID, now.Format(time.DateOnly)),
+	}); err != nil {
+		return 0, fmt.Errorf("failed to enqueue buy list: %w", err)
+	}
+	return len(signals), nil
+}
+
+func (s *digestService) getMorningBuySignals(ctx context.Context, now time.Time) ([]models.StockSignalEntity, error) {
+	signals, err := s.stockService.GetLatestStockSignal(ctx, models.GetStockBuySignalParam{
+		After: now.Add(-s.cfg.Trading.GetBuyListSignalBefore),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest stock signals: %w", err)
+	}
+
+	buySignals := make([]models.StockSignalEntity, 0, len(signals))
+	for _, signal := range signals {
+		if signal.Signal == "BUY" {
+			buySignals = append(buySignals, signal)
+		}
+	}
+	return buySignals, nil
+}
+
+func (s *digestService) composeEndOfDay(ctx context.Context, tr i18n.Translator, telegramID int64, now time.Time, compact bool) (string, error) {
+	positions, err := s.stockService.GetStockPositionsTelegramUser(ctx, telegramID, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get active positions: %w", err)
+	}
+	if len(positions) == 0 {
+		return "", nil
+	}
+
+	stockCodes := make([]string, 0, len(positions))
+	for _, position := range positions {
+		stockCodes = append(stockCodes, position.StockCode)
+	}
+	prices, err := s.priceService.GetLastPrices(ctx, stockCodes)
+	if err != nil {
+		// ringkasan tetap dikirim tanpa harga terakhir
+		s.logger.Warn("failed to get last prices for digest", logrus.Fields{
+			"telegram_id": telegramID,
+			"error":       err,
+		})
+	}
+
+	return formatEndOfDay(tr, positions, prices, now, s.marketCalendar, compact), nil
+}
+
+func (s *digestService) composeWeeklyRecap(ctx context.Context, tr i18n.Translator, telegramID int64, now time.Time) (string, error) {
+	// langsung ke repository: user tanpa posisi yang ditutup tetap mendapat rekap kosong
+	exited, err := s.stockPositionRepo.GetList(ctx, models.StockPositionQueryParam{
+		TelegramIDs: []int64{telegramID},
+		IsExit:      utils.ToPointer(true),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get exited positions: %w", err)
+	}
+
+	from := now.AddDate(0, 0, -weeklyRecapDays)
+	closed := make([]models.StockPositionEntity, 0, len(exited))
+	for _, position := range exited {
+		if position.ExitDate != nil && position.ExitPrice != nil && !position.ExitDate.Before(from) {
+			closed = append(closed, position)
+		}
+	}
+
+	active, err := s.stockService.GetStockPositionsTelegramUser(ctx, telegramID, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get active positions: %w", err)
+	}
+
+	return formatWeeklyRecap(tr, closed, len(active), from, now), nil
+}
+
+// Start menjalankan worker yang mengirim digest jatuh tempo secara berkala sampai ctx selesai.
+func (s *digestService) Start(ctx context.Context) {
+	s.wg.Add(1)
+	utils.SafeGo(func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.pollInterval())
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				s.logger.Info("Received signal to stop digest worker")
+				return
+			case <-ticker.C:
+				sent, err := s.SendDue(ctx)
+				if err != nil && ctx.Err() == nil {
+					s.logger.Error("failed to send due digests", logrus.Fields{"error": err})
+				}
+				if sent > 0 {
+					s.logger.Info("Digest queued", logrus.Fields{"count": sent})
+				}
+			}
+		}
+	})
+}
+
+func (s *digestService) Stop() {
+	s.wg.Wait()
+	s.logger.Info("Digest worker stopped")
+}
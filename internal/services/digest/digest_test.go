@@ -0,0 +1,250 @@
+package digest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/i18n"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/repository"
+	"golang-swing-trading-signal/internal/services/market_calendar"
+	"golang-swing-trading-signal/internal/services/notification"
+	"golang-swing-trading-signal/internal/services/outbox"
+	"golang-swing-trading-signal/internal/services/stocks"
+	"golang-swing-trading-signal/internal/services/users"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestNormalizeDeliveryTime(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "08:00", want: "08:00"},
+		{value: "8:05", want: "08:05"},
+		{value: "16.30", want: "16:30"},
+		{value: "23:59", want: "23:59"},
+		{value: "24:00", wantErr: true},
+		{value: "07:60", wantErr: true},
+		{value: "pagi", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := NormalizeDeliveryTime(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeDeliveryTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeDeliveryTime() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterSignals(t *testing.T) {
+	signals := []models.StockSignalEntity{{StockCode: "BBCA"}, {StockCode: "BBRI"}, {StockCode: "TLKM"}}
+
+	tests := []struct {
+		name      string
+		watchlist []string
+		want      []string
+	}{
+		{name: "empty watchlist keeps all", want: []string{"BBCA", "BBRI", "TLKM"}},
+		{name: "filtered by watchlist", watchlist: []string{"TLKM", "BBCA"}, want: []string{"BBCA", "TLKM"}},
+		{name: "no match", watchlist: []string{"ASII"}, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, signal := range FilterSignals(signals, tt.watchlist) {
+				got = append(got, signal.StockCode)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("FilterSignals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDueSlot(t *testing.T) {
+	wib := utils.TimeNowWIB().Location()
+	// Jumat 16 Oktober 2026
+	friday := func(hour, minute int) time.Time { return time.Date(2026, 10, 16, hour, minute, 0, 0, wib) }
+	saturday := time.Date(2026, 10, 17, 9, 0, 0, 0, wib)
+	weekdays := func(t time.Time) bool { return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday }
+
+	tests := []struct {
+		name         string
+		digestType   string
+		deliveryTime string
+		lastSentAt   *time.Time
+		now          time.Time
+		want         bool
+	}{
+		{name: "before delivery time", digestType: models.DigestMorningBuyList, deliveryTime: "08:00", now: friday(7, 59), want: false},
+		{name: "at delivery time", digestType: models.DigestMorningBuyList, deliveryTime: "08:00", now: friday(8, 0), want: true},
+		{name: "late but within max delay", digestType: models.DigestEndOfDay, deliveryTime: "16:30", now: friday(18, 0), want: true},
+		{name: "too late", digestType: models.DigestEndOfDay, deliveryTime: "16:30", now: friday(18, 31), want: false},
+		{name: "already sent today", digestType: models.DigestMorningBuyList, deliveryTime: "08:00", lastSentAt: utils.ToPointer(friday(8, 1)), now: friday(8, 5), want: false},
+		{name: "sent yesterday", digestType: models.DigestMorningBuyList, deliveryTime: "08:00", lastSentAt: utils.ToPointer(friday(8, 0).AddDate(0, 0, -1)), now: friday(8, 5), want: true},
+		{name: "not a trading day", digestType: models.DigestMorningBuyList, deliveryTime: "08:00", now: saturday, want: false},
+		{name: "weekly on configured day", digestType: models.DigestWeeklyRecap, deliveryTime: "17:00", now: friday(17, 10), want: true},
+		{name: "weekly on other day", digestType: models.DigestWeeklyRecap, deliveryTime: "09:00", now: saturday, want: false},
+		{name: "utc time is converted to WIB", digestType: models.DigestMorningBuyList, deliveryTime: "08:00", now: friday(8, 30).UTC(), want: true},
+		{name: "unknown type", digestType: "hourly", deliveryTime: "08:00", now: friday(8, 0), want: false},
+		{name: "invalid delivery time", digestType: models.DigestMorningBuyList, deliveryTime: "", now: friday(8, 0), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got := DueSlot(tt.digestType, tt.deliveryTime, tt.lastSentAt, tt.now, 2*time.Hour, time.Friday, weekdays)
+			if got != tt.want {
+				t.Errorf("DueSlot() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatWeeklyRecap(t *testing.T) {
+	now := time.Date(2026, 10, 16, 17, 0, 0, 0, utils.TimeNowWIB().Location())
+	closed := []models.StockPositionEntity{
+		{StockCode: "BBCA", BuyPrice: 1000, ExitPrice: utils.ToPointer(1100.0)},
+		{StockCode: "ANTM", BuyPrice: 1000, ExitPrice: utils.ToPointer(950.0)},
+	}
+
+	tests := []struct {
+		locale i18n.Locale
+		want   []string
+		empty  string
+	}{
+		{
+			locale: i18n.LocaleID,
+			want:   []string{"Win: 1 | 🔴 Lose: 1", "Win Rate: 50,00%", "Total PnL: +5,0%", "Terbaik: $BBCA (+10,0%)", "Terburuk: $ANTM (-5,0%)", "Posisi aktif saat ini: 3"},
+			empty:  "Tidak ada posisi yang ditutup",
+		},
+		{
+			locale: i18n.LocaleEN,
+			want:   []string{"Win: 1 | 🔴 Lose: 1", "Win Rate: 50.00%", "Total PnL: +5.0%", "Best: $BBCA (+10.0%)", "Worst: $ANTM (-5.0%)", "Current active positions: 3"},
+			empty:  "No positions were closed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.locale), func(t *testing.T) {
+			tr := i18n.New(tt.locale)
+			got := formatWeeklyRecap(tr, closed, 3, now.AddDate(0, 0, -7), now)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("formatWeeklyRecap() missing %q in:\n%s", want, got)
+				}
+			}
+
+			if got := formatWeeklyRecap(tr, nil, 0, now.AddDate(0, 0, -7), now); !strings.Contains(got, tt.empty) {
+				t.Errorf("formatWeeklyRecap() without closed positions = %s", got)
+			}
+		})
+	}
+}
+
+type fakeDigestRepository struct {
+	repository.DigestRepository
+	subscriptions []models.DigestSubscriptionEntity
+	sent          []uint
+}
+
+func (r *fakeDigestRepository) GetSubscriptions(ctx context.Context, param models.GetDigestSubscriptionParam, opts ...utils.DBOption) ([]models.DigestSubscriptionEntity, error) {
+	return r.subscriptions, nil
+}
+
+func (r *fakeDigestRepository) MarkSent(ctx context.Context, id uint, sentAt time.Time, opts ...utils.DBOption) error {
+	r.sent = append(r.sent, id)
+	return nil
+}
+
+// emptyPositionRepository meniru database tanpa posisi sama sekali.
+type emptyPositionRepository struct {
+	repository.StockPositionRepository
+}
+
+func (emptyPositionRepository) GetList(ctx context.Context, queryParam models.StockPositionQueryParam, opts ...utils.DBOption) ([]models.StockPositionEntity, error) {
+	return nil, nil
+}
+
+type emptyStockService struct {
+	stocks.StockService
+}
+
+func (emptyStockService) GetStockPositionsTelegramUser(ctx context.Context, telegramID int64, monitoring *models.StockPositionMonitoringQueryParam) ([]models.StockPositionEntity, error) {
+	return nil, nil
+}
+
+type memberUserService struct {
+	users.UserService
+}
+
+func (memberUserService) GetAccess(ctx context.Context, telegramID int64) (users.Access, error) {
+	return users.Access{Role: models.RoleMember}, nil
+}
+
+type sendAllNotificationService struct {
+	notification.NotificationService
+}
+
+func (sendAllNotificationService) Decide(ctx context.Context, telegramID int64, n models.Notification) (models.NotificationDecision, error) {
+	return models.NotificationDecision{Send: true}, nil
+}
+
+type recordingOutbox struct {
+	outbox.OutboxService
+	messages []models.TelegramOutboxMessage
+}
+
+func (o *recordingOutbox) Enqueue(ctx context.Context, message models.TelegramOutboxMessage) (*models.TelegramOutboxEntity, error) {
+	o.messages = append(o.messages, message)
+	return &models.TelegramOutboxEntity{}, nil
+}
+
+func TestSendDue_WeeklyRecapWithoutClosedPositions(t *testing.T) {
+	calendar, err := market_calendar.NewCalendar(nil, logrus.New())
+	if err != nil {
+		t.Fatalf("NewCalendar() error = %v", err)
+	}
+	// Jumat 17:05 WIB, tepat setelah jadwal rekap mingguan
+	now := time.Date(2026, 10, 16, 17, 5, 0, 0, utils.TimeNowWIB().Location())
+
+	digestRepository := &fakeDigestRepository{subscriptions: []models.DigestSubscriptionEntity{
+		{ID: 7, TelegramID: 42, DigestType: models.DigestWeeklyRecap, DeliveryTime: "17:00", Enabled: true},
+	}}
+	outboxService := &recordingOutbox{}
+	s := &digestService{
+		cfg:                 &config.Config{Digest: config.DigestConfig{WeeklyDay: int(time.Friday)}},
+		logger:              logrus.New(),
+		digestRepository:    digestRepository,
+		stockPositionRepo:   emptyPositionRepository{},
+		stockService:        emptyStockService{},
+		marketCalendar:      calendar,
+		userService:         memberUserService{},
+		outboxService:       outboxService,
+		notificationService: sendAllNotificationService{},
+		now:                 func() time.Time { return now },
+	}
+
+	sent, err := s.SendDue(context.Background())
+	if err != nil {
+		t.Fatalf("SendDue() error = %v", err)
+	}
+	if sent != 1 || len(outboxService.messages) != 1 {
+		t.Fatalf("SendDue() sent = %d, enqueued %d, want 1", sent, len(outboxService.messages))
+	}
+	if !strings.Contains(outboxService.messages[0].Text, "Tidak ada posisi yang ditutup") {
+		t.Errorf("weekly recap = %s, want empty recap", outboxService.messages[0].Text)
+	}
+	if len(digestRepository.sent) != 1 || digestRepository.sent[0] != 7 {
+		t.Errorf("MarkSent() ids = %v, want [7]", digestRepository.sent)
+	}
+}
@@ -0,0 +1,128 @@
+package digest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang-swing-trading-signal/internal/i18n"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/market_calendar"
+)
+
+func formatMorningBuyList(tr i18n.Translator, signals []models.StockSignalEntity, now time.Time, compact bool) string {
+	sb := &strings.Builder{}
+	sb.WriteString(tr.T("digest.morning_title", tr.ShortDate(now)))
+
+	if len(signals) == 0 {
+		sb.WriteString(tr.T("digest.morning_empty"))
+		return sb.String()
+	}
+
+	sb.WriteString(tr.Plural("digest.morning_count", len(signals)))
+	for idx, signal := range signals {
+		sb.WriteString(tr.T("digest.morning_signal", idx+1, signal.StockCode, tr.Percent(signal.ConfidenceScore, 0), signal.TechnicalScore))
+		if compact {
+			continue
+		}
+
+		var analysis models.IndividualAnalysisResponseMultiTimeframe
+		if err := json.Unmarshal(signal.Data, &analysis); err != nil || analysis.BuyPrice == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n   💰 Buy %s | 🎯 TP %s | 🛑 SL %s", tr.Int(analysis.BuyPrice), tr.Int(analysis.TargetPrice), tr.Int(analysis.CutLoss)))
+		if analysis.RiskRewardRatio > 0 {
+			sb.WriteString(fmt.Sprintf(" | R/R %s", tr.Number(analysis.RiskRewardRatio, 1)))
+		}
+	}
+
+	sb.WriteString(tr.T("digest.morning_footer"))
+	return sb.String()
+}
+
+func formatEndOfDay(tr i18n.Translator, positions []models.StockPositionEntity, prices map[string]models.MarketPrice, now time.Time, calendar *market_calendar.Calendar, compact bool) string {
+	sb := &strings.Builder{}
+	sb.WriteString(tr.T("digest.end_of_day_title", tr.ShortDate(now)))
+
+	totalPnL := 0.0
+	priced := 0
+	for _, position := range positions {
+		sb.WriteString(fmt.Sprintf("\n• <b>$%s</b> | Buy %s", position.StockCode, tr.Int(position.BuyPrice)))
+
+		if price, ok := prices[position.StockCode]; ok && price.Price > 0 && position.BuyPrice > 0 {
+			pnl := (price.Price - position.BuyPrice) / position.BuyPrice * 100
+			totalPnL += pnl
+			priced++
+			icon := "🔴"
+			if pnl >= 0 {
+				icon = "🟢"
+			}
+			sb.WriteString(fmt.Sprintf(" | Last %s | %s %s", tr.Int(price.Price), icon, tr.PercentChange(pnl)))
+
+			switch {
+			case position.TakeProfitPrice > 0 && price.Price >= position.TakeProfitPrice:
+				sb.WriteString(tr.T("digest.end_of_day_target_hit"))
+			case position.StopLossPrice > 0 && price.Price <= position.StopLossPrice:
+				sb.WriteString(tr.T("digest.end_of_day_stop_loss_hit"))
+			}
+		}
+
+		if compact {
+			continue
+		}
+		remaining := calendar.RemainingHoldingDays(position.MaxHoldingPeriodDays, position.BuyDate, now)
+		sb.WriteString(tr.Plural("digest.end_of_day_holding", remaining, calendar.HoldingDays(position.BuyDate, now)))
+	}
+
+	if priced > 0 {
+		sb.WriteString(tr.Plural("digest.end_of_day_average", priced, tr.PercentChange(totalPnL/float64(priced))))
+	}
+	sb.WriteString(tr.T("digest.end_of_day_footer"))
+	return sb.String()
+}
+
+func formatWeeklyRecap(tr i18n.Translator, closed []models.StockPositionEntity, activeCount int, from, to time.Time) string {
+	sb := &strings.Builder{}
+	sb.WriteString(tr.T("digest.weekly_title", tr.ShortDate(from), tr.ShortDate(to)))
+
+	if len(closed) == 0 {
+		sb.WriteString(tr.T("digest.weekly_empty"))
+	} else {
+		win := 0
+		totalPnL := 0.0
+		best, worst := closed[0], closed[0]
+		bestPnL, worstPnL := positionPnL(&closed[0]), positionPnL(&closed[0])
+		for i := range closed {
+			pnl := positionPnL(&closed[i])
+			totalPnL += pnl
+			if pnl > 0 {
+				win++
+			}
+			if pnl > bestPnL {
+				best, bestPnL = closed[i], pnl
+			}
+			if pnl < worstPnL {
+				worst, worstPnL = closed[i], pnl
+			}
+		}
+
+		sb.WriteString(tr.T("digest.weekly_closed", len(closed)))
+		sb.WriteString(fmt.Sprintf("\n🟢 Win: %d | 🔴 Lose: %d", win, len(closed)-win))
+		sb.WriteString(fmt.Sprintf("\n🏆 Win Rate: %s", tr.Percent(float64(win)/float64(len(closed))*100, 2)))
+		sb.WriteString(fmt.Sprintf("\n📈 Total PnL: %s", tr.PercentChange(totalPnL)))
+		sb.WriteString(tr.T("digest.weekly_best", best.StockCode, tr.PercentChange(bestPnL)))
+		sb.WriteString(tr.T("digest.weekly_worst", worst.StockCode, tr.PercentChange(worstPnL)))
+	}
+
+	sb.WriteString(tr.T("digest.weekly_active", activeCount))
+	sb.WriteString(tr.T("digest.weekly_footer"))
+	return sb.String()
+}
+
+func positionPnL(position *models.StockPositionEntity) float64 {
+	if position.ExitPrice == nil || position.BuyPrice == 0 {
+		return 0
+	}
+	return (*position.ExitPrice - position.BuyPrice) / position.BuyPrice * 100
+}
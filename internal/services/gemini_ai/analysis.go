@@ -0,0 +1,235 @@
+package gemini_ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/yahoo_finance"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultAnalysisInterval = "1d"
+	defaultAnalysisRange    = "3m"
+
+	// ringkasan berita yang lebih tua dari ini dianggap tidak relevan untuk swing 1-5 hari
+	newsSummaryMaxAge = 3 * 24 * time.Hour
+)
+
+var ErrPositionNotFound = errors.New("stock position not found")
+
+// AnalyzeStock mengambil data OHLCV dan ringkasan berita terbaru, meminta analisa ke Gemini
+// lalu menyimpan hasilnya ke stock_signals. Request dengan input yang sama (lihat
+// analysisCacheKey) memakai ulang analisa yang sudah tersimpan, dan request identik yang
+// berjalan bersamaan digabung menjadi satu panggilan LLM.
+func (c *Client) AnalyzeStock(ctx context.Context, request models.AnalyzeStockRequest) (*models.IndividualAnalysisResponseMultiTimeframe, error) {
+	symbol := strings.ToUpper(strings.TrimSpace(request.Symbol))
+	interval, period := analysisWindow(request.Interval, request.Range)
+
+	ohlcv, summary, err := c.fetchAnalysisInput(ctx, symbol, interval, period)
+	if err != nil {
+		return nil, err
+	}
+
+	version := c.individualAnalysisVersion(symbol)
+	key := analysisCacheKey(symbol, interval, period, ohlcv.Data, summary, version)
+
+	// pekerjaan bersama tidak ikut dibatalkan jika salah satu pemanggil membatalkan request-nya
+	sharedCtx := context.WithoutCancel(ctx)
+	ch := c.analysisGroup.DoChan(key, func() (any, error) {
+		return c.analyzeStock(sharedCtx, key, symbol, interval, period, version, ohlcv, summary)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		// salin agar pemanggil yang berbagi hasil tidak saling mengubah data
+		result := *res.Val.(*models.IndividualAnalysisResponseMultiTimeframe)
+		return &result, nil
+	}
+}
+
+// analyzeStock mengembalikan analisa tersimpan untuk key jika ada, atau meminta analisa baru.
+// Analisa baru tidak esensial sehingga ditolak dengan llm_usage.ErrBudgetExceeded jika
+// budget LLM sudah habis.
+func (c *Client) analyzeStock(
+	ctx context.Context,
+	key, symbol, interval, period, version string,
+	ohlcv *yahoo_finance.OHLCDataWithInfo,
+	summary *models.StockNewsSummaryEntity,
+) (*models.IndividualAnalysisResponseMultiTimeframe, error) {
+	if cached := c.getCachedAnalysis(ctx, key); cached != nil {
+		return cached, nil
+	}
+
+	if err := c.usageService.CheckBudget(ctx); err != nil {
+		c.logger.Warn("stock analysis paused", logrus.Fields{
+			"symbol": symbol,
+			"error":  err,
+		})
+		return nil, err
+	}
+
+	result, err := c.generateIndividualAnalysis(ctx, symbol, version, ohlcv.Data, ohlcv.DataInfo, summary)
+	if err != nil {
+		c.logger.Error("failed to analyze stock", logrus.Fields{
+			"symbol": symbol,
+			"error":  err,
+		})
+		return nil, fmt.Errorf("failed to analyze stock: %w", err)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal analysis result: %w", err)
+	}
+
+	signal := &models.StockSignalEntity{
+		StockCode:       symbol,
+		Signal:          result.Action,
+		ConfidenceScore: float64(result.ConfidenceLevel),
+		TechnicalScore:  result.TechnicalScore,
+		NewsScore:       result.NewsSummary.ConfidenceScore,
+		Interval:        interval,
+		Range:           period,
+		PromptVersion:   result.PromptVersion,
+		CacheKey:        key,
+		Data:            data,
+	}
+	if err := c.stockSignalRepository.Create(ctx, signal); err != nil {
+		c.logger.Error("failed to save stock signal", logrus.Fields{
+			"symbol": symbol,
+			"error":  err,
+		})
+		return nil, fmt.Errorf("failed to save stock signal: %w", err)
+	}
+
+	return result, nil
+}
+
+// MonitorPosition mengevaluasi posisi aktif dengan Gemini lalu menyimpan hasilnya ke
+// stock_position_monitorings. Mengembalikan ErrPositionNotFound jika posisi tidak ada, sudah
+// tidak aktif, atau bukan milik request.TelegramID.
+func (c *Client) MonitorPosition(ctx context.Context, positionID uint, request models.MonitorPositionRequest) (*models.PositionMonitoringResponseMultiTimeframe, error) {
+	param := models.StockPositionQueryParam{
+		IDs:      []uint{positionID},
+		IsActive: true,
+	}
+	if request.TelegramID != 0 {
+		param.TelegramIDs = []int64{request.TelegramID}
+	}
+	positions, err := c.stockPositionRepository.GetList(ctx, param)
+	if err != nil {
+		c.logger.Error("failed to get stock position", logrus.Fields{
+			"position_id": positionID,
+			"error":       err,
+		})
+		return nil, fmt.Errorf("failed to get stock position: %w", err)
+	}
+	if len(positions) == 0 {
+		return nil, ErrPositionNotFound
+	}
+	position := positions[0]
+
+	interval, period := analysisWindow(request.Interval, request.Range)
+	ohlcv, summary, err := c.fetchAnalysisInput(ctx, position.StockCode, interval, period)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.usageService.CheckBudget(ctx); err != nil {
+		c.logger.Warn("position monitoring paused", logrus.Fields{
+			"position_id": positionID,
+			"symbol":      position.StockCode,
+			"error":       err,
+		})
+		return nil, err
+	}
+
+	monitoringRequest := models.PositionMonitoringRequest{
+		Symbol:               position.StockCode,
+		BuyPrice:             position.BuyPrice,
+		BuyTime:              position.BuyDate,
+		MaxHoldingPeriodDays: position.MaxHoldingPeriodDays,
+		Interval:             interval,
+		Period:               period,
+	}
+	result, err := c.generatePositionMonitoring(ctx, monitoringRequest, ohlcv.Data, ohlcv.DataInfo, summary)
+	if err != nil {
+		c.logger.Error("failed to monitor position", logrus.Fields{
+			"position_id": positionID,
+			"symbol":      position.StockCode,
+			"error":       err,
+		})
+		return nil, fmt.Errorf("failed to monitor position: %w", err)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal monitoring result: %w", err)
+	}
+
+	monitoring := &models.StockPositionMonitoringEntity{
+		UserID:          position.UserID,
+		StockPositionID: position.ID,
+		Signal:          result.Action,
+		ConfidenceScore: float64(result.ConfidenceLevel),
+		TechnicalScore:  float64(result.TechnicalScore),
+		NewsScore:       result.NewsSummary.ConfidenceScore,
+		Interval:        interval,
+		Range:           period,
+		Data:            data,
+	}
+	if err := c.stockPositionMonitoringRepository.Create(ctx, monitoring); err != nil {
+		c.logger.Error("failed to save stock position monitoring", logrus.Fields{
+			"position_id": positionID,
+			"error":       err,
+		})
+		return nil, fmt.Errorf("failed to save stock position monitoring: %w", err)
+	}
+
+	return result, nil
+}
+
+func analysisWindow(interval, period string) (string, string) {
+	if interval == "" {
+		interval = defaultAnalysisInterval
+	}
+	if period == "" {
+		period = defaultAnalysisRange
+	}
+	return interval, period
+}
+
+func (c *Client) fetchAnalysisInput(ctx context.Context, symbol, interval, period string) (*yahoo_finance.OHLCDataWithInfo, *models.StockNewsSummaryEntity, error) {
+	ohlcv, err := c.yahooClient.GetRecentOHLCData(ctx, symbol, interval, period)
+	if err != nil {
+		c.logger.Error("failed to get ohlcv data", logrus.Fields{
+			"symbol": symbol,
+			"error":  err,
+		})
+		return nil, nil, fmt.Errorf("failed to get ohlcv data: %w", err)
+	}
+
+	summary, err := c.stockNewsSummaryRepository.GetLast(ctx, c.marketCalendar.Now().Add(-newsSummaryMaxAge), symbol)
+	if err != nil {
+		// analisa tetap berjalan tanpa konteks berita
+		c.logger.Warn("failed to get news summary", logrus.Fields{
+			"symbol": symbol,
+			"error":  err,
+		})
+		summary = nil
+	}
+
+	return ohlcv, summary, nil
+}
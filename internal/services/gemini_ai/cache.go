@@ -0,0 +1,73 @@
+package gemini_ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/prompt"
+
+	"github.com/sirupsen/logrus"
+)
+
+const defaultAnalysisCacheTTL = 30 * time.Minute
+
+// analysisCacheKey mengidentifikasi input sebuah analisa. Key hanya berubah jika ada bar
+// harga baru, ringkasan berita baru atau versi prompt berbeda, sehingga request berulang
+// dengan data yang sama tidak memicu panggilan LLM baru.
+func analysisCacheKey(symbol, interval, period string, ohlcv []models.OHLCVData, summary *models.StockNewsSummaryEntity, version string) string {
+	var latestBar int64
+	if len(ohlcv) > 0 {
+		latestBar = ohlcv[len(ohlcv)-1].Timestamp
+	}
+	var summaryID uint
+	if summary != nil {
+		summaryID = summary.ID
+	}
+	return fmt.Sprintf("%s|%s|%s|%d|%d|%s", symbol, interval, period, latestBar, summaryID, version)
+}
+
+// individualAnalysisVersion memilih versi prompt per saham per hari agar eksperimen A/B
+// membagi saham secara konsisten.
+func (c *Client) individualAnalysisVersion(symbol string) string {
+	return c.prompts.SelectVersion(prompt.IndividualAnalysis, symbol+":"+c.marketCalendar.Now().Format("2006-01-02"))
+}
+
+// getCachedAnalysis mengembalikan analisa tersimpan dengan key yang sama dan belum lebih tua
+// dari AnalysisCacheTTL. Kegagalan membaca cache diperlakukan sebagai cache miss.
+func (c *Client) getCachedAnalysis(ctx context.Context, key string) *models.IndividualAnalysisResponseMultiTimeframe {
+	ttl := c.config.AnalysisCacheTTL
+	if ttl <= 0 {
+		ttl = defaultAnalysisCacheTTL
+	}
+
+	signal, err := c.stockSignalRepository.GetByCacheKey(ctx, key, c.marketCalendar.Now().Add(-ttl))
+	if err != nil {
+		c.logger.Warn("failed to get cached analysis", logrus.Fields{
+			"cache_key": key,
+			"error":     err,
+		})
+		return nil
+	}
+	if signal == nil {
+		return nil
+	}
+
+	var result models.IndividualAnalysisResponseMultiTimeframe
+	if err := json.Unmarshal(signal.Data, &result); err != nil {
+		c.logger.Warn("failed to decode cached analysis", logrus.Fields{
+			"cache_key": key,
+			"error":     err,
+		})
+		return nil
+	}
+
+	c.logger.Debug("reusing cached analysis", logrus.Fields{
+		"cache_key": key,
+		"signal_id": signal.ID,
+	})
+	result.Cached = true
+	return &result
+}
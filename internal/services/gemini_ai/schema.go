@@ -0,0 +1,100 @@
+package gemini_ai
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"golang-swing-trading-signal/internal/models"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SchemaFor membangun response schema Gemini dari struct Go berdasarkan tag json.
+// Field tanpa omitempty menjadi required, tag enum:"A,B" menjadi enum dan
+// field bertag schema:"-" tidak dimasukkan karena diisi oleh server.
+func SchemaFor(v any) *models.GeminiSchema {
+	return schemaForType(reflect.TypeOf(v))
+}
+
+func schemaForType(t reflect.Type) *models.GeminiSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &models.GeminiSchema{Type: "STRING", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &models.GeminiSchema{Type: "STRING"}
+	case reflect.Bool:
+		return &models.GeminiSchema{Type: "BOOLEAN"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &models.GeminiSchema{Type: "INTEGER"}
+	case reflect.Float32, reflect.Float64:
+		return &models.GeminiSchema{Type: "NUMBER"}
+	case reflect.Slice, reflect.Array:
+		return &models.GeminiSchema{Type: "ARRAY", Items: schemaForType(t.Elem())}
+	case reflect.Struct:
+		schema := &models.GeminiSchema{
+			Type:       "OBJECT",
+			Properties: make(map[string]*models.GeminiSchema),
+		}
+		for _, field := range schemaFields(t) {
+			fieldSchema := schemaForType(field.Type)
+			if field.enum != nil {
+				fieldSchema.Enum = field.enum
+			}
+			schema.Properties[field.name] = fieldSchema
+			schema.PropertyOrdering = append(schema.PropertyOrdering, field.name)
+			if !field.omitEmpty {
+				schema.Required = append(schema.Required, field.name)
+			}
+		}
+		return schema
+	default:
+		return &models.GeminiSchema{Type: "STRING"}
+	}
+}
+
+type schemaField struct {
+	reflect.StructField
+	name      string
+	omitEmpty bool
+	enum      []string
+}
+
+// schemaFields mengembalikan field struct yang ikut dalam schema sesuai urutan deklarasi.
+func schemaFields(t reflect.Type) []schemaField {
+	var fields []schemaField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("schema") == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		var enum []string
+		if tag := field.Tag.Get("enum"); tag != "" {
+			enum = strings.Split(tag, ",")
+		}
+
+		fields = append(fields, schemaField{
+			StructField: field,
+			name:        name,
+			omitEmpty:   strings.Contains(opts, "omitempty"),
+			enum:        enum,
+		})
+	}
+	return fields
+}
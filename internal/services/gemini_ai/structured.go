@@ -0,0 +1,158 @@
+package gemini_ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/llm"
+	"golang-swing-trading-signal/internal/services/prompt"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	individualAnalysisSchema = SchemaFor(models.IndividualAnalysisResponseMultiTimeframe{})
+	positionMonitoringSchema = SchemaFor(models.PositionMonitoringResponseMultiTimeframe{})
+)
+
+// generateStructured mengirim prompt dengan response schema lalu memanggil parse untuk
+// decode dan validasi hasilnya. Jika parse mengembalikan *ValidationError, Gemini diminta
+// memperbaiki jawabannya sampai MaxRepairAttempts kali.
+func (c *Client) generateStructured(ctx context.Context, name string, version string, userPrompt string, schema *models.GeminiSchema, parse func(text string) error) error {
+	messages := []llm.Message{
+		{Role: llm.RoleUser, Text: userPrompt},
+	}
+
+	maxAttempts := 1 + max(c.config.MaxRepairAttempts, 0)
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		text, err := c.sendRequest(ctx, name, version, messages, schema)
+		if err != nil {
+			return err
+		}
+
+		err = parse(text)
+		if err == nil {
+			return nil
+		}
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			return err
+		}
+		lastErr = err
+
+		c.logger.Warn("gemini response failed validation", logrus.Fields{
+			"attempt":      attempt,
+			"max_attempts": maxAttempts,
+			"error":        err,
+		})
+
+		messages = append(messages,
+			llm.Message{Role: llm.RoleModel, Text: text},
+			llm.Message{Role: llm.RoleUser, Text: buildRepairPrompt(validationErr)},
+		)
+	}
+
+	return fmt.Errorf("failed to get valid response after %d attempts: %w", maxAttempts, lastErr)
+}
+
+func buildRepairPrompt(validationErr *ValidationError) string {
+	var sb strings.Builder
+	sb.WriteString("Jawaban JSON sebelumnya tidak valid. Perbaiki kesalahan berikut:\n")
+	for _, fieldErr := range validationErr.Errors {
+		sb.WriteString(fmt.Sprintf("- %s: %s\n", fieldErr.Field, fieldErr.Message))
+	}
+	sb.WriteString("\nKirim ulang seluruh JSON yang sudah diperbaiki sesuai response schema, tanpa teks lain.")
+	return sb.String()
+}
+
+func (c *Client) generateIndividualAnalysis(
+	ctx context.Context,
+	symbol string,
+	version string,
+	ohlcvData []models.OHLCVData,
+	dataInfo models.DataInfo,
+	summary *models.StockNewsSummaryEntity,
+) (*models.IndividualAnalysisResponseMultiTimeframe, error) {
+	now := c.marketCalendar.Now()
+	text, err := c.prompts.Render(prompt.IndividualAnalysis, version, prompt.IndividualAnalysisData{
+		Symbol:      symbol,
+		Range:       dataInfo.Range,
+		OHLCV:       ohlcvData,
+		MarketPrice: dataInfo.MarketPrice,
+		NewsSummary: summary,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.IndividualAnalysisResponseMultiTimeframe
+	err = c.generateStructured(ctx, prompt.IndividualAnalysis, version, text, individualAnalysisSchema, func(text string) error {
+		result = models.IndividualAnalysisResponseMultiTimeframe{}
+		if err := decodeStrict(text, &result); err != nil {
+			return err
+		}
+		return validateIndividualAnalysis(&result, symbol, dataInfo.MarketPrice)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Symbol = symbol
+	result.MarketPrice = dataInfo.MarketPrice
+	result.AnalysisDate = now
+	result.PromptVersion = version
+	return &result, nil
+}
+
+func (c *Client) generatePositionMonitoring(
+	ctx context.Context,
+	request models.PositionMonitoringRequest,
+	ohlcvData []models.OHLCVData,
+	dataInfo models.DataInfo,
+	summary *models.StockNewsSummaryEntity,
+) (*models.PositionMonitoringResponseMultiTimeframe, error) {
+	// sisa holding dihitung dalam hari bursa (weekend & libur bursa tidak dihitung)
+	now := c.marketCalendar.Now()
+	version := c.prompts.SelectVersion(prompt.PositionMonitoring, request.Symbol)
+	text, err := c.prompts.Render(prompt.PositionMonitoring, version, prompt.PositionMonitoringData{
+		Symbol:               request.Symbol,
+		BuyPrice:             request.BuyPrice,
+		BuyTime:              request.BuyTime,
+		MaxHoldingPeriodDays: request.MaxHoldingPeriodDays,
+		PositionAgeDays:      c.marketCalendar.HoldingDays(request.BuyTime, now),
+		RemainingDays:        c.marketCalendar.RemainingHoldingDays(request.MaxHoldingPeriodDays, request.BuyTime, now),
+		Range:                dataInfo.Range,
+		OHLCV:                ohlcvData,
+		MarketPrice:          dataInfo.MarketPrice,
+		NewsSummary:          summary,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.PositionMonitoringResponseMultiTimeframe
+	err = c.generateStructured(ctx, prompt.PositionMonitoring, version, text, positionMonitoringSchema, func(text string) error {
+		result = models.PositionMonitoringResponseMultiTimeframe{}
+		if err := decodeStrict(text, &result); err != nil {
+			return err
+		}
+		return validatePositionMonitoring(&result, request.Symbol, dataInfo.MarketPrice)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Symbol = request.Symbol
+	result.MarketPrice = dataInfo.MarketPrice
+	result.AnalysisDate = now
+	result.PromptVersion = version
+	result.BuyPrice = request.BuyPrice
+	result.BuyDate = request.BuyTime
+	result.MaxHoldingPeriodDays = request.MaxHoldingPeriodDays
+	return &result, nil
+}
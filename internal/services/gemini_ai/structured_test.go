@@ -0,0 +1,201 @@
+package gemini_ai
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/llm"
+	"golang-swing-trading-signal/internal/services/market_calendar"
+	"golang-swing-trading-signal/internal/services/prompt"
+	"golang-swing-trading-signal/internal/services/yahoo_finance"
+	"golang-swing-trading-signal/internal/utils"
+	"golang-swing-trading-signal/pkg/ratelimit"
+
+	"github.com/sirupsen/logrus"
+)
+
+// scriptedProvider mengembalikan response sesuai urutan dan mencatat request yang diterima.
+type scriptedProvider struct {
+	responses []string
+	requests  []llm.Request
+}
+
+func (p *scriptedProvider) Name() string  { return "scripted" }
+func (p *scriptedProvider) Model() string { return "scripted" }
+func (p *scriptedProvider) CountTokens(ctx context.Context, messages []llm.Message) (int, error) {
+	return 10, nil
+}
+func (p *scriptedProvider) Generate(ctx context.Context, request llm.Request) (*llm.Response, error) {
+	p.requests = append(p.requests, request)
+	text := p.responses[0]
+	p.responses = p.responses[1:]
+	return &llm.Response{Text: text, FinishReason: llm.FinishReasonStop}, nil
+}
+func (p *scriptedProvider) GenerateStructured(ctx context.Context, request llm.Request, schema *models.GeminiSchema) (*llm.Response, error) {
+	return p.Generate(ctx, request)
+}
+
+func newTestClient(t *testing.T, provider llm.LLMProvider) *Client {
+	t.Helper()
+	calendar, err := market_calendar.NewCalendar(nil, logrus.New())
+	if err != nil {
+		t.Fatalf("NewCalendar() error = %v", err)
+	}
+	prompts, err := prompt.NewRegistry(nil)
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+	cfg := &config.GeminiConfig{MaxRequestPerMinute: 6000, MaxTokenPerMinute: 1_000_000, MaxRepairAttempts: 1}
+	return NewClient(cfg, logrus.New(), ratelimit.NewMemoryLimiter(), ratelimit.NewTokenLimiter(cfg.MaxTokenPerMinute), provider, prompts, nil, calendar, nil, nil, nil, nil, nil)
+}
+
+const validAnalysisJSON = `{
+	"symbol": "BBCA", "action": "BUY", "buy_price": 1000, "target_price": 1150, "cut_loss": 950,
+	"confidence_level": 80, "reasoning": "trend naik", "risk_reward_ratio": 3, "technical_score": 75,
+	"estimated_holding_days": 3,
+	"timeframe_analysis": {
+		"time_frame_1d": {"trend": "BULLISH", "key_signal": "x", "rsi": 60, "support": 900, "resistance": 1100},
+		"time_frame_4h": {"trend": "BULLISH", "key_signal": "x", "rsi": 60, "support": 900, "resistance": 1100},
+		"time_frame_1h": {"trend": "BULLISH", "key_signal": "x", "rsi": 60, "support": 900, "resistance": 1100}
+	}
+}`
+
+func TestClient_generateIndividualAnalysis(t *testing.T) {
+	tests := []struct {
+		name         string
+		responses    []string
+		wantErr      bool
+		wantRequests int
+	}{
+		{name: "valid on first attempt", responses: []string{validAnalysisJSON}, wantRequests: 1},
+		{name: "repaired on second attempt", responses: []string{strings.Replace(validAnalysisJSON, `"BUY"`, `"STRONG_BUY"`, 1), validAnalysisJSON}, wantRequests: 2},
+		{name: "still invalid after repair", responses: []string{`{"symbol": "BBCA"}`, `not json`}, wantErr: true, wantRequests: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &scriptedProvider{responses: tt.responses}
+			client := newTestClient(t, provider)
+
+			got, err := client.generateIndividualAnalysis(context.Background(), "BBCA", "v1", nil, models.DataInfo{MarketPrice: 1000}, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("generateIndividualAnalysis() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(provider.requests) != tt.wantRequests {
+				t.Errorf("provider called %d times, want %d", len(provider.requests), tt.wantRequests)
+			}
+			if tt.wantRequests > 1 {
+				repair := provider.requests[1].Messages
+				if len(repair) != 3 || repair[1].Role != llm.RoleModel || repair[2].Role != llm.RoleUser {
+					t.Errorf("repair request messages = %+v", repair)
+				}
+			}
+			if !tt.wantErr && (got.Action != "BUY" || got.MarketPrice != 1000) {
+				t.Errorf("generateIndividualAnalysis() = %+v", got)
+			}
+		})
+	}
+}
+
+// memorySignalRepository menyimpan stock signal di memori untuk pengujian cache analisa.
+type memorySignalRepository struct {
+	signals []models.StockSignalEntity
+}
+
+func (r *memorySignalRepository) GetLatestSignal(ctx context.Context, param models.GetStockBuySignalParam) ([]models.StockSignalEntity, error) {
+	return r.signals, nil
+}
+
+func (r *memorySignalRepository) Create(ctx context.Context, signal *models.StockSignalEntity, opts ...utils.DBOption) error {
+	signal.CreatedAt = time.Now()
+	r.signals = append(r.signals, *signal)
+	return nil
+}
+
+func (r *memorySignalRepository) GetByCacheKey(ctx context.Context, cacheKey string, after time.Time) (*models.StockSignalEntity, error) {
+	for i := len(r.signals) - 1; i >= 0; i-- {
+		if r.signals[i].CacheKey == cacheKey && !r.signals[i].CreatedAt.Before(after) {
+			return &r.signals[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memorySignalRepository) GetLatestByInput(ctx context.Context, stockCode, interval, period string, after time.Time) (*models.StockSignalEntity, error) {
+	return nil, nil
+}
+
+type unlimitedBudget struct{}
+
+func (unlimitedBudget) RecordUsage(ctx context.Context, usage llm.Usage) {}
+func (unlimitedBudget) CheckBudget(ctx context.Context) error            { return nil }
+func (unlimitedBudget) GetReport(ctx context.Context) (*models.LLMUsageReport, error) {
+	return nil, nil
+}
+
+func TestClient_analyzeStock_Cache(t *testing.T) {
+	provider := &scriptedProvider{responses: []string{validAnalysisJSON, validAnalysisJSON}}
+	client := newTestClient(t, provider)
+	repo := &memorySignalRepository{}
+	client.stockSignalRepository = repo
+	client.usageService = unlimitedBudget{}
+
+	bars := []models.OHLCVData{{Timestamp: 100, Close: 1000}}
+	ohlcv := &yahoo_finance.OHLCDataWithInfo{Data: bars, DataInfo: models.DataInfo{Range: "3m", MarketPrice: 1000}}
+
+	analyze := func(key string) *models.IndividualAnalysisResponseMultiTimeframe {
+		t.Helper()
+		got, err := client.analyzeStock(context.Background(), key, "BBCA", "1d", "3m", "v1", ohlcv, nil)
+		if err != nil {
+			t.Fatalf("analyzeStock() error = %v", err)
+		}
+		return got
+	}
+
+	key := analysisCacheKey("BBCA", "1d", "3m", bars, nil, "v1")
+	if first := analyze(key); first.Cached {
+		t.Error("first analyzeStock() should not be cached")
+	}
+	if second := analyze(key); !second.Cached || second.Action != "BUY" {
+		t.Errorf("second analyzeStock() = %+v, want cached BUY", second)
+	}
+	if len(provider.requests) != 1 {
+		t.Errorf("provider called %d times, want 1", len(provider.requests))
+	}
+
+	// bar baru menghasilkan key berbeda sehingga memicu panggilan LLM baru
+	newKey := analysisCacheKey("BBCA", "1d", "3m", append(bars, models.OHLCVData{Timestamp: 200}), nil, "v1")
+	if newKey == key {
+		t.Fatal("analysisCacheKey() should change when a new bar arrives")
+	}
+	analyze(newKey)
+	if len(provider.requests) != 2 || len(repo.signals) != 2 {
+		t.Errorf("provider called %d times and %d signals saved, want 2 and 2", len(provider.requests), len(repo.signals))
+	}
+}
+
+func TestAnalysisCacheKey(t *testing.T) {
+	bars := []models.OHLCVData{{Timestamp: 100}}
+	base := analysisCacheKey("BBCA", "1d", "3m", bars, &models.StockNewsSummaryEntity{ID: 7}, "v1")
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{name: "different interval", key: analysisCacheKey("BBCA", "1h", "3m", bars, &models.StockNewsSummaryEntity{ID: 7}, "v1")},
+		{name: "different range", key: analysisCacheKey("BBCA", "1d", "1m", bars, &models.StockNewsSummaryEntity{ID: 7}, "v1")},
+		{name: "new news summary", key: analysisCacheKey("BBCA", "1d", "3m", bars, &models.StockNewsSummaryEntity{ID: 8}, "v1")},
+		{name: "no news summary", key: analysisCacheKey("BBCA", "1d", "3m", bars, nil, "v1")},
+		{name: "different prompt version", key: analysisCacheKey("BBCA", "1d", "3m", bars, &models.StockNewsSummaryEntity{ID: 7}, "v2")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.key == base {
+				t.Errorf("analysisCacheKey() = %q, want different from base", tt.key)
+			}
+		})
+	}
+}
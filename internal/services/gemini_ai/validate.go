@@ -0,0 +1,225 @@
+package gemini_ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+
+	"golang-swing-trading-signal/internal/models"
+)
+
+const (
+	// buy price harus dekat dengan harga pasar saat analisa
+	maxBuyPriceDeviation = 0.10
+	// target dan cut loss tidak boleh terlalu jauh dari harga pasar untuk swing 1-5 hari
+	maxExitPriceDeviation = 0.50
+	// selisih risk/reward yang dilaporkan dengan hasil hitung ulang dari harga
+	riskRewardAbsTolerance = 0.15
+	riskRewardRelTolerance = 0.10
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError berisi seluruh pelanggaran validasi pada response Gemini dan
+// dipakai sebagai bahan repair prompt.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldErr := range e.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", fieldErr.Field, fieldErr.Message))
+	}
+	return "invalid gemini response: " + strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) add(field, format string, args ...any) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (e *ValidationError) errOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// decodeStrict men-decode JSON tanpa toleransi terhadap field yang tidak dikenal
+// atau data tambahan setelah object utama.
+func decodeStrict(text string, out any) error {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(strings.TrimSpace(text))))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(out); err != nil {
+		return &ValidationError{Errors: []FieldError{{Field: "$", Message: fmt.Sprintf("invalid json: %v", err)}}}
+	}
+	if decoder.More() {
+		return &ValidationError{Errors: []FieldError{{Field: "$", Message: "unexpected data after json object"}}}
+	}
+	return nil
+}
+
+// validateEnums memeriksa seluruh field bertag enum secara rekursif.
+func validateEnums(v reflect.Value, path string, verr *ValidationError) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return
+		}
+		for _, field := range schemaFields(v.Type()) {
+			fieldValue := v.FieldByIndex(field.Index)
+			fieldPath := joinPath(path, field.name)
+			if field.omitEmpty && fieldValue.IsZero() {
+				continue
+			}
+			if field.enum != nil && fieldValue.Kind() == reflect.String {
+				value := fieldValue.String()
+				if !slices.Contains(field.enum, value) {
+					verr.add(fieldPath, "must be one of %s, got %q", strings.Join(field.enum, "|"), value)
+				}
+				continue
+			}
+			validateEnums(fieldValue, fieldPath, verr)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateEnums(v.Index(i), fmt.Sprintf("%s[%d]", path, i), verr)
+		}
+	}
+}
+
+func joinPath(parent, child string) string {
+	if parent == "" {
+		return child
+	}
+	return parent + "." + child
+}
+
+func validateRange(verr *ValidationError, field string, value, min, max int) {
+	if value < min || value > max {
+		verr.add(field, "must be between %d and %d, got %d", min, max, value)
+	}
+}
+
+func validatePriceNear(verr *ValidationError, field string, price, marketPrice, maxDeviation float64) {
+	if price <= 0 {
+		verr.add(field, "must be greater than 0, got %v", price)
+		return
+	}
+	if marketPrice <= 0 {
+		return
+	}
+	if deviation := math.Abs(price-marketPrice) / marketPrice; deviation > maxDeviation {
+		verr.add(field, "%.0f is %.1f%% away from market price %.0f (max %.0f%%)", price, deviation*100, marketPrice, maxDeviation*100)
+	}
+}
+
+// validateRiskReward memastikan rasio yang dilaporkan konsisten dengan (target-entry)/(entry-stop).
+func validateRiskReward(verr *ValidationError, field string, reported, entry, target, stop float64) {
+	if entry <= stop || target <= entry {
+		return
+	}
+	expected := (target - entry) / (entry - stop)
+	tolerance := math.Max(riskRewardAbsTolerance, expected*riskRewardRelTolerance)
+	if math.Abs(reported-expected) > tolerance {
+		verr.add(field, "reported %.2f but prices imply %.2f", reported, expected)
+	}
+}
+
+func validateTimeframes(verr *ValidationError, analysis models.TimeframeAnalysis) {
+	timeframes := []struct {
+		path string
+		data models.TimeframeAnalysisData
+	}{
+		{"timeframe_analysis.time_frame_1d", analysis.Timeframe1D},
+		{"timeframe_analysis.time_frame_4h", analysis.Timeframe4H},
+		{"timeframe_analysis.time_frame_1h", analysis.Timeframe1H},
+	}
+	for _, timeframe := range timeframes {
+		path, data := timeframe.path, timeframe.data
+		validateRange(verr, path+".rsi", data.RSI, 0, 100)
+		if data.Support <= 0 || data.Resistance <= 0 {
+			verr.add(path, "support and resistance must be greater than 0")
+		} else if data.Support >= data.Resistance {
+			verr.add(path, "support %.0f must be below resistance %.0f", data.Support, data.Resistance)
+		}
+	}
+}
+
+func validateNewsSummary(verr *ValidationError, summary models.NewsSummary) {
+	if summary.ConfidenceScore < 0 || summary.ConfidenceScore > 1 {
+		verr.add("news_summary.confidence_score", "must be between 0 and 1, got %v", summary.ConfidenceScore)
+	}
+}
+
+// validateIndividualAnalysis memvalidasi hasil analisa saham terhadap harga pasar saat ini.
+func validateIndividualAnalysis(resp *models.IndividualAnalysisResponseMultiTimeframe, symbol string, marketPrice float64) error {
+	verr := &ValidationError{}
+	validateEnums(reflect.ValueOf(resp), "", verr)
+
+	if !strings.EqualFold(resp.Symbol, symbol) {
+		verr.add("symbol", "must be %q, got %q", symbol, resp.Symbol)
+	}
+	validateRange(verr, "confidence_level", resp.ConfidenceLevel, 0, 100)
+	validateRange(verr, "technical_score", resp.TechnicalScore, 0, 100)
+	if strings.TrimSpace(resp.Reasoning) == "" {
+		verr.add("reasoning", "must not be empty")
+	}
+
+	if resp.Action == "BUY" {
+		validateRange(verr, "estimated_holding_days", resp.EstimatedHoldingDays, 1, 5)
+		validatePriceNear(verr, "buy_price", resp.BuyPrice, marketPrice, maxBuyPriceDeviation)
+		validatePriceNear(verr, "target_price", resp.TargetPrice, marketPrice, maxExitPriceDeviation)
+		validatePriceNear(verr, "cut_loss", resp.CutLoss, marketPrice, maxExitPriceDeviation)
+		if resp.CutLoss >= resp.BuyPrice || resp.BuyPrice >= resp.TargetPrice {
+			verr.add("recommendation", "BUY requires cut_loss < buy_price < target_price, got %.0f / %.0f / %.0f", resp.CutLoss, resp.BuyPrice, resp.TargetPrice)
+		}
+		validateRiskReward(verr, "risk_reward_ratio", resp.RiskRewardRatio, resp.BuyPrice, resp.TargetPrice, resp.CutLoss)
+	}
+
+	validateTimeframes(verr, resp.TimeframeAnalysis)
+	validateNewsSummary(verr, resp.NewsSummary)
+
+	return verr.errOrNil()
+}
+
+// validatePositionMonitoring memvalidasi hasil monitoring posisi terhadap harga pasar saat ini.
+func validatePositionMonitoring(resp *models.PositionMonitoringResponseMultiTimeframe, symbol string, marketPrice float64) error {
+	verr := &ValidationError{}
+	validateEnums(reflect.ValueOf(resp), "", verr)
+
+	if !strings.EqualFold(resp.Symbol, symbol) {
+		verr.add("symbol", "must be %q, got %q", symbol, resp.Symbol)
+	}
+	validateRange(verr, "confidence_level", resp.ConfidenceLevel, 0, 100)
+	validateRange(verr, "technical_score", resp.TechnicalScore, 0, 100)
+	if strings.TrimSpace(resp.Reasoning) == "" {
+		verr.add("reasoning", "must not be empty")
+	}
+
+	validatePriceNear(verr, "exit_target_price", resp.ExitTargetPrice, marketPrice, maxExitPriceDeviation)
+	validatePriceNear(verr, "exit_cut_loss_price", resp.ExitCutLossPrice, marketPrice, maxExitPriceDeviation)
+	if resp.ExitCutLossPrice >= resp.ExitTargetPrice {
+		verr.add("exit_cut_loss_price", "must be below exit_target_price, got %.0f >= %.0f", resp.ExitCutLossPrice, resp.ExitTargetPrice)
+	}
+	validateRiskReward(verr, "exit_risk_reward_ratio", resp.ExitRiskRewardRatio, marketPrice, resp.ExitTargetPrice, resp.ExitCutLossPrice)
+
+	validateTimeframes(verr, resp.TimeframeAnalysis)
+	validateNewsSummary(verr, resp.NewsSummary)
+
+	return verr.errOrNil()
+}
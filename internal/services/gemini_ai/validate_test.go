@@ -0,0 +1,177 @@
+package gemini_ai
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"golang-swing-trading-signal/internal/models"
+)
+
+func TestSchemaFor(t *testing.T) {
+	schema := SchemaFor(models.IndividualAnalysisResponseMultiTimeframe{})
+
+	if schema.Type != "OBJECT" {
+		t.Fatalf("SchemaFor() type = %v, want OBJECT", schema.Type)
+	}
+	if _, ok := schema.Properties["market_price"]; ok {
+		t.Errorf("SchemaFor() should skip fields tagged schema:\"-\"")
+	}
+	if got := schema.Properties["action"].Enum; !slices.Equal(got, []string{"BUY", "HOLD"}) {
+		t.Errorf("SchemaFor() action enum = %v", got)
+	}
+	if !slices.Contains(schema.Required, "reasoning") || slices.Contains(schema.Required, "buy_price") {
+		t.Errorf("SchemaFor() required = %v", schema.Required)
+	}
+	timeframe := schema.Properties["timeframe_analysis"].Properties["time_frame_1d"]
+	if timeframe.Properties["rsi"].Type != "INTEGER" || timeframe.Properties["support"].Type != "NUMBER" {
+		t.Errorf("SchemaFor() nested timeframe schema = %+v", timeframe)
+	}
+}
+
+func TestSchemaFor_PositionMonitoringRequiresValidatedFields(t *testing.T) {
+	schema := SchemaFor(models.PositionMonitoringResponseMultiTimeframe{})
+
+	// field yang diwajibkan validatePositionMonitoring harus required di schema, kalau tidak
+	// setiap response yang menghilangkannya memicu repair
+	for _, field := range []string{"exit_target_price", "exit_cut_loss_price", "exit_risk_reward_ratio", "reasoning"} {
+		if !slices.Contains(schema.Required, field) {
+			t.Errorf("SchemaFor() required = %v, missing %s", schema.Required, field)
+		}
+	}
+}
+
+func validTimeframes() models.TimeframeAnalysis {
+	data := models.TimeframeAnalysisData{Trend: "BULLISH", KeySignal: "EMA cross", RSI: 60, Support: 900, Resistance: 1100}
+	return models.TimeframeAnalysis{Timeframe1D: data, Timeframe4H: data, Timeframe1H: data}
+}
+
+func TestValidateIndividualAnalysis(t *testing.T) {
+	valid := func() models.IndividualAnalysisResponseMultiTimeframe {
+		return models.IndividualAnalysisResponseMultiTimeframe{
+			Symbol:               "BBCA",
+			Action:               "BUY",
+			BuyPrice:             1000,
+			TargetPrice:          1150,
+			CutLoss:              950,
+			RiskRewardRatio:      3,
+			ConfidenceLevel:      80,
+			TechnicalScore:       75,
+			Reasoning:            "trend naik",
+			EstimatedHoldingDays: 3,
+			TimeframeAnalysis:    validTimeframes(),
+		}
+	}
+
+	tests := []struct {
+		name      string
+		mutate    func(resp *models.IndividualAnalysisResponseMultiTimeframe)
+		wantField string
+	}{
+		{name: "valid buy", mutate: func(resp *models.IndividualAnalysisResponseMultiTimeframe) {}},
+		{name: "valid hold without prices", mutate: func(resp *models.IndividualAnalysisResponseMultiTimeframe) {
+			resp.Action, resp.BuyPrice, resp.TargetPrice, resp.CutLoss, resp.RiskRewardRatio = "HOLD", 0, 0, 0, 0
+		}},
+		{name: "unknown action", wantField: "action", mutate: func(resp *models.IndividualAnalysisResponseMultiTimeframe) {
+			resp.Action = "STRONG_BUY"
+		}},
+		{name: "buy price far from market", wantField: "buy_price", mutate: func(resp *models.IndividualAnalysisResponseMultiTimeframe) {
+			resp.BuyPrice, resp.TargetPrice, resp.CutLoss = 1300, 1450, 1250
+		}},
+		{name: "inconsistent risk reward", wantField: "risk_reward_ratio", mutate: func(resp *models.IndividualAnalysisResponseMultiTimeframe) {
+			resp.RiskRewardRatio = 5
+		}},
+		{name: "cut loss above buy", wantField: "recommendation", mutate: func(resp *models.IndividualAnalysisResponseMultiTimeframe) {
+			resp.CutLoss = 1050
+		}},
+		{name: "wrong symbol", wantField: "symbol", mutate: func(resp *models.IndividualAnalysisResponseMultiTimeframe) {
+			resp.Symbol = "BBRI"
+		}},
+		{name: "invalid news sentiment", wantField: "news_summary.sentiment", mutate: func(resp *models.IndividualAnalysisResponseMultiTimeframe) {
+			resp.NewsSummary = models.NewsSummary{Sentiment: "great", Impact: "bullish", ConfidenceScore: 0.5}
+		}},
+		{name: "support above resistance", wantField: "timeframe_analysis.time_frame_4h", mutate: func(resp *models.IndividualAnalysisResponseMultiTimeframe) {
+			resp.TimeframeAnalysis.Timeframe4H.Support = 1200
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := valid()
+			tt.mutate(&resp)
+			err := validateIndividualAnalysis(&resp, "BBCA", 1000)
+			assertValidationField(t, err, tt.wantField)
+		})
+	}
+}
+
+func TestValidatePositionMonitoring(t *testing.T) {
+	valid := func() models.PositionMonitoringResponseMultiTimeframe {
+		return models.PositionMonitoringResponseMultiTimeframe{
+			Symbol:              "BBCA",
+			Action:              "HOLD",
+			ExitTargetPrice:     1150,
+			ExitCutLossPrice:    950,
+			ExitRiskRewardRatio: 3,
+			ConfidenceLevel:     70,
+			TechnicalScore:      65,
+			Reasoning:           "masih dalam tren",
+			TimeframeAnalysis:   validTimeframes(),
+		}
+	}
+
+	tests := []struct {
+		name      string
+		mutate    func(resp *models.PositionMonitoringResponseMultiTimeframe)
+		wantField string
+	}{
+		{name: "valid", mutate: func(resp *models.PositionMonitoringResponseMultiTimeframe) {}},
+		{name: "unknown action", wantField: "action", mutate: func(resp *models.PositionMonitoringResponseMultiTimeframe) {
+			resp.Action = "SELL_ALL"
+		}},
+		{name: "exit prices inverted", wantField: "exit_cut_loss_price", mutate: func(resp *models.PositionMonitoringResponseMultiTimeframe) {
+			resp.ExitCutLossPrice, resp.ExitTargetPrice = 1150, 950
+		}},
+		{name: "confidence out of range", wantField: "confidence_level", mutate: func(resp *models.PositionMonitoringResponseMultiTimeframe) {
+			resp.ConfidenceLevel = 150
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := valid()
+			tt.mutate(&resp)
+			err := validatePositionMonitoring(&resp, "BBCA", 1000)
+			assertValidationField(t, err, tt.wantField)
+		})
+	}
+}
+
+func TestDecodeStrict(t *testing.T) {
+	var resp models.PositionMonitoringResponseMultiTimeframe
+	err := decodeStrict(`{"symbol": "BBCA", "unknown_field": 1}`, &resp)
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("decodeStrict() error = %v, want *ValidationError", err)
+	}
+}
+
+func assertValidationField(t *testing.T, err error, wantField string) {
+	t.Helper()
+	if wantField == "" {
+		if err != nil {
+			t.Fatalf("unexpected error = %v", err)
+		}
+		return
+	}
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("error = %v, want *ValidationError on %s", err, wantField)
+	}
+	for _, fieldErr := range validationErr.Errors {
+		if fieldErr.Field == wantField {
+			return
+		}
+	}
+	t.Errorf("error = %v, want violation on %s", err, wantField)
+}
@@ -0,0 +1,184 @@
+package groups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/repository"
+	"golang-swing-trading-signal/internal/services/quota"
+	"golang-swing-trading-signal/internal/services/symbols"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrGroupNotFound      = errors.New("group not found")
+	ErrInvalidStockCode   = errors.New("invalid stock code")
+	ErrAlreadyInWatchlist = errors.New("stock already in watchlist")
+	ErrNotInWatchlist     = errors.New("stock not in watchlist")
+)
+
+type GroupService interface {
+	// Register mengaktifkan bot di grup. Grup yang pernah terdaftar tetap memakai pengaturan lamanya.
+	Register(ctx context.Context, chatID int64, title string, addedBy int64, language string) (*models.TelegramGroupEntity, error)
+	// Deactivate dipanggil saat bot dikeluarkan dari grup.
+	Deactivate(ctx context.Context, chatID int64) error
+	GetGroup(ctx context.Context, chatID int64) (*models.TelegramGroupEntity, error)
+	SetSignalAlerts(ctx context.Context, chatID int64, enabled bool) error
+	GetWatchlist(ctx context.Context, chatID int64) ([]string, error)
+	// AddToWatchlist memakai kuota watchlist milik user yang mendaftarkan grup. Kode yang tidak
+	// terdaftar di tabel stocks dikembalikan sebagai *symbols.UnknownSymbolError.
+	AddToWatchlist(ctx context.Context, chatID int64, addedBy int64, stockCode string) error
+	RemoveFromWatchlist(ctx context.Context, chatID int64, stockCode string) error
+	// GetGroupsWatching mengembalikan grup yang harus menerima sinyal untuk stockCode.
+	GetGroupsWatching(ctx context.Context, stockCode string) ([]models.TelegramGroupEntity, error)
+}
+
+type groupService struct {
+	logger          *logrus.Logger
+	groupRepository repository.GroupRepository
+	quotaService    quota.QuotaService
+	symbolResolver  symbols.SymbolResolver
+	now             func() time.Time
+}
+
+func NewGroupService(logger *logrus.Logger, groupRepository repository.GroupRepository, quotaService quota.QuotaService, symbolResolver symbols.SymbolResolver) GroupService {
+	return &groupService{
+		logger:          logger,
+		groupRepository: groupRepository,
+		quotaService:    quotaService,
+		symbolResolver:  symbolResolver,
+		now:             utils.TimeNowWIB,
+	}
+}
+
+// NormalizeStockCode mengubah kode saham ke huruf besar tanpa akhiran .JK dan memastikan
+// formatnya kode saham BEI (4 huruf).
+func NormalizeStockCode(value string) (string, error) {
+	code, err := symbols.Normalize(value)
+	if err != nil {
+		return "", ErrInvalidStockCode
+	}
+	return code, nil
+}
+
+func (s *groupService) Register(ctx context.Context, chatID int64, title string, addedBy int64, language string) (*models.TelegramGroupEntity, error) {
+	now := s.now()
+	group := &models.TelegramGroupEntity{
+		ChatID:       chatID,
+		Title:        title,
+		AddedBy:      addedBy,
+		Language:     language,
+		SignalAlerts: true,
+		IsActive:     true,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.groupRepository.UpsertGroup(ctx, group); err != nil {
+		return nil, fmt.Errorf("failed to upsert group: %w", err)
+	}
+	return s.GetGroup(ctx, chatID)
+}
+
+func (s *groupService) Deactivate(ctx context.Context, chatID int64) error {
+	err := s.groupRepository.UpdateGroup(ctx, chatID, map[string]any{
+		"is_active":  false,
+		"updated_at": s.now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to deactivate group: %w", err)
+	}
+	return nil
+}
+
+func (s *groupService) GetGroup(ctx context.Context, chatID int64) (*models.TelegramGroupEntity, error) {
+	group, err := s.groupRepository.GetGroup(ctx, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+	if group == nil {
+		return nil, ErrGroupNotFound
+	}
+	return group, nil
+}
+
+func (s *groupService) SetSignalAlerts(ctx context.Context, chatID int64, enabled bool) error {
+	err := s.groupRepository.UpdateGroup(ctx, chatID, map[string]any{
+		"signal_alerts": enabled,
+		"updated_at":    s.now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update group signal alerts: %w", err)
+	}
+	return nil
+}
+
+func (s *groupService) GetWatchlist(ctx context.Context, chatID int64) ([]string, error) {
+	watchlist, err := s.groupRepository.GetWatchlist(ctx, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group watchlist: %w", err)
+	}
+	codes := make([]string, 0, len(watchlist))
+	for _, item := range watchlist {
+		codes = append(codes, item.StockCode)
+	}
+	return codes, nil
+}
+
+func (s *groupService) AddToWatchlist(ctx context.Context, chatID int64, addedBy int64, stockCode string) error {
+	code, err := NormalizeStockCode(stockCode)
+	if err != nil {
+		return err
+	}
+	group, err := s.GetGroup(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	if err := s.quotaService.CheckWatchlist(ctx, group.AddedBy); err != nil {
+		return err
+	}
+	if _, err := s.symbolResolver.Resolve(ctx, code); err != nil {
+		return err
+	}
+
+	added, err := s.groupRepository.AddWatchlist(ctx, &models.GroupWatchlistEntity{
+		ChatID:    chatID,
+		StockCode: code,
+		AddedBy:   addedBy,
+		CreatedAt: s.now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add group watchlist: %w", err)
+	}
+	if !added {
+		return ErrAlreadyInWatchlist
+	}
+	return nil
+}
+
+func (s *groupService) RemoveFromWatchlist(ctx context.Context, chatID int64, stockCode string) error {
+	code, err := NormalizeStockCode(stockCode)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.groupRepository.DeleteWatchlist(ctx, chatID, code)
+	if err != nil {
+		return fmt.Errorf("failed to delete group watchlist: %w", err)
+	}
+	if !deleted {
+		return ErrNotInWatchlist
+	}
+	return nil
+}
+
+func (s *groupService) GetGroupsWatching(ctx context.Context, stockCode string) ([]models.TelegramGroupEntity, error) {
+	groups, err := s.groupRepository.GetGroupsWatching(ctx, stockCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups watching %s: %w", stockCode, err)
+	}
+	return groups, nil
+}
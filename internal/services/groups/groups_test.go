@@ -0,0 +1,162 @@
+package groups
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/repository"
+	"golang-swing-trading-signal/internal/services/quota"
+	"golang-swing-trading-signal/internal/services/symbols"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestNormalizeStockCode(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "BBCA", want: "BBCA"},
+		{value: " bbri ", want: "BBRI"},
+		{value: "tlkm.jk", want: "TLKM"},
+		{value: "BBC", wantErr: true},
+		{value: "BBCA1", wantErr: true},
+		{value: "BB1A", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := NormalizeStockCode(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeStockCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeStockCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeGroupRepository struct {
+	repository.GroupRepository
+	groups    map[int64]*models.TelegramGroupEntity
+	watchlist map[int64]map[string]bool
+}
+
+func (r *fakeGroupRepository) GetGroup(ctx context.Context, chatID int64, opts ...utils.DBOption) (*models.TelegramGroupEntity, error) {
+	return r.groups[chatID], nil
+}
+
+func (r *fakeGroupRepository) AddWatchlist(ctx context.Context, item *models.GroupWatchlistEntity, opts ...utils.DBOption) (bool, error) {
+	if r.watchlist[item.ChatID] == nil {
+		r.watchlist[item.ChatID] = map[string]bool{}
+	}
+	if r.watchlist[item.ChatID][item.StockCode] {
+		return false, nil
+	}
+	r.watchlist[item.ChatID][item.StockCode] = true
+	return true, nil
+}
+
+func (r *fakeGroupRepository) DeleteWatchlist(ctx context.Context, chatID int64, stockCode string, opts ...utils.DBOption) (bool, error) {
+	if !r.watchlist[chatID][stockCode] {
+		return false, nil
+	}
+	delete(r.watchlist[chatID], stockCode)
+	return true, nil
+}
+
+// fakeQuotaService menolak penambahan watchlist untuk user di exceeded.
+type fakeQuotaService struct {
+	quota.QuotaService
+	exceeded map[int64]bool
+}
+
+func (s *fakeQuotaService) CheckWatchlist(ctx context.Context, telegramID int64) error {
+	if s.exceeded[telegramID] {
+		return &quota.QuotaExceededError{Kind: models.QuotaWatchlist, Plan: models.PlanFree, Limit: 3}
+	}
+	return nil
+}
+
+// fakeSymbolResolver hanya mengenal kode saham di known.
+type fakeSymbolResolver struct {
+	symbols.SymbolResolver
+	known map[string]bool
+}
+
+func (r *fakeSymbolResolver) Resolve(ctx context.Context, input string) (*models.StockEntity, error) {
+	if !r.known[input] {
+		return nil, &symbols.UnknownSymbolError{Input: input}
+	}
+	return &models.StockEntity{Code: input}, nil
+}
+
+func newTestService() *groupService {
+	return &groupService{
+		logger: logrus.New(),
+		groupRepository: &fakeGroupRepository{
+			groups: map[int64]*models.TelegramGroupEntity{
+				-100: {ChatID: -100, AddedBy: 1, IsActive: true},
+				-200: {ChatID: -200, AddedBy: 2, IsActive: true},
+			},
+			watchlist: map[int64]map[string]bool{-100: {"BBCA": true}},
+		},
+		quotaService:   &fakeQuotaService{exceeded: map[int64]bool{2: true}},
+		symbolResolver: &fakeSymbolResolver{known: map[string]bool{"BBCA": true, "BBRI": true}},
+		now:            utils.TimeNowWIB,
+	}
+}
+
+func TestGroupService_AddToWatchlist(t *testing.T) {
+	tests := []struct {
+		name      string
+		chatID    int64
+		stockCode string
+		wantErr   error
+	}{
+		{name: "new stock", chatID: -100, stockCode: "bbri"},
+		{name: "duplicate stock", chatID: -100, stockCode: "BBCA", wantErr: ErrAlreadyInWatchlist},
+		{name: "invalid code", chatID: -100, stockCode: "BB", wantErr: ErrInvalidStockCode},
+		{name: "unlisted code", chatID: -100, stockCode: "BBCS", wantErr: symbols.ErrUnknownSymbol},
+		{name: "unknown group", chatID: -300, stockCode: "BBRI", wantErr: ErrGroupNotFound},
+		{name: "owner quota exceeded", chatID: -200, stockCode: "BBRI", wantErr: quota.ErrQuotaExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService()
+			err := s.AddToWatchlist(context.Background(), tt.chatID, 3, tt.stockCode)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("AddToWatchlist() unexpected error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("AddToWatchlist() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGroupService_RemoveFromWatchlist(t *testing.T) {
+	tests := []struct {
+		name      string
+		stockCode string
+		wantErr   error
+	}{
+		{name: "existing stock", stockCode: "bbca"},
+		{name: "missing stock", stockCode: "BBRI", wantErr: ErrNotInWatchlist},
+		{name: "invalid code", stockCode: "12", wantErr: ErrInvalidStockCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService()
+			err := s.RemoveFromWatchlist(context.Background(), -100, tt.stockCode)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RemoveFromWatchlist() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
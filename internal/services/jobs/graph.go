@@ -0,0 +1,121 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"golang-swing-trading-signal/internal/models"
+)
+
+var ErrDependencyCycle = errors.New("job dependency cycle")
+
+// Graph adalah DAG dependency antar job dari tabel job_dependencies.
+type Graph struct {
+	dependents   map[uint][]uint // job -> job yang menunggu job ini
+	dependencies map[uint][]uint // job -> job yang harus selesai lebih dulu
+}
+
+func NewGraph(dependencies []models.JobDependencyEntity) *Graph {
+	graph := &Graph{
+		dependents:   make(map[uint][]uint),
+		dependencies: make(map[uint][]uint),
+	}
+	for _, dependency := range dependencies {
+		graph.dependents[dependency.DependsOnJobID] = append(graph.dependents[dependency.DependsOnJobID], dependency.JobID)
+		graph.dependencies[dependency.JobID] = append(graph.dependencies[dependency.JobID], dependency.DependsOnJobID)
+	}
+	for _, ids := range graph.dependents {
+		slices.Sort(ids)
+	}
+	for _, ids := range graph.dependencies {
+		slices.Sort(ids)
+	}
+	return graph
+}
+
+// Dependents mengembalikan job yang langsung bergantung pada jobID.
+func (g *Graph) Dependents(jobID uint) []uint {
+	return g.dependents[jobID]
+}
+
+// Dependencies mengembalikan job yang harus berhasil sebelum jobID dijalankan.
+func (g *Graph) Dependencies(jobID uint) []uint {
+	return g.dependencies[jobID]
+}
+
+// IsPipeline bernilai true jika ada job yang menunggu jobID.
+func (g *Graph) IsPipeline(jobID uint) bool {
+	return len(g.dependents[jobID]) > 0
+}
+
+// Stages mengembalikan root dan semua job turunannya dalam urutan topologis. Dependency ke
+// job di luar turunan root diabaikan, karena job tersebut tidak pernah berjalan di pipeline
+// run yang sama.
+func (g *Graph) Stages(root uint) ([]uint, error) {
+	reachable := map[uint]bool{root: true}
+	queue := []uint{root}
+	for len(queue) > 0 {
+		jobID := queue[0]
+		queue = queue[1:]
+		for _, dependent := range g.dependents[jobID] {
+			if !reachable[dependent] {
+				reachable[dependent] = true
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	// Kahn's algorithm, hanya menghitung edge di dalam subgraph turunan root
+	inDegree := make(map[uint]int, len(reachable))
+	for jobID := range reachable {
+		for _, dependency := range g.dependencies[jobID] {
+			if reachable[dependency] {
+				inDegree[jobID]++
+			}
+		}
+	}
+	if inDegree[root] > 0 {
+		return nil, fmt.Errorf("%w: job %d depends on its own dependents", ErrDependencyCycle, root)
+	}
+
+	stages := make([]uint, 0, len(reachable))
+	ready := []uint{root}
+	for len(ready) > 0 {
+		jobID := ready[0]
+		ready = ready[1:]
+		stages = append(stages, jobID)
+		for _, dependent := range g.dependents[jobID] {
+			inDegree[dependent]--
+			if inDegree[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	if len(stages) != len(reachable) {
+		return nil, fmt.Errorf("%w: reachable from job %d", ErrDependencyCycle, root)
+	}
+	return stages, nil
+}
+
+// Ready mengembalikan dependents dari jobID yang semua dependency-nya (di dalam stages)
+// sudah ada di completed.
+func (g *Graph) Ready(jobID uint, stages []uint, completed map[uint]bool) []uint {
+	var ready []uint
+	for _, dependent := range g.dependents[jobID] {
+		if completed[dependent] {
+			continue
+		}
+		allDone := true
+		for _, dependency := range g.dependencies[dependent] {
+			if slices.Contains(stages, dependency) && !completed[dependency] {
+				allDone = false
+				break
+			}
+		}
+		if allDone {
+			ready = append(ready, dependent)
+		}
+	}
+	return ready
+}
@@ -0,0 +1,88 @@
+package jobs
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"golang-swing-trading-signal/internal/models"
+)
+
+// dependency membuat edge "job bergantung pada dependsOn".
+func dependency(job, dependsOn uint) models.JobDependencyEntity {
+	return models.JobDependencyEntity{JobID: job, DependsOnJobID: dependsOn}
+}
+
+func TestGraph_Stages(t *testing.T) {
+	tests := []struct {
+		name         string
+		dependencies []models.JobDependencyEntity
+		root         uint
+		want         []uint
+		wantErr      error
+	}{
+		{name: "single job", root: 1, want: []uint{1}},
+		{
+			name:         "chain",
+			dependencies: []models.JobDependencyEntity{dependency(2, 1), dependency(3, 2), dependency(4, 3)},
+			root:         1,
+			want:         []uint{1, 2, 3, 4},
+		},
+		{
+			name:         "fan-in waits for both branches",
+			dependencies: []models.JobDependencyEntity{dependency(2, 1), dependency(3, 1), dependency(4, 2), dependency(4, 3)},
+			root:         1,
+			want:         []uint{1, 2, 3, 4},
+		},
+		{
+			name:         "dependency outside pipeline ignored",
+			dependencies: []models.JobDependencyEntity{dependency(2, 1), dependency(2, 9)},
+			root:         1,
+			want:         []uint{1, 2},
+		},
+		{
+			name:         "sub pipeline from middle",
+			dependencies: []models.JobDependencyEntity{dependency(2, 1), dependency(3, 2)},
+			root:         2,
+			want:         []uint{2, 3},
+		},
+		{
+			name:         "cycle",
+			dependencies: []models.JobDependencyEntity{dependency(2, 1), dependency(3, 2), dependency(2, 3)},
+			root:         1,
+			wantErr:      ErrDependencyCycle,
+		},
+		{
+			name:         "cycle through root",
+			dependencies: []models.JobDependencyEntity{dependency(2, 1), dependency(1, 2)},
+			root:         1,
+			wantErr:      ErrDependencyCycle,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewGraph(tt.dependencies).Stages(tt.root)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Stages() error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Stages() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraph_Ready(t *testing.T) {
+	graph := NewGraph([]models.JobDependencyEntity{dependency(2, 1), dependency(3, 1), dependency(4, 2), dependency(4, 3)})
+	stages := []uint{1, 2, 3, 4}
+
+	if got := graph.Ready(1, stages, map[uint]bool{1: true}); !slices.Equal(got, []uint{2, 3}) {
+		t.Errorf("Ready(1) = %v, want [2 3]", got)
+	}
+	if got := graph.Ready(2, stages, map[uint]bool{1: true, 2: true}); len(got) != 0 {
+		t.Errorf("Ready(2) before 3 completes = %v, want none", got)
+	}
+	if got := graph.Ready(3, stages, map[uint]bool{1: true, 2: true, 3: true}); !slices.Equal(got, []uint{4}) {
+		t.Errorf("Ready(3) = %v, want [4]", got)
+	}
+}
@@ -2,11 +2,14 @@ package jobs
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"golang-swing-trading-signal/internal/config"
 	"golang-swing-trading-signal/internal/models"
 	"golang-swing-trading-signal/internal/repository"
 	"golang-swing-trading-signal/internal/utils"
+	"golang-swing-trading-signal/pkg/cron"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -14,19 +17,42 @@ import (
 type JobService interface {
 	Get(ctx context.Context, param *models.GetJobParam, opts ...utils.DBOption) ([]models.JobEntity, error)
 	RunJobTask(ctx context.Context, jobID uint, opts ...utils.DBOption) error
+	// SetActive menjeda (false) atau melanjutkan (true) schedule job. Saat dilanjutkan,
+	// next_execution dihitung ulang dari sekarang sehingga jadwal yang terlewat tidak dijalankan.
+	SetActive(ctx context.Context, jobID uint, isActive bool, opts ...utils.DBOption) error
+	// PreviewSchedule memvalidasi cron expression dan mengembalikan n waktu eksekusi berikutnya.
+	PreviewSchedule(expression string, n int) ([]time.Time, error)
+	UpdateCronExpression(ctx context.Context, jobID uint, expression string, opts ...utils.DBOption) error
+	GetHistories(ctx context.Context, param *models.GetTaskExecutionHistoryParam, opts ...utils.DBOption) ([]models.TaskExecutionHistoryEntity, int64, error)
+	GetHistory(ctx context.Context, historyID uint, opts ...utils.DBOption) (*models.TaskExecutionHistoryEntity, error)
+	// CancelExecution menandai eksekusi running sebagai cancelled. Scheduler yang menjalankan
+	// eksekusi tersebut menghentikan handler pada tick berikutnya.
+	CancelExecution(ctx context.Context, historyID uint, opts ...utils.DBOption) error
+	// GetPipelineRuns mengembalikan pipeline run terbaru beserta status setiap stage.
+	GetPipelineRuns(ctx context.Context, param *models.GetPipelineRunParam, opts ...utils.DBOption) ([]models.PipelineRunSummary, error)
+	// IsPipeline bernilai true jika job punya dependents atau fan-out.
+	IsPipeline(ctx context.Context, job models.JobEntity, opts ...utils.DBOption) (bool, error)
 }
 
+var (
+	ErrHistoryNotFound     = errors.New("task execution history not found")
+	ErrExecutionNotRunning = errors.New("task execution is not running")
+	ErrCronNeverRuns       = errors.New("cron expression never matches")
+)
+
 type jobService struct {
-	cfg            *config.Config
-	log            *logrus.Logger
-	jobsRepository repository.JobsRepository
+	cfg                *config.Config
+	log                *logrus.Logger
+	jobsRepository     repository.JobsRepository
+	pipelineRepository repository.PipelineRepository
 }
 
-func NewJobService(cfg *config.Config, log *logrus.Logger, jobsRepository repository.JobsRepository) JobService {
+func NewJobService(cfg *config.Config, log *logrus.Logger, jobsRepository repository.JobsRepository, pipelineRepository repository.PipelineRepository) JobService {
 	return &jobService{
-		cfg:            cfg,
-		log:            log,
-		jobsRepository: jobsRepository,
+		cfg:                cfg,
+		log:                log,
+		jobsRepository:     jobsRepository,
+		pipelineRepository: pipelineRepository,
 	}
 }
 
@@ -44,3 +70,82 @@ func (s *jobService) Get(ctx context.Context, param *models.GetJobParam, opts ..
 func (s *jobService) RunJobTask(ctx context.Context, jobID uint, opts ...utils.DBOption) error {
 	return s.jobsRepository.RunJobTask(ctx, jobID, opts...)
 }
+
+func (s *jobService) SetActive(ctx context.Context, jobID uint, isActive bool, opts ...utils.DBOption) error {
+	updates := map[string]interface{}{"is_active": isActive}
+	if isActive {
+		jobs, err := s.jobsRepository.Get(ctx, &models.GetJobParam{IDs: []uint{jobID}}, opts...)
+		if err != nil {
+			return fmt.Errorf("failed to get job: %w", err)
+		}
+		if len(jobs) == 0 || len(jobs[0].Schedules) == 0 {
+			return fmt.Errorf("schedule for job %d not found", jobID)
+		}
+		next, err := s.PreviewSchedule(jobs[0].Schedules[0].CronExpression, 1)
+		if err != nil {
+			return fmt.Errorf("failed to compute next execution: %w", err)
+		}
+		updates["next_execution"] = next[0]
+	}
+	if err := s.jobsRepository.UpdateScheduleByJobID(ctx, jobID, updates, opts...); err != nil {
+		return fmt.Errorf("failed to update task schedule: %w", err)
+	}
+	return nil
+}
+
+func (s *jobService) PreviewSchedule(expression string, n int) ([]time.Time, error) {
+	schedule, err := cron.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+	next := schedule.NextN(utils.TimeNowWIB(), n)
+	if len(next) == 0 {
+		return nil, ErrCronNeverRuns
+	}
+	return next, nil
+}
+
+func (s *jobService) UpdateCronExpression(ctx context.Context, jobID uint, expression string, opts ...utils.DBOption) error {
+	next, err := s.PreviewSchedule(expression, 1)
+	if err != nil {
+		return err
+	}
+	if err := s.jobsRepository.UpdateScheduleByJobID(ctx, jobID, map[string]interface{}{
+		"cron_expression": expression,
+		"next_execution":  next[0],
+	}, opts...); err != nil {
+		return fmt.Errorf("failed to update task schedule: %w", err)
+	}
+	return nil
+}
+
+func (s *jobService) GetHistories(ctx context.Context, param *models.GetTaskExecutionHistoryParam, opts ...utils.DBOption) ([]models.TaskExecutionHistoryEntity, int64, error) {
+	histories, total, err := s.jobsRepository.GetHistories(ctx, param, opts...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get task execution histories: %w", err)
+	}
+	return histories, total, nil
+}
+
+func (s *jobService) GetHistory(ctx context.Context, historyID uint, opts ...utils.DBOption) (*models.TaskExecutionHistoryEntity, error) {
+	history, err := s.jobsRepository.GetHistoryByID(ctx, historyID, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task execution history: %w", err)
+	}
+	if history == nil {
+		return nil, ErrHistoryNotFound
+	}
+	return history, nil
+}
+
+func (s *jobService) CancelExecution(ctx context.Context, historyID uint, opts ...utils.DBOption) error {
+	cancelled, err := s.jobsRepository.CancelHistory(ctx, historyID, utils.TimeNowWIB(), opts...)
+	if err != nil {
+		return fmt.Errorf("failed to cancel task execution: %w", err)
+	}
+	if !cancelled {
+		return ErrExecutionNotRunning
+	}
+	s.log.Info("Task execution cancelled", logrus.Fields{"history_id": historyID})
+	return nil
+}
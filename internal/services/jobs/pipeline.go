@@ -0,0 +1,167 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+)
+
+// maxStageErrors membatasi jumlah error item yang ditampilkan per stage.
+const maxStageErrors = 3
+
+func (s *jobService) GetPipelineRuns(ctx context.Context, param *models.GetPipelineRunParam, opts ...utils.DBOption) ([]models.PipelineRunSummary, error) {
+	runs, err := s.pipelineRepository.GetRuns(ctx, param, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipeline runs: %w", err)
+	}
+	if len(runs) == 0 {
+		return []models.PipelineRunSummary{}, nil
+	}
+
+	dependencies, err := s.pipelineRepository.GetDependencies(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job dependencies: %w", err)
+	}
+	graph := NewGraph(dependencies)
+
+	jobIDs := []uint{}
+	for _, run := range runs {
+		stages, _ := graph.Stages(run.RootJobID)
+		jobIDs = append(jobIDs, stages...)
+		for _, history := range run.Histories {
+			jobIDs = append(jobIDs, history.JobID)
+		}
+	}
+	slices.Sort(jobIDs)
+	jobs, err := s.jobsRepository.GetByIDs(ctx, slices.Compact(jobIDs), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs: %w", err)
+	}
+	jobByID := make(map[uint]models.JobEntity, len(jobs))
+	for _, job := range jobs {
+		jobByID[job.ID] = job
+	}
+
+	summaries := make([]models.PipelineRunSummary, 0, len(runs))
+	for _, run := range runs {
+		summaries = append(summaries, SummarizePipelineRun(run, graph, jobByID))
+	}
+	return summaries, nil
+}
+
+func (s *jobService) IsPipeline(ctx context.Context, job models.JobEntity, opts ...utils.DBOption) (bool, error) {
+	if job.FanOut != "" {
+		return true, nil
+	}
+	dependencies, err := s.pipelineRepository.GetDependencies(ctx, opts...)
+	if err != nil {
+		return false, fmt.Errorf("failed to get job dependencies: %w", err)
+	}
+	return NewGraph(dependencies).IsPipeline(job.ID), nil
+}
+
+// SummarizePipelineRun menghitung status setiap stage dari riwayat eksekusi pipeline run.
+// Untuk item yang di-retry, hanya attempt terakhir yang dihitung.
+func SummarizePipelineRun(run models.PipelineRunEntity, graph *Graph, jobByID map[uint]models.JobEntity) models.PipelineRunSummary {
+	summary := models.PipelineRunSummary{
+		ID:          run.ID,
+		RootJobID:   run.RootJobID,
+		Status:      run.Status,
+		FailedJobID: run.FailedJobID,
+		StartedAt:   run.StartedAt,
+	}
+	if run.ErrorMessage.Valid {
+		summary.ErrorMessage = run.ErrorMessage.String
+	}
+	if run.CompletedAt.Valid {
+		summary.CompletedAt = utils.ToPointer(run.CompletedAt.Time)
+	}
+
+	// attempt terakhir per job dan item
+	type key struct {
+		jobID uint
+		item  string
+	}
+	latest := make(map[key]models.TaskExecutionHistoryEntity)
+	var order []key
+	for _, history := range run.Histories {
+		k := key{jobID: history.JobID, item: history.Item}
+		previous, ok := latest[k]
+		if !ok {
+			order = append(order, k)
+		}
+		if !ok || history.Attempt >= previous.Attempt {
+			latest[k] = history
+		}
+	}
+
+	// dependency graph bisa berubah setelah run dibuat, job yang punya riwayat tetap ditampilkan
+	stageIDs, err := graph.Stages(run.RootJobID)
+	if err != nil {
+		stageIDs = []uint{run.RootJobID}
+	}
+	for _, k := range order {
+		if !slices.Contains(stageIDs, k.jobID) {
+			stageIDs = append(stageIDs, k.jobID)
+		}
+	}
+
+	for _, jobID := range stageIDs {
+		stage := models.PipelineStage{
+			JobID:   jobID,
+			JobName: jobByID[jobID].Name,
+		}
+		for _, dependency := range graph.Dependencies(jobID) {
+			if slices.Contains(stageIDs, dependency) {
+				stage.DependsOn = append(stage.DependsOn, dependency)
+			}
+		}
+		for _, k := range order {
+			if k.jobID != jobID {
+				continue
+			}
+			history := latest[k]
+			stage.Total++
+			switch history.Status {
+			case models.StatusCompleted:
+				stage.Completed++
+			case models.StatusRunning:
+				stage.Running++
+			default:
+				stage.Failed++
+				if len(stage.Errors) < maxStageErrors && history.ErrorMessage.Valid {
+					message := history.ErrorMessage.String
+					if history.Item != "" {
+						message = history.Item + ": " + message
+					}
+					stage.Errors = append(stage.Errors, message)
+				}
+			}
+		}
+
+		failedHere := run.FailedJobID != nil && *run.FailedJobID == jobID
+		switch {
+		case stage.Failed > 0 || (failedHere && stage.Total == stage.Completed):
+			stage.Status = models.PipelineStageFailed
+			if len(stage.Errors) == 0 && run.ErrorMessage.Valid {
+				stage.Errors = append(stage.Errors, run.ErrorMessage.String)
+			}
+		case stage.Running > 0:
+			stage.Status = models.PipelineStageRunning
+		case stage.Total > 0:
+			stage.Status = models.PipelineStageCompleted
+		case run.Status == models.PipelineRunRunning:
+			stage.Status = models.PipelineStagePending
+		case run.Status == models.PipelineRunCompleted:
+			// fan-out tanpa item
+			stage.Status = models.PipelineStageCompleted
+		default:
+			stage.Status = models.PipelineStageSkipped
+		}
+		summary.Stages = append(summary.Stages, stage)
+	}
+	return summary
+}
@@ -0,0 +1,108 @@
+package jobs
+
+import (
+	"database/sql"
+	"testing"
+
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+)
+
+func history(jobID uint, item string, attempt int, status models.TaskExecutionStatus, errorMessage string) models.TaskExecutionHistoryEntity {
+	return models.TaskExecutionHistoryEntity{
+		JobID:        jobID,
+		Item:         item,
+		Attempt:      attempt,
+		Status:       status,
+		ErrorMessage: sql.NullString{String: errorMessage, Valid: errorMessage != ""},
+	}
+}
+
+func TestSummarizePipelineRun(t *testing.T) {
+	graph := NewGraph([]models.JobDependencyEntity{dependency(2, 1), dependency(3, 2)})
+	jobByID := map[uint]models.JobEntity{1: {ID: 1, Name: "fetch"}, 2: {ID: 2, Name: "analyze"}, 3: {ID: 3, Name: "buylist"}}
+
+	tests := []struct {
+		name       string
+		run        models.PipelineRunEntity
+		wantStages []string
+		wantCounts [][3]int // total, completed, failed per stage
+	}{
+		{
+			name: "running fan-out",
+			run: models.PipelineRunEntity{
+				RootJobID: 1,
+				Status:    models.PipelineRunRunning,
+				Histories: []models.TaskExecutionHistoryEntity{
+					history(1, "", 1, models.StatusCompleted, ""),
+					history(2, "BBCA", 1, models.StatusCompleted, ""),
+					history(2, "BBRI", 1, models.StatusRunning, ""),
+				},
+			},
+			wantStages: []string{models.PipelineStageCompleted, models.PipelineStageRunning, models.PipelineStagePending},
+			wantCounts: [][3]int{{1, 1, 0}, {2, 1, 0}, {0, 0, 0}},
+		},
+		{
+			name: "retried item counts latest attempt",
+			run: models.PipelineRunEntity{
+				RootJobID: 1,
+				Status:    models.PipelineRunCompleted,
+				Histories: []models.TaskExecutionHistoryEntity{
+					history(1, "", 1, models.StatusCompleted, ""),
+					history(2, "BBCA", 1, models.StatusTimeout, "context deadline exceeded"),
+					history(2, "BBCA", 2, models.StatusCompleted, ""),
+					history(3, "", 1, models.StatusCompleted, ""),
+				},
+			},
+			wantStages: []string{models.PipelineStageCompleted, models.PipelineStageCompleted, models.PipelineStageCompleted},
+			wantCounts: [][3]int{{1, 1, 0}, {1, 1, 0}, {1, 1, 0}},
+		},
+		{
+			name: "failed stage skips the rest",
+			run: models.PipelineRunEntity{
+				RootJobID:    1,
+				Status:       models.PipelineRunFailed,
+				FailedJobID:  utils.ToPointer(uint(2)),
+				ErrorMessage: sql.NullString{String: "item BBRI: boom", Valid: true},
+				Histories: []models.TaskExecutionHistoryEntity{
+					history(1, "", 1, models.StatusCompleted, ""),
+					history(2, "BBCA", 1, models.StatusCompleted, ""),
+					history(2, "BBRI", 1, models.StatusFailed, "boom"),
+				},
+			},
+			wantStages: []string{models.PipelineStageCompleted, models.PipelineStageFailed, models.PipelineStageSkipped},
+			wantCounts: [][3]int{{1, 1, 0}, {2, 1, 1}, {0, 0, 0}},
+		},
+		{
+			name: "fan-out source failed before any execution",
+			run: models.PipelineRunEntity{
+				RootJobID:    1,
+				Status:       models.PipelineRunFailed,
+				FailedJobID:  utils.ToPointer(uint(2)),
+				ErrorMessage: sql.NullString{String: "unknown fan-out source: foo", Valid: true},
+				Histories: []models.TaskExecutionHistoryEntity{
+					history(1, "", 1, models.StatusCompleted, ""),
+				},
+			},
+			wantStages: []string{models.PipelineStageCompleted, models.PipelineStageFailed, models.PipelineStageSkipped},
+			wantCounts: [][3]int{{1, 1, 0}, {0, 0, 0}, {0, 0, 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := SummarizePipelineRun(tt.run, graph, jobByID)
+			if len(summary.Stages) != len(tt.wantStages) {
+				t.Fatalf("stages = %d, want %d", len(summary.Stages), len(tt.wantStages))
+			}
+			for i, stage := range summary.Stages {
+				if stage.Status != tt.wantStages[i] {
+					t.Errorf("stage %s status = %s, want %s", stage.JobName, stage.Status, tt.wantStages[i])
+				}
+				counts := [3]int{stage.Total, stage.Completed, stage.Failed}
+				if counts != tt.wantCounts[i] {
+					t.Errorf("stage %s counts = %v, want %v", stage.JobName, counts, tt.wantCounts[i])
+				}
+			}
+		})
+	}
+}
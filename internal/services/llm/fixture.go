@@ -0,0 +1,147 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang-swing-trading-signal/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+const fixtureModel = "fixture"
+
+var ErrFixtureNotFound = errors.New("llm fixture not found")
+
+// fixtureProvider memutar ulang response yang tersimpan di direktori fixture sehingga
+// pipeline analisa bisa dijalankan tanpa akses jaringan. Fixture dicari berdasarkan hash
+// request (<hash>.json), lalu fallback ke nama request (<name>.json). Jika record diisi,
+// fixture yang belum ada diambil dari provider tersebut lalu disimpan.
+type fixtureProvider struct {
+	dir    string
+	logger *logrus.Logger
+	record LLMProvider
+}
+
+func NewFixtureProvider(dir string, logger *logrus.Logger, record LLMProvider) LLMProvider {
+	return &fixtureProvider{
+		dir:    dir,
+		logger: logger,
+		record: record,
+	}
+}
+
+func (p *fixtureProvider) Name() string {
+	return ProviderFixture
+}
+
+func (p *fixtureProvider) Model() string {
+	return fixtureModel
+}
+
+func (p *fixtureProvider) CountTokens(ctx context.Context, messages []Message) (int, error) {
+	return estimateTokens(messages), nil
+}
+
+func (p *fixtureProvider) Generate(ctx context.Context, request Request) (*Response, error) {
+	return p.replay(ctx, request, nil)
+}
+
+func (p *fixtureProvider) GenerateStructured(ctx context.Context, request Request, schema *models.GeminiSchema) (*Response, error) {
+	return p.replay(ctx, request, schema)
+}
+
+// FixtureKey adalah hash deterministik dari isi request dan schema.
+func FixtureKey(request Request, schema *models.GeminiSchema) string {
+	payload, _ := json.Marshal(struct {
+		Messages []Message            `json:"messages"`
+		Schema   *models.GeminiSchema `json:"schema,omitempty"`
+	}{request.Messages, schema})
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+func (p *fixtureProvider) replay(ctx context.Context, request Request, schema *models.GeminiSchema) (*Response, error) {
+	key := FixtureKey(request, schema)
+
+	candidates := []string{filepath.Join(p.dir, key+".json")}
+	if request.Name != "" {
+		candidates = append(candidates, filepath.Join(p.dir, request.Name+".json"))
+	}
+
+	for _, path := range candidates {
+		response, err := readFixture(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		p.logger.Debug("replaying llm fixture", logrus.Fields{"path": path})
+		return response, nil
+	}
+
+	if p.record == nil {
+		return nil, fmt.Errorf("%w: %s", ErrFixtureNotFound, key)
+	}
+
+	var (
+		response *Response
+		err      error
+	)
+	if schema != nil {
+		response, err = p.record.GenerateStructured(ctx, request, schema)
+	} else {
+		response, err = p.record.Generate(ctx, request)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeFixture(candidates[0], response); err != nil {
+		p.logger.Warn("failed to record llm fixture", logrus.Fields{
+			"path":  candidates[0],
+			"error": err,
+		})
+	}
+	return response, nil
+}
+
+func readFixture(path string) (*Response, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var response Response
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	if response.FinishReason == "" {
+		response.FinishReason = FinishReasonStop
+	}
+	if response.Model == "" {
+		response.Model = fixtureModel
+	}
+	return &response, nil
+}
+
+func writeFixture(path string, response *Response) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	raw, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, raw, 0o644)
+}
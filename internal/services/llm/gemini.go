@@ -0,0 +1,156 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/genai"
+)
+
+type geminiProvider struct {
+	config       *config.GeminiConfig
+	client       *http.Client
+	logger       *logrus.Logger
+	geminiClient *genai.Client
+}
+
+func NewGeminiProvider(cfg *config.GeminiConfig, logger *logrus.Logger, geminiClient *genai.Client) LLMProvider {
+	return &geminiProvider{
+		config: cfg,
+		client: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+		logger:       logger,
+		geminiClient: geminiClient,
+	}
+}
+
+func (p *geminiProvider) Name() string {
+	return ProviderGemini
+}
+
+func (p *geminiProvider) Model() string {
+	return p.config.Model
+}
+
+func (p *geminiProvider) CountTokens(ctx context.Context, messages []Message) (int, error) {
+	contents := make([]*genai.Content, 0, len(messages))
+	for _, message := range messages {
+		contents = append(contents, genai.NewContentFromText(message.Text, genai.Role(message.Role)))
+	}
+
+	tokenCount, err := p.geminiClient.Models.CountTokens(ctx, p.config.Model, contents, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tokens: %w", err)
+	}
+	return int(tokenCount.TotalTokens), nil
+}
+
+func (p *geminiProvider) Generate(ctx context.Context, request Request) (*Response, error) {
+	return p.generate(ctx, request, nil)
+}
+
+func (p *geminiProvider) GenerateStructured(ctx context.Context, request Request, schema *models.GeminiSchema) (*Response, error) {
+	return p.generate(ctx, request, schema)
+}
+
+func (p *geminiProvider) generate(ctx context.Context, request Request, schema *models.GeminiSchema) (*Response, error) {
+	// Build request URL
+	requestURL := fmt.Sprintf("%s/%s:generateContent?key=%s",
+		p.config.BaseURL, p.config.Model, p.config.APIKey)
+
+	contents := make([]models.GeminiContent, 0, len(request.Messages))
+	for _, message := range request.Messages {
+		contents = append(contents, models.GeminiContent{
+			Role:  message.Role,
+			Parts: []models.GeminiPart{{Text: message.Text}},
+		})
+	}
+
+	// Build request body
+	requestBody := models.GeminiAIRequest{
+		GenerationConfig: &models.GeminiGenerationConfig{
+			Temperature: request.Temperature,
+		},
+		Contents: contents,
+	}
+	if schema != nil {
+		requestBody.GenerationConfig.ResponseMimeType = "application/json"
+		requestBody.GenerationConfig.ResponseSchema = schema
+	}
+
+	jsonBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	p.logger.Debug("Sending request to Gemini AI", logrus.Fields{
+		"request_body": requestBody,
+		"model":        p.config.Model,
+	})
+
+	// Create HTTP request
+	req, err := http.NewRequestWithContext(ctx, "POST", requestURL, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	// Send request
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to Gemini AI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &utils.HTTPStatusError{Service: "gemini AI API", StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	// Read response
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Parse response
+	var geminiResp models.GeminiAIResponse
+	if err := json.Unmarshal(body, &geminiResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal Gemini AI response: %w", err)
+	}
+
+	p.logger.Debug("Gemini AI response", logrus.Fields{
+		"candidates": geminiResp.Candidates,
+	})
+
+	// Check if we have candidates
+	if len(geminiResp.Candidates) == 0 {
+		return nil, fmt.Errorf("no response candidates from Gemini AI")
+	}
+
+	candidate := geminiResp.Candidates[0]
+	if len(candidate.Content.Parts) == 0 {
+		return nil, fmt.Errorf("no content parts in Gemini AI response")
+	}
+
+	return &Response{
+		Text:         candidate.Content.Parts[0].Text,
+		Model:        p.config.Model,
+		FinishReason: strings.ToLower(candidate.FinishReason),
+		InputTokens:  geminiResp.UsageMetadata.PromptTokenCount,
+		OutputTokens: geminiResp.UsageMetadata.CandidatesTokenCount,
+	}, nil
+}
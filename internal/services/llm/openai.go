@@ -0,0 +1,202 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// openAIProvider memanggil endpoint /chat/completions yang kompatibel dengan OpenAI,
+// sehingga model lokal (llama.cpp server, vLLM, Ollama, dll) bisa dipakai.
+type openAIProvider struct {
+	config *config.LLMConfig
+	client *http.Client
+	logger *logrus.Logger
+}
+
+type openAIChatRequest struct {
+	Model          string                `json:"model"`
+	Messages       []openAIMessage       `json:"messages"`
+	Temperature    float64               `json:"temperature"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIResponseFormat struct {
+	Type       string            `json:"type"`
+	JSONSchema *openAIJSONSchema `json:"json_schema,omitempty"`
+}
+
+type openAIJSONSchema struct {
+	Name   string         `json:"name"`
+	Schema map[string]any `json:"schema"`
+	Strict bool           `json:"strict"`
+}
+
+type openAIChatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message      openAIMessage `json:"message"`
+		FinishReason string        `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+func NewOpenAIProvider(cfg *config.LLMConfig, logger *logrus.Logger) LLMProvider {
+	return &openAIProvider{
+		config: cfg,
+		client: &http.Client{
+			Timeout: 120 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+func (p *openAIProvider) Name() string {
+	return ProviderOpenAI
+}
+
+func (p *openAIProvider) Model() string {
+	return p.config.OpenAIModel
+}
+
+func (p *openAIProvider) CountTokens(ctx context.Context, messages []Message) (int, error) {
+	return estimateTokens(messages), nil
+}
+
+func (p *openAIProvider) Generate(ctx context.Context, request Request) (*Response, error) {
+	return p.generate(ctx, request, nil)
+}
+
+func (p *openAIProvider) GenerateStructured(ctx context.Context, request Request, schema *models.GeminiSchema) (*Response, error) {
+	return p.generate(ctx, request, schema)
+}
+
+func (p *openAIProvider) generate(ctx context.Context, request Request, schema *models.GeminiSchema) (*Response, error) {
+	requestBody := openAIChatRequest{
+		Model:       p.config.OpenAIModel,
+		Temperature: request.Temperature,
+	}
+	for _, message := range request.Messages {
+		role := message.Role
+		if role == RoleModel {
+			role = "assistant"
+		}
+		requestBody.Messages = append(requestBody.Messages, openAIMessage{Role: role, Content: message.Text})
+	}
+	if schema != nil {
+		name := request.Name
+		if name == "" {
+			name = "response"
+		}
+		requestBody.ResponseFormat = &openAIResponseFormat{
+			Type: "json_schema",
+			JSONSchema: &openAIJSONSchema{
+				Name:   name,
+				Schema: toJSONSchema(schema),
+			},
+		}
+	}
+
+	jsonBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	requestURL := strings.TrimRight(p.config.OpenAIBaseURL, "/") + "/chat/completions"
+	req, err := http.NewRequestWithContext(ctx, "POST", requestURL, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.config.OpenAIAPIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.config.OpenAIAPIKey)
+	}
+
+	p.logger.Debug("Sending request to OpenAI compatible endpoint", logrus.Fields{
+		"request_url": requestURL,
+		"model":       p.config.OpenAIModel,
+	})
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to OpenAI compatible endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &utils.HTTPStatusError{Service: "openai compatible endpoint", StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var chatResp openAIChatResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chat completion response: %w", err)
+	}
+	if len(chatResp.Choices) == 0 {
+		return nil, fmt.Errorf("no choices in chat completion response")
+	}
+
+	model := chatResp.Model
+	if model == "" {
+		model = p.config.OpenAIModel
+	}
+
+	choice := chatResp.Choices[0]
+	return &Response{
+		Text:         choice.Message.Content,
+		Model:        model,
+		FinishReason: strings.ToLower(choice.FinishReason),
+		InputTokens:  chatResp.Usage.PromptTokens,
+		OutputTokens: chatResp.Usage.CompletionTokens,
+	}, nil
+}
+
+// toJSONSchema mengubah schema gaya Gemini (tipe huruf besar) menjadi JSON Schema standar.
+func toJSONSchema(schema *models.GeminiSchema) map[string]any {
+	result := map[string]any{
+		"type": strings.ToLower(schema.Type),
+	}
+	if schema.Format != "" {
+		result["format"] = schema.Format
+	}
+	if len(schema.Enum) > 0 {
+		result["enum"] = schema.Enum
+	}
+	if schema.Items != nil {
+		result["items"] = toJSONSchema(schema.Items)
+	}
+	if len(schema.Properties) > 0 {
+		properties := make(map[string]any, len(schema.Properties))
+		for name, property := range schema.Properties {
+			properties[name] = toJSONSchema(property)
+		}
+		result["properties"] = properties
+		result["additionalProperties"] = false
+	}
+	if len(schema.Required) > 0 {
+		result["required"] = schema.Required
+	}
+	return result
+}
@@ -0,0 +1,98 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/genai"
+)
+
+const (
+	ProviderGemini  = "gemini"
+	ProviderOpenAI  = "openai"
+	ProviderFixture = "fixture"
+
+	RoleUser  = "user"
+	RoleModel = "model"
+
+	FinishReasonStop = "stop"
+)
+
+type Message struct {
+	Role string `json:"role"`
+	Text string `json:"text"`
+}
+
+type Request struct {
+	// Name mengidentifikasi jenis prompt, misal "individual_analysis". Dipakai sebagai
+	// nama schema pada provider OpenAI dan fallback nama fixture.
+	Name string `json:"name"`
+	// PromptVersion hanya metadata untuk pencatatan usage, tidak dikirim ke provider.
+	PromptVersion string    `json:"prompt_version,omitempty"`
+	Messages      []Message `json:"messages"`
+	Temperature   float64   `json:"temperature"`
+}
+
+type Response struct {
+	Text         string `json:"text"`
+	Model        string `json:"model"`
+	FinishReason string `json:"finish_reason"` // dinormalisasi ke huruf kecil, "stop" jika selesai normal
+	InputTokens  int    `json:"input_tokens"`
+	OutputTokens int    `json:"output_tokens"`
+}
+
+// LLMProvider adalah abstraksi model bahasa yang dipakai pipeline analisa.
+type LLMProvider interface {
+	Name() string
+	Model() string
+	CountTokens(ctx context.Context, messages []Message) (int, error)
+	Generate(ctx context.Context, request Request) (*Response, error)
+	// GenerateStructured meminta jawaban JSON yang mengikuti schema.
+	GenerateStructured(ctx context.Context, request Request, schema *models.GeminiSchema) (*Response, error)
+}
+
+// NeedsGeminiClient bernilai true jika NewProvider memakai client Gemini, yaitu provider gemini
+// atau fixture dalam mode record. Provider lain menerima client nil.
+func NeedsGeminiClient(cfg *config.LLMConfig) bool {
+	switch strings.ToLower(cfg.Provider) {
+	case "", ProviderGemini:
+		return true
+	case ProviderFixture:
+		return cfg.FixtureRecord
+	default:
+		return false
+	}
+}
+
+// NewProvider membuat provider sesuai LLM_PROVIDER (default gemini).
+func NewProvider(cfg *config.LLMConfig, geminiCfg *config.GeminiConfig, logger *logrus.Logger, geminiClient *genai.Client) (LLMProvider, error) {
+	switch strings.ToLower(cfg.Provider) {
+	case "", ProviderGemini:
+		return NewGeminiProvider(geminiCfg, logger, geminiClient), nil
+	case ProviderOpenAI:
+		return NewOpenAIProvider(cfg, logger), nil
+	case ProviderFixture:
+		var record LLMProvider
+		if cfg.FixtureRecord {
+			record = NewGeminiProvider(geminiCfg, logger, geminiClient)
+		}
+		return NewFixtureProvider(cfg.FixtureDir, logger, record), nil
+	default:
+		return nil, fmt.Errorf("unknown llm provider: %s", cfg.Provider)
+	}
+}
+
+// estimateTokens memperkirakan jumlah token (~4 karakter per token) untuk provider
+// yang tidak memiliki endpoint penghitung token.
+func estimateTokens(messages []Message) int {
+	chars := 0
+	for _, message := range messages {
+		chars += len(message.Text)
+	}
+	return (chars + 3) / 4
+}
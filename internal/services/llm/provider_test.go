@@ -0,0 +1,28 @@
+package llm
+
+import (
+	"testing"
+
+	"golang-swing-trading-signal/internal/config"
+)
+
+func TestNeedsGeminiClient(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.LLMConfig
+		want bool
+	}{
+		{name: "default provider", cfg: config.LLMConfig{}, want: true},
+		{name: "gemini", cfg: config.LLMConfig{Provider: "Gemini"}, want: true},
+		{name: "openai", cfg: config.LLMConfig{Provider: ProviderOpenAI}, want: false},
+		{name: "fixture replay", cfg: config.LLMConfig{Provider: ProviderFixture}, want: false},
+		{name: "fixture record", cfg: config.LLMConfig{Provider: ProviderFixture, FixtureRecord: true}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NeedsGeminiClient(&tt.cfg); got != tt.want {
+				t.Errorf("NeedsGeminiClient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package llm
+
+import (
+	"context"
+	"time"
+
+	"golang-swing-trading-signal/internal/models"
+)
+
+const (
+	TriggerSourceAPI      = "api"
+	TriggerSourceTelegram = "telegram"
+	TriggerSourceJob      = "job"
+)
+
+// Trigger mencatat siapa yang memicu panggilan LLM (user telegram, job scheduler atau API).
+type Trigger struct {
+	Source     string
+	TelegramID int64
+	JobID      uint
+}
+
+type triggerKey struct{}
+
+func WithTrigger(ctx context.Context, trigger Trigger) context.Context {
+	return context.WithValue(ctx, triggerKey{}, trigger)
+}
+
+func TriggerFromContext(ctx context.Context) Trigger {
+	trigger, _ := ctx.Value(triggerKey{}).(Trigger)
+	return trigger
+}
+
+// Usage adalah catatan satu panggilan ke provider.
+type Usage struct {
+	Provider      string
+	Model         string
+	PromptName    string
+	PromptVersion string
+	InputTokens   int
+	OutputTokens  int
+	Latency       time.Duration
+	FinishReason  string
+	Err           error
+	Trigger       Trigger
+}
+
+type UsageRecorder interface {
+	RecordUsage(ctx context.Context, usage Usage)
+}
+
+// usageRecordingProvider membungkus provider lain dan mencatat setiap panggilan Generate
+// dan GenerateStructured ke recorder, termasuk panggilan yang gagal.
+type usageRecordingProvider struct {
+	LLMProvider
+	recorder UsageRecorder
+}
+
+func WithUsageRecorder(provider LLMProvider, recorder UsageRecorder) LLMProvider {
+	return &usageRecordingProvider{
+		LLMProvider: provider,
+		recorder:    recorder,
+	}
+}
+
+func (p *usageRecordingProvider) Generate(ctx context.Context, request Request) (*Response, error) {
+	start := time.Now()
+	response, err := p.LLMProvider.Generate(ctx, request)
+	p.record(ctx, request, response, err, time.Since(start))
+	return response, err
+}
+
+func (p *usageRecordingProvider) GenerateStructured(ctx context.Context, request Request, schema *models.GeminiSchema) (*Response, error) {
+	start := time.Now()
+	response, err := p.LLMProvider.GenerateStructured(ctx, request, schema)
+	p.record(ctx, request, response, err, time.Since(start))
+	return response, err
+}
+
+func (p *usageRecordingProvider) record(ctx context.Context, request Request, response *Response, err error, latency time.Duration) {
+	usage := Usage{
+		Provider:      p.Name(),
+		Model:         p.Model(),
+		PromptName:    request.Name,
+		PromptVersion: request.PromptVersion,
+		Latency:       latency,
+		Err:           err,
+		Trigger:       TriggerFromContext(ctx),
+	}
+	if response != nil {
+		if response.Model != "" {
+			usage.Model = response.Model
+		}
+		usage.InputTokens = response.InputTokens
+		usage.OutputTokens = response.OutputTokens
+		usage.FinishReason = response.FinishReason
+	}
+	if usage.InputTokens == 0 && err == nil {
+		// provider tanpa usage metadata, pakai estimasi agar biaya tetap tercatat
+		usage.InputTokens = estimateTokens(request.Messages)
+	}
+
+	p.recorder.RecordUsage(ctx, usage)
+}
@@ -0,0 +1,162 @@
+package llm_usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/repository"
+	"golang-swing-trading-signal/internal/services/llm"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+var ErrBudgetExceeded = errors.New("llm budget exceeded")
+
+type UsageService interface {
+	llm.UsageRecorder
+	// CheckBudget mengembalikan ErrBudgetExceeded jika biaya hari ini atau bulan ini sudah
+	// melewati batas. Dipanggil sebelum analisa yang tidak esensial.
+	CheckBudget(ctx context.Context) error
+	GetReport(ctx context.Context) (*models.LLMUsageReport, error)
+}
+
+type usageService struct {
+	cfg                *config.LLMConfig
+	logger             *logrus.Logger
+	llmUsageRepository repository.LLMUsageRepository
+	now                func() time.Time
+}
+
+func NewUsageService(cfg *config.LLMConfig, logger *logrus.Logger, llmUsageRepository repository.LLMUsageRepository) UsageService {
+	return &usageService{
+		cfg:                cfg,
+		logger:             logger,
+		llmUsageRepository: llmUsageRepository,
+		now:                utils.TimeNowWIB,
+	}
+}
+
+// Cost menghitung biaya satu panggilan berdasarkan harga per 1 juta token. Replay fixture
+// tidak memakan biaya.
+func Cost(cfg *config.LLMConfig, usage llm.Usage) float64 {
+	if usage.Provider == llm.ProviderFixture {
+		return 0
+	}
+	return (float64(usage.InputTokens)*cfg.InputCostPerMillionTokens +
+		float64(usage.OutputTokens)*cfg.OutputCostPerMillionTokens) / 1_000_000
+}
+
+func (s *usageService) RecordUsage(ctx context.Context, usage llm.Usage) {
+	entity := &models.LLMUsageEntity{
+		Provider:      usage.Provider,
+		Model:         usage.Model,
+		PromptName:    usage.PromptName,
+		PromptVersion: usage.PromptVersion,
+		InputTokens:   usage.InputTokens,
+		OutputTokens:  usage.OutputTokens,
+		LatencyMs:     usage.Latency.Milliseconds(),
+		FinishReason:  usage.FinishReason,
+		TriggerSource: usage.Trigger.Source,
+		Cost:          Cost(s.cfg, usage),
+	}
+	if usage.Err != nil {
+		entity.Error = usage.Err.Error()
+	}
+	if usage.Trigger.TelegramID != 0 {
+		entity.TelegramID = utils.ToPointer(usage.Trigger.TelegramID)
+	}
+	if usage.Trigger.JobID != 0 {
+		entity.JobID = utils.ToPointer(usage.Trigger.JobID)
+	}
+
+	// pencatatan tetap disimpan walaupun request asal sudah dibatalkan / timeout
+	if err := s.llmUsageRepository.Create(context.WithoutCancel(ctx), entity); err != nil {
+		s.logger.Error("failed to record llm usage", logrus.Fields{
+			"model": usage.Model,
+			"error": err,
+		})
+	}
+}
+
+func (s *usageService) CheckBudget(ctx context.Context) error {
+	if s.cfg.DailyBudget <= 0 && s.cfg.MonthlyBudget <= 0 {
+		return nil
+	}
+
+	now := s.now()
+	dayStart, monthStart := periodStarts(now)
+
+	if s.cfg.DailyBudget > 0 {
+		cost, err := s.llmUsageRepository.TotalCost(ctx, dayStart, now)
+		if err != nil {
+			s.logger.Error("failed to get daily llm cost", logrus.Fields{"error": err})
+			return fmt.Errorf("failed to get daily llm cost: %w", err)
+		}
+		if cost >= s.cfg.DailyBudget {
+			return fmt.Errorf("%w: daily cost %.4f of %.4f", ErrBudgetExceeded, cost, s.cfg.DailyBudget)
+		}
+	}
+
+	if s.cfg.MonthlyBudget > 0 {
+		cost, err := s.llmUsageRepository.TotalCost(ctx, monthStart, now)
+		if err != nil {
+			s.logger.Error("failed to get monthly llm cost", logrus.Fields{"error": err})
+			return fmt.Errorf("failed to get monthly llm cost: %w", err)
+		}
+		if cost >= s.cfg.MonthlyBudget {
+			return fmt.Errorf("%w: monthly cost %.4f of %.4f", ErrBudgetExceeded, cost, s.cfg.MonthlyBudget)
+		}
+	}
+
+	return nil
+}
+
+func (s *usageService) GetReport(ctx context.Context) (*models.LLMUsageReport, error) {
+	now := s.now()
+	dayStart, monthStart := periodStarts(now)
+	end := now.Add(time.Second)
+
+	todayByModel, err := s.llmUsageRepository.SummarizeByModel(ctx, dayStart, end)
+	if err != nil {
+		s.logger.Error("failed to summarize today llm usage", logrus.Fields{"error": err})
+		return nil, fmt.Errorf("failed to summarize today llm usage: %w", err)
+	}
+	monthByModel, err := s.llmUsageRepository.SummarizeByModel(ctx, monthStart, end)
+	if err != nil {
+		s.logger.Error("failed to summarize monthly llm usage", logrus.Fields{"error": err})
+		return nil, fmt.Errorf("failed to summarize monthly llm usage: %w", err)
+	}
+	monthByDay, err := s.llmUsageRepository.SummarizeByDay(ctx, monthStart, end)
+	if err != nil {
+		s.logger.Error("failed to summarize daily llm usage", logrus.Fields{"error": err})
+		return nil, fmt.Errorf("failed to summarize daily llm usage: %w", err)
+	}
+
+	report := &models.LLMUsageReport{
+		GeneratedAt:   now,
+		TodayByModel:  todayByModel,
+		MonthByModel:  monthByModel,
+		MonthByDay:    monthByDay,
+		DailyBudget:   s.cfg.DailyBudget,
+		MonthlyBudget: s.cfg.MonthlyBudget,
+	}
+	for _, summary := range todayByModel {
+		report.TodayCost += summary.Cost
+	}
+	for _, summary := range monthByModel {
+		report.MonthCost += summary.Cost
+	}
+	return report, nil
+}
+
+// periodStarts mengembalikan awal hari dan awal bulan (WIB) dari now.
+func periodStarts(now time.Time) (time.Time, time.Time) {
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	return dayStart, monthStart
+}
@@ -0,0 +1,121 @@
+package llm_usage
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/llm"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fakeUsageRepository menghitung total biaya dari usage yang tersimpan di memori.
+type fakeUsageRepository struct {
+	usages []models.LLMUsageEntity
+}
+
+func (r *fakeUsageRepository) Create(ctx context.Context, usage *models.LLMUsageEntity, opts ...utils.DBOption) error {
+	r.usages = append(r.usages, *usage)
+	return nil
+}
+
+func (r *fakeUsageRepository) SummarizeByModel(ctx context.Context, from, to time.Time) ([]models.LLMUsageSummary, error) {
+	return nil, nil
+}
+
+func (r *fakeUsageRepository) SummarizeByDay(ctx context.Context, from, to time.Time) ([]models.LLMUsageSummary, error) {
+	return nil, nil
+}
+
+func (r *fakeUsageRepository) TotalCost(ctx context.Context, from, to time.Time) (float64, error) {
+	total := 0.0
+	for _, usage := range r.usages {
+		if !usage.CreatedAt.Before(from) && usage.CreatedAt.Before(to) {
+			total += usage.Cost
+		}
+	}
+	return total, nil
+}
+
+func TestCost(t *testing.T) {
+	cfg := &config.LLMConfig{InputCostPerMillionTokens: 0.1, OutputCostPerMillionTokens: 0.4}
+
+	tests := []struct {
+		name  string
+		usage llm.Usage
+		want  float64
+	}{
+		{name: "input and output", usage: llm.Usage{Provider: llm.ProviderGemini, InputTokens: 1_000_000, OutputTokens: 500_000}, want: 0.3},
+		{name: "no tokens", usage: llm.Usage{Provider: llm.ProviderGemini}, want: 0},
+		{name: "fixture is free", usage: llm.Usage{Provider: llm.ProviderFixture, InputTokens: 1_000_000}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Cost(cfg, tt.usage); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Cost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsageService_CheckBudget(t *testing.T) {
+	now := time.Date(2025, 3, 20, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		cfg     config.LLMConfig
+		usages  []models.LLMUsageEntity
+		wantErr bool
+	}{
+		{
+			name:   "no budget",
+			usages: []models.LLMUsageEntity{{Cost: 100, CreatedAt: now.Add(-time.Hour)}},
+		},
+		{
+			name:   "under daily budget",
+			cfg:    config.LLMConfig{DailyBudget: 1},
+			usages: []models.LLMUsageEntity{{Cost: 0.5, CreatedAt: now.Add(-time.Hour)}, {Cost: 5, CreatedAt: now.AddDate(0, 0, -1)}},
+		},
+		{
+			name:    "daily budget exceeded",
+			cfg:     config.LLMConfig{DailyBudget: 1},
+			usages:  []models.LLMUsageEntity{{Cost: 0.6, CreatedAt: now.Add(-time.Hour)}, {Cost: 0.4, CreatedAt: now.Add(-2 * time.Hour)}},
+			wantErr: true,
+		},
+		{
+			name:    "monthly budget exceeded",
+			cfg:     config.LLMConfig{DailyBudget: 1, MonthlyBudget: 10},
+			usages:  []models.LLMUsageEntity{{Cost: 0.1, CreatedAt: now.Add(-time.Hour)}, {Cost: 10, CreatedAt: now.AddDate(0, 0, -5)}},
+			wantErr: true,
+		},
+		{
+			name:   "previous month not counted",
+			cfg:    config.LLMConfig{MonthlyBudget: 10},
+			usages: []models.LLMUsageEntity{{Cost: 50, CreatedAt: now.AddDate(0, -1, 0)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &usageService{
+				cfg:                &tt.cfg,
+				logger:             logrus.New(),
+				llmUsageRepository: &fakeUsageRepository{usages: tt.usages},
+				now:                func() time.Time { return now },
+			}
+
+			err := service.CheckBudget(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckBudget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrBudgetExceeded) {
+				t.Errorf("CheckBudget() error = %v, want ErrBudgetExceeded", err)
+			}
+		})
+	}
+}
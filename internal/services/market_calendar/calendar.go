@@ -0,0 +1,434 @@
+package market_calendar
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	HolidaySourceFile     = "file"
+	HolidaySourceDatabase = "database"
+
+	dateLayout = "2006-01-02"
+)
+
+//go:embed holidays_idx.json
+var defaultHolidaysJSON []byte
+
+type Session string
+
+const (
+	SessionClosed      Session = "CLOSED"
+	SessionPreOpening  Session = "PRE_OPENING"
+	SessionFirst       Session = "SESSION_1"
+	SessionBreak       Session = "BREAK"
+	SessionSecond      Session = "SESSION_2"
+	SessionPreClosing  Session = "PRE_CLOSING"
+	SessionPostTrading Session = "POST_TRADING"
+)
+
+type Holiday struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+// clock adalah jam dalam satu hari (WIB)
+type clock struct {
+	hour, minute, second int
+}
+
+func (c clock) on(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), c.hour, c.minute, c.second, 0, day.Location())
+}
+
+type sessionWindow struct {
+	session Session
+	start   clock
+	end     clock // eksklusif
+}
+
+// Jadwal perdagangan BEI (reguler). Jumat memiliki istirahat siang yang lebih panjang.
+var (
+	scheduleMonThu = []sessionWindow{
+		{SessionPreOpening, clock{8, 45, 0}, clock{9, 0, 0}},
+		{SessionFirst, clock{9, 0, 0}, clock{12, 0, 0}},
+		{SessionBreak, clock{12, 0, 0}, clock{13, 30, 0}},
+		{SessionSecond, clock{13, 30, 0}, clock{15, 50, 0}},
+		{SessionPreClosing, clock{15, 50, 0}, clock{16, 1, 0}},
+		{SessionPostTrading, clock{16, 1, 0}, clock{16, 16, 0}},
+	}
+	scheduleFri = []sessionWindow{
+		{SessionPreOpening, clock{8, 45, 0}, clock{9, 0, 0}},
+		{SessionFirst, clock{9, 0, 0}, clock{11, 30, 0}},
+		{SessionBreak, clock{11, 30, 0}, clock{14, 0, 0}},
+		{SessionSecond, clock{14, 0, 0}, clock{15, 50, 0}},
+		{SessionPreClosing, clock{15, 50, 0}, clock{16, 1, 0}},
+		{SessionPostTrading, clock{16, 1, 0}, clock{16, 16, 0}},
+	}
+)
+
+// Calendar menyimpan daftar hari libur bursa dan menyediakan aritmatika hari bursa
+// serta deteksi sesi perdagangan BEI.
+type Calendar struct {
+	cfg      *config.MarketCalendarConfig
+	logger   *logrus.Logger
+	loc      *time.Location
+	mu       sync.RWMutex
+	holidays map[string]string // "2006-01-02" -> nama libur
+}
+
+func NewCalendar(cfg *config.MarketCalendarConfig, logger *logrus.Logger) (*Calendar, error) {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load location: %w", err)
+	}
+
+	c := &Calendar{
+		cfg:      cfg,
+		logger:   logger,
+		loc:      loc,
+		holidays: make(map[string]string),
+	}
+
+	var holidays []Holiday
+	if err := json.Unmarshal(defaultHolidaysJSON, &holidays); err != nil {
+		return nil, fmt.Errorf("failed to parse default holiday list: %w", err)
+	}
+	if err := c.SetHolidays(holidays); err != nil {
+		return nil, err
+	}
+
+	if cfg != nil && cfg.HolidayFile != "" {
+		if err := c.LoadHolidaysFromFile(cfg.HolidayFile); err != nil {
+			return nil, err
+		}
+	}
+	// sumber database dimuat belakangan lewat LoadHolidaysFromRepository
+	if cfg == nil || cfg.HolidaySource != HolidaySourceDatabase {
+		c.warnMissingYear(time.Now().In(loc).Year())
+	}
+
+	return c, nil
+}
+
+// warnMissingYear mencatat warning jika daftar libur tidak punya satu pun tanggal di year,
+// tanda kalender belum diperbarui sehingga hari libur akan dianggap hari bursa.
+func (c *Calendar) warnMissingYear(year int) {
+	if c.hasHolidaysIn(year) {
+		return
+	}
+	c.logger.Warn("market holiday list has no entries for current year", logrus.Fields{
+		"year": year,
+	})
+}
+
+func (c *Calendar) hasHolidaysIn(year int) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	prefix := fmt.Sprintf("%04d-", year)
+	for date := range c.holidays {
+		if strings.HasPrefix(date, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadHolidaysFromFile membaca file JSON berisi [{"date": "2025-01-01", "name": "..."}]
+// dan menggantikan daftar libur yang ada.
+func (c *Calendar) LoadHolidaysFromFile(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read holiday file %s: %w", path, err)
+	}
+
+	var holidays []Holiday
+	if err := json.Unmarshal(raw, &holidays); err != nil {
+		return fmt.Errorf("failed to parse holiday file %s: %w", path, err)
+	}
+
+	if err := c.SetHolidays(holidays); err != nil {
+		return err
+	}
+
+	c.logger.Info("Market holidays loaded from file", logrus.Fields{
+		"path":  path,
+		"count": len(holidays),
+	})
+	return nil
+}
+
+// LoadHolidaysFromRepository menggantikan daftar libur dengan isi tabel market_holidays.
+func (c *Calendar) LoadHolidaysFromRepository(ctx context.Context, repo repository.MarketHolidayRepository) error {
+	entities, err := repo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get market holidays: %w", err)
+	}
+
+	holidays := make([]Holiday, 0, len(entities))
+	for _, entity := range entities {
+		holidays = append(holidays, ToHoliday(entity))
+	}
+
+	if err := c.SetHolidays(holidays); err != nil {
+		return err
+	}
+
+	c.logger.Info("Market holidays loaded from database", logrus.Fields{
+		"count": len(holidays),
+	})
+	c.warnMissingYear(time.Now().In(c.loc).Year())
+	return nil
+}
+
+func (c *Calendar) SetHolidays(holidays []Holiday) error {
+	parsed := make(map[string]string, len(holidays))
+	for _, holiday := range holidays {
+		date, err := time.ParseInLocation(dateLayout, holiday.Date, c.loc)
+		if err != nil {
+			return fmt.Errorf("invalid holiday date %q: %w", holiday.Date, err)
+		}
+		parsed[date.Format(dateLayout)] = holiday.Name
+	}
+
+	c.mu.Lock()
+	c.holidays = parsed
+	c.mu.Unlock()
+	return nil
+}
+
+// Holidays mengembalikan daftar libur terurut berdasarkan tanggal.
+func (c *Calendar) Holidays() []Holiday {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]Holiday, 0, len(c.holidays))
+	for date, name := range c.holidays {
+		result = append(result, Holiday{Date: date, Name: name})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date < result[j].Date
+	})
+	return result
+}
+
+func (c *Calendar) Location() *time.Location {
+	return c.loc
+}
+
+func (c *Calendar) Now() time.Time {
+	return time.Now().In(c.loc)
+}
+
+func (c *Calendar) startOfDay(t time.Time) time.Time {
+	t = t.In(c.loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.loc)
+}
+
+// IsHoliday mengembalikan true beserta nama libur jika tanggal t adalah libur bursa.
+func (c *Calendar) IsHoliday(t time.Time) (bool, string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	name, ok := c.holidays[t.In(c.loc).Format(dateLayout)]
+	return ok, name
+}
+
+func (c *Calendar) IsTradingDay(t time.Time) bool {
+	t = t.In(c.loc)
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	holiday, _ := c.IsHoliday(t)
+	return !holiday
+}
+
+// NextTradingDay mengembalikan awal hari bursa berikutnya setelah tanggal t.
+func (c *Calendar) NextTradingDay(t time.Time) time.Time {
+	day := c.startOfDay(t).AddDate(0, 0, 1)
+	for !c.IsTradingDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// PreviousTradingDay mengembalikan awal hari bursa sebelum tanggal t.
+func (c *Calendar) PreviousTradingDay(t time.Time) time.Time {
+	day := c.startOfDay(t).AddDate(0, 0, -1)
+	for !c.IsTradingDay(day) {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// TradingDaysBetween menghitung jumlah hari bursa pada rentang (from, to].
+// Hasil negatif jika to berada sebelum from.
+func (c *Calendar) TradingDaysBetween(from, to time.Time) int {
+	start := c.startOfDay(from)
+	end := c.startOfDay(to)
+	if end.Before(start) {
+		return -c.TradingDaysBetween(to, from)
+	}
+
+	count := 0
+	for day := start.AddDate(0, 0, 1); !day.After(end); day = day.AddDate(0, 0, 1) {
+		if c.IsTradingDay(day) {
+			count++
+		}
+	}
+	return count
+}
+
+// HoldingDays adalah umur posisi dalam hari bursa sejak tanggal beli.
+func (c *Calendar) HoldingDays(buyDate time.Time, now time.Time) int {
+	days := c.TradingDaysBetween(buyDate, now)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+// RemainingHoldingDays adalah sisa hari bursa sebelum batas maksimal holding tercapai.
+func (c *Calendar) RemainingHoldingDays(maxHoldingDays int, buyDate time.Time, now time.Time) int {
+	remaining := maxHoldingDays - c.HoldingDays(buyDate, now)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+func (c *Calendar) schedule(day time.Time) []sessionWindow {
+	if day.In(c.loc).Weekday() == time.Friday {
+		return scheduleFri
+	}
+	return scheduleMonThu
+}
+
+// SessionAt mengembalikan sesi perdagangan yang sedang berlangsung pada waktu t.
+func (c *Calendar) SessionAt(t time.Time) Session {
+	t = t.In(c.loc)
+	if !c.IsTradingDay(t) {
+		return SessionClosed
+	}
+
+	for _, window := range c.schedule(t) {
+		if !t.Before(window.start.on(t)) && t.Before(window.end.on(t)) {
+			return window.session
+		}
+	}
+	return SessionClosed
+}
+
+// IsMarketOpen bernilai true hanya saat sesi perdagangan kontinu (sesi 1 dan sesi 2).
+func (c *Calendar) IsMarketOpen(t time.Time) bool {
+	session := c.SessionAt(t)
+	return session == SessionFirst || session == SessionSecond
+}
+
+// IsPreOpening bernilai true saat sesi pra-pembukaan, order sudah bisa dipasang.
+func (c *Calendar) IsPreOpening(t time.Time) bool {
+	return c.SessionAt(t) == SessionPreOpening
+}
+
+func (c *Calendar) openAt(day time.Time) time.Time {
+	return c.sessionStart(day, SessionFirst)
+}
+
+func (c *Calendar) closeAt(day time.Time) time.Time {
+	return c.sessionEnd(day, SessionSecond)
+}
+
+func (c *Calendar) sessionStart(day time.Time, session Session) time.Time {
+	for _, window := range c.schedule(day) {
+		if window.session == session {
+			return window.start.on(c.startOfDay(day))
+		}
+	}
+	return time.Time{}
+}
+
+func (c *Calendar) sessionEnd(day time.Time, session Session) time.Time {
+	for _, window := range c.schedule(day) {
+		if window.session == session {
+			return window.end.on(c.startOfDay(day))
+		}
+	}
+	return time.Time{}
+}
+
+// NextOpen mengembalikan waktu pembukaan sesi perdagangan berikutnya setelah t.
+// Jika t berada di jeda siang, yang dikembalikan adalah pembukaan sesi 2.
+func (c *Calendar) NextOpen(t time.Time) time.Time {
+	t = t.In(c.loc)
+	if c.IsTradingDay(t) {
+		if open := c.openAt(t); t.Before(open) {
+			return open
+		}
+		if second := c.sessionStart(t, SessionSecond); t.Before(second) && !c.IsMarketOpen(t) {
+			return second
+		}
+	}
+	return c.openAt(c.NextTradingDay(t))
+}
+
+// LastClose mengembalikan waktu penutupan perdagangan kontinu terakhir yang sudah terjadi
+// pada atau sebelum t.
+func (c *Calendar) LastClose(t time.Time) time.Time {
+	t = t.In(c.loc)
+	if c.IsTradingDay(t) {
+		if closeTime := c.closeAt(t); !t.Before(closeTime) {
+			return closeTime
+		}
+	}
+	return c.closeAt(c.PreviousTradingDay(t))
+}
+
+// LastTradingMoment adalah waktu terakhir saat harga masih diperdagangkan pada atau sebelum t.
+// Saat pasar buka hasilnya t itu sendiri, saat jeda siang hasilnya penutupan sesi 1,
+// selain itu penutupan perdagangan terakhir.
+func (c *Calendar) LastTradingMoment(t time.Time) time.Time {
+	t = t.In(c.loc)
+	if c.IsMarketOpen(t) {
+		return t
+	}
+	if c.SessionAt(t) == SessionBreak {
+		return c.sessionEnd(t, SessionFirst)
+	}
+	return c.LastClose(t)
+}
+
+// NextAlertTime menggeser waktu t ke waktu terdekat saat user bisa memasang order, yaitu
+// pre-opening, sesi perdagangan atau pre-closing, dipakai untuk menjadwalkan alert agar tidak
+// terkirim saat bursa tutup. Saat jeda siang hasilnya pembukaan sesi 2.
+func (c *Calendar) NextAlertTime(t time.Time) time.Time {
+	t = t.In(c.loc)
+	if c.IsMarketOpen(t) || c.IsPreOpening(t) || c.SessionAt(t) == SessionPreClosing {
+		return t
+	}
+	next := c.NextOpen(t)
+	if preOpening := c.sessionStart(next, SessionPreOpening); t.Before(preOpening) {
+		return preOpening
+	}
+	return next
+}
+
+func ToHoliday(entity models.MarketHolidayEntity) Holiday {
+	return Holiday{
+		Date: entity.Date.Format(dateLayout),
+		Name: entity.Name,
+	}
+}
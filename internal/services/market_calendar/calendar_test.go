@@ -0,0 +1,223 @@
+package market_calendar
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newTestCalendar(t *testing.T) *Calendar {
+	t.Helper()
+	c, err := NewCalendar(nil, logrus.New())
+	if err != nil {
+		t.Fatalf("NewCalendar() error = %v", err)
+	}
+	if err := c.SetHolidays([]Holiday{
+		{Date: "2025-05-01", Name: "Hari Buruh Internasional"},
+		{Date: "2025-05-12", Name: "Hari Raya Waisak"},
+		{Date: "2025-05-13", Name: "Cuti Bersama Hari Raya Waisak"},
+	}); err != nil {
+		t.Fatalf("SetHolidays() error = %v", err)
+	}
+	return c
+}
+
+func wib(t *testing.T, value string) time.Time {
+	t.Helper()
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+	parsed, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+	if err != nil {
+		t.Fatalf("ParseInLocation() error = %v", err)
+	}
+	return parsed
+}
+
+func TestCalendar_IsTradingDay(t *testing.T) {
+	c := newTestCalendar(t)
+	tests := []struct {
+		name string
+		date string
+		want bool
+	}{
+		{name: "regular weekday", date: "2025-05-02 10:00", want: true},
+		{name: "saturday", date: "2025-05-03 10:00", want: false},
+		{name: "sunday", date: "2025-05-04 10:00", want: false},
+		{name: "holiday", date: "2025-05-01 10:00", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.IsTradingDay(wib(t, tt.date)); got != tt.want {
+				t.Errorf("IsTradingDay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendar_HoldingDays(t *testing.T) {
+	c := newTestCalendar(t)
+	tests := []struct {
+		name    string
+		buyDate string
+		now     string
+		want    int
+	}{
+		{name: "same day", buyDate: "2025-05-05 09:30", now: "2025-05-05 15:00", want: 0},
+		{name: "over weekend", buyDate: "2025-05-02 09:30", now: "2025-05-05 09:30", want: 1},
+		{name: "over holidays", buyDate: "2025-05-09 09:30", now: "2025-05-14 09:30", want: 1},
+		{name: "future buy date", buyDate: "2025-05-14 09:30", now: "2025-05-09 09:30", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.HoldingDays(wib(t, tt.buyDate), wib(t, tt.now)); got != tt.want {
+				t.Errorf("HoldingDays() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendar_RemainingHoldingDays(t *testing.T) {
+	c := newTestCalendar(t)
+	tests := []struct {
+		name    string
+		max     int
+		buyDate string
+		now     string
+		want    int
+	}{
+		{name: "within period", max: 5, buyDate: "2025-05-02 09:30", now: "2025-05-06 09:30", want: 3},
+		{name: "expired", max: 1, buyDate: "2025-05-02 09:30", now: "2025-05-08 09:30", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.RemainingHoldingDays(tt.max, wib(t, tt.buyDate), wib(t, tt.now)); got != tt.want {
+				t.Errorf("RemainingHoldingDays() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendar_SessionAt(t *testing.T) {
+	c := newTestCalendar(t)
+	tests := []struct {
+		name string
+		at   string
+		want Session
+	}{
+		{name: "before pre-opening", at: "2025-05-05 08:00", want: SessionClosed},
+		{name: "pre-opening", at: "2025-05-05 08:50", want: SessionPreOpening},
+		{name: "session 1", at: "2025-05-05 09:00", want: SessionFirst},
+		{name: "lunch break", at: "2025-05-05 12:30", want: SessionBreak},
+		{name: "friday session 1 ends earlier", at: "2025-05-02 11:45", want: SessionBreak},
+		{name: "friday session 2", at: "2025-05-02 14:00", want: SessionSecond},
+		{name: "pre-closing", at: "2025-05-05 15:55", want: SessionPreClosing},
+		{name: "after close", at: "2025-05-05 17:00", want: SessionClosed},
+		{name: "holiday", at: "2025-05-01 10:00", want: SessionClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.SessionAt(wib(t, tt.at)); got != tt.want {
+				t.Errorf("SessionAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendar_NextOpen(t *testing.T) {
+	c := newTestCalendar(t)
+	tests := []struct {
+		name string
+		at   string
+		want string
+	}{
+		{name: "early morning", at: "2025-05-05 07:00", want: "2025-05-05 09:00"},
+		{name: "lunch break", at: "2025-05-05 12:30", want: "2025-05-05 13:30"},
+		{name: "after close before weekend", at: "2025-05-02 17:00", want: "2025-05-05 09:00"},
+		{name: "before long holiday", at: "2025-05-09 17:00", want: "2025-05-14 09:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.NextOpen(wib(t, tt.at)); !got.Equal(wib(t, tt.want)) {
+				t.Errorf("NextOpen() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendar_NextAlertTime(t *testing.T) {
+	c := newTestCalendar(t)
+	tests := []struct {
+		name string
+		at   string
+		want string
+	}{
+		{name: "market open", at: "2025-05-05 10:15", want: "2025-05-05 10:15"},
+		{name: "pre-opening", at: "2025-05-05 08:50", want: "2025-05-05 08:50"},
+		{name: "early morning", at: "2025-05-05 07:00", want: "2025-05-05 08:45"},
+		{name: "lunch break", at: "2025-05-05 12:30", want: "2025-05-05 13:30"},
+		{name: "pre-closing", at: "2025-05-05 15:55", want: "2025-05-05 15:55"},
+		{name: "post-trading", at: "2025-05-05 16:05", want: "2025-05-06 08:45"},
+		{name: "after close before weekend", at: "2025-05-02 17:00", want: "2025-05-05 08:45"},
+		{name: "before long holiday", at: "2025-05-09 17:00", want: "2025-05-14 08:45"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.NextAlertTime(wib(t, tt.at)); !got.Equal(wib(t, tt.want)) {
+				t.Errorf("NextAlertTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDefaultHolidays_Years memastikan kalender libur bawaan lengkap untuk tahun yang sudah
+// dirilis IDX. IDX biasanya libur sekitar 20 hari bursa per tahun termasuk cuti bersama.
+// Tambahkan tahun baru di sini saat holidays_idx.json diperbarui.
+func TestDefaultHolidays_Years(t *testing.T) {
+	const minHolidaysPerYear = 15
+
+	var holidays []Holiday
+	if err := json.Unmarshal(defaultHolidaysJSON, &holidays); err != nil {
+		t.Fatalf("failed to parse holidays_idx.json: %v", err)
+	}
+	counts := make(map[int]int)
+	for _, holiday := range holidays {
+		date, err := time.Parse(time.DateOnly, holiday.Date)
+		if err != nil {
+			t.Fatalf("invalid holiday date %q: %v", holiday.Date, err)
+		}
+		counts[date.Year()]++
+	}
+
+	for _, year := range []int{2025, 2026} {
+		t.Run(strconv.Itoa(year), func(t *testing.T) {
+			if counts[year] < minHolidaysPerYear {
+				t.Errorf("holidays_idx.json has %d holidays for %d, want at least %d", counts[year], year, minHolidaysPerYear)
+			}
+		})
+	}
+}
+
+func TestCalendar_hasHolidaysIn(t *testing.T) {
+	c := newTestCalendar(t)
+	if err := c.SetHolidays([]Holiday{{Date: "2026-01-01", Name: "Tahun Baru"}}); err != nil {
+		t.Fatalf("SetHolidays() error = %v", err)
+	}
+
+	tests := []struct {
+		year int
+		want bool
+	}{
+		{year: 2026, want: true},
+		{year: 2027, want: false},
+	}
+	for _, tt := range tests {
+		if got := c.hasHolidaysIn(tt.year); got != tt.want {
+			t.Errorf("hasHolidaysIn(%d) = %v, want %v", tt.year, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package market_price
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/market_calendar"
+	"golang-swing-trading-signal/internal/services/yahoo_finance"
+	"golang-swing-trading-signal/pkg/redis"
+
+	goRedis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultLiveMaxAge    = 2 * time.Minute
+	defaultDelayedMaxAge = 15 * time.Minute
+
+	// toleransi jam antar server sebelum timestamp dianggap berasal dari masa depan
+	maxClockSkew = 5 * time.Minute
+
+	// batas request paralel ke Yahoo Finance saat banyak harga tidak ada di redis
+	maxYahooFallbackConcurrency = 4
+)
+
+var (
+	ErrPriceMissing = errors.New("price is missing")
+	ErrPriceInvalid = errors.New("price is invalid")
+)
+
+type PriceService interface {
+	// GetLastPrices mengembalikan harga terakhir untuk setiap kode saham. Harga dari redis yang
+	// tidak ada atau tidak valid diganti dengan harga penutupan terakhir dari Yahoo Finance.
+	GetLastPrices(ctx context.Context, stockCodes []string) (map[string]models.MarketPrice, error)
+	// GetCachedPrices hanya membaca harga dari redis tanpa fallback Yahoo, untuk jalur yang
+	// harus cepat seperti inline query. Saham tanpa harga valid tidak ada di hasil.
+	GetCachedPrices(ctx context.Context, stockCodes []string) (map[string]models.MarketPrice, error)
+	GetLastPrice(ctx context.Context, stockCode string) (*models.MarketPrice, error)
+	// Classify menentukan status kesegaran harga pada waktu priceTime jika dilihat pada waktu now.
+	Classify(priceTime time.Time, now time.Time) models.PriceFreshness
+}
+
+type priceService struct {
+	cfg            *config.MarketPriceConfig
+	logger         *logrus.Logger
+	redisClient    *redis.Client
+	yahooClient    *yahoo_finance.Client
+	marketCalendar *market_calendar.Calendar
+}
+
+func NewPriceService(
+	cfg *config.MarketPriceConfig,
+	logger *logrus.Logger,
+	redisClient *redis.Client,
+	yahooClient *yahoo_finance.Client,
+	marketCalendar *market_calendar.Calendar,
+) PriceService {
+	return &priceService{
+		cfg:            cfg,
+		logger:         logger,
+		redisClient:    redisClient,
+		yahooClient:    yahooClient,
+		marketCalendar: marketCalendar,
+	}
+}
+
+func lastPriceKey(stockCode string) string {
+	return fmt.Sprintf("last_price:%s", stockCode)
+}
+
+func (s *priceService) liveMaxAge() time.Duration {
+	if s.cfg != nil && s.cfg.LiveMaxAge > 0 {
+		return s.cfg.LiveMaxAge
+	}
+	return defaultLiveMaxAge
+}
+
+func (s *priceService) delayedMaxAge() time.Duration {
+	if s.cfg != nil && s.cfg.DelayedMaxAge > 0 {
+		return s.cfg.DelayedMaxAge
+	}
+	return defaultDelayedMaxAge
+}
+
+func (s *priceService) Classify(priceTime time.Time, now time.Time) models.PriceFreshness {
+	// Harga dibandingkan dengan waktu terakhir pasar benar-benar berdagang, sehingga harga
+	// penutupan Jumat tetap dianggap segar saat dilihat pada hari Sabtu.
+	lag := s.marketCalendar.LastTradingMoment(now).Sub(priceTime)
+	switch {
+	case lag <= s.liveMaxAge():
+		return models.PriceFreshnessLive
+	case lag <= s.delayedMaxAge():
+		return models.PriceFreshnessDelayed
+	default:
+		return models.PriceFreshnessStale
+	}
+}
+
+func (s *priceService) GetLastPrice(ctx context.Context, stockCode string) (*models.MarketPrice, error) {
+	prices, err := s.GetLastPrices(ctx, []string{stockCode})
+	if err != nil {
+		return nil, err
+	}
+
+	price, ok := prices[stockCode]
+	if !ok {
+		return nil, nil
+	}
+	return &price, nil
+}
+
+func (s *priceService) GetLastPrices(ctx context.Context, stockCodes []string) (map[string]models.MarketPrice, error) {
+	now := s.marketCalendar.Now()
+	result, missing, err := s.getRedisPrices(ctx, stockCodes, now)
+	if err != nil {
+		return nil, err
+	}
+
+	for stockCode, price := range s.fallbackPrices(ctx, missing, now) {
+		result[stockCode] = price
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get last prices: %w", err)
+	}
+	return result, nil
+}
+
+func (s *priceService) GetCachedPrices(ctx context.Context, stockCodes []string) (map[string]models.MarketPrice, error) {
+	result, _, err := s.getRedisPrices(ctx, stockCodes, s.marketCalendar.Now())
+	return result, err
+}
+
+// getRedisPrices membaca harga last_price dari redis dan mengembalikan kode saham yang
+// harganya tidak ada atau tidak valid.
+func (s *priceService) getRedisPrices(ctx context.Context, stockCodes []string, now time.Time) (map[string]models.MarketPrice, []string, error) {
+	result := make(map[string]models.MarketPrice, len(stockCodes))
+	if len(stockCodes) == 0 {
+		return result, nil, nil
+	}
+
+	cmds := make(map[string]*goRedis.MapStringStringCmd, len(stockCodes))
+	pipe := s.redisClient.Pipeline()
+	for _, stockCode := range stockCodes {
+		cmds[stockCode] = pipe.HGetAll(ctx, lastPriceKey(stockCode))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.logger.Error("failed to get last prices from redis", logrus.Fields{"error": err})
+		return nil, nil, fmt.Errorf("failed to get last prices: %w", err)
+	}
+
+	missing := make([]string, 0, len(cmds))
+	for stockCode, cmd := range cmds {
+		price, err := s.parseLastPrice(stockCode, cmd.Val(), now)
+		if err == nil {
+			result[stockCode] = *price
+			continue
+		}
+
+		if errors.Is(err, ErrPriceInvalid) {
+			s.logger.Warn("invalid last price in redis", logrus.Fields{
+				"stock_code": stockCode,
+				"error":      err,
+			})
+		} else {
+			s.logger.Debug("last price not found in redis", logrus.Fields{
+				"stock_code": stockCode,
+			})
+		}
+		missing = append(missing, stockCode)
+	}
+	return result, missing, nil
+}
+
+// fallbackPrices mengambil harga penutupan dari Yahoo Finance secara paralel dengan batas
+// maxYahooFallbackConcurrency. Saham yang gagal diambil tidak ada di hasil.
+func (s *priceService) fallbackPrices(ctx context.Context, stockCodes []string, now time.Time) map[string]models.MarketPrice {
+	result := make(map[string]models.MarketPrice, len(stockCodes))
+	var (
+		mu sync.Mutex
+		g  errgroup.Group
+	)
+	g.SetLimit(maxYahooFallbackConcurrency)
+	for _, stockCode := range stockCodes {
+		if ctx.Err() != nil {
+			// request sudah dibatalkan, saham sisanya tidak perlu diambil
+			break
+		}
+		g.Go(func() error {
+			fallback, err := s.getLatestClose(ctx, stockCode, now)
+			if err != nil {
+				// harga yang gagal diambil tidak menggagalkan harga saham lain
+				s.logger.Error("failed to get latest close from yahoo", logrus.Fields{
+					"stock_code": stockCode,
+					"error":      err,
+				})
+				return nil
+			}
+			mu.Lock()
+			result[stockCode] = *fallback
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return result
+}
+
+// parseLastPrice memvalidasi isi hash last_price:<code> yang berisi field price (float)
+// dan timestamp (unix detik atau milidetik).
+func (s *priceService) parseLastPrice(stockCode string, data map[string]string, now time.Time) (*models.MarketPrice, error) {
+	if len(data) == 0 {
+		return nil, ErrPriceMissing
+	}
+
+	price, err := strconv.ParseFloat(data["price"], 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse price %q: %v", ErrPriceInvalid, data["price"], err)
+	}
+	if price <= 0 {
+		return nil, fmt.Errorf("%w: non positive price %v", ErrPriceInvalid, price)
+	}
+
+	timestamp, err := strconv.ParseInt(data["timestamp"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse timestamp %q: %v", ErrPriceInvalid, data["timestamp"], err)
+	}
+	if timestamp <= 0 {
+		return nil, fmt.Errorf("%w: non positive timestamp %d", ErrPriceInvalid, timestamp)
+	}
+
+	priceTime := time.Unix(timestamp, 0)
+	if timestamp > 1e12 {
+		priceTime = time.UnixMilli(timestamp)
+	}
+	priceTime = priceTime.In(s.marketCalendar.Location())
+	if priceTime.After(now.Add(maxClockSkew)) {
+		return nil, fmt.Errorf("%w: timestamp %s is in the future", ErrPriceInvalid, priceTime.Format(time.RFC3339))
+	}
+
+	return &models.MarketPrice{
+		StockCode: stockCode,
+		Price:     price,
+		Time:      priceTime,
+		Source:    models.PriceSourceRedis,
+		Freshness: s.Classify(priceTime, now),
+	}, nil
+}
+
+// getLatestClose mengambil harga penutupan candle harian terakhir dari Yahoo Finance.
+// Harga Yahoo tidak pernah dianggap live: delayed jika berasal dari hari bursa terakhir,
+// selain itu stale.
+func (s *priceService) getLatestClose(ctx context.Context, stockCode string, now time.Time) (*models.MarketPrice, error) {
+	latest, err := s.yahooClient.GetLatestOHLCData(ctx, stockCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest ohlc data: %w", err)
+	}
+
+	barTime := time.Unix(latest.Timestamp, 0).In(s.marketCalendar.Location())
+	freshness := models.PriceFreshnessStale
+	if barTime.Format("2006-01-02") == s.marketCalendar.LastTradingMoment(now).Format("2006-01-02") {
+		freshness = models.PriceFreshnessDelayed
+	}
+
+	return &models.MarketPrice{
+		StockCode: stockCode,
+		Price:     latest.Close,
+		Time:      barTime,
+		Source:    models.PriceSourceYahoo,
+		Freshness: freshness,
+	}, nil
+}
@@ -0,0 +1,169 @@
+package market_price
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/market_calendar"
+	"golang-swing-trading-signal/internal/services/yahoo_finance"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newTestPriceService(t *testing.T) *priceService {
+	t.Helper()
+	calendar, err := market_calendar.NewCalendar(nil, logrus.New())
+	if err != nil {
+		t.Fatalf("NewCalendar() error = %v", err)
+	}
+	return &priceService{
+		cfg:            &config.MarketPriceConfig{LiveMaxAge: 2 * time.Minute, DelayedMaxAge: 15 * time.Minute},
+		logger:         logrus.New(),
+		marketCalendar: calendar,
+	}
+}
+
+func wib(t *testing.T, value string) time.Time {
+	t.Helper()
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+	parsed, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+	if err != nil {
+		t.Fatalf("ParseInLocation() error = %v", err)
+	}
+	return parsed
+}
+
+func TestPriceService_Classify(t *testing.T) {
+	s := newTestPriceService(t)
+	tests := []struct {
+		name      string
+		priceTime string
+		now       string
+		want      models.PriceFreshness
+	}{
+		{name: "live during session", priceTime: "2025-07-07 10:00", now: "2025-07-07 10:01", want: models.PriceFreshnessLive},
+		{name: "delayed during session", priceTime: "2025-07-07 10:00", now: "2025-07-07 10:10", want: models.PriceFreshnessDelayed},
+		{name: "stale during session", priceTime: "2025-07-07 09:00", now: "2025-07-07 10:00", want: models.PriceFreshnessStale},
+		{name: "lunch break uses session 1 close", priceTime: "2025-07-07 11:59", now: "2025-07-07 13:00", want: models.PriceFreshnessLive},
+		{name: "weekend uses friday close", priceTime: "2025-07-04 15:49", now: "2025-07-05 10:00", want: models.PriceFreshnessLive},
+		{name: "previous day price before open", priceTime: "2025-07-03 15:00", now: "2025-07-04 08:00", want: models.PriceFreshnessStale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Classify(wib(t, tt.priceTime), wib(t, tt.now)); got != tt.want {
+				t.Errorf("Classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriceService_parseLastPrice(t *testing.T) {
+	s := newTestPriceService(t)
+	now := wib(t, "2025-07-07 10:01")
+	tests := []struct {
+		name      string
+		data      map[string]string
+		wantErr   error
+		wantPrice float64
+	}{
+		{name: "missing", data: map[string]string{}, wantErr: ErrPriceMissing},
+		{name: "float price", data: map[string]string{"price": "1234.5", "timestamp": "1751857200"}, wantPrice: 1234.5},
+		{name: "millisecond timestamp", data: map[string]string{"price": "1000", "timestamp": "1751857200000"}, wantPrice: 1000},
+		{name: "invalid price", data: map[string]string{"price": "abc", "timestamp": "1751857200"}, wantErr: ErrPriceInvalid},
+		{name: "zero price", data: map[string]string{"price": "0", "timestamp": "1751857200"}, wantErr: ErrPriceInvalid},
+		{name: "invalid timestamp", data: map[string]string{"price": "1000", "timestamp": ""}, wantErr: ErrPriceInvalid},
+		{name: "future timestamp", data: map[string]string{"price": "1000", "timestamp": "1851857200"}, wantErr: ErrPriceInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.parseLastPrice("BBCA", tt.data, now)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("parseLastPrice() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseLastPrice() unexpected error = %v", err)
+			}
+			if got.Price != tt.wantPrice {
+				t.Errorf("parseLastPrice() price = %v, want %v", got.Price, tt.wantPrice)
+			}
+			if got.Freshness != models.PriceFreshnessLive {
+				t.Errorf("parseLastPrice() freshness = %v, want %v", got.Freshness, models.PriceFreshnessLive)
+			}
+		})
+	}
+}
+
+func TestPriceService_fallbackPrices(t *testing.T) {
+	var inFlight, maxInFlight, calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := maxInFlight.Load()
+			if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		if strings.Contains(r.URL.Path, "FAIL") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"chart":{"result":[{"timestamp":[1751860800],"indicators":{"quote":[{"open":[100],"high":[110],"low":[90],"close":[105],"volume":[1000]}]}}]}}`)
+	}))
+	defer server.Close()
+
+	s := newTestPriceService(t)
+	s.yahooClient = yahoo_finance.NewClient(&config.YahooFinanceConfig{BaseURL: server.URL}, logrus.New())
+	now := wib(t, "2025-07-07 10:00")
+
+	tests := []struct {
+		name      string
+		codes     []string
+		cancelled bool
+		wantCodes int
+		wantCalls int32
+	}{
+		{name: "bounded concurrency", codes: []string{"AAAA", "BBBB", "CCCC", "DDDD", "EEEE", "FFFF", "GGGG", "HHHH"}, wantCodes: 8, wantCalls: 8},
+		{name: "failed code skipped", codes: []string{"AAAA", "FAIL"}, wantCodes: 1, wantCalls: 2},
+		{name: "cancelled context skips fetch", codes: []string{"AAAA", "BBBB"}, cancelled: true, wantCodes: 0, wantCalls: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls.Store(0)
+			maxInFlight.Store(0)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelled {
+				cancel()
+			}
+
+			got := s.fallbackPrices(ctx, tt.codes, now)
+			if len(got) != tt.wantCodes {
+				t.Errorf("fallbackPrices() = %d prices, want %d", len(got), tt.wantCodes)
+			}
+			if calls.Load() != tt.wantCalls {
+				t.Errorf("yahoo calls = %d, want %d", calls.Load(), tt.wantCalls)
+			}
+			if maxInFlight.Load() > maxYahooFallbackConcurrency {
+				t.Errorf("max concurrent yahoo calls = %d, want <= %d", maxInFlight.Load(), maxYahooFallbackConcurrency)
+			}
+		})
+	}
+}
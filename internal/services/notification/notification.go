@@ -0,0 +1,217 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/repository"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInvalidNotificationType = errors.New("invalid notification type")
+	ErrInvalidQuietHours       = errors.New("invalid quiet hours")
+	ErrInvalidMinConfidence    = errors.New("invalid minimum confidence")
+	ErrInvalidVerbosity        = errors.New("invalid verbosity")
+)
+
+// MinConfidenceOptions adalah pilihan minimum confidence yang bisa dipilih dari /settings.
+var MinConfidenceOptions = []int{0, 50, 60, 70, 80, 90}
+
+type NotificationService interface {
+	// GetPreference mengembalikan preferensi user, atau preferensi default jika belum diatur.
+	GetPreference(ctx context.Context, telegramID int64) (models.NotificationPreferenceEntity, error)
+	SetEnabled(ctx context.Context, telegramID int64, notificationType string, enabled bool) (models.NotificationPreferenceEntity, error)
+	// SetQuietHours menerima rentang HH:MM-HH:MM WIB, atau "off" untuk mematikan jam tenang.
+	SetQuietHours(ctx context.Context, telegramID int64, value string) (models.NotificationPreferenceEntity, error)
+	SetMinConfidence(ctx context.Context, telegramID int64, minConfidence int) (models.NotificationPreferenceEntity, error)
+	SetVerbosity(ctx context.Context, telegramID int64, verbosity string) (models.NotificationPreferenceEntity, error)
+	// Decide mengevaluasi preferensi user terhadap notifikasi otomatis: apakah dikirim,
+	// ditunda sampai jam tenang selesai, dan format pesan yang dipakai.
+	Decide(ctx context.Context, telegramID int64, notification models.Notification) (models.NotificationDecision, error)
+}
+
+type notificationService struct {
+	logger                           *logrus.Logger
+	notificationPreferenceRepository repository.NotificationPreferenceRepository
+	now                              func() time.Time
+}
+
+func NewNotificationService(logger *logrus.Logger, notificationPreferenceRepository repository.NotificationPreferenceRepository) NotificationService {
+	return &notificationService{
+		logger:                           logger,
+		notificationPreferenceRepository: notificationPreferenceRepository,
+		now:                              utils.TimeNowWIB,
+	}
+}
+
+// ParseQuietHours membaca rentang jam tenang seperti "22:00-06:00" dan mengembalikannya
+// dalam format HH:MM. "off" atau "-" mematikan jam tenang.
+func ParseQuietHours(value string) (string, string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "off" || value == "-" || value == "mati" {
+		return "", "", nil
+	}
+
+	startValue, endValue, ok := strings.Cut(value, "-")
+	if !ok {
+		return "", "", ErrInvalidQuietHours
+	}
+	startHour, startMinute, ok := utils.ParseClock(strings.TrimSpace(startValue))
+	if !ok {
+		return "", "", ErrInvalidQuietHours
+	}
+	endHour, endMinute, ok := utils.ParseClock(strings.TrimSpace(endValue))
+	if !ok {
+		return "", "", ErrInvalidQuietHours
+	}
+
+	start := fmt.Sprintf("%02d:%02d", startHour, startMinute)
+	end := fmt.Sprintf("%02d:%02d", endHour, endMinute)
+	if start == end {
+		return "", "", ErrInvalidQuietHours
+	}
+	return start, end, nil
+}
+
+// QuietUntil mengembalikan akhir jam tenang jika now berada di dalam jam tenang user.
+// Rentang yang melewati tengah malam (misal 22:00-06:00) didukung.
+func QuietUntil(preference models.NotificationPreferenceEntity, now time.Time) (time.Time, bool) {
+	if !preference.HasQuietHours() {
+		return time.Time{}, false
+	}
+	startHour, startMinute, ok := utils.ParseClock(preference.QuietStart)
+	if !ok {
+		return time.Time{}, false
+	}
+	endHour, endMinute, ok := utils.ParseClock(preference.QuietEnd)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	now = utils.TimeToWIB(now)
+	start := time.Date(now.Year(), now.Month(), now.Day(), startHour, startMinute, 0, 0, now.Location())
+	end := time.Date(now.Year(), now.Month(), now.Day(), endHour, endMinute, 0, 0, now.Location())
+
+	if start.Before(end) {
+		if !now.Before(start) && now.Before(end) {
+			return end, true
+		}
+		return time.Time{}, false
+	}
+
+	// rentang melewati tengah malam
+	if !now.Before(start) {
+		return end.AddDate(0, 0, 1), true
+	}
+	if now.Before(end) {
+		return end, true
+	}
+	return time.Time{}, false
+}
+
+// Evaluate menerapkan preferensi user pada notifikasi: jenis notifikasi yang dimatikan dan
+// perubahan sinyal di bawah minimum confidence tidak dikirim, notifikasi di jam tenang ditunda.
+func Evaluate(preference models.NotificationPreferenceEntity, notification models.Notification, now time.Time) models.NotificationDecision {
+	decision := models.NotificationDecision{Compact: preference.IsCompact()}
+	if !preference.Wants(notification.Type) {
+		return decision
+	}
+	if notification.Confidence != nil && *notification.Confidence < preference.MinConfidence {
+		return decision
+	}
+
+	decision.Send = true
+	if until, quiet := QuietUntil(preference, now); quiet {
+		decision.SendAfter = until
+	}
+	return decision
+}
+
+func (s *notificationService) GetPreference(ctx context.Context, telegramID int64) (models.NotificationPreferenceEntity, error) {
+	preference, err := s.notificationPreferenceRepository.GetPreference(ctx, telegramID)
+	if err != nil {
+		return models.NotificationPreferenceEntity{}, fmt.Errorf("failed to get notification preference: %w", err)
+	}
+	if preference == nil {
+		return models.DefaultNotificationPreference(telegramID), nil
+	}
+	return *preference, nil
+}
+
+func (s *notificationService) SetEnabled(ctx context.Context, telegramID int64, notificationType string, enabled bool) (models.NotificationPreferenceEntity, error) {
+	if !slices.Contains(models.NotificationTypes, notificationType) {
+		return models.NotificationPreferenceEntity{}, ErrInvalidNotificationType
+	}
+	return s.updatePreference(ctx, telegramID, func(preference *models.NotificationPreferenceEntity) {
+		switch notificationType {
+		case models.NotificationTargetHit:
+			preference.TargetHit = enabled
+		case models.NotificationSignalChange:
+			preference.SignalChange = enabled
+		case models.NotificationDigest:
+			preference.Digest = enabled
+		}
+	})
+}
+
+func (s *notificationService) SetQuietHours(ctx context.Context, telegramID int64, value string) (models.NotificationPreferenceEntity, error) {
+	start, end, err := ParseQuietHours(value)
+	if err != nil {
+		return models.NotificationPreferenceEntity{}, err
+	}
+	return s.updatePreference(ctx, telegramID, func(preference *models.NotificationPreferenceEntity) {
+		preference.QuietStart = start
+		preference.QuietEnd = end
+	})
+}
+
+func (s *notificationService) SetMinConfidence(ctx context.Context, telegramID int64, minConfidence int) (models.NotificationPreferenceEntity, error) {
+	if minConfidence < 0 || minConfidence > 100 {
+		return models.NotificationPreferenceEntity{}, ErrInvalidMinConfidence
+	}
+	return s.updatePreference(ctx, telegramID, func(preference *models.NotificationPreferenceEntity) {
+		preference.MinConfidence = minConfidence
+	})
+}
+
+func (s *notificationService) SetVerbosity(ctx context.Context, telegramID int64, verbosity string) (models.NotificationPreferenceEntity, error) {
+	if verbosity != models.VerbosityCompact && verbosity != models.VerbosityDetailed {
+		return models.NotificationPreferenceEntity{}, ErrInvalidVerbosity
+	}
+	return s.updatePreference(ctx, telegramID, func(preference *models.NotificationPreferenceEntity) {
+		preference.Verbosity = verbosity
+	})
+}
+
+func (s *notificationService) updatePreference(ctx context.Context, telegramID int64, update func(preference *models.NotificationPreferenceEntity)) (models.NotificationPreferenceEntity, error) {
+	preference, err := s.GetPreference(ctx, telegramID)
+	if err != nil {
+		return models.NotificationPreferenceEntity{}, err
+	}
+	update(&preference)
+
+	if err := s.notificationPreferenceRepository.UpsertPreference(ctx, &preference); err != nil {
+		s.logger.Error("failed to save notification preference", logrus.Fields{
+			"telegram_id": telegramID,
+			"error":       err,
+		})
+		return models.NotificationPreferenceEntity{}, fmt.Errorf("failed to save notification preference: %w", err)
+	}
+	return preference, nil
+}
+
+func (s *notificationService) Decide(ctx context.Context, telegramID int64, notification models.Notification) (models.NotificationDecision, error) {
+	preference, err := s.GetPreference(ctx, telegramID)
+	if err != nil {
+		return models.NotificationDecision{}, err
+	}
+	return Evaluate(preference, notification, s.now()), nil
+}
@@ -0,0 +1,112 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	tests := []struct {
+		value     string
+		wantStart string
+		wantEnd   string
+		wantErr   bool
+	}{
+		{value: "22:00-06:00", wantStart: "22:00", wantEnd: "06:00"},
+		{value: "22.30 - 5:00", wantStart: "22:30", wantEnd: "05:00"},
+		{value: "12:00-13:00", wantStart: "12:00", wantEnd: "13:00"},
+		{value: "off"},
+		{value: " OFF "},
+		{value: "-"},
+		{value: "22:00", wantErr: true},
+		{value: "22:00-22:00", wantErr: true},
+		{value: "25:00-06:00", wantErr: true},
+		{value: "malam", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			start, end, err := ParseQuietHours(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseQuietHours() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("ParseQuietHours() = %q-%q, want %q-%q", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestQuietUntil(t *testing.T) {
+	wib := utils.TimeNowWIB().Location()
+	at := func(day, hour, minute int) time.Time { return time.Date(2026, 10, day, hour, minute, 0, 0, wib) }
+	overnight := models.NotificationPreferenceEntity{QuietStart: "22:00", QuietEnd: "06:00"}
+	lunch := models.NotificationPreferenceEntity{QuietStart: "12:00", QuietEnd: "13:00"}
+
+	tests := []struct {
+		name       string
+		preference models.NotificationPreferenceEntity
+		now        time.Time
+		wantUntil  time.Time
+		wantQuiet  bool
+	}{
+		{name: "no quiet hours", preference: models.NotificationPreferenceEntity{}, now: at(16, 23, 0)},
+		{name: "overnight before start", preference: overnight, now: at(16, 21, 59)},
+		{name: "overnight at start", preference: overnight, now: at(16, 22, 0), wantUntil: at(17, 6, 0), wantQuiet: true},
+		{name: "overnight after midnight", preference: overnight, now: at(17, 2, 0), wantUntil: at(17, 6, 0), wantQuiet: true},
+		{name: "overnight at end", preference: overnight, now: at(17, 6, 0)},
+		{name: "same day inside", preference: lunch, now: at(16, 12, 30), wantUntil: at(16, 13, 0), wantQuiet: true},
+		{name: "same day outside", preference: lunch, now: at(16, 13, 30)},
+		{name: "utc input", preference: overnight, now: at(16, 23, 0).UTC(), wantUntil: at(17, 6, 0), wantQuiet: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			until, quiet := QuietUntil(tt.preference, tt.now)
+			if quiet != tt.wantQuiet || !until.Equal(tt.wantUntil) {
+				t.Errorf("QuietUntil() = %v, %v, want %v, %v", until, quiet, tt.wantUntil, tt.wantQuiet)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	wib := utils.TimeNowWIB().Location()
+	day := time.Date(2026, 10, 16, 10, 0, 0, 0, wib)
+	night := time.Date(2026, 10, 16, 23, 0, 0, 0, wib)
+
+	preference := models.DefaultNotificationPreference(1)
+	preference.MinConfidence = 70
+	preference.QuietStart, preference.QuietEnd = "22:00", "06:00"
+	preference.Digest = false
+
+	compact := preference
+	compact.Verbosity = models.VerbosityCompact
+
+	tests := []struct {
+		name          string
+		preference    models.NotificationPreferenceEntity
+		notification  models.Notification
+		now           time.Time
+		wantSend      bool
+		wantSendAfter time.Time
+		wantCompact   bool
+	}{
+		{name: "default sends everything", preference: models.DefaultNotificationPreference(1), notification: models.Notification{Type: models.NotificationDigest}, now: night, wantSend: true},
+		{name: "disabled type", preference: preference, notification: models.Notification{Type: models.NotificationDigest}, now: day},
+		{name: "below min confidence", preference: preference, notification: models.Notification{Type: models.NotificationSignalChange, Confidence: utils.ToPointer(60)}, now: day},
+		{name: "at min confidence", preference: preference, notification: models.Notification{Type: models.NotificationSignalChange, Confidence: utils.ToPointer(70)}, now: day, wantSend: true},
+		{name: "target hit ignores confidence", preference: preference, notification: models.Notification{Type: models.NotificationTargetHit}, now: day, wantSend: true},
+		{name: "deferred in quiet hours", preference: preference, notification: models.Notification{Type: models.NotificationTargetHit}, now: night, wantSend: true, wantSendAfter: time.Date(2026, 10, 17, 6, 0, 0, 0, wib)},
+		{name: "compact", preference: compact, notification: models.Notification{Type: models.NotificationTargetHit}, now: day, wantSend: true, wantCompact: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Evaluate(tt.preference, tt.notification, tt.now)
+			if got.Send != tt.wantSend || !got.SendAfter.Equal(tt.wantSendAfter) || got.Compact != tt.wantCompact {
+				t.Errorf("Evaluate() = %+v, want send %v after %v compact %v", got, tt.wantSend, tt.wantSendAfter, tt.wantCompact)
+			}
+		})
+	}
+}
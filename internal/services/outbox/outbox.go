@@ -0,0 +1,256 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/repository"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+const (
+	defaultPollInterval = 2 * time.Second
+	defaultBatchSize    = 20
+	defaultMaxAttempts  = 5
+	defaultRetryBackoff = 5 * time.Second
+	maxRetryBackoff     = 10 * time.Minute
+	// claimLease adalah lama pesan berstatus sending sebelum boleh diambil ulang jika
+	// worker yang mengambilnya mati sebelum sempat memperbarui status.
+	claimLease = 2 * time.Minute
+)
+
+// Sender mengirim pesan ke chat dengan menghormati rate limit telegram.
+// Diimplementasikan oleh ratelimit.TelegramRateLimiter.
+type Sender interface {
+	SendToChat(ctx context.Context, chatID int64, what interface{}, opts ...interface{}) (*telebot.Message, error)
+}
+
+type OutboxService interface {
+	// Enqueue menyimpan pesan ke outbox untuk dikirim worker. Pesan dengan DedupKey yang
+	// sudah pernah dimasukkan tidak disimpan ulang, entity yang lama dikembalikan.
+	Enqueue(ctx context.Context, message models.TelegramOutboxMessage) (*models.TelegramOutboxEntity, error)
+	GetByID(ctx context.Context, id uint) (*models.TelegramOutboxEntity, error)
+	Start(ctx context.Context)
+	Stop()
+}
+
+type outboxService struct {
+	cfg                      *config.TelegramConfig
+	log                      *logrus.Logger
+	telegramOutboxRepository repository.TelegramOutboxRepository
+	sender                   Sender
+	now                      func() time.Time
+	wg                       sync.WaitGroup
+}
+
+func NewOutboxService(cfg *config.TelegramConfig, log *logrus.Logger, telegramOutboxRepository repository.TelegramOutboxRepository, sender Sender) OutboxService {
+	return &outboxService{
+		cfg:                      cfg,
+		log:                      log,
+		telegramOutboxRepository: telegramOutboxRepository,
+		sender:                   sender,
+		now:                      utils.TimeNowWIB,
+	}
+}
+
+func (s *outboxService) Enqueue(ctx context.Context, message models.TelegramOutboxMessage) (*models.TelegramOutboxEntity, error) {
+	entity := &models.TelegramOutboxEntity{
+		ChatID:        message.ChatID,
+		Text:          message.Text,
+		ParseMode:     message.ParseMode,
+		Status:        models.OutboxStatusPending,
+		NextAttemptAt: s.now(),
+	}
+	if message.SendAfter.After(entity.NextAttemptAt) {
+		entity.NextAttemptAt = message.SendAfter
+	}
+	if message.DedupKey != "" {
+		entity.DedupKey = utils.ToPointer(message.DedupKey)
+	}
+
+	created, err := s.telegramOutboxRepository.Create(ctx, entity)
+	if err != nil {
+		s.log.Error("failed to enqueue telegram message", logrus.Fields{
+			"chat_id": message.ChatID,
+			"error":   err,
+		})
+		return nil, fmt.Errorf("failed to enqueue telegram message: %w", err)
+	}
+	if !created {
+		s.log.Info("telegram message already enqueued, skipping duplicate", logrus.Fields{
+			"dedup_key": message.DedupKey,
+			"id":        entity.ID,
+		})
+	}
+	return entity, nil
+}
+
+func (s *outboxService) GetByID(ctx context.Context, id uint) (*models.TelegramOutboxEntity, error) {
+	message, err := s.telegramOutboxRepository.GetByID(ctx, id)
+	if err != nil {
+		s.log.Error("failed to get telegram outbox message", logrus.Fields{
+			"id":    id,
+			"error": err,
+		})
+		return nil, fmt.Errorf("failed to get telegram outbox message: %w", err)
+	}
+	return message, nil
+}
+
+// Start menjalankan worker yang mengirim pesan outbox secara berkala sampai ctx selesai.
+func (s *outboxService) Start(ctx context.Context) {
+	s.wg.Add(1)
+	utils.SafeGo(func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.pollInterval())
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				s.log.Info("Received signal to stop telegram outbox worker")
+				return
+			case <-ticker.C:
+				s.drain(ctx)
+			}
+		}
+	})
+}
+
+func (s *outboxService) Stop() {
+	s.wg.Wait()
+	s.log.Info("Telegram outbox worker stopped")
+}
+
+// drain mengirim pesan yang sudah jatuh tempo, batch demi batch sampai habis.
+func (s *outboxService) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		messages, err := s.telegramOutboxRepository.ClaimDue(ctx, s.now(), claimLease, s.batchSize())
+		if err != nil {
+			s.log.Error("failed to claim telegram outbox messages", logrus.Fields{"error": err})
+			return
+		}
+		for i := range messages {
+			s.deliver(ctx, &messages[i])
+		}
+		if len(messages) < s.batchSize() {
+			return
+		}
+	}
+}
+
+func (s *outboxService) deliver(ctx context.Context, message *models.TelegramOutboxEntity) {
+	opts := []interface{}{}
+	if message.ParseMode != "" {
+		opts = append(opts, telebot.ParseMode(message.ParseMode))
+	}
+
+	sent, err := s.sender.SendToChat(ctx, message.ChatID, message.Text, opts...)
+	if err != nil && ctx.Err() != nil {
+		// worker dihentikan saat menunggu rate limit, pesan diambil ulang setelah lease habis
+		return
+	}
+	s.applyResult(message, sent, err)
+
+	// status tetap disimpan walaupun worker sedang dihentikan
+	if errUpdate := s.telegramOutboxRepository.Update(context.WithoutCancel(ctx), message); errUpdate != nil {
+		s.log.Error("failed to update telegram outbox message", logrus.Fields{
+			"id":    message.ID,
+			"error": errUpdate,
+		})
+	}
+}
+
+// applyResult memperbarui status pesan berdasarkan hasil pengiriman.
+func (s *outboxService) applyResult(message *models.TelegramOutboxEntity, sent *telebot.Message, err error) {
+	now := s.now()
+	if err == nil {
+		message.Status = models.OutboxStatusSent
+		message.SentAt = utils.ToPointer(now)
+		message.LastError = ""
+		if sent != nil {
+			message.MessageID = utils.ToPointer(sent.ID)
+		}
+		return
+	}
+
+	message.LastError = err.Error()
+	logFields := logrus.Fields{
+		"id":       message.ID,
+		"chat_id":  message.ChatID,
+		"attempts": message.Attempts,
+		"error":    err,
+	}
+
+	var floodErr telebot.FloodError
+	switch {
+	case errors.As(err, &floodErr):
+		// flood wait tidak dihitung sebagai kegagalan, cukup tunggu sesuai retry_after
+		message.Status = models.OutboxStatusPending
+		message.Attempts--
+		message.NextAttemptAt = now.Add(time.Duration(floodErr.RetryAfter) * time.Second)
+		s.log.Warn("telegram flood wait, retrying later", logFields)
+	case isPermanentError(err) || message.Attempts >= s.maxAttempts():
+		message.Status = models.OutboxStatusFailed
+		s.log.Error("failed to deliver telegram message", logFields)
+	default:
+		message.Status = models.OutboxStatusPending
+		message.NextAttemptAt = now.Add(s.backoff(message.Attempts))
+		s.log.Warn("failed to send telegram message, retrying later", logFields)
+	}
+}
+
+// isPermanentError mengembalikan true untuk error yang tidak akan berhasil jika diulang,
+// misal bot diblokir user atau chat tidak ditemukan.
+func isPermanentError(err error) bool {
+	var telegramErr *telebot.Error
+	if !errors.As(err, &telegramErr) {
+		return false
+	}
+	return telegramErr.Code == 400 || telegramErr.Code == 403
+}
+
+// backoff eksponensial berdasarkan jumlah percobaan, dibatasi maxRetryBackoff.
+func (s *outboxService) backoff(attempts int) time.Duration {
+	delay := s.retryBackoff()
+	for i := 1; i < attempts && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryBackoff)
+}
+
+func (s *outboxService) pollInterval() time.Duration {
+	if s.cfg.OutboxPollInterval > 0 {
+		return s.cfg.OutboxPollInterval
+	}
+	return defaultPollInterval
+}
+
+func (s *outboxService) batchSize() int {
+	if s.cfg.OutboxBatchSize > 0 {
+		return s.cfg.OutboxBatchSize
+	}
+	return defaultBatchSize
+}
+
+func (s *outboxService) maxAttempts() int {
+	if s.cfg.OutboxMaxAttempts > 0 {
+		return s.cfg.OutboxMaxAttempts
+	}
+	return defaultMaxAttempts
+}
+
+func (s *outboxService) retryBackoff() time.Duration {
+	if s.cfg.OutboxRetryBackoff > 0 {
+		return s.cfg.OutboxRetryBackoff
+	}
+	return defaultRetryBackoff
+}
@@ -0,0 +1,232 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// fakeOutboxRepository menyimpan pesan outbox di memori.
+type fakeOutboxRepository struct {
+	messages map[uint]*models.TelegramOutboxEntity
+	nextID   uint
+}
+
+func newFakeOutboxRepository() *fakeOutboxRepository {
+	return &fakeOutboxRepository{messages: make(map[uint]*models.TelegramOutboxEntity)}
+}
+
+func (r *fakeOutboxRepository) Create(ctx context.Context, message *models.TelegramOutboxEntity, opts ...utils.DBOption) (bool, error) {
+	for _, existing := range r.messages {
+		if message.DedupKey != nil && existing.DedupKey != nil && *existing.DedupKey == *message.DedupKey {
+			*message = *existing
+			return false, nil
+		}
+	}
+	r.nextID++
+	message.ID = r.nextID
+	stored := *message
+	r.messages[message.ID] = &stored
+	return true, nil
+}
+
+func (r *fakeOutboxRepository) GetByID(ctx context.Context, id uint, opts ...utils.DBOption) (*models.TelegramOutboxEntity, error) {
+	return r.messages[id], nil
+}
+
+func (r *fakeOutboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.TelegramOutboxEntity, error) {
+	var claimed []models.TelegramOutboxEntity
+	for id := uint(1); id <= r.nextID && len(claimed) < limit; id++ {
+		message, ok := r.messages[id]
+		if !ok || message.NextAttemptAt.After(now) ||
+			(message.Status != models.OutboxStatusPending && message.Status != models.OutboxStatusSending) {
+			continue
+		}
+		message.Status = models.OutboxStatusSending
+		message.Attempts++
+		message.NextAttemptAt = now.Add(lease)
+		claimed = append(claimed, *message)
+	}
+	return claimed, nil
+}
+
+func (r *fakeOutboxRepository) Update(ctx context.Context, message *models.TelegramOutboxEntity, opts ...utils.DBOption) error {
+	stored := *message
+	r.messages[message.ID] = &stored
+	return nil
+}
+
+// botSender mengirim lewat telebot.Bot ke server telegram palsu sehingga error yang diuji
+// sama persis dengan yang dikembalikan telebot.
+type botSender struct {
+	bot *telebot.Bot
+}
+
+func (s *botSender) SendToChat(ctx context.Context, chatID int64, what interface{}, opts ...interface{}) (*telebot.Message, error) {
+	return s.bot.Send(&telebot.Chat{ID: chatID}, what, opts...)
+}
+
+func newBotSender(t *testing.T, response string) *botSender {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, response)
+	}))
+	t.Cleanup(server.Close)
+
+	bot, err := telebot.NewBot(telebot.Settings{Token: "test", URL: server.URL, Offline: true})
+	if err != nil {
+		t.Fatalf("telebot.NewBot() error = %v", err)
+	}
+	return &botSender{bot: bot}
+}
+
+func TestOutboxService_deliver(t *testing.T) {
+	now := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		response        string
+		attempts        int
+		wantStatus      string
+		wantAttempts    int
+		wantNextAttempt time.Time
+	}{
+		{
+			name:         "sent",
+			response:     `{"ok":true,"result":{"message_id":42,"chat":{"id":1}}}`,
+			attempts:     0,
+			wantStatus:   models.OutboxStatusSent,
+			wantAttempts: 1,
+		},
+		{
+			name:            "flood wait honors retry_after",
+			response:        `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 7","parameters":{"retry_after":7}}`,
+			attempts:        4,
+			wantStatus:      models.OutboxStatusPending,
+			wantAttempts:    4,
+			wantNextAttempt: now.Add(7 * time.Second),
+		},
+		{
+			name:            "transient error backs off",
+			response:        `{"ok":false,"error_code":500,"description":"Internal Server Error"}`,
+			attempts:        1,
+			wantStatus:      models.OutboxStatusPending,
+			wantAttempts:    2,
+			wantNextAttempt: now.Add(20 * time.Second),
+		},
+		{
+			name:         "max attempts reached",
+			response:     `{"ok":false,"error_code":500,"description":"Internal Server Error"}`,
+			attempts:     2,
+			wantStatus:   models.OutboxStatusFailed,
+			wantAttempts: 3,
+		},
+		{
+			name:         "blocked by user is permanent",
+			response:     `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`,
+			attempts:     0,
+			wantStatus:   models.OutboxStatusFailed,
+			wantAttempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeOutboxRepository()
+			service := &outboxService{
+				cfg:                      &config.TelegramConfig{OutboxMaxAttempts: 3, OutboxRetryBackoff: 10 * time.Second},
+				log:                      logrus.New(),
+				telegramOutboxRepository: repo,
+				sender:                   newBotSender(t, tt.response),
+				now:                      func() time.Time { return now },
+			}
+
+			message, err := service.Enqueue(context.Background(), models.TelegramOutboxMessage{ChatID: 1, Text: "hello", ParseMode: telebot.ModeHTML})
+			if err != nil {
+				t.Fatalf("Enqueue() error = %v", err)
+			}
+			repo.messages[message.ID].Attempts = tt.attempts
+
+			service.drain(context.Background())
+
+			got := repo.messages[message.ID]
+			if got.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (last error: %s)", got.Status, tt.wantStatus, got.LastError)
+			}
+			if got.Attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got.Attempts, tt.wantAttempts)
+			}
+			if !tt.wantNextAttempt.IsZero() && !got.NextAttemptAt.Equal(tt.wantNextAttempt) {
+				t.Errorf("next attempt = %s, want %s", got.NextAttemptAt, tt.wantNextAttempt)
+			}
+			if tt.wantStatus == models.OutboxStatusSent && (got.MessageID == nil || *got.MessageID != 42) {
+				t.Errorf("message id = %v, want 42", got.MessageID)
+			}
+		})
+	}
+}
+
+func TestOutboxService_EnqueueDedup(t *testing.T) {
+	repo := newFakeOutboxRepository()
+	service := &outboxService{
+		cfg:                      &config.TelegramConfig{},
+		log:                      logrus.New(),
+		telegramOutboxRepository: repo,
+		now:                      time.Now,
+	}
+
+	message := models.TelegramOutboxMessage{ChatID: 1, Text: "BBCA HOLD", DedupKey: "position_monitoring:BBCA:1"}
+	first, err := service.Enqueue(context.Background(), message)
+	if err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	second, err := service.Enqueue(context.Background(), message)
+	if err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	if first.ID != second.ID || len(repo.messages) != 1 {
+		t.Errorf("duplicate message stored: ids %d and %d, total %d", first.ID, second.ID, len(repo.messages))
+	}
+}
+
+func TestOutboxService_EnqueueSendAfter(t *testing.T) {
+	now := time.Date(2025, 1, 2, 23, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		sendAfter time.Time
+		want      time.Time
+	}{
+		{name: "no delay", want: now},
+		{name: "past send after", sendAfter: now.Add(-time.Hour), want: now},
+		{name: "deferred", sendAfter: now.Add(7 * time.Hour), want: now.Add(7 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &outboxService{
+				cfg:                      &config.TelegramConfig{},
+				log:                      logrus.New(),
+				telegramOutboxRepository: newFakeOutboxRepository(),
+				now:                      func() time.Time { return now },
+			}
+
+			message, err := service.Enqueue(context.Background(), models.TelegramOutboxMessage{ChatID: 1, Text: "BBCA HOLD", SendAfter: tt.sendAfter})
+			if err != nil {
+				t.Fatalf("Enqueue() error = %v", err)
+			}
+			if !message.NextAttemptAt.Equal(tt.want) {
+				t.Errorf("NextAttemptAt = %v, want %v", message.NextAttemptAt, tt.want)
+			}
+		})
+	}
+}
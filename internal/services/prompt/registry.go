@@ -0,0 +1,174 @@
+package prompt
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+)
+
+const (
+	IndividualAnalysis = "individual_analysis"
+	PositionMonitoring = "position_monitoring"
+
+	defaultVersion = "v1"
+)
+
+//go:embed templates/*/*.tmpl
+var templateFS embed.FS
+
+// IndividualAnalysisData adalah data yang tersedia untuk template individual_analysis.
+type IndividualAnalysisData struct {
+	Symbol      string
+	Range       string
+	OHLCV       []models.OHLCVData
+	MarketPrice float64
+	NewsSummary *models.StockNewsSummaryEntity
+}
+
+// PositionMonitoringData adalah data yang tersedia untuk template position_monitoring.
+type PositionMonitoringData struct {
+	Symbol               string
+	BuyPrice             float64
+	BuyTime              time.Time
+	MaxHoldingPeriodDays int
+	PositionAgeDays      int
+	RemainingDays        int
+	Range                string
+	OHLCV                []models.OHLCVData
+	MarketPrice          float64
+	NewsSummary          *models.StockNewsSummaryEntity
+}
+
+// Registry menyimpan template prompt per nama dan versi. Template dibaca dari
+// templates/<nama>/<versi>.tmpl yang di-embed ke binary.
+type Registry struct {
+	config    *config.PromptConfig
+	templates map[string]map[string]*template.Template
+}
+
+var templateFuncs = template.FuncMap{
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02")
+	},
+	"datetime": func(t time.Time) string {
+		return t.Format("2006-01-02T15:04:05-07:00")
+	},
+	"join": strings.Join,
+	"json": func(v any) (string, error) {
+		raw, err := json.Marshal(v)
+		return string(raw), err
+	},
+}
+
+func NewRegistry(cfg *config.PromptConfig) (*Registry, error) {
+	registry := &Registry{
+		config:    cfg,
+		templates: make(map[string]map[string]*template.Template),
+	}
+
+	paths, err := fs.Glob(templateFS, "templates/*/*.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt templates: %w", err)
+	}
+	for _, file := range paths {
+		name := path.Base(path.Dir(file))
+		version := strings.TrimSuffix(path.Base(file), ".tmpl")
+
+		tmpl, err := template.New(path.Base(file)).Funcs(templateFuncs).ParseFS(templateFS, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse prompt template %s: %w", file, err)
+		}
+
+		if registry.templates[name] == nil {
+			registry.templates[name] = make(map[string]*template.Template)
+		}
+		registry.templates[name][version] = tmpl
+	}
+
+	for name, version := range map[string]string{
+		IndividualAnalysis: registry.activeVersion(IndividualAnalysis),
+		PositionMonitoring: registry.activeVersion(PositionMonitoring),
+	} {
+		if _, ok := registry.templates[name][version]; !ok {
+			return nil, fmt.Errorf("prompt %s version %s not found", name, version)
+		}
+	}
+	if cfg != nil && cfg.ExperimentVersion != "" {
+		if _, ok := registry.templates[IndividualAnalysis][cfg.ExperimentVersion]; !ok {
+			return nil, fmt.Errorf("prompt %s experiment version %s not found", IndividualAnalysis, cfg.ExperimentVersion)
+		}
+	}
+
+	return registry, nil
+}
+
+// Versions mengembalikan daftar versi yang tersedia untuk sebuah prompt, terurut.
+func (r *Registry) Versions(name string) []string {
+	versions := make([]string, 0, len(r.templates[name]))
+	for version := range r.templates[name] {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// SelectVersion menentukan versi prompt yang dipakai. Jika eksperimen aktif, sebagian request
+// individual_analysis (sesuai ExperimentPercentage) diarahkan ke ExperimentVersion. Pembagian
+// ditentukan dari hash key sehingga key yang sama selalu mendapat versi yang sama.
+func (r *Registry) SelectVersion(name string, key string) string {
+	version := r.activeVersion(name)
+	if name != IndividualAnalysis || !r.experimentEnabled() {
+		return version
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	if int(hash.Sum32()%100) < r.config.ExperimentPercentage {
+		return r.config.ExperimentVersion
+	}
+	return version
+}
+
+// Render mengeksekusi template prompt dengan data yang diberikan.
+func (r *Registry) Render(name string, version string, data any) (string, error) {
+	tmpl, ok := r.templates[name][version]
+	if !ok {
+		return "", fmt.Errorf("prompt %s version %s not found", name, version)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt %s version %s: %w", name, version, err)
+	}
+	return sb.String(), nil
+}
+
+func (r *Registry) activeVersion(name string) string {
+	version := ""
+	if r.config != nil {
+		switch name {
+		case IndividualAnalysis:
+			version = r.config.IndividualAnalysisVersion
+		case PositionMonitoring:
+			version = r.config.PositionMonitoringVersion
+		}
+	}
+	if version == "" {
+		return defaultVersion
+	}
+	return version
+}
+
+func (r *Registry) experimentEnabled() bool {
+	return r.config != nil && r.config.ExperimentVersion != "" && r.config.ExperimentPercentage > 0
+}
@@ -0,0 +1,162 @@
+package prompt
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+)
+
+func TestRegistry_Render(t *testing.T) {
+	registry, err := NewRegistry(nil)
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+
+	summary := &models.StockNewsSummaryEntity{
+		StockCode:        "BBCA",
+		SummarySentiment: "positive",
+		KeyIssues:        []string{"dividen", "laba naik"},
+		SummaryStart:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		SummaryEnd:       time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+	}
+	ohlcv := []models.OHLCVData{{Timestamp: 1735689600, Open: 1000, High: 1010, Low: 990, Close: 1005, Volume: 100}}
+
+	for _, name := range []string{IndividualAnalysis, PositionMonitoring} {
+		for _, version := range registry.Versions(name) {
+			for _, withNews := range []bool{false, true} {
+				t.Run(fmt.Sprintf("%s/%s/news=%v", name, version, withNews), func(t *testing.T) {
+					var news *models.StockNewsSummaryEntity
+					if withNews {
+						news = summary
+					}
+
+					var data any
+					switch name {
+					case IndividualAnalysis:
+						data = IndividualAnalysisData{Symbol: "BBCA", Range: "3m", OHLCV: ohlcv, MarketPrice: 1005, NewsSummary: news}
+					case PositionMonitoring:
+						data = PositionMonitoringData{Symbol: "BBCA", BuyPrice: 1000, MaxHoldingPeriodDays: 5, RemainingDays: 3, Range: "3m", OHLCV: ohlcv, MarketPrice: 1005, NewsSummary: news}
+					}
+
+					got, err := registry.Render(name, version, data)
+					if err != nil {
+						t.Fatalf("Render() error = %v", err)
+					}
+					if !strings.Contains(got, "BBCA") || !strings.Contains(got, "1005.00") || !strings.Contains(got, `"close":1005`) {
+						t.Errorf("Render() missing input data:\n%s", got)
+					}
+					if strings.Contains(got, "dividen, laba naik") != withNews {
+						t.Errorf("Render() news section present = %v, want %v", !withNews, withNews)
+					}
+					if strings.Contains(got, "<no value>") {
+						t.Errorf("Render() contains unresolved field:\n%s", got)
+					}
+				})
+			}
+		}
+	}
+
+	if _, err := registry.Render(IndividualAnalysis, "v99", nil); err == nil {
+		t.Error("Render() unknown version error = nil")
+	}
+}
+
+// actionWordPattern mencocokkan kata kapital seperti HOLD atau TAKE_PROFIT di dalam prompt.
+var actionWordPattern = regexp.MustCompile(`\b[A-Z]+(?:_[A-Z]+)*\b`)
+
+// TestRegistry_PositionMonitoringActions memastikan prompt hanya menyebut action yang ada di
+// enum schema, action lain akan ditolak validasi dan memicu repair.
+func TestRegistry_PositionMonitoringActions(t *testing.T) {
+	registry, err := NewRegistry(nil)
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+
+	field, ok := reflect.TypeOf(models.PositionMonitoringResponseMultiTimeframe{}).FieldByName("Action")
+	if !ok {
+		t.Fatal("PositionMonitoringResponseMultiTimeframe.Action not found")
+	}
+	allowed := strings.Split(field.Tag.Get("enum"), ",")
+	vocabulary := []string{"BUY", "SELL", "STRONG_BUY", "STRONG_SELL", "WAIT", "EXIT", "HOLD", "TAKE_PROFIT", "CUT_LOSS", "TRAIL_STOP"}
+
+	for _, version := range registry.Versions(PositionMonitoring) {
+		t.Run(version, func(t *testing.T) {
+			got, err := registry.Render(PositionMonitoring, version, PositionMonitoringData{Symbol: "BBCA", Range: "3m", RemainingDays: 3})
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			for _, word := range actionWordPattern.FindAllString(got, -1) {
+				if slices.Contains(vocabulary, word) && !slices.Contains(allowed, word) {
+					t.Errorf("Render() mentions action %s outside enum %v", word, allowed)
+				}
+			}
+			for _, action := range allowed {
+				if !strings.Contains(got, action) {
+					t.Errorf("Render() does not mention action %s", action)
+				}
+			}
+		})
+	}
+}
+
+func TestNewRegistry_UnknownVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *config.PromptConfig
+	}{
+		{name: "active version", cfg: &config.PromptConfig{IndividualAnalysisVersion: "v99"}},
+		{name: "experiment version", cfg: &config.PromptConfig{ExperimentVersion: "v99", ExperimentPercentage: 50}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRegistry(tt.cfg); err == nil {
+				t.Error("NewRegistry() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestRegistry_SelectVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        *config.PromptConfig
+		prompt     string
+		wantShareB [2]int // rentang jumlah key (dari 1000) yang mendapat versi eksperimen
+	}{
+		{name: "no experiment", cfg: &config.PromptConfig{}, prompt: IndividualAnalysis, wantShareB: [2]int{0, 0}},
+		{name: "zero percentage", cfg: &config.PromptConfig{ExperimentVersion: "v2"}, prompt: IndividualAnalysis, wantShareB: [2]int{0, 0}},
+		{name: "half split", cfg: &config.PromptConfig{ExperimentVersion: "v2", ExperimentPercentage: 50}, prompt: IndividualAnalysis, wantShareB: [2]int{400, 600}},
+		{name: "full rollout", cfg: &config.PromptConfig{ExperimentVersion: "v2", ExperimentPercentage: 100}, prompt: IndividualAnalysis, wantShareB: [2]int{1000, 1000}},
+		{name: "monitoring not in experiment", cfg: &config.PromptConfig{ExperimentVersion: "v2", ExperimentPercentage: 100}, prompt: PositionMonitoring, wantShareB: [2]int{0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry, err := NewRegistry(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewRegistry() error = %v", err)
+			}
+
+			shareB := 0
+			for i := 0; i < 1000; i++ {
+				key := fmt.Sprintf("STOCK%d:2025-01-02", i)
+				version := registry.SelectVersion(tt.prompt, key)
+				if version != registry.SelectVersion(tt.prompt, key) {
+					t.Fatalf("SelectVersion(%q) not deterministic", key)
+				}
+				if version == "v2" {
+					shareB++
+				}
+			}
+			if shareB < tt.wantShareB[0] || shareB > tt.wantShareB[1] {
+				t.Errorf("SelectVersion() experiment share = %d, want between %d and %d", shareB, tt.wantShareB[0], tt.wantShareB[1])
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/repository"
+	"golang-swing-trading-signal/internal/services/users"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// QuotaExceededError dikembalikan saat user sudah mencapai batas plan-nya.
+// errors.Is(err, ErrQuotaExceeded) bernilai true.
+type QuotaExceededError struct {
+	Kind    string
+	Plan    models.Plan
+	Limit   int
+	ResetAt *time.Time
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("quota %s exceeded for plan %s (limit %d)", e.Kind, e.Plan, e.Limit)
+}
+
+func (e *QuotaExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+type QuotaService interface {
+	// ConsumeAnalysis memakai satu kuota analisa on-demand hari ini.
+	ConsumeAnalysis(ctx context.Context, telegramID int64) error
+	// RefundAnalysis mengembalikan kuota jika permintaan analisa gagal dikirim.
+	RefundAnalysis(ctx context.Context, telegramID int64)
+	// CheckActivePositions dipanggil sebelum menambah posisi baru.
+	CheckActivePositions(ctx context.Context, telegramID int64) error
+	// CheckAlerts dipanggil sebelum mengaktifkan alert. stockPositionID posisi yang diubah
+	// tidak ikut dihitung, 0 untuk posisi baru.
+	CheckAlerts(ctx context.Context, telegramID int64, stockPositionID uint) error
+	// CheckWatchlist dipanggil sebelum menambah saham ke watchlist grup milik telegramID.
+	CheckWatchlist(ctx context.Context, telegramID int64) error
+	GetStatus(ctx context.Context, telegramID int64) (*models.QuotaStatus, error)
+}
+
+type quotaService struct {
+	cfg                     *config.Config
+	logger                  *logrus.Logger
+	userRepository          repository.UserRepository
+	stockPositionRepository repository.StockPositionRepository
+	quotaRepository         repository.QuotaRepository
+	groupRepository         repository.GroupRepository
+	now                     func() time.Time
+}
+
+func NewQuotaService(cfg *config.Config, logger *logrus.Logger, userRepository repository.UserRepository, stockPositionRepository repository.StockPositionRepository, quotaRepository repository.QuotaRepository, groupRepository repository.GroupRepository) QuotaService {
+	return &quotaService{
+		cfg:                     cfg,
+		logger:                  logger,
+		userRepository:          userRepository,
+		stockPositionRepository: stockPositionRepository,
+		quotaRepository:         quotaRepository,
+		groupRepository:         groupRepository,
+		now:                     utils.TimeNowWIB,
+	}
+}
+
+// Limits mengembalikan plan aktif user beserta batasnya. unlimited bernilai true untuk admin.
+func Limits(cfg *config.Config, telegramID int64, user *models.UserEntity, now time.Time) (plan models.Plan, limits config.PlanLimits, unlimited bool) {
+	plan = models.PlanFree
+	if user != nil {
+		plan = user.ActivePlan(now)
+	}
+
+	limits = cfg.Quota.Free
+	if plan == models.PlanPro {
+		limits = cfg.Quota.Pro
+	}
+	return plan, limits, users.ResolveAccess(&cfg.Telegram, telegramID, user).Role == models.RoleAdmin
+}
+
+// ResetAt mengembalikan waktu reset kuota harian, yaitu 00:00 WIB hari berikutnya.
+func ResetAt(now time.Time) time.Time {
+	return startOfDay(now).AddDate(0, 0, 1)
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = utils.TimeToWIB(t)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func (s *quotaService) limits(ctx context.Context, telegramID int64) (*models.UserEntity, models.Plan, config.PlanLimits, bool, error) {
+	user, err := s.userRepository.GetUserByTelegramID(ctx, telegramID)
+	if err != nil {
+		return nil, "", config.PlanLimits{}, false, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	plan, limits, unlimited := Limits(s.cfg, telegramID, user, s.now())
+	return user, plan, limits, unlimited, nil
+}
+
+func (s *quotaService) ConsumeAnalysis(ctx context.Context, telegramID int64) error {
+	_, plan, limits, unlimited, err := s.limits(ctx, telegramID)
+	if err != nil {
+		return err
+	}
+	if unlimited {
+		return nil
+	}
+
+	now := s.now()
+	ok, err := s.quotaRepository.IncrementUsage(ctx, telegramID, startOfDay(now), models.QuotaAnalysis, limits.AnalysesPerDay)
+	if err != nil {
+		return fmt.Errorf("failed to increment quota usage: %w", err)
+	}
+	if !ok {
+		return &QuotaExceededError{
+			Kind:    models.QuotaAnalysis,
+			Plan:    plan,
+			Limit:   limits.AnalysesPerDay,
+			ResetAt: utils.ToPointer(ResetAt(now)),
+		}
+	}
+	return nil
+}
+
+func (s *quotaService) RefundAnalysis(ctx context.Context, telegramID int64) {
+	if err := s.quotaRepository.DecrementUsage(context.WithoutCancel(ctx), telegramID, startOfDay(s.now()), models.QuotaAnalysis); err != nil {
+		s.logger.Error("failed to refund quota usage", logrus.Fields{
+			"telegram_id": telegramID,
+			"error":       err,
+		})
+	}
+}
+
+func (s *quotaService) CheckActivePositions(ctx context.Context, telegramID int64) error {
+	_, plan, limits, unlimited, err := s.limits(ctx, telegramID)
+	if err != nil {
+		return err
+	}
+	if unlimited || limits.MaxActivePositions == 0 {
+		return nil
+	}
+
+	positions, err := s.activePositions(ctx, telegramID)
+	if err != nil {
+		return err
+	}
+	if len(positions) >= limits.MaxActivePositions {
+		return &QuotaExceededError{Kind: models.QuotaActivePosition, Plan: plan, Limit: limits.MaxActivePositions}
+	}
+	return nil
+}
+
+func (s *quotaService) CheckAlerts(ctx context.Context, telegramID int64, stockPositionID uint) error {
+	_, plan, limits, unlimited, err := s.limits(ctx, telegramID)
+	if err != nil {
+		return err
+	}
+	if unlimited || limits.MaxAlerts == 0 {
+		return nil
+	}
+
+	positions, err := s.activePositions(ctx, telegramID)
+	if err != nil {
+		return err
+	}
+
+	alerts := 0
+	for _, position := range positions {
+		if position.ID != stockPositionID && hasAlert(&position) {
+			alerts++
+		}
+	}
+	if alerts >= limits.MaxAlerts {
+		return &QuotaExceededError{Kind: models.QuotaAlert, Plan: plan, Limit: limits.MaxAlerts}
+	}
+	return nil
+}
+
+func (s *quotaService) CheckWatchlist(ctx context.Context, telegramID int64) error {
+	_, plan, limits, unlimited, err := s.limits(ctx, telegramID)
+	if err != nil {
+		return err
+	}
+	if unlimited || limits.MaxWatchlist == 0 {
+		return nil
+	}
+
+	current, err := s.groupRepository.CountWatchlistByOwner(ctx, telegramID)
+	if err != nil {
+		return fmt.Errorf("failed to count watchlist: %w", err)
+	}
+	if current >= limits.MaxWatchlist {
+		return &QuotaExceededError{Kind: models.QuotaWatchlist, Plan: plan, Limit: limits.MaxWatchlist}
+	}
+	return nil
+}
+
+func (s *quotaService) GetStatus(ctx context.Context, telegramID int64) (*models.QuotaStatus, error) {
+	user, plan, limits, unlimited, err := s.limits(ctx, telegramID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	analyses, err := s.quotaRepository.GetUsage(ctx, telegramID, startOfDay(now), models.QuotaAnalysis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quota usage: %w", err)
+	}
+
+	positions, err := s.activePositions(ctx, telegramID)
+	if err != nil {
+		return nil, err
+	}
+	alerts := 0
+	for _, position := range positions {
+		if hasAlert(&position) {
+			alerts++
+		}
+	}
+
+	watchlist, err := s.groupRepository.CountWatchlistByOwner(ctx, telegramID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count watchlist: %w", err)
+	}
+
+	status := &models.QuotaStatus{
+		Plan:      plan,
+		Unlimited: unlimited,
+		Items: []models.QuotaItem{
+			{Kind: models.QuotaAnalysis, Used: analyses, Limit: limits.AnalysesPerDay, ResetAt: utils.ToPointer(ResetAt(now))},
+			{Kind: models.QuotaActivePosition, Used: len(positions), Limit: limits.MaxActivePositions},
+			{Kind: models.QuotaAlert, Used: alerts, Limit: limits.MaxAlerts},
+			{Kind: models.QuotaWatchlist, Used: watchlist, Limit: limits.MaxWatchlist},
+		},
+	}
+	if plan == models.PlanPro && user != nil {
+		status.PlanExpiresAt = user.PlanExpiresAt
+	}
+	if unlimited {
+		for i := range status.Items {
+			status.Items[i].Limit = 0
+		}
+	}
+	return status, nil
+}
+
+func (s *quotaService) activePositions(ctx context.Context, telegramID int64) ([]models.StockPositionEntity, error) {
+	positions, err := s.stockPositionRepository.GetList(ctx, models.StockPositionQueryParam{
+		TelegramIDs: []int64{telegramID},
+		IsActive:    true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active positions: %w", err)
+	}
+	return positions, nil
+}
+
+func hasAlert(position *models.StockPositionEntity) bool {
+	return (position.PriceAlert != nil && *position.PriceAlert) || (position.MonitorPosition != nil && *position.MonitorPosition)
+}
@@ -0,0 +1,263 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/repository"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+type fakeUserRepository struct {
+	repository.UserRepository
+	users map[int64]*models.UserEntity
+}
+
+func (r *fakeUserRepository) GetUserByTelegramID(ctx context.Context, telegramID int64, opts ...utils.DBOption) (*models.UserEntity, error) {
+	return r.users[telegramID], nil
+}
+
+type fakeStockPositionRepository struct {
+	repository.StockPositionRepository
+	positions []models.StockPositionEntity
+}
+
+func (r *fakeStockPositionRepository) GetList(ctx context.Context, queryParam models.StockPositionQueryParam, opts ...utils.DBOption) ([]models.StockPositionEntity, error) {
+	return r.positions, nil
+}
+
+type fakeGroupRepository struct {
+	repository.GroupRepository
+	watchlist map[int64]int
+}
+
+func (r *fakeGroupRepository) CountWatchlistByOwner(ctx context.Context, telegramID int64, opts ...utils.DBOption) (int, error) {
+	return r.watchlist[telegramID], nil
+}
+
+// fakeQuotaRepository menyimpan pemakaian per (telegram_id, tanggal, jenis) di memori.
+type fakeQuotaRepository struct {
+	usages map[string]int
+}
+
+func (r *fakeQuotaRepository) key(telegramID int64, date time.Time, kind string) string {
+	return fmt.Sprintf("%d|%s|%s", telegramID, date.Format(time.DateOnly), kind)
+}
+
+func (r *fakeQuotaRepository) IncrementUsage(ctx context.Context, telegramID int64, date time.Time, kind string, limit int, opts ...utils.DBOption) (bool, error) {
+	key := r.key(telegramID, date, kind)
+	if limit != 0 && r.usages[key] >= limit {
+		return false, nil
+	}
+	r.usages[key]++
+	return true, nil
+}
+
+func (r *fakeQuotaRepository) DecrementUsage(ctx context.Context, telegramID int64, date time.Time, kind string, opts ...utils.DBOption) error {
+	key := r.key(telegramID, date, kind)
+	if r.usages[key] > 0 {
+		r.usages[key]--
+	}
+	return nil
+}
+
+func (r *fakeQuotaRepository) GetUsage(ctx context.Context, telegramID int64, date time.Time, kind string, opts ...utils.DBOption) (int, error) {
+	return r.usages[r.key(telegramID, date, kind)], nil
+}
+
+func testConfig() *config.Config {
+	return &config.Config{
+		Telegram: config.TelegramConfig{AdminIDs: []int64{1}},
+		Quota: config.QuotaConfig{
+			Free: config.PlanLimits{AnalysesPerDay: 2, MaxActivePositions: 2, MaxAlerts: 1, MaxWatchlist: 3},
+			Pro:  config.PlanLimits{AnalysesPerDay: 10, MaxActivePositions: 10, MaxAlerts: 10},
+		},
+	}
+}
+
+func TestLimits(t *testing.T) {
+	cfg := testConfig()
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	expired := utils.ToPointer(now.Add(-time.Hour))
+	active := utils.ToPointer(now.Add(time.Hour))
+
+	tests := []struct {
+		name          string
+		telegramID    int64
+		user          *models.UserEntity
+		wantPlan      models.Plan
+		wantLimits    config.PlanLimits
+		wantUnlimited bool
+	}{
+		{name: "unknown user is free", telegramID: 2, wantPlan: models.PlanFree, wantLimits: cfg.Quota.Free},
+		{name: "free user", telegramID: 2, user: &models.UserEntity{Plan: models.PlanFree, Role: models.RoleMember}, wantPlan: models.PlanFree, wantLimits: cfg.Quota.Free},
+		{name: "pro without expiry", telegramID: 2, user: &models.UserEntity{Plan: models.PlanPro, Role: models.RoleMember}, wantPlan: models.PlanPro, wantLimits: cfg.Quota.Pro},
+		{name: "pro still active", telegramID: 2, user: &models.UserEntity{Plan: models.PlanPro, PlanExpiresAt: active, Role: models.RoleMember}, wantPlan: models.PlanPro, wantLimits: cfg.Quota.Pro},
+		{name: "expired pro falls back to free", telegramID: 2, user: &models.UserEntity{Plan: models.PlanPro, PlanExpiresAt: expired, Role: models.RoleMember}, wantPlan: models.PlanFree, wantLimits: cfg.Quota.Free},
+		{name: "promoted admin is unlimited", telegramID: 2, user: &models.UserEntity{Plan: models.PlanFree, Role: models.RoleAdmin}, wantPlan: models.PlanFree, wantLimits: cfg.Quota.Free, wantUnlimited: true},
+		{name: "config admin is unlimited", telegramID: 1, wantPlan: models.PlanFree, wantLimits: cfg.Quota.Free, wantUnlimited: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, limits, unlimited := Limits(cfg, tt.telegramID, tt.user, now)
+			if plan != tt.wantPlan || limits != tt.wantLimits || unlimited != tt.wantUnlimited {
+				t.Errorf("Limits() = %v, %+v, %v, want %v, %+v, %v", plan, limits, unlimited, tt.wantPlan, tt.wantLimits, tt.wantUnlimited)
+			}
+		})
+	}
+}
+
+func TestResetAt(t *testing.T) {
+	wib := utils.TimeNowWIB().Location()
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{name: "morning", now: time.Date(2026, 10, 18, 9, 0, 0, 0, wib), want: time.Date(2026, 10, 19, 0, 0, 0, 0, wib)},
+		{name: "just before midnight", now: time.Date(2026, 10, 18, 23, 59, 59, 0, wib), want: time.Date(2026, 10, 19, 0, 0, 0, 0, wib)},
+		{name: "utc evening is next day in WIB", now: time.Date(2026, 10, 18, 18, 0, 0, 0, time.UTC), want: time.Date(2026, 10, 20, 0, 0, 0, 0, wib)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResetAt(tt.now); !got.Equal(tt.want) {
+				t.Errorf("ResetAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func newTestService(positions []models.StockPositionEntity) *quotaService {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, utils.TimeNowWIB().Location())
+	return &quotaService{
+		cfg:    testConfig(),
+		logger: logrus.New(),
+		userRepository: &fakeUserRepository{users: map[int64]*models.UserEntity{
+			2: {TelegramID: 2, Plan: models.PlanFree, Role: models.RoleMember},
+		}},
+		stockPositionRepository: &fakeStockPositionRepository{positions: positions},
+		quotaRepository:         &fakeQuotaRepository{usages: map[string]int{}},
+		groupRepository:         &fakeGroupRepository{watchlist: map[int64]int{2: 1}},
+		now:                     func() time.Time { return now },
+	}
+}
+
+func TestQuotaService_ConsumeAnalysis(t *testing.T) {
+	s := newTestService(nil)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := s.ConsumeAnalysis(ctx, 2); err != nil {
+			t.Fatalf("ConsumeAnalysis() #%d error = %v", i+1, err)
+		}
+	}
+
+	err := s.ConsumeAnalysis(ctx, 2)
+	var quotaErr *QuotaExceededError
+	if !errors.As(err, &quotaErr) || !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("ConsumeAnalysis() error = %v, want QuotaExceededError", err)
+	}
+	if quotaErr.Kind != models.QuotaAnalysis || quotaErr.Limit != 2 || quotaErr.ResetAt == nil {
+		t.Errorf("QuotaExceededError = %+v", quotaErr)
+	}
+
+	s.RefundAnalysis(ctx, 2)
+	if err := s.ConsumeAnalysis(ctx, 2); err != nil {
+		t.Errorf("ConsumeAnalysis() after refund error = %v", err)
+	}
+
+	// admin dari config tidak terkena kuota
+	for i := 0; i < 5; i++ {
+		if err := s.ConsumeAnalysis(ctx, 1); err != nil {
+			t.Fatalf("ConsumeAnalysis() admin error = %v", err)
+		}
+	}
+}
+
+func TestQuotaService_CheckAlerts(t *testing.T) {
+	positions := []models.StockPositionEntity{
+		{ID: 1, PriceAlert: utils.ToPointer(true), MonitorPosition: utils.ToPointer(true)},
+		{ID: 2, PriceAlert: utils.ToPointer(false), MonitorPosition: utils.ToPointer(false)},
+	}
+
+	tests := []struct {
+		name            string
+		stockPositionID uint
+		wantErr         bool
+	}{
+		{name: "new position exceeds limit", stockPositionID: 0, wantErr: true},
+		{name: "other position exceeds limit", stockPositionID: 2, wantErr: true},
+		{name: "same position with alert is not counted twice", stockPositionID: 1, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTestService(positions).CheckAlerts(context.Background(), 2, tt.stockPositionID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckAlerts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuotaService_CheckWatchlist(t *testing.T) {
+	tests := []struct {
+		name       string
+		telegramID int64
+		watchlist  int
+		wantErr    bool
+	}{
+		{name: "below limit", telegramID: 2, watchlist: 2, wantErr: false},
+		{name: "limit reached", telegramID: 2, watchlist: 3, wantErr: true},
+		{name: "admin is unlimited", telegramID: 1, watchlist: 50, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(nil)
+			s.groupRepository = &fakeGroupRepository{watchlist: map[int64]int{tt.telegramID: tt.watchlist}}
+			err := s.CheckWatchlist(context.Background(), tt.telegramID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckWatchlist() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuotaService_GetStatus(t *testing.T) {
+	positions := []models.StockPositionEntity{
+		{ID: 1, PriceAlert: utils.ToPointer(true), MonitorPosition: utils.ToPointer(false)},
+		{ID: 2, PriceAlert: utils.ToPointer(false), MonitorPosition: utils.ToPointer(false)},
+	}
+	s := newTestService(positions)
+	ctx := context.Background()
+	if err := s.ConsumeAnalysis(ctx, 2); err != nil {
+		t.Fatalf("ConsumeAnalysis() error = %v", err)
+	}
+
+	status, err := s.GetStatus(ctx, 2)
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+
+	want := map[string][2]int{
+		models.QuotaAnalysis:       {1, 2},
+		models.QuotaActivePosition: {2, 2},
+		models.QuotaAlert:          {1, 1},
+		models.QuotaWatchlist:      {1, 3},
+	}
+	for _, item := range status.Items {
+		if got := [2]int{item.Used, item.Limit}; got != want[item.Kind] {
+			t.Errorf("item %s used/limit = %v, want %v", item.Kind, got, want[item.Kind])
+		}
+	}
+	if status.Plan != models.PlanFree || status.Unlimited {
+		t.Errorf("GetStatus() plan = %v unlimited = %v", status.Plan, status.Unlimited)
+	}
+}
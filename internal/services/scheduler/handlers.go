@@ -0,0 +1,178 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/repository"
+	"golang-swing-trading-signal/internal/services/digest"
+	"golang-swing-trading-signal/internal/services/market_price"
+	"golang-swing-trading-signal/internal/services/stocks"
+	"golang-swing-trading-signal/internal/utils"
+)
+
+// jobStockCodes mengembalikan item fan-out, kode saham di payload, atau semua saham di STOCK_LIST.
+func jobStockCodes(ctx context.Context, cfg *config.TradingConfig, stockCodes []string) []string {
+	if item, ok := ItemFromContext(ctx); ok {
+		return []string{item}
+	}
+	if len(stockCodes) == 0 {
+		return cfg.StockList
+	}
+	return stockCodes
+}
+
+// NewStockAnalyzerHandler meminta analisa untuk saham di payload job, atau semua saham di
+// STOCK_LIST jika payload kosong, lalu menunggu signal hasil worker tersimpan sehingga stage
+// berikutnya memakai hasil analisa ini. Timeout job harus cukup untuk durasi analisa.
+func NewStockAnalyzerHandler(cfg *config.TradingConfig, stockService stocks.StockService) HandlerFunc {
+	return func(ctx context.Context, job models.JobEntity) (string, error) {
+		var payload models.StockAnalyzerJobPayload
+		if len(job.Payload) > 0 {
+			if err := json.Unmarshal(job.Payload, &payload); err != nil {
+				return "", fmt.Errorf("invalid stock analyzer payload: %w", err)
+			}
+		}
+		stockCodes := jobStockCodes(ctx, cfg, payload.StockCodes)
+
+		requests := make([]*models.RequestStockAnalyzer, 0, len(stockCodes))
+		for _, stockCode := range stockCodes {
+			request := &models.RequestStockAnalyzer{
+				StockCode: stockCode,
+				Interval:  payload.Interval,
+				Range:     payload.Range,
+			}
+			if err := stockService.RequestStockAnalyzer(ctx, request); err != nil {
+				return "", fmt.Errorf("failed to request analysis %s: %w", stockCode, err)
+			}
+			requests = append(requests, request)
+		}
+
+		// semua request dikirim dulu agar worker menganalisa paralel, baru hasilnya ditunggu
+		signals := make(map[string]int)
+		for _, request := range requests {
+			signal, err := stockService.WaitStockSignal(ctx, request)
+			if err != nil {
+				return "", err
+			}
+			signals[signal.Signal]++
+		}
+		return fmt.Sprintf("analyzed %d stocks: %d BUY", len(stockCodes), signals["BUY"]), nil
+	}
+}
+
+// NewFetchPricesHandler mengambil harga terakhir saham di payload job atau STOCK_LIST. Stage
+// gagal jika ada saham tanpa harga agar analisa tidak berjalan dengan data harga yang hilang.
+func NewFetchPricesHandler(cfg *config.TradingConfig, priceService market_price.PriceService) HandlerFunc {
+	return func(ctx context.Context, job models.JobEntity) (string, error) {
+		var payload models.StockListJobPayload
+		if len(job.Payload) > 0 {
+			if err := json.Unmarshal(job.Payload, &payload); err != nil {
+				return "", fmt.Errorf("invalid fetch prices payload: %w", err)
+			}
+		}
+		stockCodes := jobStockCodes(ctx, cfg, payload.StockCodes)
+
+		prices, err := priceService.GetLastPrices(ctx, stockCodes)
+		if err != nil {
+			return "", fmt.Errorf("failed to get last prices: %w", err)
+		}
+
+		var missing []string
+		for _, stockCode := range stockCodes {
+			if _, ok := prices[stockCode]; !ok {
+				missing = append(missing, stockCode)
+			}
+		}
+		if len(missing) > 0 {
+			return "", fmt.Errorf("missing prices for %s", strings.Join(missing, ", "))
+		}
+		return fmt.Sprintf("fetched prices for %d stocks", len(prices)), nil
+	}
+}
+
+// NewNewsSummaryHandler mencatat cakupan ringkasan berita dari worker berita untuk saham di
+// payload job atau STOCK_LIST. Analisa tetap bisa berjalan tanpa berita, sehingga saham tanpa
+// ringkasan tidak menggagalkan stage.
+func NewNewsSummaryHandler(cfg *config.TradingConfig, stockService stocks.StockService, maxAgeInDays int) HandlerFunc {
+	return func(ctx context.Context, job models.JobEntity) (string, error) {
+		var payload models.StockListJobPayload
+		if len(job.Payload) > 0 {
+			if err := json.Unmarshal(job.Payload, &payload); err != nil {
+				return "", fmt.Errorf("invalid news summary payload: %w", err)
+			}
+		}
+		stockCodes := jobStockCodes(ctx, cfg, payload.StockCodes)
+
+		summaries, err := stockService.GetLastStockNewsSummaries(ctx, maxAgeInDays, stockCodes)
+		if err != nil {
+			return "", err
+		}
+
+		var missing []string
+		for _, stockCode := range stockCodes {
+			if _, ok := summaries[stockCode]; !ok {
+				missing = append(missing, stockCode)
+			}
+		}
+		output := fmt.Sprintf("news summary available for %d of %d stocks", len(stockCodes)-len(missing), len(stockCodes))
+		if len(missing) > 0 {
+			output += ", missing: " + strings.Join(missing, ", ")
+		}
+		return output, nil
+	}
+}
+
+// NewSendBuyListHandler mengirim buy-list dari signal terbaru ke chat TELEGRAM_CHAT_ID. Job
+// dilewati jika chat belum dikonfigurasi.
+func NewSendBuyListHandler(telegramCfg *config.TelegramConfig, digestService digest.DigestService) HandlerFunc {
+	return func(ctx context.Context, job models.JobEntity) (string, error) {
+		if telegramCfg.ChatID == "" {
+			return "skipped: TELEGRAM_CHAT_ID not configured", nil
+		}
+		chatID, err := strconv.ParseInt(telegramCfg.ChatID, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid chat ID: %w", err)
+		}
+
+		count, err := digestService.SendBuyList(ctx, chatID)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("sent buy list with %d signals", count), nil
+	}
+}
+
+// NewPositionMonitorHandler meminta monitoring untuk semua posisi aktif yang mengaktifkan
+// monitor position, hasilnya dikirim ke pemilik posisi.
+func NewPositionMonitorHandler(stockService stocks.StockService, stockPositionRepository repository.StockPositionRepository) HandlerFunc {
+	return func(ctx context.Context, job models.JobEntity) (string, error) {
+		positions, err := stockPositionRepository.GetList(ctx, models.StockPositionQueryParam{IsActive: true}, utils.WithPreload("User"))
+		if err != nil {
+			return "", fmt.Errorf("failed to get active positions: %w", err)
+		}
+
+		requested := 0
+		for _, position := range positions {
+			if position.MonitorPosition == nil || !*position.MonitorPosition {
+				continue
+			}
+			if err := stockService.RequestStockPositionMonitoring(ctx, &models.RequestStockPositionMonitoring{
+				TelegramID:      position.User.TelegramID,
+				StockCode:       position.StockCode,
+				StockPositionID: position.ID,
+				SendToTelegram:  true,
+				Scheduled:       true,
+			}); err != nil {
+				return "", fmt.Errorf("failed to request position monitoring %s: %w", position.StockCode, err)
+			}
+			requested++
+		}
+		return fmt.Sprintf("requested monitoring for %d of %d active positions", requested, len(positions)), nil
+	}
+}
@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/market_price"
+	"golang-swing-trading-signal/internal/services/stocks"
+)
+
+// fakeStockService mencatat request analisa dan mengembalikan signal dari signals.
+type fakeStockService struct {
+	stocks.StockService
+	requested []string
+	signals   map[string]string
+}
+
+func (s *fakeStockService) RequestStockAnalyzer(ctx context.Context, param *models.RequestStockAnalyzer) error {
+	s.requested = append(s.requested, param.StockCode)
+	return nil
+}
+
+func (s *fakeStockService) WaitStockSignal(ctx context.Context, param *models.RequestStockAnalyzer) (*models.StockSignalEntity, error) {
+	signal, ok := s.signals[param.StockCode]
+	if !ok {
+		return nil, errors.New("analysis finished without signal")
+	}
+	return &models.StockSignalEntity{StockCode: param.StockCode, Signal: signal}, nil
+}
+
+type fakePriceService struct {
+	market_price.PriceService
+	prices map[string]models.MarketPrice
+}
+
+func (s *fakePriceService) GetLastPrices(ctx context.Context, stockCodes []string) (map[string]models.MarketPrice, error) {
+	return s.prices, nil
+}
+
+func TestStockAnalyzerHandler_WaitsForSignals(t *testing.T) {
+	cfg := &config.TradingConfig{StockList: []string{"BBCA", "BBRI"}}
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		signals    map[string]string
+		wantOutput string
+		wantErr    bool
+	}{
+		{name: "all signals saved", ctx: context.Background(), signals: map[string]string{"BBCA": "BUY", "BBRI": "HOLD"}, wantOutput: "analyzed 2 stocks: 1 BUY"},
+		{name: "fan-out item", ctx: WithItem(context.Background(), "BBRI"), signals: map[string]string{"BBRI": "BUY"}, wantOutput: "analyzed 1 stocks: 1 BUY"},
+		{name: "missing signal fails stage", ctx: context.Background(), signals: map[string]string{"BBCA": "BUY"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stockService := &fakeStockService{signals: tt.signals}
+			output, err := NewStockAnalyzerHandler(cfg, stockService)(tt.ctx, models.JobEntity{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handler error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if output != tt.wantOutput {
+				t.Errorf("handler output = %q, want %q", output, tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestFetchPricesHandler(t *testing.T) {
+	cfg := &config.TradingConfig{StockList: []string{"BBCA", "BBRI"}}
+	price := models.MarketPrice{Price: 1000, Time: time.Now()}
+	tests := []struct {
+		name    string
+		prices  map[string]models.MarketPrice
+		wantErr bool
+	}{
+		{name: "all prices", prices: map[string]models.MarketPrice{"BBCA": price, "BBRI": price}},
+		{name: "missing price fails stage", prices: map[string]models.MarketPrice{"BBCA": price}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFetchPricesHandler(cfg, &fakePriceService{prices: tt.prices})(context.Background(), models.JobEntity{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handler error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
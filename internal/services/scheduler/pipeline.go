@@ -0,0 +1,238 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/jobs"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FanOutFunc mengembalikan item untuk job dengan JobEntity.FanOut, misal daftar kode saham.
+type FanOutFunc func(ctx context.Context) ([]string, error)
+
+type itemKey struct{}
+
+// WithItem menyimpan item fan-out yang sedang dijalankan handler.
+func WithItem(ctx context.Context, item string) context.Context {
+	return context.WithValue(ctx, itemKey{}, item)
+}
+
+// ItemFromContext mengembalikan item fan-out, ok bernilai false jika job tidak di-fan-out.
+func ItemFromContext(ctx context.Context) (string, bool) {
+	item, ok := ctx.Value(itemKey{}).(string)
+	return item, ok
+}
+
+// pipelineStart adalah job root yang sudah di-claim dan dibuatkan pipeline run.
+type pipelineStart struct {
+	run        *models.PipelineRunEntity
+	job        models.JobEntity
+	scheduleID uint
+	graph      *jobs.Graph
+}
+
+// pipelineRun melacak progres stage satu pipeline run di memori. Semua stage dijalankan
+// oleh replica yang meng-claim job root, sehingga run yang terputus karena restart ditandai
+// failed oleh recoverInterrupted saat scheduler start berikutnya.
+type pipelineRun struct {
+	entity *models.PipelineRunEntity
+	graph  *jobs.Graph
+	stages []uint
+
+	mu        sync.Mutex
+	pending   map[uint]int // job -> jumlah item yang belum selesai
+	completed map[uint]bool
+	finished  bool
+}
+
+// startPipeline menjalankan stage root. Stage berikutnya dijalankan oleh stageDone setelah
+// semua dependency-nya berhasil.
+func (s *Scheduler) startPipeline(ctx context.Context, start pipelineStart) {
+	run := &pipelineRun{
+		entity:    start.run,
+		graph:     start.graph,
+		pending:   make(map[uint]int),
+		completed: make(map[uint]bool),
+	}
+	stages, err := start.graph.Stages(start.job.ID)
+	if err != nil {
+		s.failPipeline(ctx, run, start.job, err)
+		return
+	}
+	run.stages = stages
+
+	s.log.Info("Pipeline run started", logrus.Fields{
+		"pipeline_run_id": run.entity.ID,
+		"root_job_id":     start.job.ID,
+		"stages":          len(stages),
+	})
+	s.startStage(ctx, run, start.job, utils.ToPointer(start.scheduleID))
+}
+
+// startStage membuat riwayat eksekusi untuk setiap item fan-out job lalu men-dispatch-nya.
+// Dispatch berjalan di goroutine terpisah karena bisa menunggu slot MaxConcurrentJobs.
+func (s *Scheduler) startStage(ctx context.Context, run *pipelineRun, job models.JobEntity, scheduleID *uint) {
+	items := []string{""}
+	if job.FanOut != "" {
+		fanOut, ok := s.fanOuts[job.FanOut]
+		if !ok {
+			s.failPipeline(ctx, run, job, fmt.Errorf("unknown fan-out source: %s", job.FanOut))
+			return
+		}
+		var err error
+		items, err = fanOut(ctx)
+		if err != nil {
+			s.failPipeline(ctx, run, job, fmt.Errorf("failed to fan out %s: %w", job.FanOut, err))
+			return
+		}
+	}
+
+	run.mu.Lock()
+	run.pending[job.ID] = len(items)
+	run.mu.Unlock()
+	if len(items) == 0 {
+		s.stageCompleted(ctx, run, job.ID)
+		return
+	}
+
+	executions := make([]execution, 0, len(items))
+	for _, item := range items {
+		history := &models.TaskExecutionHistoryEntity{
+			JobID:         job.ID,
+			ScheduleID:    scheduleID,
+			Attempt:       1,
+			PipelineRunID: utils.ToPointer(run.entity.ID),
+			Item:          item,
+			StartedAt:     s.now(),
+			Status:        models.StatusRunning,
+		}
+		if err := s.jobsRepository.CreateHistory(ctx, history); err != nil {
+			s.failPipeline(ctx, run, job, fmt.Errorf("failed to create task execution history: %w", err))
+			return
+		}
+		executions = append(executions, execution{job: job, history: history, run: run})
+	}
+
+	s.wg.Add(1)
+	utils.SafeGo(func() {
+		defer s.wg.Done()
+		for _, execution := range executions {
+			s.dispatch(ctx, execution)
+		}
+	})
+}
+
+// stageDone dipanggil setelah satu item selesai (termasuk semua retry). Stage dianggap
+// selesai (fan-in) ketika semua item berhasil; satu item gagal menggagalkan pipeline run.
+func (s *Scheduler) stageDone(ctx context.Context, execution execution, err error) {
+	run := execution.run
+	if run == nil {
+		return
+	}
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		if execution.history.Item != "" {
+			err = fmt.Errorf("item %s: %w", execution.history.Item, err)
+		}
+		s.failPipeline(ctx, run, execution.job, err)
+		return
+	}
+
+	run.mu.Lock()
+	run.pending[execution.job.ID]--
+	remaining := run.pending[execution.job.ID]
+	run.mu.Unlock()
+	if remaining == 0 {
+		s.stageCompleted(ctx, run, execution.job.ID)
+	}
+}
+
+// stageCompleted menandai stage berhasil lalu menjalankan dependents yang sudah siap, atau
+// menyelesaikan pipeline run jika semua stage berhasil.
+func (s *Scheduler) stageCompleted(ctx context.Context, run *pipelineRun, jobID uint) {
+	run.mu.Lock()
+	if run.finished {
+		run.mu.Unlock()
+		return
+	}
+	run.completed[jobID] = true
+	var ready []uint
+	for _, next := range run.graph.Ready(jobID, run.stages, run.completed) {
+		// stage yang sudah dimulai punya entry pending, jangan dijalankan dua kali
+		if _, started := run.pending[next]; !started {
+			run.pending[next] = 0
+			ready = append(ready, next)
+		}
+	}
+	done := len(run.completed) == len(run.stages)
+	if done {
+		run.finished = true
+		run.entity.Status = models.PipelineRunCompleted
+		run.entity.CompletedAt = sql.NullTime{Time: s.now(), Valid: true}
+	}
+	run.mu.Unlock()
+
+	if done {
+		s.log.Info("Pipeline run completed", logrus.Fields{"pipeline_run_id": run.entity.ID})
+		s.saveRun(ctx, run)
+		return
+	}
+	if len(ready) == 0 {
+		return
+	}
+
+	nextJobs, err := s.jobsRepository.GetByIDs(ctx, ready)
+	if err != nil {
+		s.failPipeline(ctx, run, models.JobEntity{ID: ready[0]}, fmt.Errorf("failed to get jobs: %w", err))
+		return
+	}
+	for _, job := range nextJobs {
+		s.startStage(ctx, run, job, nil)
+	}
+}
+
+// failPipeline menandai pipeline run gagal pada job. Hanya kegagalan pertama yang dicatat.
+func (s *Scheduler) failPipeline(ctx context.Context, run *pipelineRun, job models.JobEntity, err error) {
+	run.mu.Lock()
+	if run.finished {
+		run.mu.Unlock()
+		return
+	}
+	run.finished = true
+	run.entity.Status = models.PipelineRunFailed
+	run.entity.FailedJobID = utils.ToPointer(job.ID)
+	run.entity.ErrorMessage = sql.NullString{String: err.Error(), Valid: true}
+	run.entity.CompletedAt = sql.NullTime{Time: s.now(), Valid: true}
+	run.mu.Unlock()
+
+	fields := logrus.Fields{
+		"pipeline_run_id": run.entity.ID,
+		"job_id":          job.ID,
+		"job":             job.Name,
+		"error":           err,
+	}
+	if errors.Is(err, context.Canceled) {
+		s.log.Warn("Pipeline run stopped", fields)
+	} else {
+		s.log.Error("Pipeline run failed", fields)
+	}
+	s.saveRun(ctx, run)
+}
+
+func (s *Scheduler) saveRun(ctx context.Context, run *pipelineRun) {
+	if err := s.pipelineRepository.UpdateRun(context.WithoutCancel(ctx), run.entity); err != nil {
+		s.log.Error("failed to update pipeline run", logrus.Fields{
+			"pipeline_run_id": run.entity.ID,
+			"error":           err,
+		})
+	}
+}
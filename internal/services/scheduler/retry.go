@@ -0,0 +1,169 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+
+	"google.golang.org/genai"
+)
+
+const (
+	defaultRetryInitialInterval = 30 * time.Second
+	defaultRetryMaxInterval     = 10 * time.Minute
+)
+
+var defaultRetryOn = []string{
+	models.RetryOnTimeout,
+	models.RetryOnNetwork,
+	models.RetryOnRateLimit,
+	models.RetryOnServer,
+}
+
+// retryPolicy adalah JobRetryPolicy yang sudah divalidasi dan diisi default.
+type retryPolicy struct {
+	maxAttempts     int
+	backoff         string
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	retryOn         []string
+}
+
+// defaultRetryPolicy menjalankan job sekali tanpa retry.
+func defaultRetryPolicy() retryPolicy {
+	return retryPolicy{
+		maxAttempts:     1,
+		backoff:         models.BackoffExponential,
+		initialInterval: defaultRetryInitialInterval,
+		maxInterval:     defaultRetryMaxInterval,
+		retryOn:         defaultRetryOn,
+	}
+}
+
+// parseRetryPolicy membaca jobs.retry_policy. Kolom kosong berarti defaultRetryPolicy.
+func parseRetryPolicy(raw []byte) (retryPolicy, error) {
+	policy := defaultRetryPolicy()
+	if len(raw) == 0 || string(raw) == "null" {
+		return policy, nil
+	}
+
+	var config models.JobRetryPolicy
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return retryPolicy{}, fmt.Errorf("failed to unmarshal retry policy: %w", err)
+	}
+	if config.MaxAttempts > 0 {
+		policy.maxAttempts = config.MaxAttempts
+	}
+	switch config.Backoff {
+	case "":
+	case models.BackoffFixed, models.BackoffLinear, models.BackoffExponential:
+		policy.backoff = config.Backoff
+	default:
+		return retryPolicy{}, fmt.Errorf("unknown backoff strategy: %s", config.Backoff)
+	}
+	if config.InitialInterval != "" {
+		interval, err := time.ParseDuration(config.InitialInterval)
+		if err != nil {
+			return retryPolicy{}, fmt.Errorf("invalid initial_interval: %w", err)
+		}
+		policy.initialInterval = interval
+	}
+	if config.MaxInterval != "" {
+		interval, err := time.ParseDuration(config.MaxInterval)
+		if err != nil {
+			return retryPolicy{}, fmt.Errorf("invalid max_interval: %w", err)
+		}
+		policy.maxInterval = interval
+	}
+	if len(config.RetryOn) > 0 {
+		for _, class := range config.RetryOn {
+			switch class {
+			case models.RetryOnTimeout, models.RetryOnNetwork, models.RetryOnRateLimit, models.RetryOnServer, models.RetryOnAny:
+			default:
+				return retryPolicy{}, fmt.Errorf("unknown retry_on class: %s", class)
+			}
+		}
+		policy.retryOn = config.RetryOn
+	}
+	return policy, nil
+}
+
+// shouldRetry menentukan apakah attempt yang gagal dengan err boleh diulang.
+func (p retryPolicy) shouldRetry(attempt int, err error) bool {
+	if err == nil || attempt >= p.maxAttempts || errors.Is(err, ErrNoHandler) || errors.Is(err, ErrCancelled) {
+		return false
+	}
+	if slices.Contains(p.retryOn, models.RetryOnAny) {
+		return true
+	}
+	class := classifyError(err)
+	return class != "" && slices.Contains(p.retryOn, class)
+}
+
+// delay menghitung jeda sebelum attempt berikutnya setelah attempt ke-n gagal.
+func (p retryPolicy) delay(attempt int) time.Duration {
+	delay := p.initialInterval
+	switch p.backoff {
+	case models.BackoffLinear:
+		delay = p.initialInterval * time.Duration(attempt)
+	case models.BackoffExponential:
+		for i := 1; i < attempt && delay < p.maxInterval; i++ {
+			delay *= 2
+		}
+	}
+	if p.maxInterval > 0 && delay > p.maxInterval {
+		delay = p.maxInterval
+	}
+	return delay
+}
+
+// classifyError memetakan error ke kelas RetryOn, atau string kosong jika error dianggap
+// permanen (misal payload salah atau simbol tidak ditemukan).
+func classifyError(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return models.RetryOnTimeout
+	}
+
+	var statusErr *utils.HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return classifyStatusCode(statusErr.StatusCode)
+	}
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		return classifyStatusCode(apiErr.Code)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return models.RetryOnTimeout
+		}
+		return models.RetryOnNetwork
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return models.RetryOnNetwork
+	}
+	return ""
+}
+
+func classifyStatusCode(code int) string {
+	switch {
+	case code == http.StatusTooManyRequests:
+		return models.RetryOnRateLimit
+	case code == http.StatusRequestTimeout:
+		return models.RetryOnTimeout
+	case code >= http.StatusInternalServerError:
+		return models.RetryOnServer
+	}
+	return ""
+}
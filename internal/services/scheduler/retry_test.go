@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+
+	"google.golang.org/genai"
+)
+
+func TestParseRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    retryPolicy
+		wantErr bool
+	}{
+		{name: "empty", raw: "", want: defaultRetryPolicy()},
+		{name: "null", raw: "null", want: defaultRetryPolicy()},
+		{
+			name: "full policy",
+			raw:  `{"max_attempts": 3, "backoff": "linear", "initial_interval": "10s", "max_interval": "1m", "retry_on": ["rate_limit"]}`,
+			want: retryPolicy{
+				maxAttempts:     3,
+				backoff:         models.BackoffLinear,
+				initialInterval: 10 * time.Second,
+				maxInterval:     time.Minute,
+				retryOn:         []string{models.RetryOnRateLimit},
+			},
+		},
+		{
+			name: "defaults for missing fields",
+			raw:  `{"max_attempts": 2}`,
+			want: retryPolicy{
+				maxAttempts:     2,
+				backoff:         models.BackoffExponential,
+				initialInterval: defaultRetryInitialInterval,
+				maxInterval:     defaultRetryMaxInterval,
+				retryOn:         defaultRetryOn,
+			},
+		},
+		{name: "invalid json", raw: `{`, wantErr: true},
+		{name: "unknown backoff", raw: `{"backoff": "random"}`, wantErr: true},
+		{name: "invalid interval", raw: `{"initial_interval": "10"}`, wantErr: true},
+		{name: "unknown retry class", raw: `{"retry_on": ["parse"]}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRetryPolicy([]byte(tt.raw))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRetryPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("parseRetryPolicy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_delay(t *testing.T) {
+	tests := []struct {
+		backoff string
+		want    []time.Duration
+	}{
+		{backoff: models.BackoffFixed, want: []time.Duration{10 * time.Second, 10 * time.Second, 10 * time.Second}},
+		{backoff: models.BackoffLinear, want: []time.Duration{10 * time.Second, 20 * time.Second, 30 * time.Second}},
+		{backoff: models.BackoffExponential, want: []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, 45 * time.Second}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.backoff, func(t *testing.T) {
+			policy := retryPolicy{backoff: tt.backoff, initialInterval: 10 * time.Second, maxInterval: 45 * time.Second}
+			for i, want := range tt.want {
+				if got := policy.delay(i + 1); got != want {
+					t.Errorf("delay(%d) = %s, want %s", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_shouldRetry(t *testing.T) {
+	policy := defaultRetryPolicy()
+	policy.maxAttempts = 3
+
+	tests := []struct {
+		name    string
+		policy  retryPolicy
+		attempt int
+		err     error
+		want    bool
+	}{
+		{name: "success", policy: policy, attempt: 1, err: nil, want: false},
+		{name: "timeout", policy: policy, attempt: 1, err: context.DeadlineExceeded, want: true},
+		{name: "yahoo rate limit", policy: policy, attempt: 2, err: fmt.Errorf("failed to get data: %w", &utils.HTTPStatusError{StatusCode: 429}), want: true},
+		{name: "gemini server error", policy: policy, attempt: 1, err: genai.APIError{Code: 503}, want: true},
+		{name: "network", policy: policy, attempt: 1, err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: true},
+		{name: "bad request is permanent", policy: policy, attempt: 1, err: &utils.HTTPStatusError{StatusCode: 400}, want: false},
+		{name: "plain error is permanent", policy: policy, attempt: 1, err: errors.New("no data returned for symbol"), want: false},
+		{name: "max attempts reached", policy: policy, attempt: 3, err: context.DeadlineExceeded, want: false},
+		{name: "no handler never retried", policy: retryPolicy{maxAttempts: 3, retryOn: []string{models.RetryOnAny}}, attempt: 1, err: ErrNoHandler, want: false},
+		{name: "any", policy: retryPolicy{maxAttempts: 3, retryOn: []string{models.RetryOnAny}}, attempt: 1, err: errors.New("boom"), want: true},
+		{name: "class not configured", policy: retryPolicy{maxAttempts: 3, retryOn: []string{models.RetryOnRateLimit}}, attempt: 1, err: context.DeadlineExceeded, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.shouldRetry(tt.attempt, tt.err); got != tt.want {
+				t.Errorf("shouldRetry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,642 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/repository"
+	"golang-swing-trading-signal/internal/services/jobs"
+	"golang-swing-trading-signal/internal/services/llm"
+	"golang-swing-trading-signal/internal/utils"
+	"golang-swing-trading-signal/pkg/cron"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultPollInterval      = 10 * time.Second
+	defaultBatchSize         = 10
+	defaultMaxConcurrentJobs = 4
+	defaultJobTimeout        = 60 * time.Second
+)
+
+var (
+	ErrNoHandler = errors.New("no handler registered for job type")
+	ErrCancelled = errors.New("execution cancelled")
+	// ErrInterrupted dicatat pada eksekusi dan pipeline run yang masih running saat scheduler
+	// berhenti, karena progresnya hanya dilacak di memori proses yang menjalankannya.
+	ErrInterrupted = errors.New("execution interrupted by scheduler restart")
+)
+
+// HandlerFunc menjalankan satu job. Output disimpan di riwayat eksekusi.
+type HandlerFunc func(ctx context.Context, job models.JobEntity) (output string, err error)
+
+// Scheduler menjalankan job dari tabel task_schedules sesuai cron expression. Schedule yang
+// jatuh tempo di-claim dengan FOR UPDATE SKIP LOCKED sehingga aman dijalankan di beberapa
+// replica, lalu dieksekusi oleh handler yang terdaftar untuk JobEntity.Type.
+type Scheduler struct {
+	cfg                *config.SchedulerConfig
+	log                *logrus.Logger
+	jobsRepository     repository.JobsRepository
+	pipelineRepository repository.PipelineRepository
+	unitOfWork         repository.UnitOfWork
+	handlers           map[string]HandlerFunc
+	fanOuts            map[string]FanOutFunc
+	slots              chan struct{}
+	wg                 sync.WaitGroup
+	now                func() time.Time
+
+	mu      sync.Mutex
+	running map[uint]context.CancelCauseFunc // history ID -> cancel attempt yang sedang berjalan
+}
+
+func NewScheduler(cfg *config.SchedulerConfig, log *logrus.Logger, jobsRepository repository.JobsRepository, pipelineRepository repository.PipelineRepository, unitOfWork repository.UnitOfWork) *Scheduler {
+	maxConcurrentJobs := cfg.MaxConcurrentJobs
+	if maxConcurrentJobs <= 0 {
+		maxConcurrentJobs = defaultMaxConcurrentJobs
+	}
+	return &Scheduler{
+		cfg:                cfg,
+		log:                log,
+		jobsRepository:     jobsRepository,
+		pipelineRepository: pipelineRepository,
+		unitOfWork:         unitOfWork,
+		handlers:           make(map[string]HandlerFunc),
+		fanOuts:            make(map[string]FanOutFunc),
+		slots:              make(chan struct{}, maxConcurrentJobs),
+		now:                utils.TimeNowWIB,
+		running:            make(map[uint]context.CancelCauseFunc),
+	}
+}
+
+// Register mendaftarkan handler untuk tipe job. Harus dipanggil sebelum Start.
+func (s *Scheduler) Register(jobType string, handler HandlerFunc) {
+	s.handlers[jobType] = handler
+}
+
+// RegisterFanOut mendaftarkan sumber item untuk JobEntity.FanOut. Harus dipanggil sebelum Start.
+func (s *Scheduler) RegisterFanOut(source string, fanOut FanOutFunc) {
+	s.fanOuts[source] = fanOut
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	s.wg.Add(1)
+	utils.SafeGo(func() {
+		defer s.wg.Done()
+		s.log.Info("Scheduler started", logrus.Fields{"job_types": len(s.handlers)})
+		if err := s.recoverInterrupted(ctx); err != nil {
+			s.log.Error("failed to recover interrupted executions", logrus.Fields{"error": err})
+		}
+
+		ticker := time.NewTicker(s.pollInterval())
+		defer ticker.Stop()
+		for {
+			s.tick(ctx)
+			select {
+			case <-ctx.Done():
+				s.log.Info("Received signal to stop scheduler")
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// Stop menunggu loop scheduler dan semua job yang sedang berjalan selesai.
+func (s *Scheduler) Stop() {
+	s.wg.Wait()
+	s.log.Info("Scheduler stopped")
+}
+
+// recoverInterrupted menandai eksekusi running yang sudah melewati timeout job, beserta
+// pipeline run-nya, sebagai failed. Progres eksekusi hanya dilacak di memori sehingga setelah
+// restart tidak ada yang akan menyelesaikannya. Eksekusi yang belum melewati timeout
+// dibiarkan karena bisa jadi masih dijalankan replica lain.
+func (s *Scheduler) recoverInterrupted(ctx context.Context) error {
+	now := s.now()
+	histories, _, err := s.jobsRepository.GetHistories(ctx, &models.GetTaskExecutionHistoryParam{
+		Status: utils.ToPointer(models.StatusRunning),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get running task execution histories: %w", err)
+	}
+	runs, err := s.pipelineRepository.GetRuns(ctx, &models.GetPipelineRunParam{
+		Status: utils.ToPointer(models.PipelineRunRunning),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get running pipeline runs: %w", err)
+	}
+	if len(histories) == 0 && len(runs) == 0 {
+		return nil
+	}
+
+	jobIDs := make([]uint, 0, len(histories)+len(runs))
+	for _, history := range histories {
+		jobIDs = append(jobIDs, history.JobID)
+	}
+	for _, run := range runs {
+		jobIDs = append(jobIDs, run.RootJobID)
+	}
+	jobList, err := s.jobsRepository.GetByIDs(ctx, jobIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get jobs: %w", err)
+	}
+	timeouts := make(map[uint]time.Duration, len(jobList))
+	for _, job := range jobList {
+		timeouts[job.ID] = jobTimeout(job)
+	}
+	expired := func(jobID uint, since time.Time) bool {
+		timeout, ok := timeouts[jobID]
+		if !ok {
+			timeout = defaultJobTimeout
+		}
+		return now.Sub(since) >= timeout
+	}
+
+	// job pertama yang terputus per pipeline run, dicatat sebagai FailedJobID
+	interrupted := make(map[uint]uint)
+	recovered := make(map[uint]bool)
+	for i := range histories {
+		history := &histories[i]
+		if !expired(history.JobID, history.StartedAt) {
+			continue
+		}
+		finishHistory(history, now, models.StatusFailed, models.ExitCodeFailed, "", ErrInterrupted)
+		updated, err := s.jobsRepository.FinishHistory(ctx, history)
+		if err != nil {
+			return fmt.Errorf("failed to update task execution history %d: %w", history.ID, err)
+		}
+		if !updated {
+			continue
+		}
+		recovered[history.ID] = true
+		if history.PipelineRunID != nil {
+			if _, ok := interrupted[*history.PipelineRunID]; !ok {
+				interrupted[*history.PipelineRunID] = history.JobID
+			}
+		}
+		s.log.Warn("Marked interrupted execution as failed", logrus.Fields{
+			"history_id": history.ID,
+			"job_id":     history.JobID,
+			"item":       history.Item,
+		})
+	}
+
+	for i := range runs {
+		run := &runs[i]
+		failedJobID, ok := interrupted[run.ID]
+		if !ok {
+			// run tanpa eksekusi yang terputus hanya dianggap mati jika tidak ada eksekusi yang
+			// masih running dan aktivitas terakhirnya sudah melewati timeout, misal terhenti
+			// di antara dua stage
+			var active bool
+			lastActivity, lastJobID := run.StartedAt, run.RootJobID
+			for _, history := range run.Histories {
+				if history.Status == models.StatusRunning && !recovered[history.ID] {
+					active = true
+					break
+				}
+				at := history.StartedAt
+				if history.CompletedAt.Valid {
+					at = history.CompletedAt.Time
+				}
+				if !at.Before(lastActivity) {
+					lastActivity, lastJobID = at, history.JobID
+				}
+			}
+			if active || !expired(run.RootJobID, lastActivity) {
+				continue
+			}
+			failedJobID = lastJobID
+		}
+
+		run.Status = models.PipelineRunFailed
+		run.FailedJobID = utils.ToPointer(failedJobID)
+		run.ErrorMessage = sql.NullString{String: ErrInterrupted.Error(), Valid: true}
+		run.CompletedAt = sql.NullTime{Time: now, Valid: true}
+		if err := s.pipelineRepository.UpdateRun(ctx, run); err != nil {
+			return fmt.Errorf("failed to update pipeline run %d: %w", run.ID, err)
+		}
+		s.log.Warn("Marked interrupted pipeline run as failed", logrus.Fields{
+			"pipeline_run_id": run.ID,
+			"failed_job_id":   failedJobID,
+		})
+	}
+	return nil
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	if err := s.cancelRequested(ctx); err != nil {
+		s.log.Error("failed to check cancelled executions", logrus.Fields{"error": err})
+	}
+	if err := s.scheduleNew(ctx); err != nil {
+		s.log.Error("failed to initialize task schedules", logrus.Fields{"error": err})
+	}
+
+	for ctx.Err() == nil {
+		executions, pipelines, err := s.claim(ctx)
+		if err != nil {
+			s.log.Error("failed to claim due task schedules", logrus.Fields{"error": err})
+			return
+		}
+		for _, execution := range executions {
+			s.dispatch(ctx, execution)
+		}
+		for _, pipeline := range pipelines {
+			s.startPipeline(ctx, pipeline)
+		}
+		if len(executions)+len(pipelines) < s.batchSize() {
+			return
+		}
+	}
+}
+
+// cancelRequested menghentikan attempt yang riwayatnya sudah ditandai cancelled lewat
+// JobService.CancelExecution.
+func (s *Scheduler) cancelRequested(ctx context.Context) error {
+	s.mu.Lock()
+	ids := make([]uint, 0, len(s.running))
+	for id := range s.running {
+		ids = append(ids, id)
+	}
+	s.mu.Unlock()
+	if len(ids) == 0 {
+		return nil
+	}
+
+	histories, err := s.jobsRepository.GetHistoriesByIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get running task execution histories: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, history := range histories {
+		if cancel, ok := s.running[history.ID]; ok && history.Status == models.StatusCancelled {
+			s.log.Info("Cancelling job execution", logrus.Fields{"job_id": history.JobID, "history_id": history.ID})
+			cancel(ErrCancelled)
+		}
+	}
+	return nil
+}
+
+// scheduleNew mengisi next_execution untuk schedule aktif yang belum pernah dijadwalkan.
+func (s *Scheduler) scheduleNew(ctx context.Context) error {
+	schedules, err := s.jobsRepository.GetUnscheduled(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get unscheduled task schedules: %w", err)
+	}
+	now := s.now()
+	for i := range schedules {
+		schedule := &schedules[i]
+		next, err := NextExecution(schedule.CronExpression, now)
+		if err != nil {
+			s.log.Error("invalid cron expression", logrus.Fields{
+				"schedule_id": schedule.ID,
+				"cron":        schedule.CronExpression,
+				"error":       err,
+			})
+			continue
+		}
+		schedule.NextExecution = next
+		if err := s.jobsRepository.UpdateSchedule(ctx, schedule); err != nil {
+			return fmt.Errorf("failed to update task schedule %d: %w", schedule.ID, err)
+		}
+	}
+	return nil
+}
+
+// execution adalah satu eksekusi job (atau satu item fan-out) beserta riwayat eksekusinya.
+type execution struct {
+	job     models.JobEntity
+	history *models.TaskExecutionHistoryEntity
+	run     *pipelineRun // nil jika job bukan bagian dari pipeline
+}
+
+// claim mengunci schedule yang jatuh tempo, memajukan next_execution dan membuat riwayat
+// eksekusi berstatus running dalam satu transaksi. Job yang punya dependents atau fan-out
+// dijalankan sebagai pipeline run, riwayatnya dibuat per stage oleh startPipeline.
+func (s *Scheduler) claim(ctx context.Context) ([]execution, []pipelineStart, error) {
+	var executions []execution
+	var pipelines []pipelineStart
+	err := s.unitOfWork.Run(func(opts ...utils.DBOption) error {
+		now := s.now()
+		schedules, err := s.jobsRepository.ClaimDueSchedules(ctx, now, s.batchSize(), opts...)
+		if err != nil {
+			return fmt.Errorf("failed to claim due task schedules: %w", err)
+		}
+		if len(schedules) == 0 {
+			return nil
+		}
+
+		jobIDs := make([]uint, 0, len(schedules))
+		for _, schedule := range schedules {
+			jobIDs = append(jobIDs, schedule.JobID)
+		}
+		claimedJobs, err := s.jobsRepository.GetByIDs(ctx, jobIDs, opts...)
+		if err != nil {
+			return fmt.Errorf("failed to get jobs: %w", err)
+		}
+		jobByID := make(map[uint]models.JobEntity, len(claimedJobs))
+		for _, job := range claimedJobs {
+			jobByID[job.ID] = job
+		}
+		dependencies, err := s.pipelineRepository.GetDependencies(ctx, opts...)
+		if err != nil {
+			return fmt.Errorf("failed to get job dependencies: %w", err)
+		}
+		graph := jobs.NewGraph(dependencies)
+
+		for i := range schedules {
+			schedule := &schedules[i]
+			history := &models.TaskExecutionHistoryEntity{
+				JobID:      schedule.JobID,
+				ScheduleID: utils.ToPointer(schedule.ID),
+				Attempt:    1,
+				StartedAt:  now,
+				Status:     models.StatusRunning,
+			}
+
+			next, errCron := NextExecution(schedule.CronExpression, now)
+			schedule.NextExecution = next
+			schedule.LastExecution = sql.NullTime{Time: now, Valid: true}
+			if err := s.jobsRepository.UpdateSchedule(ctx, schedule, opts...); err != nil {
+				return fmt.Errorf("failed to update task schedule %d: %w", schedule.ID, err)
+			}
+
+			job, ok := jobByID[schedule.JobID]
+			switch {
+			case errCron != nil:
+				finishHistory(history, now, models.StatusFailed, models.ExitCodeFailed, "", errCron)
+			case !ok:
+				finishHistory(history, now, models.StatusFailed, models.ExitCodeFailed, "", fmt.Errorf("job %d not found", schedule.JobID))
+			case graph.IsPipeline(job.ID) || job.FanOut != "":
+				run := &models.PipelineRunEntity{
+					RootJobID: job.ID,
+					Status:    models.PipelineRunRunning,
+					StartedAt: now,
+				}
+				if err := s.pipelineRepository.CreateRun(ctx, run, opts...); err != nil {
+					return fmt.Errorf("failed to create pipeline run: %w", err)
+				}
+				pipelines = append(pipelines, pipelineStart{run: run, job: job, scheduleID: schedule.ID, graph: graph})
+				continue
+			}
+			if err := s.jobsRepository.CreateHistory(ctx, history, opts...); err != nil {
+				return fmt.Errorf("failed to create task execution history: %w", err)
+			}
+			if history.Status == models.StatusRunning {
+				executions = append(executions, execution{job: job, history: history})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return executions, pipelines, nil
+}
+
+// dispatch menjalankan job di goroutine terpisah, dibatasi MaxConcurrentJobs. Attempt yang
+// gagal diulang sesuai JobEntity.RetryPolicy, setiap attempt punya riwayat eksekusi sendiri.
+func (s *Scheduler) dispatch(ctx context.Context, execution execution) {
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		// scheduler berhenti sebelum job sempat jalan
+		err := s.finish(ctx, execution, "", ctx.Err())
+		s.stageDone(ctx, execution, err)
+		return
+	}
+
+	s.wg.Add(1)
+	utils.SafeGo(func() {
+		defer s.wg.Done()
+		err := s.execute(ctx, &execution)
+		// slot dilepas sebelum stage berikutnya di-dispatch supaya tidak deadlock
+		<-s.slots
+		s.stageDone(ctx, execution, err)
+	})
+}
+
+// execute menjalankan attempt sampai berhasil atau retry policy habis, lalu mengembalikan
+// error attempt terakhir.
+func (s *Scheduler) execute(ctx context.Context, execution *execution) error {
+	policy, err := parseRetryPolicy(execution.job.RetryPolicy)
+	if err != nil {
+		s.log.Error("invalid job retry policy, running without retry", logrus.Fields{
+			"job_id": execution.job.ID,
+			"error":  err,
+		})
+		policy = defaultRetryPolicy()
+	}
+
+	for {
+		output, err := s.runAttempt(ctx, *execution)
+		err = s.finish(ctx, *execution, output, err)
+		if !policy.shouldRetry(execution.history.Attempt, err) {
+			return err
+		}
+
+		delay := policy.delay(execution.history.Attempt)
+		s.log.Warn("Retrying job", logrus.Fields{
+			"job_id":  execution.job.ID,
+			"job":     execution.job.Name,
+			"item":    execution.history.Item,
+			"attempt": execution.history.Attempt + 1,
+			"delay":   delay.String(),
+			"error":   err,
+		})
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		next, errCreate := s.startAttempt(ctx, execution.history)
+		if errCreate != nil {
+			s.log.Error("failed to create task execution history", logrus.Fields{
+				"job_id": execution.job.ID,
+				"error":  errCreate,
+			})
+			return err
+		}
+		execution.history = next
+	}
+}
+
+// runAttempt menjalankan satu attempt dan mendaftarkannya supaya bisa dibatalkan.
+func (s *Scheduler) runAttempt(ctx context.Context, execution execution) (string, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	s.mu.Lock()
+	s.running[execution.history.ID] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, execution.history.ID)
+		s.mu.Unlock()
+	}()
+
+	if execution.history.Item != "" {
+		ctx = WithItem(ctx, execution.history.Item)
+	}
+	return s.run(ctx, execution.job)
+}
+
+// startAttempt membuat riwayat eksekusi berstatus running untuk attempt berikutnya.
+func (s *Scheduler) startAttempt(ctx context.Context, previous *models.TaskExecutionHistoryEntity) (*models.TaskExecutionHistoryEntity, error) {
+	history := &models.TaskExecutionHistoryEntity{
+		JobID:         previous.JobID,
+		ScheduleID:    previous.ScheduleID,
+		Attempt:       previous.Attempt + 1,
+		PipelineRunID: previous.PipelineRunID,
+		Item:          previous.Item,
+		StartedAt:     s.now(),
+		Status:        models.StatusRunning,
+	}
+	if err := s.jobsRepository.CreateHistory(ctx, history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// run memanggil handler sesuai tipe job dengan timeout job.Timeout detik. Handler yang tidak
+// menghormati ctx tetap dianggap timeout begitu deadline lewat, supaya job yang hang terlihat
+// di riwayat eksekusi walaupun goroutine-nya masih berjalan.
+func (s *Scheduler) run(ctx context.Context, job models.JobEntity) (string, error) {
+	handler, ok := s.handlers[job.Type]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNoHandler, job.Type)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, jobTimeout(job))
+	defer cancel()
+	ctx = llm.WithTrigger(ctx, llm.Trigger{Source: llm.TriggerSourceJob, JobID: job.ID})
+
+	type result struct {
+		output string
+		err    error
+	}
+	done := make(chan result, 1)
+	s.log.Info("Running job", logrus.Fields{"job_id": job.ID, "job": job.Name, "type": job.Type})
+	go func() {
+		var res result
+		defer func() {
+			if r := recover(); r != nil {
+				res.err = fmt.Errorf("job panicked: %v", r)
+			}
+			done <- res
+		}()
+		res.output, res.err = handler(ctx, job)
+	}()
+
+	select {
+	case res := <-done:
+		if ctx.Err() != nil && (res.err == nil || errors.Is(res.err, ctx.Err())) {
+			res.err = context.Cause(ctx)
+		}
+		return res.output, res.err
+	case <-ctx.Done():
+		s.log.Warn("Job context done before handler returned", logrus.Fields{"job_id": job.ID, "job": job.Name, "error": context.Cause(ctx)})
+		return "", context.Cause(ctx)
+	}
+}
+
+// finish menyimpan hasil attempt dan mengembalikan error akhirnya. Jika admin membatalkan
+// eksekusi sebelum hasilnya tersimpan, pembatalan yang berlaku dan ErrCancelled dikembalikan.
+func (s *Scheduler) finish(ctx context.Context, execution execution, output string, err error) error {
+	status, exitCode := models.StatusCompleted, models.ExitCodeSuccess
+	switch {
+	case errors.Is(err, ErrNoHandler):
+		status, exitCode = models.StatusFailed, models.ExitCodeNoHandler
+	case errors.Is(err, ErrCancelled):
+		status, exitCode = models.StatusCancelled, models.ExitCodeCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		status, exitCode = models.StatusTimeout, models.ExitCodeTimeout
+	case err != nil:
+		status, exitCode = models.StatusFailed, models.ExitCodeFailed
+	}
+	finishHistory(execution.history, s.now(), status, exitCode, output, err)
+
+	// riwayat tetap disimpan walaupun scheduler sedang dihentikan
+	updated, errUpdate := s.jobsRepository.FinishHistory(context.WithoutCancel(ctx), execution.history)
+	if errUpdate != nil {
+		s.log.Error("failed to update task execution history", logrus.Fields{
+			"history_id": execution.history.ID,
+			"error":      errUpdate,
+		})
+	}
+	if errUpdate == nil && !updated {
+		// baris sudah ditandai cancelled, hasil handler dibuang
+		execution.history.Status = models.StatusCancelled
+		execution.history.ExitCode = sql.NullInt32{Int32: models.ExitCodeCancelled, Valid: true}
+		status, exitCode, err = models.StatusCancelled, models.ExitCodeCancelled, ErrCancelled
+	}
+
+	fields := logrus.Fields{
+		"job_id":    execution.job.ID,
+		"job":       execution.job.Name,
+		"item":      execution.history.Item,
+		"attempt":   execution.history.Attempt,
+		"status":    status,
+		"exit_code": exitCode,
+	}
+	if err != nil {
+		fields["error"] = err
+		s.log.Error("Job failed", fields)
+	} else {
+		s.log.Info("Job completed", fields)
+	}
+	return err
+}
+
+func jobTimeout(job models.JobEntity) time.Duration {
+	if job.Timeout > 0 {
+		return time.Duration(job.Timeout) * time.Second
+	}
+	return defaultJobTimeout
+}
+
+func finishHistory(history *models.TaskExecutionHistoryEntity, now time.Time, status models.TaskExecutionStatus, exitCode int, output string, err error) {
+	history.Status = status
+	history.CompletedAt = sql.NullTime{Time: now, Valid: true}
+	history.ExitCode = sql.NullInt32{Int32: int32(exitCode), Valid: true}
+	history.Output = sql.NullString{String: output, Valid: output != ""}
+	if err != nil {
+		history.ErrorMessage = sql.NullString{String: err.Error(), Valid: true}
+	}
+}
+
+// NextExecution menghitung eksekusi berikutnya setelah now. Mengembalikan NULL jika cron
+// tidak pernah terpenuhi, sehingga schedule tidak di-claim lagi.
+func NextExecution(expression string, now time.Time) (sql.NullTime, error) {
+	schedule, err := cron.Parse(expression)
+	if err != nil {
+		return sql.NullTime{}, err
+	}
+	next := schedule.Next(now)
+	return sql.NullTime{Time: next, Valid: !next.IsZero()}, nil
+}
+
+func (s *Scheduler) pollInterval() time.Duration {
+	if s.cfg.PollInterval > 0 {
+		return s.cfg.PollInterval
+	}
+	return defaultPollInterval
+}
+
+func (s *Scheduler) batchSize() int {
+	if s.cfg.BatchSize > 0 {
+		return s.cfg.BatchSize
+	}
+	return defaultBatchSize
+}
@@ -0,0 +1,658 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// fakeJobsRepository menyimpan job, schedule dan riwayat eksekusi di memori.
+type fakeJobsRepository struct {
+	mu        sync.Mutex
+	jobs      map[uint]models.JobEntity
+	schedules map[uint]*models.TaskScheduleEntity
+	histories []*models.TaskExecutionHistoryEntity
+	cancelled map[uint]bool
+}
+
+func (r *fakeJobsRepository) Get(ctx context.Context, param *models.GetJobParam, opts ...utils.DBOption) ([]models.JobEntity, error) {
+	return nil, nil
+}
+
+func (r *fakeJobsRepository) RunJobTask(ctx context.Context, jobID uint, opts ...utils.DBOption) error {
+	return nil
+}
+
+func (r *fakeJobsRepository) GetByIDs(ctx context.Context, ids []uint, opts ...utils.DBOption) ([]models.JobEntity, error) {
+	var jobs []models.JobEntity
+	for _, id := range ids {
+		if job, ok := r.jobs[id]; ok {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *fakeJobsRepository) ClaimDueSchedules(ctx context.Context, now time.Time, limit int, opts ...utils.DBOption) ([]models.TaskScheduleEntity, error) {
+	var schedules []models.TaskScheduleEntity
+	for _, schedule := range r.schedules {
+		if schedule.IsActive && schedule.NextExecution.Valid && !schedule.NextExecution.Time.After(now) {
+			schedules = append(schedules, *schedule)
+		}
+	}
+	return schedules, nil
+}
+
+func (r *fakeJobsRepository) GetUnscheduled(ctx context.Context, opts ...utils.DBOption) ([]models.TaskScheduleEntity, error) {
+	var schedules []models.TaskScheduleEntity
+	for _, schedule := range r.schedules {
+		if schedule.IsActive && !schedule.NextExecution.Valid {
+			schedules = append(schedules, *schedule)
+		}
+	}
+	return schedules, nil
+}
+
+func (r *fakeJobsRepository) UpdateSchedule(ctx context.Context, schedule *models.TaskScheduleEntity, opts ...utils.DBOption) error {
+	stored := *schedule
+	r.schedules[schedule.ID] = &stored
+	return nil
+}
+
+func (r *fakeJobsRepository) CreateHistory(ctx context.Context, history *models.TaskExecutionHistoryEntity, opts ...utils.DBOption) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	history.ID = uint(len(r.histories) + 1)
+	r.histories = append(r.histories, history)
+	return nil
+}
+
+func (r *fakeJobsRepository) FinishHistory(ctx context.Context, history *models.TaskExecutionHistoryEntity, opts ...utils.DBOption) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cancelled[history.ID] {
+		return false, nil
+	}
+	for _, stored := range r.histories {
+		if stored.ID == history.ID && stored != history {
+			*stored = *history
+		}
+	}
+	return true, nil
+}
+
+func (r *fakeJobsRepository) UpdateScheduleByJobID(ctx context.Context, jobID uint, updates map[string]interface{}, opts ...utils.DBOption) error {
+	return nil
+}
+
+func (r *fakeJobsRepository) GetHistories(ctx context.Context, param *models.GetTaskExecutionHistoryParam, opts ...utils.DBOption) ([]models.TaskExecutionHistoryEntity, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var histories []models.TaskExecutionHistoryEntity
+	for _, history := range r.histories {
+		if param.Status == nil || history.Status == *param.Status {
+			histories = append(histories, *history)
+		}
+	}
+	return histories, int64(len(histories)), nil
+}
+
+func (r *fakeJobsRepository) GetHistoryByID(ctx context.Context, id uint, opts ...utils.DBOption) (*models.TaskExecutionHistoryEntity, error) {
+	return nil, nil
+}
+
+func (r *fakeJobsRepository) GetHistoriesByIDs(ctx context.Context, ids []uint, opts ...utils.DBOption) ([]models.TaskExecutionHistoryEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var histories []models.TaskExecutionHistoryEntity
+	for _, id := range ids {
+		status := models.StatusRunning
+		if r.cancelled[id] {
+			status = models.StatusCancelled
+		}
+		histories = append(histories, models.TaskExecutionHistoryEntity{ID: id, Status: status})
+	}
+	return histories, nil
+}
+
+func (r *fakeJobsRepository) CancelHistory(ctx context.Context, id uint, now time.Time, opts ...utils.DBOption) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cancelled == nil {
+		r.cancelled = make(map[uint]bool)
+	}
+	r.cancelled[id] = true
+	return true, nil
+}
+
+// fakePipelineRepository menyimpan dependency dan pipeline run di memori.
+type fakePipelineRepository struct {
+	mu           sync.Mutex
+	dependencies []models.JobDependencyEntity
+	runs         []models.PipelineRunEntity
+}
+
+func (r *fakePipelineRepository) GetDependencies(ctx context.Context, opts ...utils.DBOption) ([]models.JobDependencyEntity, error) {
+	return r.dependencies, nil
+}
+
+func (r *fakePipelineRepository) CreateRun(ctx context.Context, run *models.PipelineRunEntity, opts ...utils.DBOption) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run.ID = uint(len(r.runs) + 1)
+	r.runs = append(r.runs, *run)
+	return nil
+}
+
+func (r *fakePipelineRepository) UpdateRun(ctx context.Context, run *models.PipelineRunEntity, opts ...utils.DBOption) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs[run.ID-1] = *run
+	return nil
+}
+
+func (r *fakePipelineRepository) GetRuns(ctx context.Context, param *models.GetPipelineRunParam, opts ...utils.DBOption) ([]models.PipelineRunEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var runs []models.PipelineRunEntity
+	for _, run := range r.runs {
+		if param.Status == nil || run.Status == *param.Status {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+type fakeUnitOfWork struct{}
+
+func (fakeUnitOfWork) Begin() *gorm.DB { return nil }
+func (fakeUnitOfWork) Commit() error   { return nil }
+func (fakeUnitOfWork) Rollback() error { return nil }
+func (fakeUnitOfWork) Run(fn func(opts ...utils.DBOption) error) error {
+	return fn()
+}
+
+func TestScheduler_tick(t *testing.T) {
+	now := time.Date(2025, 1, 2, 9, 0, 30, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		jobType      string
+		cron         string
+		timeout      int
+		handler      HandlerFunc
+		wantStatus   models.TaskExecutionStatus
+		wantExitCode int32
+		wantOutput   string
+		wantNext     sql.NullTime
+	}{
+		{
+			name:    "completed",
+			jobType: "ok",
+			cron:    "*/5 * * * *",
+			handler: func(ctx context.Context, job models.JobEntity) (string, error) {
+				return "done", nil
+			},
+			wantStatus:   models.StatusCompleted,
+			wantExitCode: models.ExitCodeSuccess,
+			wantOutput:   "done",
+			wantNext:     sql.NullTime{Time: time.Date(2025, 1, 2, 9, 5, 0, 0, time.UTC), Valid: true},
+		},
+		{
+			name:    "failed",
+			jobType: "fail",
+			cron:    "0 9 * * *",
+			handler: func(ctx context.Context, job models.JobEntity) (string, error) {
+				return "partial", errors.New("boom")
+			},
+			wantStatus:   models.StatusFailed,
+			wantExitCode: models.ExitCodeFailed,
+			wantOutput:   "partial",
+			wantNext:     sql.NullTime{Time: time.Date(2025, 1, 3, 9, 0, 0, 0, time.UTC), Valid: true},
+		},
+		{
+			name:    "timeout",
+			jobType: "slow",
+			cron:    "* * * * *",
+			timeout: 1,
+			handler: func(ctx context.Context, job models.JobEntity) (string, error) {
+				<-ctx.Done()
+				return "", ctx.Err()
+			},
+			wantStatus:   models.StatusTimeout,
+			wantExitCode: models.ExitCodeTimeout,
+			wantNext:     sql.NullTime{Time: time.Date(2025, 1, 2, 9, 1, 0, 0, time.UTC), Valid: true},
+		},
+		{
+			name:         "no handler",
+			jobType:      "unknown",
+			cron:         "* * * * *",
+			wantStatus:   models.StatusFailed,
+			wantExitCode: models.ExitCodeNoHandler,
+			wantNext:     sql.NullTime{Time: time.Date(2025, 1, 2, 9, 1, 0, 0, time.UTC), Valid: true},
+		},
+		{
+			name:         "invalid cron",
+			jobType:      "ok",
+			cron:         "not a cron",
+			wantStatus:   models.StatusFailed,
+			wantExitCode: models.ExitCodeFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeJobsRepository{
+				jobs: map[uint]models.JobEntity{1: {ID: 1, Name: tt.name, Type: tt.jobType, Timeout: tt.timeout}},
+				schedules: map[uint]*models.TaskScheduleEntity{1: {
+					ID:             1,
+					JobID:          1,
+					CronExpression: tt.cron,
+					IsActive:       true,
+					NextExecution:  sql.NullTime{Time: now.Add(-time.Minute), Valid: true},
+				}},
+			}
+			s := NewScheduler(&config.SchedulerConfig{}, logrus.New(), repo, &fakePipelineRepository{}, fakeUnitOfWork{})
+			s.now = func() time.Time { return now }
+			if tt.handler != nil {
+				s.Register(tt.jobType, tt.handler)
+			}
+
+			s.tick(context.Background())
+			s.wg.Wait()
+
+			if len(repo.histories) != 1 {
+				t.Fatalf("histories = %d, want 1", len(repo.histories))
+			}
+			history := repo.histories[0]
+			if history.Status != tt.wantStatus || history.ExitCode.Int32 != tt.wantExitCode {
+				t.Errorf("history = %s (%d), want %s (%d), error %q", history.Status, history.ExitCode.Int32, tt.wantStatus, tt.wantExitCode, history.ErrorMessage.String)
+			}
+			if history.Output.String != tt.wantOutput {
+				t.Errorf("output = %q, want %q", history.Output.String, tt.wantOutput)
+			}
+
+			schedule := repo.schedules[1]
+			if schedule.NextExecution != tt.wantNext {
+				t.Errorf("next execution = %v, want %v", schedule.NextExecution, tt.wantNext)
+			}
+			if !schedule.LastExecution.Valid || !schedule.LastExecution.Time.Equal(now) {
+				t.Errorf("last execution = %v, want %s", schedule.LastExecution, now)
+			}
+		})
+	}
+}
+
+func TestScheduler_scheduleNew(t *testing.T) {
+	now := time.Date(2025, 1, 2, 9, 0, 30, 0, time.UTC)
+	repo := &fakeJobsRepository{
+		schedules: map[uint]*models.TaskScheduleEntity{1: {ID: 1, JobID: 1, CronExpression: "0 16 * * 1-5", IsActive: true}},
+	}
+	s := NewScheduler(&config.SchedulerConfig{}, logrus.New(), repo, &fakePipelineRepository{}, fakeUnitOfWork{})
+	s.now = func() time.Time { return now }
+
+	s.tick(context.Background())
+
+	want := time.Date(2025, 1, 2, 16, 0, 0, 0, time.UTC)
+	if got := repo.schedules[1].NextExecution; !got.Valid || !got.Time.Equal(want) {
+		t.Errorf("next execution = %v, want %s", got, want)
+	}
+	if len(repo.histories) != 0 {
+		t.Errorf("new schedule should not run immediately, got %d executions", len(repo.histories))
+	}
+}
+
+func TestScheduler_retry(t *testing.T) {
+	now := time.Date(2025, 1, 2, 9, 0, 30, 0, time.UTC)
+	rateLimited := &utils.HTTPStatusError{Service: "Yahoo Finance API", StatusCode: 429}
+
+	tests := []struct {
+		name         string
+		retryPolicy  string
+		timeout      int
+		handler      func(attempt int) HandlerFunc
+		wantStatuses []models.TaskExecutionStatus
+	}{
+		{
+			name:        "flaky job recovers",
+			retryPolicy: `{"max_attempts": 3, "initial_interval": "1ms"}`,
+			handler: func(attempt int) HandlerFunc {
+				return func(ctx context.Context, job models.JobEntity) (string, error) {
+					if attempt < 3 {
+						return "", rateLimited
+					}
+					return "done", nil
+				}
+			},
+			wantStatuses: []models.TaskExecutionStatus{models.StatusFailed, models.StatusFailed, models.StatusCompleted},
+		},
+		{
+			name:        "stops at max attempts",
+			retryPolicy: `{"max_attempts": 2, "backoff": "fixed", "initial_interval": "1ms"}`,
+			handler: func(attempt int) HandlerFunc {
+				return func(ctx context.Context, job models.JobEntity) (string, error) {
+					return "", rateLimited
+				}
+			},
+			wantStatuses: []models.TaskExecutionStatus{models.StatusFailed, models.StatusFailed},
+		},
+		{
+			name:        "permanent error not retried",
+			retryPolicy: `{"max_attempts": 3, "initial_interval": "1ms"}`,
+			handler: func(attempt int) HandlerFunc {
+				return func(ctx context.Context, job models.JobEntity) (string, error) {
+					return "", errors.New("invalid payload")
+				}
+			},
+			wantStatuses: []models.TaskExecutionStatus{models.StatusFailed},
+		},
+		{
+			name:        "invalid policy runs once",
+			retryPolicy: `{"max_attempts": "three"}`,
+			handler: func(attempt int) HandlerFunc {
+				return func(ctx context.Context, job models.JobEntity) (string, error) {
+					return "", rateLimited
+				}
+			},
+			wantStatuses: []models.TaskExecutionStatus{models.StatusFailed},
+		},
+		{
+			name:        "hung job marked timeout",
+			retryPolicy: `{"max_attempts": 1}`,
+			timeout:     1,
+			handler: func(attempt int) HandlerFunc {
+				return func(ctx context.Context, job models.JobEntity) (string, error) {
+					// mengabaikan ctx, seperti client tanpa timeout
+					time.Sleep(3 * time.Second)
+					return "late", nil
+				}
+			},
+			wantStatuses: []models.TaskExecutionStatus{models.StatusTimeout},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeJobsRepository{
+				jobs: map[uint]models.JobEntity{1: {ID: 1, Name: tt.name, Type: "flaky", Timeout: tt.timeout, RetryPolicy: []byte(tt.retryPolicy)}},
+				schedules: map[uint]*models.TaskScheduleEntity{1: {
+					ID:             1,
+					JobID:          1,
+					CronExpression: "* * * * *",
+					IsActive:       true,
+					NextExecution:  sql.NullTime{Time: now.Add(-time.Minute), Valid: true},
+				}},
+			}
+			s := NewScheduler(&config.SchedulerConfig{}, logrus.New(), repo, &fakePipelineRepository{}, fakeUnitOfWork{})
+			s.now = func() time.Time { return now }
+			attempt := 0
+			s.Register("flaky", func(ctx context.Context, job models.JobEntity) (string, error) {
+				attempt++
+				return tt.handler(attempt)(ctx, job)
+			})
+
+			s.tick(context.Background())
+			s.wg.Wait()
+
+			if len(repo.histories) != len(tt.wantStatuses) {
+				t.Fatalf("histories = %d, want %d", len(repo.histories), len(tt.wantStatuses))
+			}
+			for i, history := range repo.histories {
+				if history.Attempt != i+1 {
+					t.Errorf("history #%d attempt = %d, want %d", i, history.Attempt, i+1)
+				}
+				if history.Status != tt.wantStatuses[i] {
+					t.Errorf("history #%d status = %s, want %s", i, history.Status, tt.wantStatuses[i])
+				}
+			}
+		})
+	}
+}
+
+func TestScheduler_cancel(t *testing.T) {
+	now := time.Date(2025, 1, 2, 9, 0, 30, 0, time.UTC)
+	repo := &fakeJobsRepository{
+		jobs: map[uint]models.JobEntity{1: {ID: 1, Name: "long", Type: "long", RetryPolicy: []byte(`{"max_attempts": 3, "retry_on": ["any"]}`)}},
+		schedules: map[uint]*models.TaskScheduleEntity{1: {
+			ID:             1,
+			JobID:          1,
+			CronExpression: "* * * * *",
+			IsActive:       true,
+			NextExecution:  sql.NullTime{Time: now.Add(-time.Minute), Valid: true},
+		}},
+	}
+	s := NewScheduler(&config.SchedulerConfig{}, logrus.New(), repo, &fakePipelineRepository{}, fakeUnitOfWork{})
+	s.now = func() time.Time { return now }
+	started := make(chan struct{})
+	s.Register("long", func(ctx context.Context, job models.JobEntity) (string, error) {
+		close(started)
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+
+	s.tick(context.Background())
+	<-started
+	if _, err := repo.CancelHistory(context.Background(), 1, now); err != nil {
+		t.Fatalf("CancelHistory() error = %v", err)
+	}
+	if err := s.cancelRequested(context.Background()); err != nil {
+		t.Fatalf("cancelRequested() error = %v", err)
+	}
+	s.wg.Wait()
+
+	// eksekusi yang dibatalkan tidak di-retry walaupun retry_on any
+	if len(repo.histories) != 1 {
+		t.Fatalf("histories = %d, want 1", len(repo.histories))
+	}
+	history := repo.histories[0]
+	if history.Status != models.StatusCancelled || history.ExitCode.Int32 != models.ExitCodeCancelled {
+		t.Errorf("history = %s (%d), want %s (%d)", history.Status, history.ExitCode.Int32, models.StatusCancelled, models.ExitCodeCancelled)
+	}
+}
+
+func TestScheduler_cancelBeforeFinish(t *testing.T) {
+	now := time.Date(2025, 1, 2, 9, 0, 30, 0, time.UTC)
+	repo := &fakeJobsRepository{
+		jobs: map[uint]models.JobEntity{1: {ID: 1, Name: "quick", Type: "quick", RetryPolicy: []byte(`{"max_attempts": 3, "retry_on": ["any"]}`)}},
+		schedules: map[uint]*models.TaskScheduleEntity{1: {
+			ID:             1,
+			JobID:          1,
+			CronExpression: "* * * * *",
+			IsActive:       true,
+			NextExecution:  sql.NullTime{Time: now.Add(-time.Minute), Valid: true},
+		}},
+	}
+	s := NewScheduler(&config.SchedulerConfig{}, logrus.New(), repo, &fakePipelineRepository{}, fakeUnitOfWork{})
+	s.now = func() time.Time { return now }
+	// admin membatalkan eksekusi, tapi handler selesai sebelum tick berikutnya menerapkan pembatalan
+	s.Register("quick", func(ctx context.Context, job models.JobEntity) (string, error) {
+		if _, err := repo.CancelHistory(ctx, 1, now); err != nil {
+			return "", err
+		}
+		return "done", nil
+	})
+
+	s.tick(context.Background())
+	s.wg.Wait()
+
+	if len(repo.histories) != 1 {
+		t.Fatalf("histories = %d, want 1", len(repo.histories))
+	}
+	history := repo.histories[0]
+	if history.Status != models.StatusCancelled || history.ExitCode.Int32 != models.ExitCodeCancelled {
+		t.Errorf("history = %s (%d), want %s (%d)", history.Status, history.ExitCode.Int32, models.StatusCancelled, models.ExitCodeCancelled)
+	}
+}
+
+func TestScheduler_pipeline(t *testing.T) {
+	now := time.Date(2025, 1, 2, 9, 0, 30, 0, time.UTC)
+	// fetch prices -> analyze per saham (fan-out) -> send buy list (fan-in)
+	dependencies := []models.JobDependencyEntity{
+		{JobID: 2, DependsOnJobID: 1},
+		{JobID: 3, DependsOnJobID: 2},
+	}
+
+	tests := []struct {
+		name          string
+		failItem      string
+		wantExecuted  []string
+		wantStatus    models.PipelineRunStatus
+		wantFailedJob uint
+	}{
+		{
+			name:         "all stages succeed",
+			wantExecuted: []string{"fetch:", "analyze:BBCA", "analyze:BBRI", "buylist:"},
+			wantStatus:   models.PipelineRunCompleted,
+		},
+		{
+			name:          "fan-out item fails",
+			failItem:      "BBRI",
+			wantExecuted:  []string{"fetch:", "analyze:BBCA", "analyze:BBRI"},
+			wantStatus:    models.PipelineRunFailed,
+			wantFailedJob: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeJobsRepository{
+				jobs: map[uint]models.JobEntity{
+					1: {ID: 1, Name: "fetch", Type: "fetch"},
+					2: {ID: 2, Name: "analyze", Type: "analyze", FanOut: models.JobFanOutStockList},
+					3: {ID: 3, Name: "buylist", Type: "buylist"},
+				},
+				schedules: map[uint]*models.TaskScheduleEntity{1: {
+					ID:             1,
+					JobID:          1,
+					CronExpression: "0 9 * * *",
+					IsActive:       true,
+					NextExecution:  sql.NullTime{Time: now.Add(-time.Minute), Valid: true},
+				}},
+			}
+			pipelineRepo := &fakePipelineRepository{dependencies: dependencies}
+			s := NewScheduler(&config.SchedulerConfig{MaxConcurrentJobs: 1}, logrus.New(), repo, pipelineRepo, fakeUnitOfWork{})
+			s.now = func() time.Time { return now }
+
+			var mu sync.Mutex
+			var executed []string
+			handler := func(ctx context.Context, job models.JobEntity) (string, error) {
+				item, _ := ItemFromContext(ctx)
+				mu.Lock()
+				executed = append(executed, job.Name+":"+item)
+				mu.Unlock()
+				if item != "" && item == tt.failItem {
+					return "", errors.New("no data returned for symbol")
+				}
+				return "ok", nil
+			}
+			for _, jobType := range []string{"fetch", "analyze", "buylist"} {
+				s.Register(jobType, handler)
+			}
+			s.RegisterFanOut(models.JobFanOutStockList, func(ctx context.Context) ([]string, error) {
+				return []string{"BBCA", "BBRI"}, nil
+			})
+
+			s.tick(context.Background())
+			s.wg.Wait()
+
+			if fmt.Sprint(executed) != fmt.Sprint(tt.wantExecuted) {
+				t.Errorf("executed = %v, want %v", executed, tt.wantExecuted)
+			}
+			if len(pipelineRepo.runs) != 1 {
+				t.Fatalf("pipeline runs = %d, want 1", len(pipelineRepo.runs))
+			}
+			run := pipelineRepo.runs[0]
+			if run.Status != tt.wantStatus {
+				t.Errorf("run status = %s, want %s (%s)", run.Status, tt.wantStatus, run.ErrorMessage.String)
+			}
+			if tt.wantFailedJob != 0 && (run.FailedJobID == nil || *run.FailedJobID != tt.wantFailedJob) {
+				t.Errorf("failed job = %v, want %d", run.FailedJobID, tt.wantFailedJob)
+			}
+			for _, history := range repo.histories {
+				if history.PipelineRunID == nil || *history.PipelineRunID != run.ID {
+					t.Errorf("history job %d item %q pipeline run = %v, want %d", history.JobID, history.Item, history.PipelineRunID, run.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestScheduler_recoverInterrupted(t *testing.T) {
+	now := time.Date(2025, 1, 2, 9, 30, 0, 0, time.UTC)
+	history := func(id, jobID uint, runID *uint, status models.TaskExecutionStatus, startedAt time.Time) *models.TaskExecutionHistoryEntity {
+		return &models.TaskExecutionHistoryEntity{ID: id, JobID: jobID, PipelineRunID: runID, Status: status, StartedAt: startedAt}
+	}
+
+	repo := &fakeJobsRepository{
+		jobs: map[uint]models.JobEntity{
+			1: {ID: 1, Name: "fetch", Timeout: 60},
+			2: {ID: 2, Name: "analyze", Timeout: 600},
+			3: {ID: 3, Name: "report", Timeout: 60},
+		},
+		histories: []*models.TaskExecutionHistoryEntity{
+			// run 1 terputus di stage analyze
+			history(1, 1, utils.ToPointer(uint(1)), models.StatusCompleted, now.Add(-time.Hour)),
+			history(2, 2, utils.ToPointer(uint(1)), models.StatusRunning, now.Add(-time.Hour)),
+			// run 2 masih berjalan di replica lain, belum melewati timeout analyze
+			history(3, 2, utils.ToPointer(uint(2)), models.StatusRunning, now.Add(-5*time.Minute)),
+			// eksekusi tanpa pipeline yang terputus
+			history(4, 3, nil, models.StatusRunning, now.Add(-2*time.Minute)),
+		},
+	}
+	pipelineRepo := &fakePipelineRepository{runs: []models.PipelineRunEntity{
+		{ID: 1, RootJobID: 1, Status: models.PipelineRunRunning, StartedAt: now.Add(-time.Hour), Histories: []models.TaskExecutionHistoryEntity{*repo.histories[0], *repo.histories[1]}},
+		{ID: 2, RootJobID: 1, Status: models.PipelineRunRunning, StartedAt: now.Add(-10 * time.Minute), Histories: []models.TaskExecutionHistoryEntity{*repo.histories[2]}},
+		// run 3 terhenti di antara stage, stage terakhir selesai 10 menit lalu
+		{ID: 3, RootJobID: 1, Status: models.PipelineRunRunning, StartedAt: now.Add(-time.Hour), Histories: []models.TaskExecutionHistoryEntity{
+			{ID: 5, JobID: 1, Status: models.StatusCompleted, StartedAt: now.Add(-time.Hour), CompletedAt: sql.NullTime{Time: now.Add(-10 * time.Minute), Valid: true}},
+		}},
+	}}
+	s := NewScheduler(&config.SchedulerConfig{}, logrus.New(), repo, pipelineRepo, fakeUnitOfWork{})
+	s.now = func() time.Time { return now }
+
+	if err := s.recoverInterrupted(context.Background()); err != nil {
+		t.Fatalf("recoverInterrupted() error = %v", err)
+	}
+
+	wantHistories := map[uint]models.TaskExecutionStatus{
+		1: models.StatusCompleted,
+		2: models.StatusFailed,
+		3: models.StatusRunning,
+		4: models.StatusFailed,
+	}
+	for _, history := range repo.histories {
+		if history.Status != wantHistories[history.ID] {
+			t.Errorf("history %d status = %s, want %s", history.ID, history.Status, wantHistories[history.ID])
+		}
+	}
+
+	tests := []struct {
+		runID         uint
+		wantStatus    models.PipelineRunStatus
+		wantFailedJob uint
+	}{
+		{runID: 1, wantStatus: models.PipelineRunFailed, wantFailedJob: 2},
+		{runID: 2, wantStatus: models.PipelineRunRunning},
+		{runID: 3, wantStatus: models.PipelineRunFailed, wantFailedJob: 1},
+	}
+	for _, tt := range tests {
+		run := pipelineRepo.runs[tt.runID-1]
+		if run.Status != tt.wantStatus {
+			t.Errorf("run %d status = %s, want %s", tt.runID, run.Status, tt.wantStatus)
+		}
+		if tt.wantFailedJob != 0 && (run.FailedJobID == nil || *run.FailedJobID != tt.wantFailedJob) {
+			t.Errorf("run %d failed job = %v, want %d", tt.runID, run.FailedJobID, tt.wantFailedJob)
+		}
+		if tt.wantStatus == models.PipelineRunFailed && run.ErrorMessage.String != ErrInterrupted.Error() {
+			t.Errorf("run %d error = %q, want %q", tt.runID, run.ErrorMessage.String, ErrInterrupted)
+		}
+	}
+}
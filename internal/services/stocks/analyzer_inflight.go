@@ -0,0 +1,299 @@
+package stocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang-swing-trading-signal/internal/events"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+	"golang-swing-trading-signal/pkg/redis"
+
+	goRedis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultAnalyzerInflightTTL = 10 * time.Minute
+	defaultFreshSignalTTL      = 30 * time.Minute
+	signalPollInterval         = 5 * time.Second
+)
+
+// analyzerInflightStore menyimpan penanda analisa in-flight per input (saham, interval, range)
+// beserta user yang menunggu hasilnya. Penanda dimiliki oleh correlation ID request yang
+// dikirim ke worker, sehingga hasil worker cukup membawa correlation ID yang sama.
+type analyzerInflightStore interface {
+	// Acquire mengembalikan true jika correlationID menjadi pemilik penanda input. Jika input
+	// sedang in-flight, waiter (0 jika tidak perlu notifikasi) dicatat sebagai penunggu.
+	Acquire(ctx context.Context, input, correlationID string, waiter int64, ttl time.Duration) (bool, error)
+	// Release melepas penanda input jika masih dimiliki correlationID.
+	Release(ctx context.Context, input, correlationID string) error
+	// Take melepas penanda milik correlationID dan mengambil penunggunya secara atomik.
+	Take(ctx context.Context, correlationID string) ([]int64, error)
+	// InFlight bernilai true selama penanda input masih ada.
+	InFlight(ctx context.Context, input string) (bool, error)
+}
+
+// acquireAnalyzerScript menandai input sebagai in-flight milik ARGV[3] dan mencatat input
+// tersebut di key request. Jika input sedang berjalan, telegram ID (ARGV[2], 0 jika tidak
+// perlu notifikasi) dicatat sebagai penunggu hasil.
+var acquireAnalyzerScript = goRedis.NewScript(`
+if redis.call('SET', KEYS[1], ARGV[3], 'NX', 'PX', ARGV[1]) then
+	redis.call('SET', KEYS[3], ARGV[4], 'PX', ARGV[1])
+	return 1
+end
+if ARGV[2] ~= '0' then
+	redis.call('SADD', KEYS[2], ARGV[2])
+	redis.call('PEXPIRE', KEYS[2], ARGV[1])
+end
+return 0
+`)
+
+// releaseAnalyzerScript hanya menghapus penanda yang masih dimiliki ARGV[1]. Penunggu
+// dibiarkan agar ikut mendapat hasil dari request berikutnya.
+var releaseAnalyzerScript = goRedis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('DEL', KEYS[1])
+end
+redis.call('DEL', KEYS[2])
+return 1
+`)
+
+// takeAnalyzerWaitersScript melepas penanda in-flight dan mengambil penunggu secara atomik
+// agar tidak ada user yang ditambahkan setelah penunggu dibaca. Penanda yang sudah kedaluwarsa
+// dan diambil alih request lain tidak disentuh.
+var takeAnalyzerWaitersScript = goRedis.NewScript(`
+redis.call('DEL', KEYS[3])
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return {}
+end
+local waiters = redis.call('SMEMBERS', KEYS[2])
+redis.call('DEL', KEYS[1], KEYS[2])
+return waiters
+`)
+
+func analyzerInput(stockCode, interval, period string) string {
+	return fmt.Sprintf("%s:%s:%s", stockCode, interval, period)
+}
+
+func analyzerInflightKey(input string) string {
+	return "stock_analyzer:inflight:" + input
+}
+
+func analyzerWaitersKey(input string) string {
+	return "stock_analyzer:waiters:" + input
+}
+
+func analyzerRequestKey(correlationID string) string {
+	return "stock_analyzer:request:" + correlationID
+}
+
+type redisAnalyzerInflightStore struct {
+	client *redis.Client
+	logger *logrus.Logger
+}
+
+func newRedisAnalyzerInflightStore(client *redis.Client, logger *logrus.Logger) analyzerInflightStore {
+	return &redisAnalyzerInflightStore{client: client, logger: logger}
+}
+
+func (r *redisAnalyzerInflightStore) Acquire(ctx context.Context, input, correlationID string, waiter int64, ttl time.Duration) (bool, error) {
+	acquired, err := acquireAnalyzerScript.Run(ctx, r.client,
+		[]string{analyzerInflightKey(input), analyzerWaitersKey(input), analyzerRequestKey(correlationID)},
+		ttl.Milliseconds(), waiter, correlationID, input,
+	).Int()
+	if err != nil {
+		return false, err
+	}
+	return acquired == 1, nil
+}
+
+func (r *redisAnalyzerInflightStore) Release(ctx context.Context, input, correlationID string) error {
+	return releaseAnalyzerScript.Run(ctx, r.client,
+		[]string{analyzerInflightKey(input), analyzerRequestKey(correlationID)},
+		correlationID,
+	).Err()
+}
+
+func (r *redisAnalyzerInflightStore) Take(ctx context.Context, correlationID string) ([]int64, error) {
+	// key request tidak pernah berubah setelah dibuat sehingga aman dibaca di luar script
+	input, err := r.client.Get(ctx, analyzerRequestKey(correlationID)).Result()
+	if errors.Is(err, goRedis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := takeAnalyzerWaitersScript.Run(ctx, r.client,
+		[]string{analyzerInflightKey(input), analyzerWaitersKey(input), analyzerRequestKey(correlationID)},
+		correlationID,
+	).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	waiters := make([]int64, 0, len(members))
+	for _, member := range members {
+		telegramID, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			r.logger.Warn("invalid stock analyzer waiter", logrus.Fields{
+				"waiter": member,
+				"error":  err,
+			})
+			continue
+		}
+		waiters = append(waiters, telegramID)
+	}
+	return waiters, nil
+}
+
+func (r *redisAnalyzerInflightStore) InFlight(ctx context.Context, input string) (bool, error) {
+	exists, err := r.client.Exists(ctx, analyzerInflightKey(input)).Result()
+	if err != nil {
+		return false, err
+	}
+	return exists > 0, nil
+}
+
+func (s *stockService) freshSignalAfter() time.Time {
+	ttl := s.cfg.Gemini.AnalysisCacheTTL
+	if ttl <= 0 {
+		ttl = defaultFreshSignalTTL
+	}
+	return utils.TimeNowWIB().Add(-ttl)
+}
+
+func (s *stockService) analyzerInflightTTL() time.Duration {
+	if s.cfg.Trading.AnalyzerInflightTTL > 0 {
+		return s.cfg.Trading.AnalyzerInflightTTL
+	}
+	return defaultAnalyzerInflightTTL
+}
+
+// reuseFreshStockSignal memakai ulang signal dengan input yang sama yang belum lebih tua dari
+// cache analisa. User yang meminta notifikasi langsung dikirimi hasil lewat stream hasil.
+func (s *stockService) reuseFreshStockSignal(ctx context.Context, param *models.RequestStockAnalyzer) (bool, error) {
+	signal, err := s.stockSignalRepository.GetLatestByInput(ctx, param.StockCode, param.Interval, param.Range, s.freshSignalAfter())
+	if err != nil {
+		s.logger.Error("failed to get fresh stock signal", logrus.Fields{
+			"stock_code": param.StockCode,
+			"error":      err,
+		})
+		return false, fmt.Errorf("failed to get fresh stock signal: %w", err)
+	}
+	if signal == nil {
+		return false, nil
+	}
+
+	s.logger.Debug("reusing fresh stock signal", logrus.Fields{
+		"stock_code": param.StockCode,
+		"signal_id":  signal.ID,
+	})
+	if !param.NotifyUser || param.TelegramID == 0 {
+		return true, nil
+	}
+
+	if _, err := s.publisher.Publish(ctx, models.RedisStreamStockAnalyzerResult, events.TypeStockAnalyzerCompleted, &models.StockAnalyzerResult{
+		StockCode:  param.StockCode,
+		TelegramID: param.TelegramID,
+		NotifyUser: true,
+		Reused:     true,
+	}); err != nil {
+		s.logger.Error("failed to send redis stream stock analyzer result", logrus.Fields{
+			"error": err,
+		})
+		return false, err
+	}
+	return true, nil
+}
+
+// acquireStockAnalyzer mengembalikan true jika request harus dikirim ke worker. Request
+// dengan saham, interval dan range yang sama selama analisa masih berjalan hanya menunggu
+// hasil analisa tersebut. Jika redis gagal, request tetap dikirim.
+func (s *stockService) acquireStockAnalyzer(ctx context.Context, param *models.RequestStockAnalyzer, correlationID string) bool {
+	var waiter int64
+	if param.NotifyUser {
+		waiter = param.TelegramID
+	}
+
+	acquired, err := s.analyzerInflight.Acquire(ctx, analyzerInput(param.StockCode, param.Interval, param.Range), correlationID, waiter, s.analyzerInflightTTL())
+	if err != nil {
+		s.logger.Warn("failed to acquire stock analyzer in-flight key", logrus.Fields{
+			"stock_code": param.StockCode,
+			"error":      err,
+		})
+		return true
+	}
+	if !acquired {
+		s.logger.Info("stock analyzer already in-flight, waiting for result", logrus.Fields{
+			"stock_code":  param.StockCode,
+			"telegram_id": param.TelegramID,
+		})
+		return false
+	}
+	return true
+}
+
+// releaseStockAnalyzer menghapus penanda in-flight saat request gagal dikirim agar request
+// berikutnya tidak menunggu hasil yang tidak akan datang.
+func (s *stockService) releaseStockAnalyzer(ctx context.Context, param *models.RequestStockAnalyzer, correlationID string) {
+	if err := s.analyzerInflight.Release(ctx, analyzerInput(param.StockCode, param.Interval, param.Range), correlationID); err != nil {
+		s.logger.Warn("failed to release stock analyzer in-flight key", logrus.Fields{
+			"stock_code": param.StockCode,
+			"error":      err,
+		})
+	}
+}
+
+func (s *stockService) TakeStockAnalyzerWaiters(ctx context.Context, correlationID string) ([]int64, error) {
+	waiters, err := s.analyzerInflight.Take(ctx, correlationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to take stock analyzer waiters: %w", err)
+	}
+	return waiters, nil
+}
+
+func (s *stockService) WaitStockSignal(ctx context.Context, param *models.RequestStockAnalyzer) (*models.StockSignalEntity, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.analyzerInflightTTL())
+	defer cancel()
+
+	// signal dari analisa yang sedang berjalan maupun signal segar yang dipakai ulang sama-sama
+	// dibuat setelah batas ini
+	after := s.freshSignalAfter()
+	input := analyzerInput(param.StockCode, param.Interval, param.Range)
+	ticker := time.NewTicker(signalPollInterval)
+	defer ticker.Stop()
+
+	for {
+		signal, err := s.stockSignalRepository.GetLatestByInput(ctx, param.StockCode, param.Interval, param.Range, after)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get stock signal %s: %w", param.StockCode, err)
+		}
+		if signal != nil {
+			return signal, nil
+		}
+
+		// penanda in-flight dilepas saat hasil worker diterima, tanpa signal berarti analisa gagal
+		inFlight, err := s.analyzerInflight.InFlight(ctx, input)
+		if err == nil && !inFlight {
+			signal, err = s.stockSignalRepository.GetLatestByInput(ctx, param.StockCode, param.Interval, param.Range, after)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get stock signal %s: %w", param.StockCode, err)
+			}
+			if signal == nil {
+				return nil, fmt.Errorf("analysis %s finished without signal", param.StockCode)
+			}
+			return signal, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to wait stock signal %s: %w", param.StockCode, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
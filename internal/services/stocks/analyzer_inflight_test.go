@@ -0,0 +1,224 @@
+package stocks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/events"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/repository"
+	"golang-swing-trading-signal/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+type recordingPublisher struct {
+	streams        []string
+	correlationIDs []string
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, stream string, eventType string, payload interface{}) (*events.Envelope, error) {
+	p.streams = append(p.streams, stream)
+	p.correlationIDs = append(p.correlationIDs, events.CorrelationIDFromContext(ctx))
+	return &events.Envelope{CorrelationID: events.CorrelationIDFromContext(ctx)}, nil
+}
+
+// memoryAnalyzerInflightStore meniru semantik script redis di memori.
+type memoryAnalyzerInflightStore struct {
+	mu       sync.Mutex
+	owners   map[string]string // input -> correlation ID
+	waiters  map[string][]int64
+	requests map[string]string // correlation ID -> input
+}
+
+func newMemoryAnalyzerInflightStore() *memoryAnalyzerInflightStore {
+	return &memoryAnalyzerInflightStore{
+		owners:   map[string]string{},
+		waiters:  map[string][]int64{},
+		requests: map[string]string{},
+	}
+}
+
+func (m *memoryAnalyzerInflightStore) Acquire(ctx context.Context, input, correlationID string, waiter int64, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.owners[input]; !ok {
+		m.owners[input] = correlationID
+		m.requests[correlationID] = input
+		return true, nil
+	}
+	if waiter != 0 {
+		m.waiters[input] = append(m.waiters[input], waiter)
+	}
+	return false, nil
+}
+
+func (m *memoryAnalyzerInflightStore) Release(ctx context.Context, input, correlationID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.owners[input] == correlationID {
+		delete(m.owners, input)
+	}
+	delete(m.requests, correlationID)
+	return nil
+}
+
+func (m *memoryAnalyzerInflightStore) Take(ctx context.Context, correlationID string) ([]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	input, ok := m.requests[correlationID]
+	delete(m.requests, correlationID)
+	if !ok || m.owners[input] != correlationID {
+		return nil, nil
+	}
+	waiters := m.waiters[input]
+	delete(m.owners, input)
+	delete(m.waiters, input)
+	return waiters, nil
+}
+
+func (m *memoryAnalyzerInflightStore) InFlight(ctx context.Context, input string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.owners[input]
+	return ok, nil
+}
+
+type freshSignalRepository struct {
+	repository.StockSignalRepository
+	signal *models.StockSignalEntity
+}
+
+func (r *freshSignalRepository) GetLatestByInput(ctx context.Context, stockCode, interval, period string, after time.Time) (*models.StockSignalEntity, error) {
+	return r.signal, nil
+}
+
+// newTestRedis membutuhkan redis, jalankan dengan REDIS_TEST_ADDR=localhost:6379.
+func newTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	addr := os.Getenv("REDIS_TEST_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_TEST_ADDR not set")
+	}
+	var host string
+	var port int
+	if _, err := fmt.Sscanf(addr, "%[^:]:%d", &host, &port); err != nil {
+		t.Fatalf("invalid REDIS_TEST_ADDR: %v", err)
+	}
+	client, err := redis.NewClient(redis.Config{Host: host, Port: port})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestRequestStockAnalyzer_ReusesFreshSignal(t *testing.T) {
+	tests := []struct {
+		name        string
+		notifyUser  bool
+		wantStreams []string
+	}{
+		{name: "notify user through result stream", notifyUser: true, wantStreams: []string{models.RedisStreamStockAnalyzerResult}},
+		{name: "scheduled request skipped", notifyUser: false, wantStreams: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &recordingPublisher{}
+			s := &stockService{
+				cfg:                   &config.Config{},
+				logger:                logrus.New(),
+				stockSignalRepository: &freshSignalRepository{signal: &models.StockSignalEntity{ID: 1, StockCode: "BBCA"}},
+				publisher:             publisher,
+			}
+
+			err := s.RequestStockAnalyzer(context.Background(), &models.RequestStockAnalyzer{
+				StockCode:  "BBCA",
+				TelegramID: 1,
+				NotifyUser: tt.notifyUser,
+			})
+			if err != nil {
+				t.Fatalf("RequestStockAnalyzer() error = %v", err)
+			}
+			if !reflect.DeepEqual(publisher.streams, tt.wantStreams) {
+				t.Errorf("published streams = %v, want %v", publisher.streams, tt.wantStreams)
+			}
+		})
+	}
+}
+
+func TestRequestStockAnalyzer_CoalescesInflight(t *testing.T) {
+	testCoalescesInflight(t, newMemoryAnalyzerInflightStore(), "BBCA")
+}
+
+func TestRequestStockAnalyzer_CoalescesInflightRedis(t *testing.T) {
+	client := newTestRedis(t)
+	stockCode := fmt.Sprintf("T%d", time.Now().UnixNano()%1000)
+	t.Cleanup(func() {
+		input := analyzerInput(stockCode, "", "")
+		client.Del(context.Background(), analyzerInflightKey(input), analyzerWaitersKey(input))
+	})
+	testCoalescesInflight(t, newRedisAnalyzerInflightStore(client, logrus.New()), stockCode)
+}
+
+func testCoalescesInflight(t *testing.T, store analyzerInflightStore, stockCode string) {
+	t.Helper()
+	ctx := context.Background()
+	publisher := &recordingPublisher{}
+	s := &stockService{
+		cfg:                   &config.Config{},
+		logger:                logrus.New(),
+		stockSignalRepository: &freshSignalRepository{},
+		publisher:             publisher,
+		analyzerInflight:      store,
+	}
+
+	for _, telegramID := range []int64{1, 2, 3} {
+		if err := s.RequestStockAnalyzer(ctx, &models.RequestStockAnalyzer{StockCode: stockCode, TelegramID: telegramID, NotifyUser: true}); err != nil {
+			t.Fatalf("RequestStockAnalyzer() error = %v", err)
+		}
+	}
+	if len(publisher.streams) != 1 {
+		t.Fatalf("published %d requests, want 1", len(publisher.streams))
+	}
+
+	// hasil dengan correlation ID lain tidak boleh melepas penanda milik request yang berjalan
+	waiters, err := s.TakeStockAnalyzerWaiters(ctx, "unknown")
+	if err != nil {
+		t.Fatalf("TakeStockAnalyzerWaiters() error = %v", err)
+	}
+	if len(waiters) != 0 {
+		t.Errorf("TakeStockAnalyzerWaiters(unknown) = %v, want none", waiters)
+	}
+
+	// worker tidak wajib mengembalikan interval dan range, cukup correlation ID request
+	waiters, err = s.TakeStockAnalyzerWaiters(ctx, publisher.correlationIDs[0])
+	if err != nil {
+		t.Fatalf("TakeStockAnalyzerWaiters() error = %v", err)
+	}
+	sort.Slice(waiters, func(i, j int) bool { return waiters[i] < waiters[j] })
+	if !reflect.DeepEqual(waiters, []int64{2, 3}) {
+		t.Errorf("TakeStockAnalyzerWaiters() = %v, want [2 3]", waiters)
+	}
+
+	// setelah hasil diterima, request berikutnya boleh dikirim lagi dengan correlation ID baru
+	if err := s.RequestStockAnalyzer(ctx, &models.RequestStockAnalyzer{StockCode: stockCode, TelegramID: 4, NotifyUser: true}); err != nil {
+		t.Fatalf("RequestStockAnalyzer() error = %v", err)
+	}
+	if len(publisher.streams) != 2 {
+		t.Fatalf("published %d requests, want 2", len(publisher.streams))
+	}
+	if publisher.correlationIDs[1] == publisher.correlationIDs[0] {
+		t.Error("second request reused the first correlation ID")
+	}
+	if _, err := s.TakeStockAnalyzerWaiters(ctx, publisher.correlationIDs[1]); err != nil {
+		t.Fatalf("TakeStockAnalyzerWaiters() error = %v", err)
+	}
+}
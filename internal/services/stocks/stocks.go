@@ -2,16 +2,17 @@ package stocks
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/events"
 	"golang-swing-trading-signal/internal/models"
 	"golang-swing-trading-signal/internal/repository"
+	"golang-swing-trading-signal/internal/services/quota"
 	"golang-swing-trading-signal/internal/utils"
 	"golang-swing-trading-signal/pkg/redis"
+	"strings"
 
-	goRedis "github.com/redis/go-redis/v9"
-
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
@@ -23,12 +24,24 @@ type StockService interface {
 	GetStockPositionsTelegramUser(ctx context.Context, telegramID int64, monitoring *models.StockPositionMonitoringQueryParam) ([]models.StockPositionEntity, error)
 	GetStockPosition(ctx context.Context, param models.StockPositionQueryParam) ([]models.StockPositionEntity, error)
 	GetByParam(ctx context.Context, param models.GetStocksParam) ([]models.StockEntity, error)
+	// SearchStocks mencari saham berdasarkan awalan kode atau nama untuk inline query.
+	SearchStocks(ctx context.Context, query string, limit int) ([]models.StockEntity, error)
 	GetTopNews(ctx context.Context, param models.StockNewsQueryParam) ([]models.StockNewsEntity, error)
 	GetLastStockNewsSummary(ctx context.Context, age int, stockCode string) (*models.StockNewsSummaryEntity, error)
+	// GetLastStockNewsSummaries mengembalikan ringkasan berita terbaru per kode saham dalam satu query.
+	GetLastStockNewsSummaries(ctx context.Context, age int, stockCodes []string) (map[string]models.StockNewsSummaryEntity, error)
 	GetLatestStockSignal(ctx context.Context, param models.GetStockBuySignalParam) ([]models.StockSignalEntity, error)
 	GetLatestStockPositionMonitoring(ctx context.Context, param models.GetStockPositionMonitoringParam) ([]models.StockPositionMonitoringEntity, error)
 	RequestStockPositionMonitoring(ctx context.Context, param *models.RequestStockPositionMonitoring) error
+	// RequestStockAnalyzer meminta analisa ke worker. Signal yang masih segar dipakai ulang dan
+	// request selama analisa yang sama masih berjalan menunggu hasil analisa tersebut.
 	RequestStockAnalyzer(ctx context.Context, param *models.RequestStockAnalyzer) error
+	// TakeStockAnalyzerWaiters melepas penanda analisa in-flight milik request dengan
+	// correlationID tersebut dan mengembalikan telegram ID user yang menunggu hasilnya.
+	TakeStockAnalyzerWaiters(ctx context.Context, correlationID string) ([]int64, error)
+	// WaitStockSignal menunggu signal hasil RequestStockAnalyzer tersimpan, dibatasi ctx dan
+	// AnalyzerInflightTTL. Error dikembalikan jika analisa selesai tanpa signal baru.
+	WaitStockSignal(ctx context.Context, param *models.RequestStockAnalyzer) (*models.StockSignalEntity, error)
 	GetTopNewsGlobal(ctx context.Context, limit int, age int) ([]models.TopNewsCustomResult, error)
 	GetStockPositionWithHistoryMonitoring(ctx context.Context, param models.StockPositionQueryParam) (*models.StockPositionEntity, error)
 }
@@ -44,7 +57,9 @@ type stockService struct {
 	stockNewsRepository               repository.StocksNewsRepository
 	stockSignalRepository             repository.StockSignalRepository
 	stockPositionMonitoringRepository repository.StockPositionMonitoringRepository
-	redisClient                       *redis.Client
+	publisher                         events.Publisher
+	quotaService                      quota.QuotaService
+	analyzerInflight                  analyzerInflightStore
 }
 
 func NewStockService(
@@ -58,6 +73,8 @@ func NewStockService(
 	stockNewsRepository repository.StocksNewsRepository,
 	stockSignalRepository repository.StockSignalRepository,
 	stockPositionMonitoringRepository repository.StockPositionMonitoringRepository,
+	publisher events.Publisher,
+	quotaService quota.QuotaService,
 	redisClient *redis.Client,
 ) StockService {
 	return &stockService{
@@ -71,7 +88,9 @@ func NewStockService(
 		stockNewsRepository:               stockNewsRepository,
 		stockSignalRepository:             stockSignalRepository,
 		stockPositionMonitoringRepository: stockPositionMonitoringRepository,
-		redisClient:                       redisClient,
+		publisher:                         publisher,
+		quotaService:                      quotaService,
+		analyzerInflight:                  newRedisAnalyzerInflightStore(redisClient, logger),
 	}
 }
 
@@ -83,6 +102,19 @@ func (s *stockService) GetByParam(ctx context.Context, param models.GetStocksPar
 	return s.stocksRepository.GetStocks(ctx, param)
 }
 
+func (s *stockService) SearchStocks(ctx context.Context, query string, limit int) ([]models.StockEntity, error) {
+	query = strings.TrimPrefix(strings.TrimSpace(query), "$")
+	if query == "" {
+		return nil, nil
+	}
+
+	stocks, err := s.stocksRepository.SearchStocks(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search stocks: %w", err)
+	}
+	return stocks, nil
+}
+
 func (s *stockService) GetTopNews(ctx context.Context, param models.StockNewsQueryParam) ([]models.StockNewsEntity, error) {
 	return s.stockNewsRepository.GetTopNews(ctx, param)
 }
@@ -95,6 +127,19 @@ func (s *stockService) GetLastStockNewsSummary(ctx context.Context, age int, sto
 	return s.stockNewsSummaryRepository.GetLast(ctx, utils.TimeNowWIB().AddDate(0, 0, -age), stockCode)
 }
 
+func (s *stockService) GetLastStockNewsSummaries(ctx context.Context, age int, stockCodes []string) (map[string]models.StockNewsSummaryEntity, error) {
+	summaries, err := s.stockNewsSummaryRepository.GetLastByStockCodes(ctx, utils.TimeNowWIB().AddDate(0, 0, -age), stockCodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last stock news summaries: %w", err)
+	}
+
+	result := make(map[string]models.StockNewsSummaryEntity, len(summaries))
+	for _, summary := range summaries {
+		result[summary.StockCode] = summary
+	}
+	return result, nil
+}
+
 func (s *stockService) GetLatestStockSignal(ctx context.Context, param models.GetStockBuySignalParam) ([]models.StockSignalEntity, error) {
 	result, err := s.stockSignalRepository.GetLatestSignal(ctx, param)
 	if err != nil {
@@ -143,20 +188,19 @@ func (s *stockService) RequestStockPositionMonitoring(ctx context.Context, param
 		param.StockPositionID = positions[0].ID
 	}
 
-	streamDataJSON, err := json.Marshal(param)
-	if err != nil {
-		s.logger.Error("failed to parse json data request stock position monitoring", logrus.Fields{
-			"error": err,
-		})
-		return err
+	if param.OnDemand {
+		if err := s.quotaService.ConsumeAnalysis(ctx, param.TelegramID); err != nil {
+			return err
+		}
 	}
-	if err := s.redisClient.XAdd(ctx, &goRedis.XAddArgs{
-		Stream: models.RedisStreamStockPositionMonitor,
-		Values: map[string]interface{}{"payload": streamDataJSON},
-	}).Err(); err != nil {
+
+	if _, err := s.publisher.Publish(ctx, models.RedisStreamStockPositionMonitor, events.TypeStockPositionMonitorRequested, param); err != nil {
 		s.logger.Error("failed to send redis stream stock position monitoring", logrus.Fields{
 			"error": err,
 		})
+		if param.OnDemand {
+			s.quotaService.RefundAnalysis(ctx, param.TelegramID)
+		}
 		return err
 	}
 
@@ -164,21 +208,37 @@ func (s *stockService) RequestStockPositionMonitoring(ctx context.Context, param
 }
 
 func (s *stockService) RequestStockAnalyzer(ctx context.Context, param *models.RequestStockAnalyzer) error {
-
-	streamDataJSON, err := json.Marshal(param)
+	reused, err := s.reuseFreshStockSignal(ctx, param)
 	if err != nil {
-		s.logger.Error("failed to parse json data request stock analyzer", logrus.Fields{
-			"error": err,
-		})
 		return err
 	}
-	if err := s.redisClient.XAdd(ctx, &goRedis.XAddArgs{
-		Stream: models.RedisStreamStockAnalyzer,
-		Values: map[string]interface{}{"payload": streamDataJSON},
-	}).Err(); err != nil {
+	if reused {
+		return nil
+	}
+
+	// setiap request analisa memulai rantai event sendiri, worker mengembalikan correlation ID
+	// yang sama pada hasilnya sehingga penanda in-flight bisa dilepas tanpa bergantung payload
+	correlationID := uuid.NewString()
+	ctx = events.WithCorrelationID(ctx, correlationID)
+	if !s.acquireStockAnalyzer(ctx, param, correlationID) {
+		return nil
+	}
+
+	if param.OnDemand {
+		if err := s.quotaService.ConsumeAnalysis(ctx, param.TelegramID); err != nil {
+			s.releaseStockAnalyzer(ctx, param, correlationID)
+			return err
+		}
+	}
+
+	if _, err := s.publisher.Publish(ctx, models.RedisStreamStockAnalyzer, events.TypeStockAnalyzerRequested, param); err != nil {
 		s.logger.Error("failed to send redis stream stock analyzer", logrus.Fields{
 			"error": err,
 		})
+		s.releaseStockAnalyzer(ctx, param, correlationID)
+		if param.OnDemand {
+			s.quotaService.RefundAnalysis(ctx, param.TelegramID)
+		}
 		return err
 	}
 
@@ -273,6 +333,14 @@ func (s *stockService) UpdateStockPositionTelegramUser(ctx context.Context, tele
 		return fmt.Errorf("position not found")
 	}
 
+	// kuota alert hanya dicek saat alert baru diaktifkan
+	enableAlert := (update.PriceAlert != nil && *update.PriceAlert) || (update.MonitorPosition != nil && *update.MonitorPosition)
+	if enableAlert {
+		if err := s.quotaService.CheckAlerts(ctx, telegramID, stockPositionID); err != nil {
+			return err
+		}
+	}
+
 	newUpdate := positions[0]
 
 	if update.BuyPrice != nil {
@@ -347,6 +415,15 @@ func (s *stockService) SetStockPosition(ctx context.Context, request *models.Req
 		return fmt.Errorf("position already exists")
 	}
 
+	if err := s.quotaService.CheckActivePositions(ctx, request.UserTelegram.ID); err != nil {
+		return err
+	}
+	if request.AlertPrice || request.AlertMonitor {
+		if err := s.quotaService.CheckAlerts(ctx, request.UserTelegram.ID, 0); err != nil {
+			return err
+		}
+	}
+
 	err = s.unitOfWork.Run(func(opts ...utils.DBOption) error {
 		if user == nil {
 			user = request.UserTelegram.ToUserEntity()
@@ -0,0 +1,244 @@
+package symbols
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/repository"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// MaxSuggestions adalah jumlah saran kode saham yang ditampilkan saat kode tidak dikenal.
+	MaxSuggestions = 5
+	// daftar saham jarang berubah, cukup dimuat ulang berkala untuk kebutuhan saran
+	stockListTTL = time.Hour
+)
+
+var (
+	ErrInvalidSymbol = errors.New("invalid stock symbol")
+	ErrUnknownSymbol = errors.New("unknown stock symbol")
+)
+
+// UnknownSymbolError dikembalikan saat input tidak cocok dengan saham mana pun di tabel stocks,
+// berisi saran kode atau nama saham yang mirip.
+type UnknownSymbolError struct {
+	Input       string
+	Suggestions []models.StockEntity
+}
+
+func (e *UnknownSymbolError) Error() string {
+	return fmt.Sprintf("unknown stock symbol %q", e.Input)
+}
+
+func (e *UnknownSymbolError) Unwrap() error {
+	return ErrUnknownSymbol
+}
+
+type SymbolResolver interface {
+	// Resolve menormalisasi input user lalu memastikan sahamnya terdaftar. Jika tidak terdaftar,
+	// error berupa *UnknownSymbolError dengan saran saham yang mirip.
+	Resolve(ctx context.Context, input string) (*models.StockEntity, error)
+	// Suggest mengembalikan saham yang kode atau namanya paling mirip dengan query.
+	Suggest(ctx context.Context, query string, limit int) ([]models.StockEntity, error)
+}
+
+type symbolResolver struct {
+	logger           *logrus.Logger
+	stocksRepository repository.StocksRepository
+	now              func() time.Time
+
+	mu       sync.Mutex
+	stocks   []models.StockEntity
+	loadedAt time.Time
+}
+
+func NewSymbolResolver(logger *logrus.Logger, stocksRepository repository.StocksRepository) SymbolResolver {
+	return &symbolResolver{
+		logger:           logger,
+		stocksRepository: stocksRepository,
+		now:              utils.TimeNowWIB,
+	}
+}
+
+// Normalize mengubah input seperti " $bbca ", "bbca.jk" menjadi kode saham BEI "BBCA" dan
+// memastikan formatnya 4 huruf.
+func Normalize(input string) (string, error) {
+	code := cleanInput(input)
+	if len(code) != 4 {
+		return "", ErrInvalidSymbol
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", ErrInvalidSymbol
+		}
+	}
+	return code, nil
+}
+
+func cleanInput(input string) string {
+	value := strings.ToUpper(strings.TrimSpace(input))
+	value = strings.TrimPrefix(value, "$")
+	return strings.TrimSuffix(value, ".JK")
+}
+
+func (s *symbolResolver) Resolve(ctx context.Context, input string) (*models.StockEntity, error) {
+	query := cleanInput(input)
+	if query == "" {
+		return nil, ErrInvalidSymbol
+	}
+
+	if code, err := Normalize(query); err == nil {
+		stocks, err := s.stocksRepository.GetStocks(ctx, models.GetStocksParam{StockCodes: []string{code}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get stock %s: %w", code, err)
+		}
+		if len(stocks) > 0 {
+			return &stocks[0], nil
+		}
+	}
+
+	suggestions, err := s.Suggest(ctx, query, MaxSuggestions)
+	if err != nil {
+		return nil, err
+	}
+	return nil, &UnknownSymbolError{Input: query, Suggestions: suggestions}
+}
+
+func (s *symbolResolver) Suggest(ctx context.Context, query string, limit int) ([]models.StockEntity, error) {
+	stocks, err := s.stockList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return RankSuggestions(query, stocks, limit), nil
+}
+
+// stockList mengembalikan daftar saham dari cache dan memuat ulang dari database jika sudah kedaluwarsa.
+func (s *symbolResolver) stockList(ctx context.Context) ([]models.StockEntity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stocks != nil && s.now().Sub(s.loadedAt) < stockListTTL {
+		return s.stocks, nil
+	}
+
+	stocks, err := s.stocksRepository.GetStocks(ctx, models.GetStocksParam{})
+	if err != nil {
+		if s.stocks != nil {
+			// daftar lama masih cukup untuk saran, jangan gagalkan permintaan user
+			s.logger.Warn("failed to reload stock list, using cached list", logrus.Fields{
+				"error": err,
+			})
+			return s.stocks, nil
+		}
+		return nil, fmt.Errorf("failed to get stock list: %w", err)
+	}
+	s.stocks = stocks
+	s.loadedAt = s.now()
+	return stocks, nil
+}
+
+// RankSuggestions mengurutkan saham berdasarkan kemiripan dengan query: kode sama persis, awalan
+// kode, awalan kata pada nama, nama mengandung query, lalu kode dengan salah ketik kecil.
+func RankSuggestions(query string, stocks []models.StockEntity, limit int) []models.StockEntity {
+	query = cleanInput(query)
+	if query == "" || limit <= 0 {
+		return nil
+	}
+
+	type candidate struct {
+		stock models.StockEntity
+		score int
+	}
+
+	candidates := make([]candidate, 0, limit)
+	for _, stock := range stocks {
+		if score, ok := matchScore(query, stock); ok {
+			candidates = append(candidates, candidate{stock: stock, score: score})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score < candidates[j].score
+		}
+		return candidates[i].stock.Code < candidates[j].stock.Code
+	})
+
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	result := make([]models.StockEntity, 0, len(candidates))
+	for _, c := range candidates {
+		result = append(result, c.stock)
+	}
+	return result
+}
+
+// matchScore mengembalikan skor kemiripan, makin kecil makin mirip.
+func matchScore(query string, stock models.StockEntity) (int, bool) {
+	code := strings.ToUpper(stock.Code)
+	name := strings.ToUpper(stock.Name)
+
+	switch {
+	case code == query:
+		return 0, true
+	case strings.HasPrefix(code, query):
+		return 1, true
+	}
+
+	// pencarian nama butuh minimal 3 huruf agar tidak mencocokkan hampir semua emiten
+	if len(query) >= 3 && name != "" {
+		if strings.HasPrefix(name, query) || strings.Contains(name, " "+query) {
+			return 2, true
+		}
+		if strings.Contains(name, query) {
+			return 3, true
+		}
+	}
+
+	maxDistance := 1
+	if len(query) >= 4 {
+		maxDistance = 2
+	}
+	if distance := editDistance(query, code); distance <= maxDistance {
+		return 3 + distance, true
+	}
+	return 0, false
+}
+
+// editDistance menghitung jarak Damerau-Levenshtein (optimal string alignment) sehingga huruf
+// yang tertukar seperti "BBAC" untuk "BBCA" dihitung sebagai satu salah ketik.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := make([][]int, len(ra)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(rb)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(ra)][len(rb)]
+}
@@ -0,0 +1,174 @@
+package symbols
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/repository"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+var testStocks = []models.StockEntity{
+	{Code: "ANTM", Name: "Aneka Tambang Tbk"},
+	{Code: "BBCA", Name: "Bank Central Asia Tbk"},
+	{Code: "BBNI", Name: "Bank Negara Indonesia (Persero) Tbk"},
+	{Code: "BBRI", Name: "Bank Rakyat Indonesia (Persero) Tbk"},
+	{Code: "BMRI", Name: "Bank Mandiri (Persero) Tbk"},
+	{Code: "TLKM", Name: "Telkom Indonesia (Persero) Tbk"},
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "BBCA", want: "BBCA"},
+		{value: " bbri ", want: "BBRI"},
+		{value: "tlkm.jk", want: "TLKM"},
+		{value: "$antm", want: "ANTM"},
+		{value: "BBC", wantErr: true},
+		{value: "BBCA1", wantErr: true},
+		{value: "bank central", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := Normalize(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Normalize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankSuggestions(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []string
+	}{
+		{name: "code prefix", query: "bb", limit: 5, want: []string{"BBCA", "BBNI", "BBRI"}},
+		{name: "transposed letters ranked first", query: "BBAC", limit: 5, want: []string{"BBCA", "BBNI", "BBRI"}},
+		{name: "single typo", query: "TLKN", limit: 1, want: []string{"TLKM"}},
+		{name: "name word", query: "mandiri", limit: 5, want: []string{"BMRI"}},
+		{name: "name phrase", query: "bank rakyat", limit: 5, want: []string{"BBRI"}},
+		{name: "name before typo", query: "negara", limit: 5, want: []string{"BBNI"}},
+		{name: "limit applied", query: "indonesia", limit: 2, want: []string{"BBNI", "BBRI"}},
+		{name: "no match", query: "ZZZZ", limit: 5, want: []string{}},
+		{name: "empty query", query: " ", limit: 5, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RankSuggestions(tt.query, testStocks, tt.limit)
+			var codes []string
+			if got != nil {
+				codes = make([]string, 0, len(got))
+				for _, stock := range got {
+					codes = append(codes, stock.Code)
+				}
+			}
+			if !reflect.DeepEqual(codes, tt.want) {
+				t.Errorf("RankSuggestions() = %v, want %v", codes, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "BBCA", b: "BBCA", want: 0},
+		{a: "BBCS", b: "BBCA", want: 1},
+		{a: "BBAC", b: "BBCA", want: 1},
+		{a: "BCA", b: "BBCA", want: 1},
+		{a: "ANTM", b: "BBCA", want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			if got := editDistance(tt.a, tt.b); got != tt.want {
+				t.Errorf("editDistance() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeStocksRepository struct {
+	repository.StocksRepository
+	stocks []models.StockEntity
+}
+
+func (r *fakeStocksRepository) GetStocks(ctx context.Context, param models.GetStocksParam) ([]models.StockEntity, error) {
+	if len(param.StockCodes) == 0 {
+		return r.stocks, nil
+	}
+	var result []models.StockEntity
+	for _, stock := range r.stocks {
+		for _, code := range param.StockCodes {
+			if stock.Code == code {
+				result = append(result, stock)
+			}
+		}
+	}
+	return result, nil
+}
+
+func TestSymbolResolver_Resolve(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		want            string
+		wantErr         error
+		wantSuggestions []string
+	}{
+		{name: "known code", input: "bbca.jk", want: "BBCA"},
+		{name: "unknown code with typo", input: "BBCS", wantErr: ErrUnknownSymbol, wantSuggestions: []string{"BBCA", "BBNI", "BBRI"}},
+		{name: "company name", input: "telkom", wantErr: ErrUnknownSymbol, wantSuggestions: []string{"TLKM"}},
+		{name: "empty", input: "  ", wantErr: ErrInvalidSymbol},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &symbolResolver{
+				logger:           logrus.New(),
+				stocksRepository: &fakeStocksRepository{stocks: testStocks},
+				now:              utils.TimeNowWIB,
+			}
+			got, err := s.Resolve(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Resolve() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				if got == nil || got.Code != tt.want {
+					t.Errorf("Resolve() = %v, want %s", got, tt.want)
+				}
+				return
+			}
+
+			var unknown *UnknownSymbolError
+			if !errors.As(err, &unknown) {
+				if tt.wantSuggestions != nil {
+					t.Fatalf("Resolve() error = %v, want UnknownSymbolError", err)
+				}
+				return
+			}
+			codes := make([]string, 0, len(unknown.Suggestions))
+			for _, stock := range unknown.Suggestions {
+				codes = append(codes, stock.Code)
+			}
+			if !reflect.DeepEqual(codes, tt.wantSuggestions) {
+				t.Errorf("Resolve() suggestions = %v, want %v", codes, tt.wantSuggestions)
+			}
+		})
+	}
+}
@@ -3,7 +3,6 @@ package telegram_bot
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"golang-swing-trading-signal/internal/models"
 	"golang-swing-trading-signal/internal/utils"
 
@@ -11,21 +10,30 @@ import (
 )
 
 func (t *TelegramBotService) handleAnalyze(ctx context.Context, c telebot.Context) error {
-	userID := c.Sender().ID
+	session := sessionKeyOf(c)
 
 	// Start a new conversation for analysis
-	t.userStates[userID] = StateWaitingAnalyzeSymbol
-	t.userAnalysisPositionData[userID] = &models.RequestAnalysisPositionData{} // Reuse this to store the symbol
+	t.userStates[session] = StateWaitingAnalyzeSymbol
+	t.userAnalysisPositionData[session] = &models.RequestAnalysisPositionData{} // Reuse this to store the symbol
 
-	return c.Send("Silakan masukkan simbol saham yang ingin Anda analisis (contoh: BBCA, ANTM).")
+	return c.Send(t.translator(c).T("analyze.prompt_symbol"))
 }
 
 func (t *TelegramBotService) handleGeneralAnalysis(ctx context.Context, c telebot.Context) error {
-	symbol := c.Text()
+	symbol, ok := t.resolveSymbolInput(ctx, c, c.Text())
+	if !ok {
+		return nil
+	}
+	return t.startGeneralAnalysis(ctx, c, symbol)
+}
+
+func (t *TelegramBotService) startGeneralAnalysis(ctx context.Context, c telebot.Context, symbol string) error {
+	session := sessionKeyOf(c)
+	tr := t.translator(c)
 
 	stopChan := make(chan struct{})
 
-	t.ResetUserState(c.Sender().ID)
+	t.ResetUserState(session)
 
 	// Mulai loading animasi
 	msg := t.showLoadingFlowAnalysis(c, stopChan)
@@ -44,7 +52,7 @@ func (t *TelegramBotService) handleGeneralAnalysis(ctx context.Context, c telebo
 			t.logger.WithError(err).WithField("symbol", symbol).Error("Failed to get stock signal")
 
 			// Send error message
-			_, err = t.telegramRateLimiter.Send(newCtx, c, fmt.Sprintf("❌ Failed to get stock signal %s: %s", symbol, err.Error()))
+			_, err = t.telegramRateLimiter.Send(newCtx, c, tr.T("analyze.signal_failed", symbol, err.Error()))
 			if err != nil {
 				t.logger.WithError(err).Error("Failed to send error message")
 			}
@@ -54,13 +62,17 @@ func (t *TelegramBotService) handleGeneralAnalysis(ctx context.Context, c telebo
 		if len(stockSignal) == 0 {
 			defer close(stopChan)
 			t.logger.WithField("symbol", symbol).Warn("No stock signal found")
-			t.stockService.RequestStockAnalyzer(newCtx, &models.RequestStockAnalyzer{
+			if err := t.stockService.RequestStockAnalyzer(newCtx, &models.RequestStockAnalyzer{
 				TelegramID: c.Sender().ID,
 				StockCode:  symbol,
 				NotifyUser: true,
-			})
+				OnDemand:   true,
+			}); err != nil {
+				t.editRequestAnalysisError(newCtx, c, msg, symbol, err)
+				return
+			}
 
-			if _, err := t.telegramRateLimiter.Edit(newCtx, c, msg, fmt.Sprintf(messageAnalysisNotAvailable, symbol), &telebot.SendOptions{
+			if _, err := t.telegramRateLimiter.Edit(newCtx, c, msg, tr.T("analysis.not_available", symbol), &telebot.SendOptions{
 				ParseMode: telebot.ModeMarkdown,
 			}); err != nil {
 				t.logger.WithError(err).Error("Failed to edit message")
@@ -74,7 +86,7 @@ func (t *TelegramBotService) handleGeneralAnalysis(ctx context.Context, c telebo
 			close(stopChan)
 			t.logger.WithError(err).WithField("symbol", symbol).Error("Failed to unmarshal analysis")
 			// Send error message
-			_, err = t.telegramRateLimiter.Send(newCtx, c, fmt.Sprintf("❌ Gagal parse data %s", symbol))
+			_, err = t.telegramRateLimiter.Send(newCtx, c, tr.T("analyze.parse_failed", symbol))
 			if err != nil {
 				t.logger.WithError(err).Error("Failed to send error message")
 			}
@@ -82,7 +94,7 @@ func (t *TelegramBotService) handleGeneralAnalysis(ctx context.Context, c telebo
 		}
 
 		// Format analysis message
-		analysisMessage := t.FormatAnalysisMessage(&analysis)
+		analysisMessage := t.FormatAnalysisMessage(tr, &analysis)
 
 		// Stop animasi loading
 		close(stopChan)
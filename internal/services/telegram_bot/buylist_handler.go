@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"golang-swing-trading-signal/internal/models"
 	"golang-swing-trading-signal/internal/utils"
 	"strings"
@@ -15,19 +14,19 @@ import (
 )
 
 func (t *TelegramBotService) handleBuyList(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	session := sessionKeyOf(c)
 
 	stockSignals, err := t.stockService.GetLatestStockSignal(ctx, models.GetStockBuySignalParam{
 		After: utils.TimeNowWIB().Add(-t.tradingConfig.GetBuyListSignalBefore),
 	})
 
 	if err != nil {
-		return t.telegramRateLimiter.EditWithoutMsg(ctx, c, commonMessageInternalError, &telebot.ReplyMarkup{}, telebot.ModeMarkdown)
+		return t.telegramRateLimiter.EditWithoutMsg(ctx, c, tr.T("common.internal_error"), &telebot.ReplyMarkup{}, telebot.ModeMarkdown)
 	}
 
 	if len(stockSignals) == 0 {
-		msg := `❌ Tidak ditemukan sinyal BUY hari ini.
-
-Coba lagi nanti atau gunakan filter /analyze untuk menemukan peluang baru.`
+		msg := tr.T("buylist.empty_title") + "\n\n" + tr.T("buylist.empty_hint")
 		_, err := t.telegramRateLimiter.Send(ctx, c, msg, &telebot.ReplyMarkup{}, telebot.ModeMarkdown)
 		if err != nil {
 			return err
@@ -38,11 +37,11 @@ Coba lagi nanti atau gunakan filter /analyze untuk menemukan peluang baru.`
 	utils.SafeGo(func() {
 		var cancel context.CancelFunc
 		t.mu.Lock()
-		if prevCancel, exists := t.userCancelFuncs[c.Sender().ID]; exists {
+		if prevCancel, exists := t.userCancelFuncs[session]; exists {
 			prevCancel()
 		}
 		newCtx, cancel := context.WithTimeout(t.ctx, t.config.TimeoutBuyListDuration)
-		t.userCancelFuncs[c.Sender().ID] = cancel
+		t.userCancelFuncs[session] = cancel
 		t.mu.Unlock()
 
 		var wg sync.WaitGroup
@@ -50,24 +49,22 @@ Coba lagi nanti atau gunakan filter /analyze untuk menemukan peluang baru.`
 		defer func() {
 			wg.Wait()
 			t.mu.Lock()
-			delete(t.userCancelFuncs, c.Sender().ID)
+			delete(t.userCancelFuncs, session)
 			t.mu.Unlock()
 			cancel()
 		}()
 
-		msgRoot, err := t.telegramRateLimiter.Send(newCtx, c, `🧠 Sedang menganalisis saham terbaik untuk dibeli...`, telebot.ModeMarkdown)
+		msgRoot, err := t.telegramRateLimiter.Send(newCtx, c, tr.T("buylist.loading"), telebot.ModeMarkdown)
 		if err != nil {
 			t.logger.WithError(err).Error("Failed to send loading message")
-			t.telegramRateLimiter.EditWithoutMsg(newCtx, c, commonMessageInternalError, &telebot.ReplyMarkup{}, telebot.ModeMarkdown)
+			t.telegramRateLimiter.EditWithoutMsg(newCtx, c, tr.T("common.internal_error"), &telebot.ReplyMarkup{}, telebot.ModeMarkdown)
 			return
 		}
 
 		buyListResultMsg := &strings.Builder{}
 
 		msgHeader := &strings.Builder{}
-		msgHeader.WriteString(`
-📊 Analisis Saham Sedang Berlangsung...
-`)
+		msgHeader.WriteString(tr.T("buylist.in_progress"))
 
 		progressCh := make(chan Progress, len(stockSignals)+1)
 		t.showProgressBarWithChannel(newCtx, c, msgRoot, progressCh, len(stockSignals), &wg)
@@ -80,9 +77,9 @@ Coba lagi nanti atau gunakan filter /analyze untuk menemukan peluang baru.`
 			if stop, err := utils.ShouldStopCtx(newCtx, t.logger); stop {
 				switch {
 				case errors.Is(err, context.Canceled):
-					t.telegramRateLimiter.SendWithoutLimit(newCtx, c, "✅ Proses analisa berhasil dihentikan.")
+					t.telegramRateLimiter.SendWithoutLimit(newCtx, c, tr.T("buylist.cancelled"))
 				case errors.Is(err, context.DeadlineExceeded):
-					t.telegramRateLimiter.SendWithoutLimit(newCtx, c, "⏰ Proses analisa dihentikan karena timeout.")
+					t.telegramRateLimiter.SendWithoutLimit(newCtx, c, tr.T("buylist.timeout"))
 				}
 				return
 			}
@@ -97,14 +94,14 @@ Coba lagi nanti atau gunakan filter /analyze untuk menemukan peluang baru.`
 
 			if err != nil {
 				t.logger.WithError(err).WithField("symbol", stockSignal.StockCode).Error("Buy list - Gagal mengambil data")
-				buyListResultMsg.WriteString(fmt.Sprintf("\n• %s* - ❌ Gagal mengambil data", stockSignal.StockCode))
+				buyListResultMsg.WriteString(tr.T("buylist.fetch_failed", stockSignal.StockCode))
 				progressCh <- Progress{Index: idx + 1, StockCode: stockSignal.StockCode, Content: buyListResultMsg.String(), Header: msgHeader.String()}
 				continue
 			}
 
 			if len(stockSignals) == 0 {
 				t.logger.Warn("Buy list - Tidak ditemukan sinyal BUY", fields)
-				buyListResultMsg.WriteString(fmt.Sprintf("*\n• %s* - ❌ Saat ini data tidak tersedia", stockSignal.StockCode))
+				buyListResultMsg.WriteString(tr.T("buylist.not_available", stockSignal.StockCode))
 				progressCh <- Progress{Index: idx + 1, StockCode: stockSignal.StockCode, Content: buyListResultMsg.String(), Header: msgHeader.String()}
 				continue
 			}
@@ -116,16 +113,16 @@ Coba lagi nanti atau gunakan filter /analyze untuk menemukan peluang baru.`
 
 			buyCount++
 			if buyCount == 1 {
-				msgHeader.WriteString("\n📈 Ditemukan sinyal BUY:")
+				msgHeader.WriteString(tr.T("buylist.found"))
 			}
 			var analysis models.IndividualAnalysisResponseMultiTimeframe
 			if err := json.Unmarshal([]byte(stockSignal.Data), &analysis); err != nil {
 				t.logger.WithError(err).WithField("symbol", stockSignal.StockCode).Error("Failed to unmarshal analysis")
-				buyListResultMsg.WriteString(fmt.Sprintf("\n• %s* - ❌ Gagal parse data", stockSignal.StockCode))
+				buyListResultMsg.WriteString(tr.T("buylist.parse_failed", stockSignal.StockCode))
 				progressCh <- Progress{Index: idx + 1, StockCode: stockSignal.StockCode, Content: buyListResultMsg.String(), Header: msgHeader.String()}
 				continue
 			}
-			newBuyListMsg := t.formatMessageBuyList(buyCount, &analysis)
+			newBuyListMsg := t.formatMessageBuyList(tr, buyCount, &analysis)
 
 			buyListResultMsg.WriteString(newBuyListMsg.String())
 			progressCh <- Progress{Index: idx + 1, StockCode: stockSignal.StockCode, Content: buyListResultMsg.String(), Header: msgHeader.String()}
@@ -134,22 +131,14 @@ Coba lagi nanti atau gunakan filter /analyze untuk menemukan peluang baru.`
 
 		if buyCount > 0 {
 			msgHeader.Reset()
-			msgHeader.WriteString(fmt.Sprintf("📈 Berikut %d saham yang direkomendasikan untuk BUY:", buyCount))
-			msgFooter := `
-
-🧠 Rekomendasi berdasarkan analisis teknikal dan sentimen pasar
-
-`
+			msgHeader.WriteString(tr.Plural("buylist.result_title", buyCount))
+			msgFooter := tr.T("buylist.result_footer")
 			buyListResultMsg.WriteString(msgFooter)
 			progressCh <- Progress{Index: len(stockSignals), StockCode: stockSignals[len(stockSignals)-1].StockCode, Content: buyListResultMsg.String(), Header: msgHeader.String()}
 		} else {
 			msgHeader.Reset()
-			msgHeader.WriteString("❌ Tidak ditemukan sinyal BUY hari ini.")
-			msgFooter := `
-
-Coba lagi nanti atau gunakan filter /analyze untuk menemukan peluang baru.
-			
-			`
+			msgHeader.WriteString(tr.T("buylist.empty_title"))
+			msgFooter := "\n\n" + tr.T("buylist.empty_hint") + "\n"
 			buyListResultMsg.WriteString(msgFooter)
 			progressCh <- Progress{Index: len(stockSignals), StockCode: stockSignals[len(stockSignals)-1].StockCode, Content: buyListResultMsg.String(), Header: msgHeader.String()}
 		}
@@ -160,11 +149,11 @@ Coba lagi nanti atau gunakan filter /analyze untuk menemukan peluang baru.
 }
 
 func (t *TelegramBotService) handleBtnCancelBuyListAnalysis(ctx context.Context, c telebot.Context) error {
-	userID := c.Sender().ID
+	session := sessionKeyOf(c)
 
-	t.ResetUserState(userID)
+	t.ResetUserState(session)
 
 	return t.telegramRateLimiter.Respond(ctx, c, &telebot.CallbackResponse{
-		Text: "❌ Analisis dibatalkan.",
+		Text: t.translator(c).T("buylist.cancel_response"),
 	})
 }
@@ -0,0 +1,173 @@
+package telegram_bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang-swing-trading-signal/internal/i18n"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/digest"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+func (t *TelegramBotService) handleDigest(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	message, menu, err := t.digestMenu(ctx, tr, c.Sender().ID)
+	if err != nil {
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	_, err = t.telegramRateLimiter.Send(ctx, c, message, menu, telebot.ModeHTML)
+	return err
+}
+
+func (t *TelegramBotService) handleBtnDigestToggle(ctx context.Context, c telebot.Context) error {
+	userID := c.Sender().ID
+	digestType := c.Data()
+	tr := t.translator(c)
+
+	preferences, err := t.digestService.GetPreferences(ctx, userID)
+	if err != nil {
+		t.logger.Error("failed to get digest preferences", logrus.Fields{
+			"error": err,
+		})
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T("common.internal_error"), ShowAlert: true})
+	}
+
+	enabled := true
+	for _, preference := range preferences {
+		if preference.DigestType == digestType {
+			enabled = !preference.Enabled
+		}
+	}
+
+	if err := t.digestService.SetEnabled(ctx, userID, digestType, enabled); err != nil {
+		t.logger.Error("failed to update digest subscription", logrus.Fields{
+			"digest_type": digestType,
+			"error":       err,
+		})
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T("common.internal_error"), ShowAlert: true})
+	}
+
+	return t.editDigestMenu(ctx, c)
+}
+
+func (t *TelegramBotService) handleBtnDigestSetTime(ctx context.Context, c telebot.Context) error {
+	session := sessionKeyOf(c)
+	t.ResetUserState(session)
+
+	t.mu.Lock()
+	t.userStates[session] = StateWaitingDigestDeliveryTime
+	t.userDigestType[session] = c.Data()
+	t.mu.Unlock()
+
+	tr := t.translator(c)
+	_, err := t.telegramRateLimiter.Send(ctx, c, tr.T("digest.time_prompt", tr.T(digestLabel(c.Data()))), telebot.ModeHTML)
+	return err
+}
+
+func (t *TelegramBotService) handleDigestConversation(ctx context.Context, c telebot.Context) error {
+	userID := c.Sender().ID
+	session := sessionKeyOf(c)
+	tr := t.translator(c)
+
+	digestType, ok := t.userDigestType[session]
+	if !ok {
+		t.ResetUserState(session)
+		_, err := t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	if err := t.digestService.SetDeliveryTime(ctx, userID, digestType, strings.TrimSpace(c.Text())); err != nil {
+		if errors.Is(err, digest.ErrInvalidDeliveryTime) {
+			_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("digest.time_invalid"), telebot.ModeHTML)
+			return err
+		}
+		t.logger.Error("failed to update digest delivery time", logrus.Fields{
+			"digest_type": digestType,
+			"error":       err,
+		})
+		t.ResetUserState(session)
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	t.ResetUserState(session)
+	return t.handleDigest(ctx, c)
+}
+
+func (t *TelegramBotService) editDigestMenu(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	message, menu, err := t.digestMenu(ctx, tr, c.Sender().ID)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T("common.internal_error"), ShowAlert: true})
+	}
+
+	_, err = t.telegramRateLimiter.Edit(ctx, c, c.Message(), message, menu, telebot.ModeHTML)
+	return err
+}
+
+func (t *TelegramBotService) digestMenu(ctx context.Context, tr i18n.Translator, telegramID int64) (string, *telebot.ReplyMarkup, error) {
+	preferences, err := t.digestService.GetPreferences(ctx, telegramID)
+	if err != nil {
+		t.logger.Error("failed to get digest preferences", logrus.Fields{
+			"error": err,
+		})
+		return "", nil, err
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString(tr.T("digest.menu_title"))
+
+	menu := &telebot.ReplyMarkup{}
+	rows := make([]telebot.Row, 0, len(preferences)+1)
+	for _, preference := range preferences {
+		status, toggle := tr.T("common.disabled"), tr.T("common.enable")
+		if preference.Enabled {
+			status, toggle = tr.T("common.active"), tr.T("common.disable")
+		}
+		label := tr.T(digestLabel(preference.DigestType))
+		sb.WriteString(fmt.Sprintf("\n<b>%s</b>\n%s\n%s | ⏰ %s\n", label, tr.T(digestDescription(preference.DigestType)), status, preference.DeliveryTime))
+
+		rows = append(rows, menu.Row(
+			menu.Data(fmt.Sprintf("%s %s", toggle, label), btnDigestToggle.Unique, preference.DigestType),
+			menu.Data(fmt.Sprintf("⏰ %s", preference.DeliveryTime), btnDigestSetTime.Unique, preference.DigestType),
+		))
+	}
+	rows = append(rows, menu.Row(button(tr, btnDeleteMessage)))
+	menu.Inline(rows...)
+
+	return sb.String(), menu, nil
+}
+
+// digestLabel dan digestDescription mengembalikan key katalog i18n untuk tipe digest.
+func digestLabel(digestType string) string {
+	switch digestType {
+	case models.DigestMorningBuyList:
+		return "digest.label_morning_buy_list"
+	case models.DigestEndOfDay:
+		return "digest.label_end_of_day"
+	case models.DigestWeeklyRecap:
+		return "digest.label_weekly_recap"
+	default:
+		return digestType
+	}
+}
+
+func digestDescription(digestType string) string {
+	switch digestType {
+	case models.DigestMorningBuyList:
+		return "digest.description_morning_buy_list"
+	case models.DigestEndOfDay:
+		return "digest.description_end_of_day"
+	case models.DigestWeeklyRecap:
+		return "digest.description_weekly_recap"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,398 @@
+package telegram_bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+
+	"golang-swing-trading-signal/internal/i18n"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/groups"
+	"golang-swing-trading-signal/internal/services/symbols"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// groupCommands adalah perintah yang boleh dipakai di grup. Perintah lain bisa membuka
+// data pribadi user (posisi, kuota, preferensi) sehingga hanya dilayani di chat pribadi.
+var groupCommands = []string{"/start", "/help", "/group", "/watchlist", "/cancel"}
+
+// groupCallbacks adalah tombol inline yang boleh ditekan di grup.
+var groupCallbacks = []string{btnGroupToggleSignal.Unique, btnGroupToggleDigest.Unique, btnDeleteMessage.Unique}
+
+func isGroupChat(c telebot.Context) bool {
+	chat := c.Chat()
+	return chat != nil && (chat.Type == telebot.ChatGroup || chat.Type == telebot.ChatSuperGroup)
+}
+
+// GroupChatMiddleware membatasi bot di grup: pesan biasa diabaikan, perintah untuk bot lain
+// diabaikan, dan perintah pribadi dijawab dengan ajakan membuka chat pribadi.
+func (t *TelegramBotService) GroupChatMiddleware() telebot.MiddlewareFunc {
+	return func(next telebot.HandlerFunc) telebot.HandlerFunc {
+		return func(c telebot.Context) error {
+			if !isGroupChat(c) {
+				return next(c)
+			}
+
+			if callback := c.Callback(); callback != nil {
+				if slices.Contains(groupCallbacks, callback.Unique) {
+					return next(c)
+				}
+				return c.Respond(&telebot.CallbackResponse{Text: t.translator(c).T("group.private_only_short"), ShowAlert: true})
+			}
+
+			// update lain seperti bot ditambahkan atau dikeluarkan dari grup tetap diteruskan
+			message := c.Message()
+			if message == nil || message.Text == "" {
+				return next(c)
+			}
+			if !strings.HasPrefix(message.Text, "/") {
+				return nil
+			}
+
+			command, target, _ := strings.Cut(strings.Fields(message.Text)[0], "@")
+			if target != "" && t.bot.Me != nil && !strings.EqualFold(target, t.bot.Me.Username) {
+				return nil
+			}
+			if slices.Contains(groupCommands, strings.ToLower(command)) {
+				return next(c)
+			}
+
+			_, err := t.telegramRateLimiter.Send(t.ctx, c, t.groupPrivateOnlyMessage(t.translator(c)), telebot.ModeHTML)
+			return err
+		}
+	}
+}
+
+func (t *TelegramBotService) groupPrivateOnlyMessage(tr i18n.Translator) string {
+	username := ""
+	if t.bot.Me != nil {
+		username = t.bot.Me.Username
+	}
+	return tr.T("group.private_only", username)
+}
+
+// isGroupAdmin memastikan pengirim adalah admin grup Telegram sekaligus member bot.
+func (t *TelegramBotService) isGroupAdmin(ctx context.Context, c telebot.Context) (bool, error) {
+	member, err := t.bot.ChatMemberOf(c.Chat(), c.Sender())
+	if err != nil {
+		return false, fmt.Errorf("failed to get chat member: %w", err)
+	}
+	if member.Role != telebot.Creator && member.Role != telebot.Administrator {
+		return false, nil
+	}
+
+	access, err := t.userService.GetAccess(ctx, c.Sender().ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get user access: %w", err)
+	}
+	return !access.Banned && access.Role.Allows(models.RoleMember), nil
+}
+
+// requireGroupAdmin mengirim pesan penolakan dan mengembalikan false jika pengirim bukan admin grup.
+func (t *TelegramBotService) requireGroupAdmin(ctx context.Context, c telebot.Context) (bool, error) {
+	tr := t.translator(c)
+	ok, err := t.isGroupAdmin(ctx, c)
+	if err != nil {
+		t.logger.Error("failed to check group admin", logrus.Fields{
+			"chat_id": c.Chat().ID,
+			"user_id": c.Sender().ID,
+			"error":   err,
+		})
+		return false, t.replyGroup(ctx, c, tr.T("common.internal_error"))
+	}
+	if !ok {
+		return false, t.replyGroup(ctx, c, tr.T("group.admin_only"))
+	}
+	return true, nil
+}
+
+// replyGroup membalas di grup, atau menampilkan alert jika update berasal dari tombol.
+func (t *TelegramBotService) replyGroup(ctx context.Context, c telebot.Context, message string) error {
+	if c.Callback() != nil {
+		return c.Respond(&telebot.CallbackResponse{Text: message, ShowAlert: true})
+	}
+	_, err := t.telegramRateLimiter.Send(ctx, c, message, telebot.ModeHTML)
+	return err
+}
+
+func (t *TelegramBotService) handleAddedToGroup(ctx context.Context, c telebot.Context) error {
+	_, err := t.telegramRateLimiter.Send(ctx, c, t.translator(c).T("group.intro"), telebot.ModeHTML)
+	return err
+}
+
+// handleMyChatMember menonaktifkan grup saat bot dikeluarkan, watchlist tetap disimpan
+// supaya bisa dipakai lagi jika bot ditambahkan kembali.
+func (t *TelegramBotService) handleMyChatMember(ctx context.Context, c telebot.Context) error {
+	update := c.ChatMember()
+	if update == nil || update.NewChatMember == nil || !isGroupChat(c) {
+		return nil
+	}
+	if update.NewChatMember.Role != telebot.Left && update.NewChatMember.Role != telebot.Kicked {
+		return nil
+	}
+
+	if err := t.groupService.Deactivate(ctx, c.Chat().ID); err != nil {
+		t.logger.Error("failed to deactivate group", logrus.Fields{
+			"chat_id": c.Chat().ID,
+			"error":   err,
+		})
+		return err
+	}
+	t.logger.WithField("chat_id", c.Chat().ID).Info("Bot removed from group, group deactivated")
+	return nil
+}
+
+// handleGroup menampilkan pengaturan grup untuk admin grup. Pemanggilan pertama sekaligus
+// mengaktifkan grup dengan admin tersebut sebagai pemilik kuota watchlist.
+func (t *TelegramBotService) handleGroup(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	if !isGroupChat(c) {
+		_, err := t.telegramRateLimiter.Send(ctx, c, tr.T("group.group_only"))
+		return err
+	}
+	if ok, err := t.requireGroupAdmin(ctx, c); !ok {
+		return err
+	}
+
+	group, err := t.groupService.Register(ctx, c.Chat().ID, c.Chat().Title, c.Sender().ID, string(i18n.Resolve("", c.Sender().LanguageCode)))
+	if err != nil {
+		t.logger.Error("failed to register group", logrus.Fields{
+			"chat_id": c.Chat().ID,
+			"error":   err,
+		})
+		return t.replyGroup(ctx, c, tr.T("common.internal_error"))
+	}
+
+	message, menu, err := t.groupMenu(ctx, tr, group)
+	if err != nil {
+		return t.replyGroup(ctx, c, tr.T("common.internal_error"))
+	}
+	_, err = t.telegramRateLimiter.Send(ctx, c, message, menu, telebot.ModeHTML)
+	return err
+}
+
+func (t *TelegramBotService) handleBtnGroupToggleSignal(ctx context.Context, c telebot.Context) error {
+	return t.toggleGroupSetting(ctx, c, func(group *models.TelegramGroupEntity) error {
+		return t.groupService.SetSignalAlerts(ctx, group.ChatID, !group.SignalAlerts)
+	})
+}
+
+func (t *TelegramBotService) handleBtnGroupToggleDigest(ctx context.Context, c telebot.Context) error {
+	digestType := c.Data()
+	return t.toggleGroupSetting(ctx, c, func(group *models.TelegramGroupEntity) error {
+		if !slices.Contains(models.GroupDigestTypes, digestType) {
+			return fmt.Errorf("digest %s is not available for groups", digestType)
+		}
+		enabled, err := t.groupDigestEnabled(ctx, group.ChatID, digestType)
+		if err != nil {
+			return err
+		}
+		return t.digestService.SetEnabled(ctx, group.ChatID, digestType, !enabled)
+	})
+}
+
+func (t *TelegramBotService) toggleGroupSetting(ctx context.Context, c telebot.Context, apply func(group *models.TelegramGroupEntity) error) error {
+	tr := t.translator(c)
+	if ok, err := t.requireGroupAdmin(ctx, c); !ok {
+		return err
+	}
+
+	group, err := t.groupService.GetGroup(ctx, c.Chat().ID)
+	if err == nil {
+		err = apply(group)
+	}
+	if err == nil {
+		group, err = t.groupService.GetGroup(ctx, c.Chat().ID)
+	}
+	if err != nil {
+		t.logger.Error("failed to update group setting", logrus.Fields{
+			"chat_id": c.Chat().ID,
+			"error":   err,
+		})
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T("common.internal_error"), ShowAlert: true})
+	}
+
+	message, menu, err := t.groupMenu(ctx, tr, group)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T("common.internal_error"), ShowAlert: true})
+	}
+	_, err = t.telegramRateLimiter.Edit(ctx, c, c.Message(), message, menu, telebot.ModeHTML)
+	return err
+}
+
+func (t *TelegramBotService) groupDigestEnabled(ctx context.Context, chatID int64, digestType string) (bool, error) {
+	preferences, err := t.digestService.GetPreferences(ctx, chatID)
+	if err != nil {
+		return false, err
+	}
+	for _, preference := range preferences {
+		if preference.DigestType == digestType {
+			return preference.Enabled, nil
+		}
+	}
+	return false, nil
+}
+
+func (t *TelegramBotService) groupMenu(ctx context.Context, tr i18n.Translator, group *models.TelegramGroupEntity) (string, *telebot.ReplyMarkup, error) {
+	watchlist, err := t.groupService.GetWatchlist(ctx, group.ChatID)
+	if err != nil {
+		t.logger.Error("failed to get group watchlist", logrus.Fields{
+			"chat_id": group.ChatID,
+			"error":   err,
+		})
+		return "", nil, err
+	}
+	preferences, err := t.digestService.GetPreferences(ctx, group.ChatID)
+	if err != nil {
+		t.logger.Error("failed to get group digest preferences", logrus.Fields{
+			"chat_id": group.ChatID,
+			"error":   err,
+		})
+		return "", nil, err
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString(tr.T("group.menu_title", html.EscapeString(group.Title)))
+
+	menu := &telebot.ReplyMarkup{}
+	rows := make([]telebot.Row, 0, len(models.GroupDigestTypes)+2)
+
+	icon := statusIcon(group.SignalAlerts)
+	sb.WriteString(fmt.Sprintf("%s %s\n", icon, tr.T("group.label_signal_alerts")))
+	rows = append(rows, menu.Row(menu.Data(fmt.Sprintf("%s %s", icon, tr.T("group.label_signal_alerts")), btnGroupToggleSignal.Unique)))
+
+	for _, preference := range preferences {
+		if !slices.Contains(models.GroupDigestTypes, preference.DigestType) {
+			continue
+		}
+		icon := statusIcon(preference.Enabled)
+		label := tr.T(digestLabel(preference.DigestType))
+		sb.WriteString(fmt.Sprintf("%s %s (⏰ %s)\n", icon, label, preference.DeliveryTime))
+		rows = append(rows, menu.Row(menu.Data(fmt.Sprintf("%s %s", icon, label), btnGroupToggleDigest.Unique, preference.DigestType)))
+	}
+
+	sb.WriteString(formatGroupWatchlist(tr, watchlist))
+	sb.WriteString(tr.T("group.menu_footer"))
+
+	rows = append(rows, menu.Row(button(tr, btnDeleteMessage)))
+	menu.Inline(rows...)
+	return sb.String(), menu, nil
+}
+
+// handleWatchlist menampilkan watchlist grup. Format: /watchlist [add|remove KODE...],
+// menambah dan menghapus hanya untuk admin grup.
+func (t *TelegramBotService) handleWatchlist(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	if !isGroupChat(c) {
+		_, err := t.telegramRateLimiter.Send(ctx, c, tr.T("group.group_only"))
+		return err
+	}
+
+	group, err := t.groupService.GetGroup(ctx, c.Chat().ID)
+	if err != nil {
+		if errors.Is(err, groups.ErrGroupNotFound) {
+			return t.replyGroup(ctx, c, tr.T("group.not_registered"))
+		}
+		t.logger.Error("failed to get group", logrus.Fields{
+			"chat_id": c.Chat().ID,
+			"error":   err,
+		})
+		return t.replyGroup(ctx, c, tr.T("common.internal_error"))
+	}
+	if !group.IsActive {
+		return t.replyGroup(ctx, c, tr.T("group.not_registered"))
+	}
+
+	args := c.Args()
+	if len(args) == 0 {
+		watchlist, err := t.groupService.GetWatchlist(ctx, group.ChatID)
+		if err != nil {
+			t.logger.Error("failed to get group watchlist", logrus.Fields{
+				"chat_id": group.ChatID,
+				"error":   err,
+			})
+			return t.replyGroup(ctx, c, tr.T("common.internal_error"))
+		}
+		return t.replyGroup(ctx, c, tr.T("group.watchlist_title")+formatGroupWatchlist(tr, watchlist))
+	}
+
+	action := strings.ToLower(args[0])
+	if (action != "add" && action != "remove") || len(args) < 2 {
+		return t.replyGroup(ctx, c, tr.T("group.watchlist_usage"))
+	}
+	if ok, err := t.requireGroupAdmin(ctx, c); !ok {
+		return err
+	}
+
+	sb := &strings.Builder{}
+	for _, code := range args[1:] {
+		sb.WriteString(t.applyWatchlistChange(ctx, tr, group.ChatID, c.Sender().ID, action, code))
+		sb.WriteString("\n")
+	}
+	return t.replyGroup(ctx, c, sb.String())
+}
+
+// applyWatchlistChange mengembalikan satu baris hasil untuk setiap kode saham.
+func (t *TelegramBotService) applyWatchlistChange(ctx context.Context, tr i18n.Translator, chatID, userID int64, action, code string) string {
+	// service dan resolver menerima kode apa adanya, escape hanya untuk balasan HTML
+	code = strings.ToUpper(code)
+	escaped := html.EscapeString(code)
+
+	var err error
+	if action == "add" {
+		err = t.groupService.AddToWatchlist(ctx, chatID, userID, code)
+	} else {
+		err = t.groupService.RemoveFromWatchlist(ctx, chatID, code)
+	}
+
+	if message, ok := quotaExceededMessage(tr, err); ok {
+		return message
+	}
+	var unknown *symbols.UnknownSymbolError
+	switch {
+	case err == nil && action == "add":
+		return tr.T("group.watchlist_added", escaped)
+	case err == nil:
+		return tr.T("group.watchlist_removed", escaped)
+	case errors.Is(err, groups.ErrInvalidStockCode):
+		return tr.T("group.watchlist_invalid", escaped)
+	case errors.Is(err, groups.ErrAlreadyInWatchlist):
+		return tr.T("group.watchlist_exists", escaped)
+	case errors.Is(err, groups.ErrNotInWatchlist):
+		return tr.T("group.watchlist_missing", escaped)
+	case errors.As(err, &unknown) && len(unknown.Suggestions) > 0:
+		return tr.T("group.watchlist_unknown_suggestions", escaped, formatSymbolSuggestionList(unknown.Suggestions))
+	case errors.Is(err, symbols.ErrUnknownSymbol):
+		return tr.T("group.watchlist_unknown", escaped)
+	default:
+		t.logger.Error("failed to update group watchlist", logrus.Fields{
+			"chat_id":    chatID,
+			"stock_code": code,
+			"error":      err,
+		})
+		return tr.T("group.watchlist_failed", escaped)
+	}
+}
+
+func formatGroupWatchlist(tr i18n.Translator, watchlist []string) string {
+	if len(watchlist) == 0 {
+		return tr.T("group.watchlist_empty")
+	}
+	codes := make([]string, 0, len(watchlist))
+	for _, code := range watchlist {
+		codes = append(codes, "$"+code)
+	}
+	return tr.Plural("group.watchlist_summary", len(watchlist), strings.Join(codes, ", "))
+}
+
+func statusIcon(enabled bool) string {
+	if enabled {
+		return "✅"
+	}
+	return "❌"
+}
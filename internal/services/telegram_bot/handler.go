@@ -7,56 +7,96 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	"gopkg.in/telebot.v3"
 )
 
 func (t *TelegramBotService) registerHandlers() {
 	// Command handlers
 	t.bot.Handle("/start", t.WithContext(t.handleStart))
-	t.bot.Handle("/help", t.WithContext(t.handleHelp))
-	t.bot.Handle("/analyze", t.WithContext(t.handleAnalyze), t.IsOnConversationMiddleware())
-	t.bot.Handle("/buylist", t.WithContext(t.handleBuyList), t.IsOnConversationMiddleware())
-	t.bot.Handle("/setposition", t.WithContext(t.handleSetPosition), t.IsOnConversationMiddleware())
+	t.bot.Handle("/help", t.WithContext(t.handleHelp), t.RequireRole(models.RoleGuest))
+	t.bot.Handle("/analyze", t.WithContext(t.handleAnalyze), t.RequireRole(models.RoleMember), t.IsOnConversationMiddleware())
+	t.bot.Handle("/buylist", t.WithContext(t.handleBuyList), t.RequireRole(models.RoleMember), t.IsOnConversationMiddleware())
+	t.bot.Handle("/setposition", t.WithContext(t.handleSetPosition), t.RequireRole(models.RoleMember), t.IsOnConversationMiddleware())
 	t.bot.Handle("/cancel", t.handleCancel)
-	t.bot.Handle("/myposition", t.WithContext(t.handleMyPosition), t.IsOnConversationMiddleware())
-	t.bot.Handle("/news", t.WithContext(t.handleNews), t.IsOnConversationMiddleware())
-	t.bot.Handle("/report", t.WithContext(t.handleReport), t.IsOnConversationMiddleware())
-	t.bot.Handle("/scheduler", t.WithContext(t.handleScheduler))
+	t.bot.Handle("/myposition", t.WithContext(t.handleMyPosition), t.RequireRole(models.RoleMember), t.IsOnConversationMiddleware())
+	t.bot.Handle("/news", t.WithContext(t.handleNews), t.RequireRole(models.RoleMember), t.IsOnConversationMiddleware())
+	t.bot.Handle("/report", t.WithContext(t.handleReport), t.RequireRole(models.RoleMember), t.IsOnConversationMiddleware())
+	t.bot.Handle("/quota", t.WithContext(t.handleQuota), t.RequireRole(models.RoleMember))
+	t.bot.Handle("/digest", t.WithContext(t.handleDigest), t.RequireRole(models.RoleMember), t.IsOnConversationMiddleware())
+	t.bot.Handle("/settings", t.WithContext(t.handleSettings), t.RequireRole(models.RoleMember), t.IsOnConversationMiddleware())
+	t.bot.Handle("/language", t.WithContext(t.handleLanguage), t.RequireRole(models.RoleGuest))
+	t.bot.Handle("/scheduler", t.WithContext(t.handleScheduler), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle("/usage", t.WithContext(t.handleUsage), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle("/users", t.WithContext(t.handleUsers), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle("/invite", t.WithContext(t.handleInvite), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle("/promote", t.WithContext(t.handlePromote), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle("/demote", t.WithContext(t.handleDemote), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle("/ban", t.WithContext(t.handleBan), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle("/unban", t.WithContext(t.handleUnban), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle("/setplan", t.WithContext(t.handleSetPlan), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle("/group", t.WithContext(t.handleGroup), t.RequireRole(models.RoleGuest))
+	t.bot.Handle("/watchlist", t.WithContext(t.handleWatchlist), t.RequireRole(models.RoleGuest))
+
+	// Inline mode, "@bot KODE" di chat mana pun
+	t.bot.Handle(telebot.OnQuery, t.WithContext(t.handleInlineQuery))
+
+	// Group membership handlers
+	t.bot.Handle(telebot.OnAddedToGroup, t.WithContext(t.handleAddedToGroup))
+	t.bot.Handle(telebot.OnMyChatMember, t.WithContext(t.handleMyChatMember))
 
 	// Inline button handlers
 
 	// Set position handlers
-	t.bot.Handle(&btnSetPositionAlertPriceYes, t.WithContext(t.handleBtnSetPositionAlertPriceYes))
-	t.bot.Handle(&btnSetPositionAlertPriceNo, t.WithContext(t.handleBtnSetPositionAlertPriceNo))
-	t.bot.Handle(&btnSetPositionAlertMonitorYes, t.WithContext(t.handleSetPositionAlertMonitorYes))
-	t.bot.Handle(&btnSetPositionAlertMonitorNo, t.WithContext(t.handleSetPositionAlertMonitorNo))
-
-	t.bot.Handle(&btnStockPositionMonitoring, t.WithContext(t.handleBtnTimeframeStockPositionMonitoring))
-	t.bot.Handle(&btnManageStockPosition, t.WithContext(t.handleBtnManageStockPosition))
-	t.bot.Handle(&btnToDetailStockPosition, t.WithContext(t.handleBtnToDetailStockPosition))
-	t.bot.Handle(&btnBackStockPosition, t.WithContext(t.handleBtnBackStockPosition))
-	t.bot.Handle(&btnBackActionStockPosition, t.WithContext(t.handleBtnBackActionStockPosition))
-	t.bot.Handle(&btnBackDetailStockPosition, t.WithContext(t.handleBtnBackDetailStockPosition))
+	t.bot.Handle(&btnSetPositionAlertPriceYes, t.WithContext(t.handleBtnSetPositionAlertPriceYes), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnSetPositionAlertPriceNo, t.WithContext(t.handleBtnSetPositionAlertPriceNo), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnSetPositionAlertMonitorYes, t.WithContext(t.handleSetPositionAlertMonitorYes), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnSetPositionAlertMonitorNo, t.WithContext(t.handleSetPositionAlertMonitorNo), t.RequireRole(models.RoleMember))
+
+	t.bot.Handle(&btnStockPositionMonitoring, t.WithContext(t.handleBtnTimeframeStockPositionMonitoring), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnManageStockPosition, t.WithContext(t.handleBtnManageStockPosition), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnToDetailStockPosition, t.WithContext(t.handleBtnToDetailStockPosition), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnBackStockPosition, t.WithContext(t.handleBtnBackStockPosition), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnBackActionStockPosition, t.WithContext(t.handleBtnBackActionStockPosition), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnBackDetailStockPosition, t.WithContext(t.handleBtnBackDetailStockPosition), t.RequireRole(models.RoleMember))
 	t.bot.Handle(&btnDeleteMessage, t.WithContext(t.handleBtnDeleteMessage))
 
-	t.bot.Handle(&btnDeleteStockPosition, t.WithContext(t.handleBtnDeleteStockPosition))
-	t.bot.Handle(&btnUpdateAlertPrice, t.WithContext(t.handleBtnUpdateAlertPrice))
-	t.bot.Handle(&btnUpdateAlertMonitor, t.WithContext(t.handleBtnUpdateAlertMonitor))
-	t.bot.Handle(&btnExitStockPosition, t.WithContext(t.handleBtnExitStockPosition))
+	t.bot.Handle(&btnDeleteStockPosition, t.WithContext(t.handleBtnDeleteStockPosition), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnUpdateAlertPrice, t.WithContext(t.handleBtnUpdateAlertPrice), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnUpdateAlertMonitor, t.WithContext(t.handleBtnUpdateAlertMonitor), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnExitStockPosition, t.WithContext(t.handleBtnExitStockPosition), t.RequireRole(models.RoleMember))
 	t.bot.Handle(&btnCancelGeneral, t.WithContext(t.handleBtnCancel))
-	t.bot.Handle(&btnSaveExitPosition, t.WithContext(t.handleBtnSaveExitPosition))
-	t.bot.Handle(&btnCancelBuyListAnalysis, t.WithContext(t.handleBtnCancelBuyListAnalysis))
-	t.bot.Handle(&btnActionNewsFind, t.WithContext(t.handleBtnActionNewsFind))
-	t.bot.Handle(&btnNewsConfirmSendSummary, t.WithContext(t.handleBtnNewsConfirmSendSummary))
-	t.bot.Handle(&btnNewsStockPosition, t.WithContext(t.handleBtnNewsStockPosition))
-	t.bot.Handle(&btnActionTopNews, t.WithContext(t.handleBtnActionTopNews))
-	t.bot.Handle(&btnAdjustTargetPosition, t.WithContext(t.handleBtnAdjustTargetPosition))
-	t.bot.Handle(&btnAdjustTargetPositionConfirm, t.WithContext(t.handleBtnAdjustTargetPositionConfirm))
-	t.bot.Handle(&btnDetailJob, t.WithContext(t.handleBtnDetailJob))
-	t.bot.Handle(&btnActionBackToJobList, t.WithContext(t.handleBtnActionBackToJobList))
-	t.bot.Handle(&btnActionRunJob, t.WithContext(t.handleBtnActionRunJob))
+	t.bot.Handle(&btnSaveExitPosition, t.WithContext(t.handleBtnSaveExitPosition), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnCancelBuyListAnalysis, t.WithContext(t.handleBtnCancelBuyListAnalysis), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnActionNewsFind, t.WithContext(t.handleBtnActionNewsFind), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnNewsConfirmSendSummary, t.WithContext(t.handleBtnNewsConfirmSendSummary), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnNewsStockPosition, t.WithContext(t.handleBtnNewsStockPosition), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnActionTopNews, t.WithContext(t.handleBtnActionTopNews), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnAdjustTargetPosition, t.WithContext(t.handleBtnAdjustTargetPosition), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnAdjustTargetPositionConfirm, t.WithContext(t.handleBtnAdjustTargetPositionConfirm), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnDigestToggle, t.WithContext(t.handleBtnDigestToggle), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnDigestSetTime, t.WithContext(t.handleBtnDigestSetTime), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnSettingsToggle, t.WithContext(t.handleBtnSettingsToggle), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnSettingsQuietHours, t.WithContext(t.handleBtnSettingsQuietHours), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnSettingsMinConfidence, t.WithContext(t.handleBtnSettingsMinConfidence), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnSettingsVerbosity, t.WithContext(t.handleBtnSettingsVerbosity), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnLanguage, t.WithContext(t.handleBtnLanguage), t.RequireRole(models.RoleGuest))
+	t.bot.Handle(&btnGroupToggleSignal, t.WithContext(t.handleBtnGroupToggleSignal), t.RequireRole(models.RoleGuest))
+	t.bot.Handle(&btnGroupToggleDigest, t.WithContext(t.handleBtnGroupToggleDigest), t.RequireRole(models.RoleGuest))
+	t.bot.Handle(&btnSymbolSuggestion, t.WithContext(t.handleBtnSymbolSuggestion), t.RequireRole(models.RoleMember))
+	t.bot.Handle(&btnDetailJob, t.WithContext(t.handleBtnDetailJob), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle(&btnActionBackToJobList, t.WithContext(t.handleBtnActionBackToJobList), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle(&btnActionRunJob, t.WithContext(t.handleBtnActionRunJob), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle(&btnActionPauseJob, t.WithContext(t.handleBtnActionPauseJob), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle(&btnActionResumeJob, t.WithContext(t.handleBtnActionResumeJob), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle(&btnActionEditJobCron, t.WithContext(t.handleBtnActionEditJobCron), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle(&btnActionEditJobCronConfirm, t.WithContext(t.handleBtnActionEditJobCronConfirm), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle(&btnJobHistory, t.WithContext(t.handleBtnJobHistory), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle(&btnJobHistoryDetail, t.WithContext(t.handleBtnJobHistoryDetail), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle(&btnActionCancelExecution, t.WithContext(t.handleBtnActionCancelExecution), t.RequireRole(models.RoleAdmin))
+	t.bot.Handle(&btnJobPipeline, t.WithContext(t.handleBtnJobPipeline), t.RequireRole(models.RoleAdmin))
 	// Handle incoming text messages for conversations
-	t.bot.Handle(telebot.OnText, t.WithContext(t.handleConversation))
+	t.bot.Handle(telebot.OnText, t.WithContext(t.handleConversation), t.RequireRole(models.RoleMember))
 
 	// Handle webhook setup
 	t.router.POST("/telegram/webhook", func(c *gin.Context) {
@@ -72,65 +112,50 @@ func (t *TelegramBotService) registerHandlers() {
 }
 
 func (t *TelegramBotService) handleStart(ctx context.Context, c telebot.Context) error {
-	message := `👋 *Halo, selamat datang di Bot Swing Trading!* 🤖  
-Saya di sini untuk membantu kamu memantau saham dan mencari peluang terbaik dari pergerakan harga.
-
-🔧 Berikut beberapa perintah yang bisa kamu gunakan:
-
-📈 /analyze - Analisa saham pilihanmu berdasarkan strategi  
-📋 /buylist - Lihat daftar saham potensial untuk dibeli  
-📝 /setposition - Catat posisi saham yang sedang kamu pegang  
-📊 /myposition - Lihat semua posisi yang sedang dipantau  
-📰 /news - Lihat berita terkini, alert berita penting saham, ringkasan berita
-💰 /report Melihat ringkasan hasil trading kamu berdasarkan posisi yang sudah kamu entry dan exit.
-🔄 /scheduler	- Lihat status scheduler & jalankan job secara manual  
+	// di grup tidak ada pendaftaran user, cukup perkenalan mode grup
+	if isGroupChat(c) {
+		return t.handleAddedToGroup(ctx, c)
+	}
 
+	user, err := t.userService.Register(ctx, models.ToRequestUserTelegram(c.Sender()))
+	if err != nil {
+		t.logger.Error("failed to register user", logrus.Fields{
+			"user_id": c.Sender().ID,
+			"error":   err,
+		})
+		return c.Send(t.translator(c).T("common.internal_error"))
+	}
+	tr := t.translator(c)
+	if user.IsBanned() {
+		return c.Send(tr.T("access.banned"))
+	}
 
-💡 Info & Bantuan:
-🆘 /help - Lihat panduan penggunaan lengkap  
-🔁 /start - Tampilkan pesan ini lagi  
-❌ /cancel - Batalkan perintah yang sedang berjalan
+	role := user.Role
+	if code := strings.TrimSpace(c.Message().Payload); code != "" && code != inlineStartPayload {
+		if role, err = t.handleStartRedeemInvite(ctx, c, code, role); err != nil {
+			return err
+		}
+	}
 
-🚀 *Siap mulai?* Coba ketik /analyze untuk memulai analisa pertamamu!`
-	go t.analyzer.CreateTelegramUserIfNotExist(ctx, models.ToRequestUserTelegram(c.Sender()))
-	return c.Send(message, &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
+	if !role.Allows(models.RoleMember) {
+		return c.Send(tr.T("start.guest_welcome", c.Sender().ID), &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+	}
+	return c.Send(tr.T("start.message"), &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
 }
 
 func (t *TelegramBotService) handleHelp(ctx context.Context, c telebot.Context) error {
-	message := `❓ *Panduan Penggunaan Bot Swing Trading* ❓
-
-Bot ini membantu kamu memantau saham dan mencari peluang terbaik dengan analisa teknikal yang disesuaikan untuk swing trading.
-
-Berikut daftar perintah yang bisa kamu gunakan:
-
-🤖 *Perintah Utama:*
-/start - Menampilkan pesan sambutan  
-/help - Menampilkan panduan ini  
-/analyze - Mulai analisa interaktif untuk saham tertentu  
-/buylist - Lihat saham potensial yang sedang menarik untuk dibeli  
-/setposition - Catat saham yang kamu beli agar bisa dipantau otomatis  
-/myposition - Lihat semua posisi yang sedang kamu pantau  
-/news - Lihat berita terkini, alert berita penting saham, ringkasan berita
-/cancel - Batalkan perintah yang sedang berjalan
-/report - Melihat ringkasan hasil trading kamu berdasarkan posisi yang sudah kamu entry dan exit.
-/scheduler	- Lihat status scheduler & jalankan job secara manual  
-
-💡 *Tips Penggunaan:*
-1. Gunakan /analyze untuk analisa cepat atau mendalam (bisa juga langsung kirim kode saham, misalnya: 'BBCA')  
-2. Jalankan /buylist setiap pagi untuk melihat peluang baru  
-3. Setelah beli saham, gunakan /setposition agar bot bisa bantu awasi harga  
-4. Pantau semua posisi aktif kamu lewat /myposition
-
-
-📌 Gunakan sinyal ini sebagai referensi tambahan saja, ya.  
-Keputusan tetap di tangan kamu — jangan lupa *Do Your Own Research!* 🔍`
+	if isGroupChat(c) {
+		_, err := t.telegramRateLimiter.Send(ctx, c, t.translator(c).T("group.help"), telebot.ModeHTML)
+		return err
+	}
+	message := t.translator(c).T("help.message")
 	return c.Send(message, &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
 }
 
 func (t *TelegramBotService) handleConversation(ctx context.Context, c telebot.Context) error {
-	userID := c.Sender().ID
+	session := sessionKeyOf(c)
 
-	state, ok := t.userStates[userID]
+	state, ok := t.userStates[session]
 	if !ok || state == StateIdle {
 		// This should not be treated as a conversation.
 		// Let the generic text handler deal with it.
@@ -149,25 +174,31 @@ func (t *TelegramBotService) handleConversation(ctx context.Context, c telebot.C
 		return t.handleNewsFindConversation(ctx, c)
 	case state >= StateWaitingAdjustTargetPositionInputTargetPrice && state <= StateWaitingAdjustTargetPositionConfirm:
 		return t.handleAdjustTargetPositionConversation(ctx, c)
+	case state >= StateWaitingSchedulerCronExpression && state <= StateWaitingSchedulerCronConfirm:
+		return t.handleSchedulerCronConversation(ctx, c)
+	case state == StateWaitingDigestDeliveryTime:
+		return t.handleDigestConversation(ctx, c)
+	case state == StateWaitingSettingsQuietHours:
+		return t.handleSettingsQuietHoursConversation(ctx, c)
 	default:
 		// If no specific conversation is matched, maybe it's a dangling state.
-		t.ResetUserState(userID)
-		return c.Send("Sepertinya Anda tidak sedang dalam percakapan aktif. Gunakan /help untuk melihat perintah yang tersedia.")
+		t.ResetUserState(session)
+		return c.Send(t.translator(c).T("common.no_active_conversation"))
 	}
 }
 
 func (t *TelegramBotService) handleTextMessage(ctx context.Context, c telebot.Context) error {
-	userID := c.Sender().ID
+	session := sessionKeyOf(c)
 
 	// If user is in a conversation, handle it
-	if state, ok := t.userStates[userID]; ok && state != StateIdle {
+	if state, ok := t.userStates[session]; ok && state != StateIdle {
 		t.handleConversation(ctx, c)
 		return nil
 	}
 
 	// Cek apakah bukan command
 	if !strings.HasPrefix(c.Text(), "/") {
-		return c.Send("Saya tidak mengenali perintahmu. Gunakan /help untuk melihat daftar perintah.")
+		return c.Send(t.translator(c).T("common.unknown_command"))
 	}
 
 	return nil
@@ -0,0 +1,207 @@
+package telegram_bot
+
+import (
+	"context"
+	"encoding/json"
+	"html"
+	"strings"
+
+	"golang-swing-trading-signal/internal/i18n"
+	"golang-swing-trading-signal/internal/models"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+const (
+	maxInlineResults = 10
+	// hasil inline di-cache Telegram per user karena bahasa kartu mengikuti bahasa user
+	inlineCacheSeconds = 60
+	// inlineStartPayload dikirim ke /start saat user yang belum terdaftar menekan tombol
+	// buka bot dari hasil inline, bukan kode undangan.
+	inlineStartPayload = "inline"
+)
+
+// inlineStockCard berisi data satu saham untuk hasil inline query.
+type inlineStockCard struct {
+	stock     models.StockEntity
+	price     *models.MarketPrice
+	analysis  *models.IndividualAnalysisResponseMultiTimeframe
+	sentiment string
+}
+
+// handleInlineQuery menjawab "@bot KODE" di chat mana pun dengan kartu harga terakhir, sinyal
+// terakhir dan sentimen berita untuk saham yang cocok dengan awalan kode atau nama.
+func (t *TelegramBotService) handleInlineQuery(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+
+	access, err := t.userService.GetAccess(ctx, c.Sender().ID)
+	if err != nil {
+		t.logger.Error("failed to get user access for inline query", logrus.Fields{
+			"user_id": c.Sender().ID,
+			"error":   err,
+		})
+		return c.Answer(&telebot.QueryResponse{Results: telebot.Results{}, IsPersonal: true})
+	}
+	if access.Banned || !access.Role.Allows(models.RoleMember) {
+		return c.Answer(&telebot.QueryResponse{
+			Results:           telebot.Results{},
+			IsPersonal:        true,
+			SwitchPMText:      tr.T("inline.member_only"),
+			SwitchPMParameter: inlineStartPayload,
+		})
+	}
+
+	cards, err := t.lookupInlineStocks(ctx, c.Query().Text)
+	if err != nil {
+		t.logger.Error("failed to lookup stocks for inline query", logrus.Fields{
+			"query": c.Query().Text,
+			"error": err,
+		})
+		return c.Answer(&telebot.QueryResponse{Results: telebot.Results{}, IsPersonal: true})
+	}
+
+	results := make(telebot.Results, 0, len(cards))
+	for _, card := range cards {
+		result := &telebot.ArticleResult{
+			Title:       formatInlineTitle(card),
+			Description: formatInlineDescription(tr, card),
+		}
+		result.SetResultID(card.stock.Code)
+		result.SetContent(&telebot.InputTextMessageContent{
+			Text:           formatInlineCard(tr, card),
+			ParseMode:      telebot.ModeHTML,
+			DisablePreview: true,
+		})
+		results = append(results, result)
+	}
+
+	return c.Answer(&telebot.QueryResponse{
+		Results:    results,
+		CacheTime:  inlineCacheSeconds,
+		IsPersonal: true,
+	})
+}
+
+func (t *TelegramBotService) lookupInlineStocks(ctx context.Context, query string) ([]inlineStockCard, error) {
+	stocks, err := t.stockService.SearchStocks(ctx, query, maxInlineResults)
+	if err != nil {
+		return nil, err
+	}
+	if len(stocks) == 0 {
+		return nil, nil
+	}
+
+	codes := make([]string, 0, len(stocks))
+	for _, stock := range stocks {
+		codes = append(codes, stock.Code)
+	}
+
+	// harga dan sinyal bersifat pelengkap, kartu tetap ditampilkan jika salah satunya gagal.
+	// Inline query harus dijawab cepat, jadi harga hanya dari redis tanpa fallback Yahoo.
+	prices, err := t.priceService.GetCachedPrices(ctx, codes)
+	if err != nil {
+		t.logger.Warn("failed to get last prices for inline query", logrus.Fields{
+			"error": err,
+		})
+	}
+	signals, err := t.stockService.GetLatestStockSignal(ctx, models.GetStockBuySignalParam{StockCodes: codes})
+	if err != nil {
+		t.logger.Warn("failed to get latest signals for inline query", logrus.Fields{
+			"error": err,
+		})
+	}
+	analyses := make(map[string]*models.IndividualAnalysisResponseMultiTimeframe, len(signals))
+	for _, signal := range signals {
+		var analysis models.IndividualAnalysisResponseMultiTimeframe
+		if err := json.Unmarshal([]byte(signal.Data), &analysis); err != nil {
+			t.logger.Warn("failed to unmarshal signal for inline query", logrus.Fields{
+				"stock_code": signal.StockCode,
+				"error":      err,
+			})
+			continue
+		}
+		analyses[signal.StockCode] = &analysis
+	}
+	// ringkasan berita terbaru lebih baru dari ringkasan di dalam sinyal
+	summaries, err := t.stockService.GetLastStockNewsSummaries(ctx, t.config.FeatureNewsMaxAgeInDays, codes)
+	if err != nil {
+		t.logger.Warn("failed to get news summaries for inline query", logrus.Fields{
+			"error": err,
+		})
+	}
+
+	cards := make([]inlineStockCard, 0, len(stocks))
+	for _, stock := range stocks {
+		card := inlineStockCard{stock: stock, analysis: analyses[stock.Code]}
+		if price, ok := prices[stock.Code]; ok {
+			card.price = &price
+		}
+		if card.analysis != nil {
+			card.sentiment = card.analysis.NewsSummary.Sentiment
+		}
+		if summary, ok := summaries[stock.Code]; ok && summary.SummarySentiment != "" {
+			card.sentiment = summary.SummarySentiment
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+func formatInlineTitle(card inlineStockCard) string {
+	if card.stock.Name == "" {
+		return card.stock.Code
+	}
+	return card.stock.Code + " · " + card.stock.Name
+}
+
+func formatInlineDescription(tr i18n.Translator, card inlineStockCard) string {
+	parts := make([]string, 0, 3)
+	if card.price != nil {
+		parts = append(parts, tr.T("inline.description_price", tr.Int(card.price.Price)))
+	}
+	if card.analysis != nil {
+		parts = append(parts, tr.T("inline.description_signal", card.analysis.Action, card.analysis.ConfidenceLevel))
+	} else {
+		parts = append(parts, tr.T("inline.description_no_signal"))
+	}
+	if card.sentiment != "" {
+		parts = append(parts, tr.T("inline.description_sentiment", card.sentiment))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func formatInlineCard(tr i18n.Translator, card inlineStockCard) string {
+	var sb strings.Builder
+	sb.WriteString(tr.T("inline.card_title", card.stock.Code, html.EscapeString(card.stock.Name)))
+
+	if card.price != nil {
+		sb.WriteString(tr.T("inline.card_price", tr.Int(card.price.Price), formatPriceFreshness(tr, card.price.Freshness, card.price.Source)))
+	} else {
+		sb.WriteString(tr.T("inline.card_price_unavailable"))
+	}
+
+	if analysis := card.analysis; analysis != nil {
+		signalIcon := "🟡"
+		if analysis.Action == "BUY" {
+			signalIcon = "🟢"
+		}
+		sb.WriteString(tr.T("inline.card_signal", signalIcon, analysis.Action, analysis.ConfidenceLevel))
+		if analysis.Action == "BUY" && analysis.BuyPrice > 0 {
+			gain := (analysis.TargetPrice - analysis.BuyPrice) / analysis.BuyPrice * 100
+			loss := (analysis.CutLoss - analysis.BuyPrice) / analysis.BuyPrice * 100
+			sb.WriteString(tr.T("inline.card_trade_plan", tr.Int(analysis.BuyPrice), tr.Int(analysis.TargetPrice), tr.PercentChange(gain), tr.Int(analysis.CutLoss), tr.PercentChange(loss)))
+		}
+	} else {
+		sb.WriteString(tr.T("inline.card_no_signal"))
+	}
+
+	if card.sentiment != "" {
+		sb.WriteString(tr.T("inline.card_sentiment", card.sentiment))
+	}
+	if card.analysis != nil {
+		sb.WriteString(tr.T("inline.card_analyzed", tr.DateTime(card.analysis.AnalysisDate)))
+	}
+	sb.WriteString(tr.T("inline.card_footer"))
+	return sb.String()
+}
@@ -0,0 +1,79 @@
+package telegram_bot
+
+import (
+	"context"
+	"errors"
+
+	"golang-swing-trading-signal/internal/i18n"
+	"golang-swing-trading-signal/internal/services/users"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// languageFollowTelegram adalah data tombol untuk kembali mengikuti bahasa aplikasi Telegram.
+const languageFollowTelegram = "auto"
+
+func (t *TelegramBotService) handleLanguage(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	_, err := t.telegramRateLimiter.Send(ctx, c, languageMessage(tr), languageMenu(tr), telebot.ModeHTML)
+	return err
+}
+
+func (t *TelegramBotService) handleBtnLanguage(ctx context.Context, c telebot.Context) error {
+	language := c.Data()
+	if language == languageFollowTelegram {
+		language = ""
+	}
+
+	if err := t.userService.SetLanguage(ctx, c.Sender().ID, language); err != nil {
+		if !errors.Is(err, users.ErrInvalidLanguage) {
+			t.logger.Error("failed to update user language", logrus.Fields{
+				"language": language,
+				"error":    err,
+			})
+		}
+		return c.Respond(&telebot.CallbackResponse{Text: t.translator(c).T("common.internal_error"), ShowAlert: true})
+	}
+
+	// translator lama masih tersimpan di context, resolve ulang dengan bahasa yang baru
+	access, err := t.userService.GetAccess(ctx, c.Sender().ID)
+	if err != nil {
+		return c.Respond(&telebot.CallbackResponse{Text: t.translator(c).T("common.internal_error"), ShowAlert: true})
+	}
+	tr := translatorForAccess(access, c.Sender().LanguageCode)
+	c.Set(contextKeyTranslator, tr)
+
+	if err := c.Respond(&telebot.CallbackResponse{Text: tr.T("language.updated")}); err != nil {
+		t.logger.Warn("failed to respond language callback", logrus.Fields{
+			"error": err,
+		})
+	}
+	_, err = t.telegramRateLimiter.Edit(ctx, c, c.Message(), languageMessage(tr), languageMenu(tr), telebot.ModeHTML)
+	return err
+}
+
+func languageMessage(tr i18n.Translator) string {
+	return tr.T("language.menu", tr.T(languageLabel(tr.Locale())))
+}
+
+func languageMenu(tr i18n.Translator) *telebot.ReplyMarkup {
+	menu := &telebot.ReplyMarkup{}
+	menu.Inline(
+		menu.Row(
+			menu.Data(tr.T(languageLabel(i18n.LocaleID)), btnLanguage.Unique, string(i18n.LocaleID)),
+			menu.Data(tr.T(languageLabel(i18n.LocaleEN)), btnLanguage.Unique, string(i18n.LocaleEN)),
+		),
+		menu.Row(menu.Data(tr.T("language.follow_telegram"), btnLanguage.Unique, languageFollowTelegram)),
+		menu.Row(button(tr, btnDeleteMessage)),
+	)
+	return menu
+}
+
+// languageLabel mengembalikan key katalog i18n untuk nama bahasa.
+func languageLabel(locale i18n.Locale) string {
+	if locale == i18n.LocaleEN {
+		return "language.name_en"
+	}
+	return "language.name_id"
+}
@@ -4,9 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"golang-swing-trading-signal/internal/i18n"
 	"golang-swing-trading-signal/internal/models"
 	"golang-swing-trading-signal/internal/utils"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -14,13 +14,14 @@ import (
 	"gopkg.in/telebot.v3"
 )
 
-func (t *TelegramBotService) FormatPositionMonitoringMessage(position *models.PositionMonitoringResponseMultiTimeframe) string {
+func (t *TelegramBotService) FormatPositionMonitoringMessage(tr i18n.Translator, position *models.PositionMonitoringResponseMultiTimeframe) string {
 	var sb strings.Builder
 
 	unrealizedPnLPercentage := ((position.MarketPrice - position.BuyPrice) / position.BuyPrice) * 100
 
-	daysRemaining := utils.RemainingDays(position.MaxHoldingPeriodDays, position.BuyDate)
-	ageDays := int(time.Since(position.BuyDate).Hours() / 24)
+	now := t.marketCalendar.Now()
+	daysRemaining := t.marketCalendar.RemainingHoldingDays(position.MaxHoldingPeriodDays, position.BuyDate, now)
+	ageDays := t.marketCalendar.HoldingDays(position.BuyDate, now)
 
 	iconAction := "❔"
 	if position.Action == "HOLD" {
@@ -33,57 +34,78 @@ func (t *TelegramBotService) FormatPositionMonitoringMessage(position *models.Po
 		iconAction = "🟠"
 	}
 
-	sb.WriteString(fmt.Sprintf("\n📊 <b>Position Update: %s</b>\n", position.Symbol))
-	sb.WriteString(fmt.Sprintf("💰 Buy: $%d\n", int(position.BuyPrice)))
-	sb.WriteString(fmt.Sprintf("📌 Last Price: $%d %s\n", int(position.MarketPrice), utils.FormatPercentage(unrealizedPnLPercentage)))
-	sb.WriteString(fmt.Sprintf("🎯 TP: $%d | SL: $%d | RR: %.2f\n", int(position.TargetPrice), int(position.CutLoss), position.RiskRewardRatio))
-	sb.WriteString(fmt.Sprintf("📈 Age: %d days | Remaining: %d days\n\n", ageDays, daysRemaining))
+	sb.WriteString(tr.T("monitoring.title", position.Symbol))
+	sb.WriteString(tr.T("monitoring.buy", tr.Int(position.BuyPrice)))
+	sb.WriteString(tr.T("monitoring.last_price", tr.Int(position.MarketPrice), tr.PercentChange(unrealizedPnLPercentage)))
+	sb.WriteString(tr.T("monitoring.plan", tr.Int(position.TargetPrice), tr.Int(position.CutLoss), tr.Number(position.RiskRewardRatio, 2)))
+	sb.WriteString(tr.Plural("monitoring.age", ageDays))
+	sb.WriteString(tr.Plural("monitoring.remaining", daysRemaining))
 
 	// Recommendation
 	gain := float64(position.ExitTargetPrice-position.BuyPrice) / float64(position.BuyPrice) * 100
 	loss := float64(position.ExitCutLossPrice-position.BuyPrice) / float64(position.BuyPrice) * 100
-	sb.WriteString("💡 <b>Recommendation:</b>\n")
-	sb.WriteString(fmt.Sprintf(" • Action: %s %s\n", iconAction, position.Action))
-	sb.WriteString(fmt.Sprintf(" • Target Price: $%d %s\n", int(position.ExitTargetPrice), utils.FormatPercentage(gain)))
-	sb.WriteString(fmt.Sprintf(" • Stop Loss: $%d %s\n", int(position.ExitCutLossPrice), utils.FormatPercentage(loss)))
-	sb.WriteString(fmt.Sprintf(" • Risk/Reward Ratio: %.2f\n", position.ExitRiskRewardRatio))
-	sb.WriteString(fmt.Sprintf(" • Confidence: %d%%\n", position.ConfidenceLevel))
-	sb.WriteString(fmt.Sprintf(" • Technical Score: %d\n\n", position.TechnicalScore))
+	sb.WriteString(tr.T("monitoring.recommendation"))
+	sb.WriteString(tr.T("monitoring.action", iconAction, position.Action))
+	sb.WriteString(tr.T("monitoring.target_price", tr.Int(position.ExitTargetPrice), tr.PercentChange(gain)))
+	sb.WriteString(tr.T("monitoring.stop_loss", tr.Int(position.ExitCutLossPrice), tr.PercentChange(loss)))
+	sb.WriteString(tr.T("monitoring.risk_reward", tr.Number(position.ExitRiskRewardRatio, 2)))
+	sb.WriteString(tr.T("monitoring.confidence", position.ConfidenceLevel))
+	sb.WriteString(tr.T("monitoring.technical_score", position.TechnicalScore))
 	// Reasoning
-	sb.WriteString(fmt.Sprintf("🧠 <b>Reasoning:</b>\n %s\n\n", position.Reasoning))
-
-	// Technical Analysis
-	sb.WriteString("🔍 <b>Analisa Multi-Timeframe</b>")
-	sb.WriteString(fmt.Sprintf("\n<b>Daily (1D)</b>: %s | RSI: %d\n", position.TimeframeAnalysis.Timeframe4H.Trend, position.TimeframeAnalysis.Timeframe1D.RSI))
-	sb.WriteString(fmt.Sprintf("> Sinyal Kunci: %s\n", position.TimeframeAnalysis.Timeframe1D.KeySignal))
-	sb.WriteString(fmt.Sprintf("> Support/Resistance: %d/%d\n", int(position.TimeframeAnalysis.Timeframe1D.Support), int(position.TimeframeAnalysis.Timeframe1D.Resistance)))
-
-	sb.WriteString(fmt.Sprintf("\n<b>4 Hours (4H)</b>: %s | RSI: %d\n", position.TimeframeAnalysis.Timeframe4H.Trend, position.TimeframeAnalysis.Timeframe4H.RSI))
-	sb.WriteString(fmt.Sprintf("> Sinyal Kunci: %s\n", position.TimeframeAnalysis.Timeframe4H.KeySignal))
-	sb.WriteString(fmt.Sprintf("> Support/Resistance: %d/%d\n", int(position.TimeframeAnalysis.Timeframe4H.Support), int(position.TimeframeAnalysis.Timeframe4H.Resistance)))
-
-	sb.WriteString(fmt.Sprintf("\n<b>1 Hour (1H)</b>: %s | RSI: %d\n", position.TimeframeAnalysis.Timeframe1H.Trend, position.TimeframeAnalysis.Timeframe1H.RSI))
-	sb.WriteString(fmt.Sprintf("> Sinyal Kunci: %s\n", position.TimeframeAnalysis.Timeframe1H.KeySignal))
-	sb.WriteString(fmt.Sprintf("> Support/Resistance: %d/%d\n", int(position.TimeframeAnalysis.Timeframe1H.Support), int(position.TimeframeAnalysis.Timeframe1H.Resistance)))
-
-	// News Summary
-	sb.WriteString("\n📰 <b>News Analysis:</b>\n")
-	if position.NewsSummary.ConfidenceScore > 0 {
-		sb.WriteString(fmt.Sprintf("Confidence Score: %.2f\n", position.NewsSummary.ConfidenceScore))
-		sb.WriteString(fmt.Sprintf("Sentiment: %s\n", position.NewsSummary.Sentiment))
-		sb.WriteString(fmt.Sprintf("Impact: %s\n\n", position.NewsSummary.Impact))
-		sb.WriteString(fmt.Sprintf("🧠 News Insight: \n%s\n\n", position.NewsSummary.Reasoning))
-	} else {
-		sb.WriteString("<i>Belum ada data berita terbaru yang tersedia untuk saham ini.</i>\n\n")
-	}
+	sb.WriteString(tr.T("analysis.reasoning", position.Reasoning))
+
+	writeTimeframeAnalysis(&sb, tr, position.TimeframeAnalysis)
+	writeNewsSummary(&sb, tr, position.NewsSummary)
 
 	sb.WriteString("\n")
-	sb.WriteString(fmt.Sprintf("📅 <i>Terakhir dianalisis: %s</i>\n", position.AnalysisDate.Format("2006-01-02 15:04:05")))
+	sb.WriteString(tr.T("analysis.last_analyzed", tr.DateTime(position.AnalysisDate)))
 
 	return sb.String()
 }
 
-func (t *TelegramBotService) FormatAnalysisMessage(analysis *models.IndividualAnalysisResponseMultiTimeframe) string {
+// FormatPositionMonitoringCompactMessage adalah versi ringkas update posisi untuk user yang
+// memilih pesan ringkas di /settings.
+func (t *TelegramBotService) FormatPositionMonitoringCompactMessage(tr i18n.Translator, position *models.PositionMonitoringResponseMultiTimeframe) string {
+	var sb strings.Builder
+
+	unrealizedPnLPercentage := ((position.MarketPrice - position.BuyPrice) / position.BuyPrice) * 100
+
+	sb.WriteString(tr.T("monitoring.compact_title", position.Symbol, position.Action, position.ConfidenceLevel))
+	sb.WriteString(tr.T("monitoring.last_price", tr.Int(position.MarketPrice), tr.PercentChange(unrealizedPnLPercentage)))
+	sb.WriteString(tr.T("monitoring.compact_plan", tr.Int(position.ExitTargetPrice), tr.Int(position.ExitCutLossPrice)))
+	sb.WriteString(tr.T("monitoring.compact_footer"))
+
+	return sb.String()
+}
+
+// writeTimeframeAnalysis menulis ringkasan analisa 1D, 4H dan 1H.
+func writeTimeframeAnalysis(sb *strings.Builder, tr i18n.Translator, analysis models.TimeframeAnalysis) {
+	sb.WriteString(tr.T("analysis.timeframe_title"))
+	timeframes := []struct {
+		label string
+		data  models.TimeframeAnalysisData
+	}{
+		{tr.T("analysis.timeframe_1d"), analysis.Timeframe1D},
+		{tr.T("analysis.timeframe_4h"), analysis.Timeframe4H},
+		{tr.T("analysis.timeframe_1h"), analysis.Timeframe1H},
+	}
+	for _, timeframe := range timeframes {
+		sb.WriteString(fmt.Sprintf("\n<b>%s</b>: %s | RSI: %d\n", timeframe.label, timeframe.data.Trend, timeframe.data.RSI))
+		sb.WriteString(tr.T("analysis.key_signal", timeframe.data.KeySignal))
+		sb.WriteString(tr.T("analysis.support_resistance", tr.Int(timeframe.data.Support), tr.Int(timeframe.data.Resistance)))
+	}
+}
+
+func writeNewsSummary(sb *strings.Builder, tr i18n.Translator, summary models.NewsSummary) {
+	sb.WriteString(tr.T("analysis.news_title"))
+	if summary.ConfidenceScore > 0 {
+		sb.WriteString(tr.T("analysis.news_detail", tr.Number(summary.ConfidenceScore, 2), summary.Sentiment, summary.Impact, summary.Reasoning))
+	} else {
+		sb.WriteString(tr.T("analysis.news_empty"))
+	}
+}
+
+func (t *TelegramBotService) FormatAnalysisMessage(tr i18n.Translator, analysis *models.IndividualAnalysisResponseMultiTimeframe) string {
 	var sb strings.Builder
 	signalIcon := "🟡"
 	if analysis.Action == "BUY" {
@@ -94,131 +116,103 @@ func (t *TelegramBotService) FormatAnalysisMessage(analysis *models.IndividualAn
 	if analysis.Action != "HOLD" {
 		gain := float64(analysis.TargetPrice-analysis.BuyPrice) / float64(analysis.BuyPrice) * 100
 		loss := float64(analysis.CutLoss-analysis.BuyPrice) / float64(analysis.BuyPrice) * 100
-		sb.WriteString("<b>Trade Plan</b>\n")
-		sb.WriteString(fmt.Sprintf("📌 Last Price: %d (%s)\n", int(analysis.MarketPrice), analysis.AnalysisDate.Format("01-02 15:04")))
-		sb.WriteString(fmt.Sprintf("💵 Buy Area: $%d\n", int(analysis.BuyPrice)))
-		sb.WriteString(fmt.Sprintf("🎯 Target Price: $%d %s\n", int(analysis.TargetPrice), utils.FormatPercentage(gain)))
-		sb.WriteString(fmt.Sprintf("🛡 Cut Loss: $%d %s\n", int(analysis.CutLoss), utils.FormatPercentage(loss)))
-		sb.WriteString(fmt.Sprintf("⚖️ Risk/Reward Ratio: %.2f\n", analysis.RiskRewardRatio))
-		sb.WriteString(fmt.Sprintf("<i>⏳ Estimasi Waktu Profit: %d hari kerja</i>\n", analysis.EstimatedHoldingDays))
+		sb.WriteString(tr.T("analysis.trade_plan"))
+		sb.WriteString(tr.T("analysis.last_price", tr.Int(analysis.MarketPrice), analysis.AnalysisDate.Format("01-02 15:04")))
+		sb.WriteString(tr.T("analysis.buy_area", tr.Int(analysis.BuyPrice)))
+		sb.WriteString(tr.T("analysis.target_price", tr.Int(analysis.TargetPrice), tr.PercentChange(gain)))
+		sb.WriteString(tr.T("analysis.cut_loss", tr.Int(analysis.CutLoss), tr.PercentChange(loss)))
+		sb.WriteString(tr.T("analysis.risk_reward", tr.Number(analysis.RiskRewardRatio, 2)))
+		sb.WriteString(tr.Plural("analysis.estimated_profit_days", analysis.EstimatedHoldingDays))
 	} else if analysis.Action == "HOLD" {
-		sb.WriteString("<b>Status saat ini</b>\n")
-		sb.WriteString(fmt.Sprintf("📌 Last Price: %d (%s)\n", int(analysis.MarketPrice), analysis.AnalysisDate.Format("01-02 15:04")))
+		sb.WriteString(tr.T("analysis.current_status"))
+		sb.WriteString(tr.T("analysis.last_price", tr.Int(analysis.MarketPrice), analysis.AnalysisDate.Format("01-02 15:04")))
 		if analysis.EstimatedHoldingDays > 0 {
-			sb.WriteString(fmt.Sprintf("<i>🔍 Perkiraan Waktu Tunggu: %d hari kerja</i>\n", analysis.EstimatedHoldingDays))
+			sb.WriteString(tr.Plural("analysis.estimated_wait_days", analysis.EstimatedHoldingDays))
 		}
 	}
 
-	sb.WriteString("\n<b>Key Metrics</b>\n")
-	sb.WriteString(fmt.Sprintf("📶 Confidence: %d%%\n", analysis.ConfidenceLevel))
-	sb.WriteString(fmt.Sprintf("🔢 Technical Score: %d\n", analysis.TechnicalScore))
+	sb.WriteString(tr.T("analysis.key_metrics", analysis.ConfidenceLevel, analysis.TechnicalScore))
 
 	// Reasoning
-	sb.WriteString(fmt.Sprintf("\n🧠 <b>Reasoning:</b>\n%s\n\n", analysis.Reasoning))
-
-	sb.WriteString("🔍 <b>Analisa Multi-Timeframe</b>")
-	sb.WriteString(fmt.Sprintf("\n<b>Daily (1D)</b>: %s | RSI: %d\n", analysis.TimeframeAnalysis.Timeframe4H.Trend, analysis.TimeframeAnalysis.Timeframe1D.RSI))
-	sb.WriteString(fmt.Sprintf("> Sinyal Kunci: %s\n", analysis.TimeframeAnalysis.Timeframe1D.KeySignal))
-	sb.WriteString(fmt.Sprintf("> Support/Resistance: %d/%d\n", int(analysis.TimeframeAnalysis.Timeframe1D.Support), int(analysis.TimeframeAnalysis.Timeframe1D.Resistance)))
-
-	sb.WriteString(fmt.Sprintf("\n<b>4 Hours (4H)</b>: %s | RSI: %d\n", analysis.TimeframeAnalysis.Timeframe4H.Trend, analysis.TimeframeAnalysis.Timeframe4H.RSI))
-	sb.WriteString(fmt.Sprintf("> Sinyal Kunci: %s\n", analysis.TimeframeAnalysis.Timeframe4H.KeySignal))
-	sb.WriteString(fmt.Sprintf("> Support/Resistance: %d/%d\n", int(analysis.TimeframeAnalysis.Timeframe4H.Support), int(analysis.TimeframeAnalysis.Timeframe4H.Resistance)))
-
-	sb.WriteString(fmt.Sprintf("\n<b>1 Hour (1H)</b>: %s | RSI: %d\n", analysis.TimeframeAnalysis.Timeframe1H.Trend, analysis.TimeframeAnalysis.Timeframe1H.RSI))
-	sb.WriteString(fmt.Sprintf("> Sinyal Kunci: %s\n", analysis.TimeframeAnalysis.Timeframe1H.KeySignal))
-	sb.WriteString(fmt.Sprintf("> Support/Resistance: %d/%d\n", int(analysis.TimeframeAnalysis.Timeframe1H.Support), int(analysis.TimeframeAnalysis.Timeframe1H.Resistance)))
-
-	// News Summary
-	sb.WriteString("\n📰 <b>News Analysis:</b>\n")
-	if analysis.NewsSummary.ConfidenceScore > 0 {
-		sb.WriteString(fmt.Sprintf("Confidence Score: %.2f\n", analysis.NewsSummary.ConfidenceScore))
-		sb.WriteString(fmt.Sprintf("Sentiment: %s\n", analysis.NewsSummary.Sentiment))
-		sb.WriteString(fmt.Sprintf("Impact: %s\n\n", analysis.NewsSummary.Impact))
-		sb.WriteString(fmt.Sprintf("🧠 News Insight: \n%s\n\n", analysis.NewsSummary.Reasoning))
-	} else {
-		sb.WriteString("<i>Belum ada data berita terbaru yang tersedia untuk saham ini.</i>\n\n")
-	}
+	sb.WriteString(tr.T("analysis.reasoning", analysis.Reasoning))
+
+	writeTimeframeAnalysis(&sb, tr, analysis.TimeframeAnalysis)
+	writeNewsSummary(&sb, tr, analysis.NewsSummary)
 
 	sb.WriteString("\n")
-	sb.WriteString(fmt.Sprintf("📅 <i>Terakhir dianalisis: %s</i>\n", analysis.AnalysisDate.Format("2006-01-02 15:04:05")))
+	sb.WriteString(tr.T("analysis.last_analyzed", tr.DateTime(analysis.AnalysisDate)))
 
 	return sb.String()
 }
 
-func (t *TelegramBotService) FormatResultSetPositionMessage(data *models.RequestSetPositionData) string {
+func (t *TelegramBotService) FormatResultSetPositionMessage(tr i18n.Translator, data *models.RequestSetPositionData) string {
 	var sb strings.Builder
 
-	sb.WriteString("💾 Posisi saham berhasil disimpan!\n\n")
-	sb.WriteString("📊 Detail:\n")
-	sb.WriteString("— Saham: " + data.Symbol + "\n")
-	sb.WriteString("— Harga Beli: " + strconv.FormatFloat(data.BuyPrice, 'f', 0, 64) + "\n")
-	sb.WriteString("— Tanggal Beli: " + data.BuyDate + "\n")
-	sb.WriteString("— Take Profit: " + strconv.FormatFloat(data.TakeProfit, 'f', 0, 64) + "\n")
-	sb.WriteString("— Stop Loss: " + strconv.FormatFloat(data.StopLoss, 'f', 0, 64) + "\n")
-	sb.WriteString("— Max Hold: " + strconv.Itoa(data.MaxHolding) + " hari\n\n")
+	sb.WriteString(tr.T("setposition.saved",
+		data.Symbol,
+		tr.Int(data.BuyPrice),
+		data.BuyDate,
+		tr.Int(data.TakeProfit),
+		tr.Int(data.StopLoss),
+	))
+	sb.WriteString(tr.Plural("setposition.saved_max_hold", data.MaxHolding))
 
 	if data.AlertPrice {
-		sb.WriteString("🔔 Alert harga *ON* — sistem akan kirim notifikasi jika harga menyentuh TP atau SL.\n")
+		sb.WriteString(tr.T("setposition.saved_alert_on"))
 	} else {
-		sb.WriteString("🔕 Alert harga *OFF*.\n")
+		sb.WriteString(tr.T("setposition.saved_alert_off"))
 	}
 
 	if data.AlertMonitor {
-		sb.WriteString("🧠 Monitoring *ON* — kamu akan dapat laporan harian selama posisi masih berjalan.")
+		sb.WriteString(tr.T("setposition.saved_monitor_on"))
 	} else {
-		sb.WriteString("🧠 Monitoring *OFF*.\n")
+		sb.WriteString(tr.T("setposition.saved_monitor_off"))
 	}
 
 	return sb.String()
 }
 
-func (t *TelegramBotService) FormatMyStockPositionMessage(position *models.StockPositionEntity, marketPrice *models.RedisLastPrice) string {
-	now := time.Now()
-	age := int(now.Sub(position.BuyDate).Hours() / 24)
-	if age < 0 {
-		age = 0
-	}
-	remaining := position.MaxHoldingPeriodDays - age
-	if remaining < 0 {
-		remaining = 0
-	}
+func (t *TelegramBotService) FormatMyStockPositionMessage(tr i18n.Translator, position *models.StockPositionEntity, marketPrice *models.MarketPrice) string {
+	now := t.marketCalendar.Now()
+	age := t.marketCalendar.HoldingDays(position.BuyDate, now)
+	remaining := t.marketCalendar.RemainingHoldingDays(position.MaxHoldingPeriodDays, position.BuyDate, now)
 
 	gain := float64(position.TakeProfitPrice-position.BuyPrice) / float64(position.BuyPrice) * 100
 	loss := float64(position.StopLossPrice-position.BuyPrice) / float64(position.BuyPrice) * 100
 
-	alertStatus := "✅ Aktif"
+	alertStatus := tr.T("common.active")
 	if position.PriceAlert == nil || !*position.PriceAlert {
-		alertStatus = "❌ Tidak Aktif"
+		alertStatus = tr.T("common.inactive")
 	}
 
-	monitorStatus := "✅ Aktif"
+	monitorStatus := tr.T("common.active")
 	if position.MonitorPosition == nil || !*position.MonitorPosition {
-		monitorStatus = "❌ Tidak Aktif"
+		monitorStatus = tr.T("common.inactive")
 	}
 
 	sb := strings.Builder{}
 	sb.WriteString("```\n")
-	sb.WriteString("📊 Monitoring Saham\n\n")
+	sb.WriteString(tr.T("myposition.detail_title"))
 	sb.WriteString(fmt.Sprintf("📦 %s\n", position.StockCode))
 	sb.WriteString("────────────────────────────────\n")
-	sb.WriteString(fmt.Sprintf("💰 Harga Beli   : %d\n", int(position.BuyPrice)))
+	sb.WriteString(tr.T("myposition.detail_buy_price", tr.Int(position.BuyPrice)))
 	if marketPrice != nil && marketPrice.Price > 0 {
 		pnl := (marketPrice.Price - position.BuyPrice) / position.BuyPrice * 100
-		sb.WriteString(fmt.Sprintf("💵 Harga Pasar  : %d (%s)\n", int(marketPrice.Price), utils.FormatPercentage(pnl)))
+		sb.WriteString(tr.T("myposition.detail_market_price", tr.Int(marketPrice.Price), tr.PercentChange(pnl)))
+		sb.WriteString(tr.T("myposition.detail_price_update", marketPrice.Time.Format("02/01 15:04"), formatPriceFreshness(tr, marketPrice.Freshness, marketPrice.Source)))
 	}
-	sb.WriteString(fmt.Sprintf("🎯 Target Jual  : %d %s\n", int(position.TakeProfitPrice), utils.FormatPercentage(gain)))
-	sb.WriteString(fmt.Sprintf("🛑 Stop Loss    : %d %s\n", int(position.StopLossPrice), utils.FormatPercentage(loss)))
-	sb.WriteString(fmt.Sprintf("📅 Tgl Beli     : %s\n", position.BuyDate.Format("02 Jan 2006")))
-	sb.WriteString(fmt.Sprintf("⏳ Umur Posisi  : %d hari\n", age))
-	sb.WriteString(fmt.Sprintf("⌛ Sisa Waktu   : %d hari\n", remaining))
+	sb.WriteString(tr.T("myposition.detail_target", tr.Int(position.TakeProfitPrice), tr.PercentChange(gain)))
+	sb.WriteString(tr.T("myposition.detail_stop_loss", tr.Int(position.StopLossPrice), tr.PercentChange(loss)))
+	sb.WriteString(tr.T("myposition.detail_buy_date", tr.Date(position.BuyDate)))
+	sb.WriteString(tr.Plural("myposition.detail_age", age))
+	sb.WriteString(tr.Plural("myposition.detail_remaining", remaining))
 	sb.WriteString("────────────────────────────────\n")
-	sb.WriteString(fmt.Sprintf("🔔 Alert        : %s\n", alertStatus))
-	sb.WriteString(fmt.Sprintf("📡 Monitoring   : %s\n", monitorStatus))
+	sb.WriteString(tr.T("myposition.detail_alert", alertStatus))
+	sb.WriteString(tr.T("myposition.detail_monitoring", monitorStatus))
 	sb.WriteString("────────────────────────────────\n")
 
 	if len(position.StockPositionMonitorings) > 0 {
-		sb.WriteString("📖 Riwayat Analisa\n")
+		sb.WriteString(tr.T("myposition.detail_history"))
 		for _, monitoring := range position.StockPositionMonitorings {
 			var data models.PositionMonitoringResponseMultiTimeframe
 			err := json.Unmarshal([]byte(monitoring.Data), &data)
@@ -234,9 +228,9 @@ func (t *TelegramBotService) FormatMyStockPositionMessage(position *models.Stock
 				iconAction = "🟡"
 			}
 			sb.WriteString("\n")
-			sb.WriteString(fmt.Sprintf("• 🕒 %s | Conf: %d/100\n", data.AnalysisDate.Format("02 Jan 15:04"), int(data.ConfidenceLevel)))
-			sb.WriteString(fmt.Sprintf("  %s %s @%d (%.2f%%)\n",
-				iconAction, data.Action, int(data.MarketPrice), ((data.MarketPrice - data.BuyPrice) / data.BuyPrice * 100)))
+			sb.WriteString(fmt.Sprintf("• 🕒 %s | Conf: %d/100\n", data.AnalysisDate.Format("02/01 15:04"), int(data.ConfidenceLevel)))
+			sb.WriteString(fmt.Sprintf("  %s %s @%s (%s)\n",
+				iconAction, data.Action, tr.Int(data.MarketPrice), tr.PercentChange((data.MarketPrice-data.BuyPrice)/data.BuyPrice*100)))
 		}
 	}
 	sb.WriteString("```\n")
@@ -244,37 +238,43 @@ func (t *TelegramBotService) FormatMyStockPositionMessage(position *models.Stock
 	return sb.String()
 }
 
-func (t *TelegramBotService) FormatMyPositionListMessage(positions []models.StockPositionEntity, lastMarketPriceMap map[string]models.RedisLastPrice) string {
+func (t *TelegramBotService) FormatMyPositionListMessage(tr i18n.Translator, positions []models.StockPositionEntity, lastMarketPriceMap map[string]models.MarketPrice) string {
 	var sb strings.Builder
+	now := t.marketCalendar.Now()
 
 	for _, position := range positions {
 		var (
 			lastMarketPrice   float64
 			lastMarketPriceAt time.Time
+			freshness         models.PriceFreshness
+			source            string
 		)
 
 		sb.WriteString(fmt.Sprintf("\n• %s", position.StockCode))
-		sb.WriteString(fmt.Sprintf("\n 🎯 Buy: %d | TP: %d | SL: %d\n", int(position.BuyPrice), int(position.TakeProfitPrice), int(position.StopLossPrice)))
+		sb.WriteString(fmt.Sprintf("\n 🎯 Buy: %s | TP: %s | SL: %s\n", tr.Int(position.BuyPrice), tr.Int(position.TakeProfitPrice), tr.Int(position.StopLossPrice)))
 		if len(position.StockPositionMonitorings) == 0 {
-			sb.WriteString(" ℹ️ <i>Saat ini data belum tersedia. Silakan coba lagi nanti.</i>\n")
+			sb.WriteString(tr.T("myposition.list_no_data"))
 			continue
 		}
 		var dataStockMonitoring *models.PositionMonitoringResponseMultiTimeframe
 		err := json.Unmarshal([]byte(position.StockPositionMonitorings[0].Data), &dataStockMonitoring)
 		if err != nil {
-			sb.WriteString(" ℹ️ <i>Data tidak valid. Silakan coba lagi nanti.</i>\n")
+			sb.WriteString(tr.T("myposition.list_invalid_data"))
 			continue
 		}
 
 		if lastMarketPriceData, ok := lastMarketPriceMap[position.StockCode]; ok && lastMarketPriceData.Price > 0 {
 			lastMarketPrice = lastMarketPriceData.Price
 			lastMarketPriceAt = lastMarketPriceData.Time
+			freshness = lastMarketPriceData.Freshness
+			source = lastMarketPriceData.Source
 		} else {
 			lastMarketPrice = dataStockMonitoring.MarketPrice
 			lastMarketPriceAt = dataStockMonitoring.AnalysisDate
+			freshness = t.priceService.Classify(lastMarketPriceAt, now)
 		}
 
-		sb.WriteString(fmt.Sprintf(" 💰 Last Price: %d (%s)\n", int(lastMarketPrice), lastMarketPriceAt.Format("01/02 15:04")))
+		sb.WriteString(tr.T("myposition.list_last_price", tr.Int(lastMarketPrice), lastMarketPriceAt.Format("02/01 15:04"), formatPriceFreshness(tr, freshness, source)))
 
 		iconAction := "🔴"
 		switch dataStockMonitoring.Action {
@@ -287,55 +287,24 @@ func (t *TelegramBotService) FormatMyPositionListMessage(positions []models.Stoc
 		}
 
 		pnl := (lastMarketPrice - dataStockMonitoring.BuyPrice) / dataStockMonitoring.BuyPrice * 100
-		sb.WriteString(fmt.Sprintf(" 📈 PnL: %s\n", utils.FormatPercentage(pnl)))
+		sb.WriteString(fmt.Sprintf(" 📈 PnL: %s\n", tr.PercentChange(pnl)))
 		sb.WriteString(fmt.Sprintf(" %s %s | Confidence: %d/100\n", iconAction, dataStockMonitoring.Action, int(dataStockMonitoring.ConfidenceLevel)))
-		sb.WriteString(fmt.Sprintf(" <i>🗓️ Last Analysis: %s</i>\n", dataStockMonitoring.AnalysisDate.Format("02 Jan 15:04")))
+		sb.WriteString(tr.T("myposition.list_last_analysis", dataStockMonitoring.AnalysisDate.Format("02/01 15:04")))
 	}
 	return sb.String()
 }
 
-func (t *TelegramBotService) FormatNotesTimeFrameStockMessage() string {
-	return `
-Berikut adalah penjelasan singkat tentang setiap time frame:
-━━━━━━━━━━━━━
-
-🔹 *Main Signal*  
-⏱️ Time Frame: 1 hari  |  📅 Range: 3 bulan  
-📌 Untuk melihat arah tren besar saham.  
-👉 Cocok kalau kamu ingin tahu apakah saham ini sedang bagus untuk dibeli.
-
-🔹 *Entry Presisi*  
-⏱️ Time Frame: 4 jam  |  📅 Range: 1 bulan  
-📌 Untuk cari waktu terbaik masuk setelah sinyal beli muncul.  
-👉 Cocok kalau kamu sudah yakin mau beli, tapi ingin harga yang lebih pas.
-
-🔹 *Exit Presisi*  
-⏱️ Time Frame: 1 jam  |  📅 Range: 14 hari  
-📌 Untuk bantu kamu ambil untung atau cut loss.  
-👉 Cocok kalau kamu sudah punya saham dan ingin tahu kapan jual.
-`
+func (t *TelegramBotService) FormatNotesTimeFrameStockMessage(tr i18n.Translator) string {
+	return tr.T("analysis.timeframe_notes")
 }
 
-func (t *TelegramBotService) ShowAnalysisInProgress(stockCode string, interval string, period string) string {
-	return fmt.Sprintf(`
-🔍 Sedang menganalisis *$%s*...
-
-🕐 Interval: %s  
-📆 Range: %s
-
-⏳ Mohon tunggu sebentar, bot sedang memproses data:
-- Mengambil data harga 📈
-- Menghitung sinyal teknikal 📊
-- Menyusun rekomendasi 💡
-
-📬 Hasil analisa akan muncul dalam beberapa detik...
-`, stockCode, interval, period)
-
+func (t *TelegramBotService) ShowAnalysisInProgress(tr i18n.Translator, stockCode string, interval string, period string) string {
+	return tr.T("analysis.in_progress", stockCode, interval, period)
 }
 
 func (t *TelegramBotService) showLoadingFlowAnalysis(c telebot.Context, stop <-chan struct{}) *telebot.Message {
 	msgRoot := c.Message()
-	initial := "Sedang menganalisis saham kamu, mohon tunggu"
+	initial := t.translator(c).T("loading.analysis")
 
 	var msg *telebot.Message
 	var err error
@@ -378,7 +347,7 @@ func (t *TelegramBotService) showLoadingFlowAnalysis(c telebot.Context, stop <-c
 func (t *TelegramBotService) showLoadingGeneral(c telebot.Context, stop <-chan struct{}) *telebot.Message {
 	msgRoot := c.Message()
 
-	initial := "Mohon tunggu sebentar, bot sedang memproses data"
+	initial := t.translator(c).T("loading.general")
 	msg, _ := t.bot.Edit(msgRoot, initial)
 
 	go func() {
@@ -402,12 +371,13 @@ func (t *TelegramBotService) showLoadingGeneral(c telebot.Context, stop <-chan s
 }
 
 func (t *TelegramBotService) showLoadingBuyList(c telebot.Context, stockCode string, msgRoot *telebot.Message, stop <-chan struct{}, result *strings.Builder) *telebot.Message {
+	tr := t.translator(c)
 	steps := []string{
-		fmt.Sprintf("📊 *Sedang menganalisis saham %s...*\n", stockCode),
-		"🔍 Langkah 1: Mengecek pergerakan harga (OHLC)...",
-		"🗞️ Langkah 2: Memindai berita dan sentimen pasar...",
-		"🧠 Langkah 3: AI sedang melakukan analisa teknikal & fundamental...",
-		"\nMohon tunggu sebentar, hasil segera keluar...",
+		tr.T("loading.buylist_title", stockCode),
+		tr.T("loading.buylist_step_price"),
+		tr.T("loading.buylist_step_news"),
+		tr.T("loading.buylist_step_ai"),
+		tr.T("loading.buylist_wait"),
 	}
 
 	stepsCount := 0
@@ -476,6 +446,8 @@ func (t *TelegramBotService) showProgressBarWithChannel(
 	totalSteps int,
 	wg *sync.WaitGroup,
 ) {
+	tr := t.translator(c)
+
 	utils.SafeGo(func() {
 		const barLength = 15 // total panjang bar, bisa diubah sesuai estetika
 
@@ -513,13 +485,13 @@ func (t *TelegramBotService) showProgressBarWithChannel(
 				}
 
 				// Buat bar: ▓ untuk progress, ░ untuk sisanya
-				currentAnalysis := fmt.Sprintf(messageLoadingAnalysis, current.StockCode)
+				currentAnalysis := tr.T("analysis.loading", current.StockCode)
 				filled := strings.Repeat("▓", progressBlocks)
 				empty := strings.Repeat("░", barLength-progressBlocks)
 				progressBar := fmt.Sprintf("⏳ Progress: [%s%s] %d%%", filled, empty, percent)
 
 				menu := &telebot.ReplyMarkup{}
-				btnCancel := menu.Data(btnCancelBuyListAnalysis.Text, btnCancelBuyListAnalysis.Unique)
+				btnCancel := menu.Data(tr.T(btnCancelBuyListAnalysis.Text), btnCancelBuyListAnalysis.Unique)
 				menu.Inline(menu.Row(btnCancel))
 
 				body := &strings.Builder{}
@@ -555,50 +527,39 @@ func (t *TelegramBotService) showProgressBarWithChannel(
 	})
 }
 
-func (t *TelegramBotService) formatMessageBuyList(index int, analysis *models.IndividualAnalysisResponseMultiTimeframe) *strings.Builder {
+func (t *TelegramBotService) formatMessageBuyList(tr i18n.Translator, index int, analysis *models.IndividualAnalysisResponseMultiTimeframe) *strings.Builder {
 	profitPercentage := ((analysis.TargetPrice - analysis.BuyPrice) / analysis.BuyPrice) * 100
 	sb := &strings.Builder{}
 	sb.WriteString(fmt.Sprintf("\n• `$%s` - _(%s)_\n", analysis.Symbol, analysis.AnalysisDate.Format("01/02 15:04")))
-	sb.WriteString(fmt.Sprintf("   🔖 Last Price: %d\n", int(analysis.MarketPrice)))
-	sb.WriteString(fmt.Sprintf("   💵 Buy: %d 📊 Score: %d\n", int(analysis.BuyPrice), ((analysis.ConfidenceLevel + analysis.TechnicalScore) / 2)))
-	sb.WriteString(fmt.Sprintf("   🎯 TP: %d  🛡 SL: %d\n", int(analysis.TargetPrice), int(analysis.CutLoss)))
-	sb.WriteString(fmt.Sprintf("   🔁 RR: %.1f   💰 Profit: +%.1f%%\n", analysis.RiskRewardRatio, profitPercentage))
+	sb.WriteString(fmt.Sprintf("   🔖 Last Price: %s\n", tr.Int(analysis.MarketPrice)))
+	sb.WriteString(fmt.Sprintf("   💵 Buy: %s 📊 Score: %d\n", tr.Int(analysis.BuyPrice), ((analysis.ConfidenceLevel + analysis.TechnicalScore) / 2)))
+	sb.WriteString(fmt.Sprintf("   🎯 TP: %s  🛡 SL: %s\n", tr.Int(analysis.TargetPrice), tr.Int(analysis.CutLoss)))
+	sb.WriteString(fmt.Sprintf("   🔁 RR: %s   💰 Profit: %s\n", tr.Number(analysis.RiskRewardRatio, 1), tr.PercentChange(profitPercentage)))
 	return sb
 
 }
 
-func (t *TelegramBotService) formatMessageMenuNews() string {
-	result := `📋 Menu Berita Saham
-
-Tetap update dengan pergerakan pasar.
-Cari berita saham, aktifkan rangkuman harian,
-atau nyalakan alert otomatis saat muncul berita penting
-yang bisa mempengaruhi harga saham.
-
-Pilih fitur yang ingin kamu akses:`
-
-	return result
-
+func (t *TelegramBotService) formatMessageMenuNews(tr i18n.Translator) string {
+	return tr.T("news.menu")
 }
 
-func (t *TelegramBotService) formatMessageNewsList(newsList []models.StockNewsEntity, age int) string {
+func (t *TelegramBotService) formatMessageNewsList(tr i18n.Translator, newsList []models.StockNewsEntity, age int) string {
 	sb := &strings.Builder{}
-	sb.WriteString(fmt.Sprintf("📢 Berikut adalah rangkuman berita penting terbaru yang berkaitan dengan saham $%s dalam %d hari terakhir", newsList[0].StockCode, age))
+	sb.WriteString(tr.Plural("news.list_title", age, newsList[0].StockCode))
 
 	for idx, news := range newsList {
 		sb.WriteString(fmt.Sprintf("\n\n %d. %s\n", idx+1, utils.TruncateTitle(news.Title, 80)))
-		sb.WriteString(fmt.Sprintf(" 📅 %s | 🌐 %s\n", news.PublishedAt.Format("2006-01-02"), utils.ExtractDomain(news.Link)))
-		sb.WriteString(fmt.Sprintf(" 📊 Sentimen: %s | 💯 Score: %.2f\n", news.Sentiment, news.FinalScore))
+		sb.WriteString(fmt.Sprintf(" 📅 %s | 🌐 %s\n", tr.ShortDate(*news.PublishedAt), utils.ExtractDomain(news.Link)))
+		sb.WriteString(tr.T("news.list_sentiment", news.Sentiment, tr.Number(news.FinalScore, 2)))
 		sb.WriteString(fmt.Sprintf(" 🧠 %s\n", utils.TruncateTitle(news.Reason, 200)))
-		link := fmt.Sprintf("[Selengkapnya](%s)", news.Link)
-		sb.WriteString(fmt.Sprintf(" 🔗 %s\n", link))
+		sb.WriteString(tr.T("news.list_link_markdown", news.Link))
 	}
 	sb.WriteString("\n")
 
 	return sb.String()
 }
 
-func (t *TelegramBotService) formatMessageNewsSummary(summary *models.StockNewsSummaryEntity) string {
+func (t *TelegramBotService) formatMessageNewsSummary(tr i18n.Translator, summary *models.StockNewsSummaryEntity) string {
 
 	action := strings.ToUpper(summary.SuggestedAction)
 	iconAction := "❔"
@@ -611,46 +572,30 @@ func (t *TelegramBotService) formatMessageNewsSummary(summary *models.StockNewsS
 	}
 
 	sb := &strings.Builder{}
-	sb.WriteString(fmt.Sprintf("📚 *Ringkasan Analisis Saham $%s*\n\n", summary.StockCode))
+	sb.WriteString(tr.T("news.summary_title", summary.StockCode))
 	sb.WriteString(fmt.Sprintf("📝 *TL;DR:* %s\n\n", summary.ShortSummary))
-	sb.WriteString(fmt.Sprintf("🧠 *Sentimen:* %s\n", summary.SummarySentiment))
-	sb.WriteString(fmt.Sprintf("📈 *Dampak:* %s\n", summary.SummaryImpact))
-	sb.WriteString(fmt.Sprintf("📉 *Confidence Score:* %.2f\n", summary.SummaryConfidenceScore))
-	sb.WriteString(fmt.Sprintf("🎯 *Saran:* %s %s\n", iconAction, action))
-	sb.WriteString("\n🔑 *Isu Kunci:*\n")
+	sb.WriteString(tr.T("news.summary_detail", summary.SummarySentiment, summary.SummaryImpact, tr.Number(summary.SummaryConfidenceScore, 2), iconAction, action))
+	sb.WriteString(tr.T("news.summary_key_issues"))
 	for _, issue := range summary.KeyIssues {
 		sb.WriteString(fmt.Sprintf("• %s\n", issue))
 	}
-	sb.WriteString(fmt.Sprintf("\n🧩 *Alasan:* %s\n\n", summary.Reasoning))
-	sb.WriteString(fmt.Sprintf("📆 *Periode:* %s - %s\n", summary.SummaryStart.Format("2006-01-02"), summary.SummaryEnd.Format("2006-01-02")))
+	sb.WriteString(tr.T("news.summary_reasoning", summary.Reasoning))
+	sb.WriteString(tr.T("news.summary_period", tr.ShortDate(summary.SummaryStart), tr.ShortDate(summary.SummaryEnd)))
 
 	return sb.String()
 }
 
-func (t *TelegramBotService) formatMessageReportNotExits() string {
-	return `📭 *Belum Ada Riwayat Trading*
-
-Kamu belum memiliki data trading yang bisa ditampilkan.
-
-📌 Berikut alur untuk mulai mencatat performa trading kamu:
-
-1️⃣ Gunakan perintah */setposition* untuk mencatat saat kamu masuk posisi (BUY/SELL).
-
-2️⃣ Setelah keluar dari posisi, klik tombol *Exit Posisi* dan isi form exit (harga keluar, tanggal, dll).
-
-3️⃣ Setelah posisi ditutup, kamu bisa menggunakan perintah */report* untuk melihat performa trading kamu.
-
-💡 Data baru akan muncul di report setelah kamu menyelesaikan langkah di atas minimal 1 kali.`
+func (t *TelegramBotService) formatMessageReportNotExits(tr i18n.Translator) string {
+	return tr.T("report.empty")
 }
 
-func (t *TelegramBotService) formatMessageReport(positions []models.StockPositionEntity) string {
+func (t *TelegramBotService) formatMessageReport(tr i18n.Translator, positions []models.StockPositionEntity) string {
 	sb := &strings.Builder{}
 	// header
-	sb.WriteString("📊 <b>Trading Report</b>\n")
-	sb.WriteString("Laporan ini menampilkan ringkasan performa dari posisi trading yang sudah selesai. Gunakan sebagai bahan evaluasi untuk strategi swing trading kamu.\n")
+	sb.WriteString(tr.T("report.title"))
 
 	sbBody := &strings.Builder{}
-	sbBody.WriteString("\n\n🔎 Detail Saham:")
+	sbBody.WriteString(tr.T("report.detail_title"))
 
 	countWin := 0
 	countLose := 0
@@ -665,37 +610,92 @@ func (t *TelegramBotService) formatMessageReport(positions []models.StockPositio
 		} else {
 			countLose++
 		}
-		sbBody.WriteString(fmt.Sprintf("\n- $%s <i>(%s-%s)</i>", position.StockCode, position.BuyDate.Format("01/02"), position.ExitDate.Format("01/02")))
-		sbBody.WriteString(fmt.Sprintf("\n		%s PnL: %+.2f%%", icon, pnl))
-		sbBody.WriteString(fmt.Sprintf("\n		💰 Buy: %d | Exit: %d", int(position.BuyPrice), int(*position.ExitPrice)))
+		sbBody.WriteString(fmt.Sprintf("\n- $%s <i>(%s-%s)</i>", position.StockCode, position.BuyDate.Format("02/01"), position.ExitDate.Format("02/01")))
+		sbBody.WriteString(fmt.Sprintf("\n		%s PnL: %s", icon, tr.PercentChange(pnl)))
+		sbBody.WriteString(fmt.Sprintf("\n		💰 Buy: %s | Exit: %s", tr.Int(position.BuyPrice), tr.Int(*position.ExitPrice)))
 	}
 
 	sbSummary := &strings.Builder{}
 	sbSummary.WriteString(fmt.Sprintf("\n🟢 <b>Win</b>: %d | 🔴 Lose: %d", countWin, countLose))
-	sbSummary.WriteString(fmt.Sprintf("\n📈 <b>Total PnL</b>: %+.2f%%", countPnL))
-	sbSummary.WriteString(fmt.Sprintf("\n🏆 <b>Win Rate</b>: %.2f%%", float64(countWin)/float64(len(positions))*100))
+	sbSummary.WriteString(fmt.Sprintf("\n📈 <b>Total PnL</b>: %s", tr.PercentChange(countPnL)))
+	sbSummary.WriteString(fmt.Sprintf("\n🏆 <b>Win Rate</b>: %s", tr.Percent(float64(countWin)/float64(len(positions))*100, 2)))
 
 	result := fmt.Sprintf("%s%s%s", sb.String(), sbSummary.String(), sbBody.String())
 	return result
 }
 
-func (t *TelegramBotService) formatMessageTopNewsList(newsList []models.TopNewsCustomResult) string {
+func (t *TelegramBotService) formatMessageTopNewsList(tr i18n.Translator, newsList []models.TopNewsCustomResult) string {
 	sb := &strings.Builder{}
-	sb.WriteString(fmt.Sprintf("📈 <b>Top News Saham Hari Ini (%s)</b>\n", utils.TimeNowWIB().Format("02/01 15:04")))
+	sb.WriteString(tr.T("news.top_title", utils.TimeNowWIB().Format("02/01 15:04")))
 
 	for _, news := range newsList {
-		sb.WriteString(fmt.Sprintf("\n<i>📅 %s | 🌐  %s</i>\n", news.PublishedAt.Format("2006-01-02"), utils.ExtractDomain(news.Link)))
+		sb.WriteString(fmt.Sprintf("\n<i>📅 %s | 🌐  %s</i>\n", tr.ShortDate(news.PublishedAt), utils.ExtractDomain(news.Link)))
 		sb.WriteString(fmt.Sprintf("<b>%s</b>\n", utils.TruncateTitle(news.Title, 80)))
 		sb.WriteString(utils.TruncateTitle(news.Summary, 300))
 		sb.WriteString("\n")
 		if len(news.StockCodes) > 0 {
-			sb.WriteString(fmt.Sprintf(" 📊 Saham: %s\n", strings.Join(news.StockCodes, ", ")))
+			sb.WriteString(tr.T("news.top_stocks", strings.Join(news.StockCodes, ", ")))
 		}
-		link := fmt.Sprintf("<a href='%s'>Selengkapnya</a>", news.Link)
-		sb.WriteString(fmt.Sprintf(" 🔗 %s\n", link))
+		sb.WriteString(tr.T("news.list_link_html", news.Link))
 
 	}
 	sb.WriteString("\n")
 
 	return sb.String()
 }
+
+func (t *TelegramBotService) formatMessageUsageReport(tr i18n.Translator, report *models.LLMUsageReport) string {
+	sb := &strings.Builder{}
+	sb.WriteString(fmt.Sprintf("🤖 <b>LLM Usage (%s)</b>\n", report.GeneratedAt.Format("02/01 15:04")))
+
+	sb.WriteString(tr.T("usage.today_cost", formatUsageCost(tr, report.TodayCost, report.DailyBudget)))
+	sb.WriteString(tr.T("usage.month_cost", formatUsageCost(tr, report.MonthCost, report.MonthlyBudget)))
+
+	writeSummaries := func(title string, summaries []models.LLMUsageSummary) {
+		sb.WriteString(fmt.Sprintf("\n<b>%s</b>\n", title))
+		if len(summaries) == 0 {
+			sb.WriteString(tr.T("usage.empty"))
+			return
+		}
+		for _, summary := range summaries {
+			sb.WriteString(fmt.Sprintf("• <code>%s</code>\n", summary.Group))
+			sb.WriteString(tr.T("usage.summary_requests", summary.Requests, summary.Errors, tr.Number(summary.AvgLatencyMs, 0)))
+			sb.WriteString(fmt.Sprintf("   🔤 in %d / out %d token | $%.4f\n", summary.InputTokens, summary.OutputTokens, summary.Cost))
+		}
+	}
+	writeSummaries(tr.T("usage.today_by_model"), report.TodayByModel)
+	writeSummaries(tr.T("usage.month_by_model"), report.MonthByModel)
+	writeSummaries(tr.T("usage.month_by_day"), report.MonthByDay)
+
+	return sb.String()
+}
+
+func formatUsageCost(tr i18n.Translator, cost float64, budget float64) string {
+	if budget <= 0 {
+		return tr.T("usage.cost_unlimited", cost)
+	}
+
+	icon := "🟢"
+	if cost >= budget {
+		icon = tr.T("usage.budget_exceeded")
+	} else if cost >= budget*0.8 {
+		icon = "🟡"
+	}
+	return fmt.Sprintf("$%.4f / $%.2f %s", cost, budget, icon)
+}
+
+// formatPriceFreshness menampilkan badge kesegaran harga, misal "🟡 Delayed · Yahoo".
+func formatPriceFreshness(tr i18n.Translator, freshness models.PriceFreshness, source string) string {
+	badge := tr.T("price.stale")
+	switch freshness {
+	case models.PriceFreshnessLive:
+		badge = tr.T("price.live")
+	case models.PriceFreshnessDelayed:
+		badge = tr.T("price.delayed")
+	}
+
+	if source == models.PriceSourceYahoo {
+		badge += " · Yahoo"
+	}
+	return badge
+}
@@ -1,6 +1,7 @@
 package telegram_bot
 
 import (
+	"golang-swing-trading-signal/internal/models"
 	"golang-swing-trading-signal/internal/utils"
 	"time"
 
@@ -12,6 +13,7 @@ func (t *TelegramBotService) RegisterMiddleware() {
 	t.bot.Use(t.LoggingMiddleware)
 	t.bot.Use(t.RecoverMiddleware())
 	t.bot.Use(t.DeleteUserStateOnErrorMiddleware())
+	t.bot.Use(t.GroupChatMiddleware())
 }
 
 func (t *TelegramBotService) LoggingMiddleware(next telebot.HandlerFunc) telebot.HandlerFunc {
@@ -24,7 +26,7 @@ func (t *TelegramBotService) LoggingMiddleware(next telebot.HandlerFunc) telebot
 			"user_id":   userID,
 			"error":     err,
 			"duration":  time.Since(now),
-			"message":   c.Message().Text,
+			"message":   c.Text(),
 		})
 
 		return err
@@ -39,9 +41,9 @@ func (t *TelegramBotService) RecoverMiddleware() telebot.MiddlewareFunc {
 					t.logger.Error("Recovered from panic: ", logrus.Fields{
 						"user_id": c.Sender().ID,
 						"error":   r,
-						"message": c.Message().Text,
+						"message": c.Text(),
 					})
-					_ = c.Send("⚠️ Terjadi kesalahan internal. Mohon coba lagi nanti.")
+					_ = c.Send(t.translator(c).T("common.internal_error"))
 				}
 			}()
 			return next(c)
@@ -52,7 +54,7 @@ func (t *TelegramBotService) RecoverMiddleware() telebot.MiddlewareFunc {
 func (t *TelegramBotService) IsOnConversationMiddleware() telebot.MiddlewareFunc {
 	return func(next telebot.HandlerFunc) telebot.HandlerFunc {
 		return func(c telebot.Context) (err error) {
-			if _, inConversation := t.userStates[c.Sender().ID]; inConversation {
+			if _, inConversation := t.userStates[sessionKeyOf(c)]; inConversation {
 				t.handleCancel(c)
 			}
 			return next(c)
@@ -60,12 +62,50 @@ func (t *TelegramBotService) IsOnConversationMiddleware() telebot.MiddlewareFunc
 	}
 }
 
+// RequireRole membatasi handler hanya untuk user dengan role minimal required.
+// User yang diblokir ditolak untuk semua handler.
+func (t *TelegramBotService) RequireRole(required models.Role) telebot.MiddlewareFunc {
+	return func(next telebot.HandlerFunc) telebot.HandlerFunc {
+		return func(c telebot.Context) (err error) {
+			access, err := t.userService.GetAccess(t.ctx, c.Sender().ID)
+			if err != nil {
+				t.logger.Error("failed to get user access", logrus.Fields{
+					"user_id": c.Sender().ID,
+					"error":   err,
+				})
+				return t.denyAccess(c, "common.internal_error")
+			}
+
+			c.Set(contextKeyTranslator, translatorForAccess(access, c.Sender().LanguageCode))
+
+			switch {
+			case access.Banned:
+				return t.denyAccess(c, "access.banned")
+			case !access.Role.Allows(required) && required == models.RoleAdmin:
+				return t.denyAccess(c, "access.admin_only")
+			case !access.Role.Allows(required):
+				return t.denyAccess(c, "access.member_only")
+			}
+			return next(c)
+		}
+	}
+}
+
+// denyAccess mengirim pesan penolakan, key berupa key katalog i18n.
+func (t *TelegramBotService) denyAccess(c telebot.Context, key string) error {
+	message := t.translator(c).T(key)
+	if c.Callback() != nil {
+		return c.Respond(&telebot.CallbackResponse{Text: message, ShowAlert: true})
+	}
+	return c.Send(message)
+}
+
 func (t *TelegramBotService) DeleteUserStateOnErrorMiddleware() telebot.MiddlewareFunc {
 	return func(next telebot.HandlerFunc) telebot.HandlerFunc {
 		return func(c telebot.Context) (err error) {
 			defer func() {
 				if err != nil {
-					t.ResetUserState(c.Sender().ID)
+					t.ResetUserState(sessionKeyOf(c))
 				}
 			}()
 			return next(c)
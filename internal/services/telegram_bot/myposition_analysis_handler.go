@@ -3,7 +3,6 @@ package telegram_bot
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"golang-swing-trading-signal/internal/models"
 	"golang-swing-trading-signal/internal/utils"
 
@@ -17,6 +16,7 @@ func (t *TelegramBotService) handleBtnBackDetailStockPosition(ctx context.Contex
 
 func (t *TelegramBotService) handleBtnTimeframeStockPositionMonitoring(ctx context.Context, c telebot.Context) error {
 	symbol := c.Data() // The symbol is passed as data
+	tr := t.translator(c)
 
 	stopChan := make(chan struct{})
 
@@ -36,7 +36,7 @@ func (t *TelegramBotService) handleBtnTimeframeStockPositionMonitoring(ctx conte
 		if err != nil {
 			close(stopChan)
 			t.logger.WithError(err).WithField("symbol", symbol).Error("Failed to get stock position monitoring")
-			_, err := t.telegramRateLimiter.Edit(newCtx, c, msg, commonMessageInternalError, &telebot.ReplyMarkup{}, telebot.ModeMarkdown)
+			_, err := t.telegramRateLimiter.Edit(newCtx, c, msg, tr.T("common.internal_error"), &telebot.ReplyMarkup{}, telebot.ModeMarkdown)
 			if err != nil {
 				t.logger.WithError(err).Error("Failed to send error message")
 			}
@@ -46,13 +46,17 @@ func (t *TelegramBotService) handleBtnTimeframeStockPositionMonitoring(ctx conte
 		if len(positions) == 0 {
 			defer close(stopChan)
 			t.logger.WithField("symbol", symbol).Warn("No stock position monitoring found")
-			t.stockService.RequestStockPositionMonitoring(newCtx, &models.RequestStockPositionMonitoring{
+			if err := t.stockService.RequestStockPositionMonitoring(newCtx, &models.RequestStockPositionMonitoring{
 				TelegramID:     c.Sender().ID,
 				StockCode:      symbol,
 				SendToTelegram: true,
-			})
+				OnDemand:       true,
+			}); err != nil {
+				t.editRequestAnalysisError(newCtx, c, msg, symbol, err)
+				return
+			}
 
-			if _, err := t.telegramRateLimiter.Edit(newCtx, c, msg, fmt.Sprintf(messageAnalysisNotAvailable, symbol), &telebot.SendOptions{
+			if _, err := t.telegramRateLimiter.Edit(newCtx, c, msg, tr.T("analysis.not_available", symbol), &telebot.SendOptions{
 				ParseMode: telebot.ModeMarkdown,
 			}); err != nil {
 				t.logger.WithError(err).Error("Failed to edit message")
@@ -67,7 +71,7 @@ func (t *TelegramBotService) handleBtnTimeframeStockPositionMonitoring(ctx conte
 		if err := json.Unmarshal([]byte(position.Data), &stockMonitoring); err != nil {
 			t.logger.WithError(err).WithField("symbol", symbol).Error("Failed to unmarshal stock monitoring")
 			// Send error message
-			_, err := t.telegramRateLimiter.Send(newCtx, c, fmt.Sprintf("❌ Gagal parse data %s", symbol))
+			_, err := t.telegramRateLimiter.Send(newCtx, c, tr.T("analyze.parse_failed", symbol))
 			if err != nil {
 				t.logger.WithError(err).Error("Failed to send error message")
 			}
@@ -75,7 +79,7 @@ func (t *TelegramBotService) handleBtnTimeframeStockPositionMonitoring(ctx conte
 		}
 
 		// Format position monitoring message
-		message := t.FormatPositionMonitoringMessage(&stockMonitoring)
+		message := t.FormatPositionMonitoringMessage(tr, &stockMonitoring)
 
 		// Send the position monitoring results
 		_, err = t.telegramRateLimiter.Edit(newCtx, c, msg, message, &telebot.SendOptions{
@@ -2,7 +2,6 @@ package telegram_bot
 
 import (
 	"context"
-	"fmt"
 	"golang-swing-trading-signal/internal/models"
 	"golang-swing-trading-signal/internal/utils"
 	"strconv"
@@ -12,68 +11,62 @@ import (
 )
 
 func (t *TelegramBotService) handleExitPositionConversation(ctx context.Context, c telebot.Context) error {
-	userID := c.Sender().ID
+	tr := t.translator(c)
+	session := sessionKeyOf(c)
 	text := c.Text()
-	state := t.userStates[userID] // We already know the state exists
+	state := t.userStates[session] // We already know the state exists
 
-	data, data_ok := t.userExitPositionData[userID]
+	data, data_ok := t.userExitPositionData[session]
 	if !data_ok {
 		// Should not happen, but as a safeguard
-		delete(t.userStates, userID)
-		return c.Send("Terjadi kesalahan internal (data posisi tidak ditemukan).")
+		delete(t.userStates, session)
+		return c.Send(tr.T("exit.data_not_found"))
 	}
 
 	switch state {
 	case StateWaitingExitPositionInputExitPrice:
 		price, err := strconv.ParseFloat(text, 64)
 		if err != nil {
-			return c.Send("Format harga jual tidak valid. Silakan masukkan angka (contoh: 150.5).")
+			return c.Send(tr.T("exit.invalid_price"))
 		}
 		data.ExitPrice = price
-		err = c.Send(fmt.Sprintf(`
-🚀 Exit posisi saham *%s (2/2)*
-
-📅 Kapan tanggal jualnya? (contoh: 2025-05-18)`, data.Symbol), &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
+		err = c.Send(tr.T("exit.prompt_date", data.Symbol), &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
 		if err != nil {
-			return c.Send(commonMessageInternalError)
+			return c.Send(tr.T("common.internal_error"))
 		}
-		t.userStates[userID] = StateWaitingExitPositionInputExitDate
+		t.userStates[session] = StateWaitingExitPositionInputExitDate
 		return nil
 	case StateWaitingExitPositionInputExitDate:
 		date, err := time.Parse("2006-01-02", text)
 		if err != nil {
-			return c.Send("Format tanggal tidak valid. Silakan gunakan format YYYY-MM-DD.")
+			return c.Send(tr.T("exit.invalid_date"))
 		}
 		data.ExitDate = date
-		t.userStates[userID] = StateWaitingExitPositionConfirm
-		msg := fmt.Sprintf(`
-📌 Mohon cek kembali data yang kamu masukkan:
-
-• Kode Saham   : %s 
-• Harga Exit   : %.2f  
-• Tanggal Exit : %s  
-		`, data.Symbol, data.ExitPrice, data.ExitDate.Format("2006-01-02"))
+		t.userStates[session] = StateWaitingExitPositionConfirm
+		msg := tr.T("exit.confirm", data.Symbol, tr.Number(data.ExitPrice, 2), data.ExitDate.Format("2006-01-02"))
 		menu := &telebot.ReplyMarkup{}
-		btnSave := menu.Data(btnSaveExitPosition.Text, btnSaveExitPosition.Unique)
-		btnCancel := menu.Data(btnCancelGeneral.Text, btnCancelGeneral.Unique)
+		btnSave := menu.Data(tr.T(btnSaveExitPosition.Text), btnSaveExitPosition.Unique)
+		btnCancel := menu.Data(tr.T(btnCancelGeneral.Text), btnCancelGeneral.Unique)
 		menu.Inline(
 			menu.Row(btnSave, btnCancel),
 		)
 		return c.Send(msg, menu, telebot.ModeMarkdown)
 	case StateWaitingExitPositionConfirm:
-		return c.Send("👆 Silakan pilih salah satu opsi di atas, atau kirim /cancel untuk membatalkan.")
+		return c.Send(tr.T("common.choose_option"))
 	}
 	return nil
 }
 
 func (t *TelegramBotService) handleBtnSaveExitPosition(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
 	userID := c.Sender().ID
-	data := t.userExitPositionData[userID]
+	session := sessionKeyOf(c)
+	data := t.userExitPositionData[session]
 	if data == nil {
-		return c.Send(commonMessageInternalError)
+		return c.Send(tr.T("common.internal_error"))
 	}
 	if data.ExitPrice == 0 || data.ExitDate.IsZero() {
-		return c.Send("❌ Data tidak lengkap, silakan masukkan harga exit dan tanggal exit.")
+		return c.Send(tr.T("exit.incomplete"))
 	}
 
 	stopChan := make(chan struct{})
@@ -89,18 +82,18 @@ func (t *TelegramBotService) handleBtnSaveExitPosition(ctx context.Context, c te
 			IsActive:  utils.ToPointer(false),
 		}); err != nil {
 			close(stopChan)
-			if err := c.Send(commonMessageInternalError); err != nil {
+			if err := c.Send(tr.T("common.internal_error")); err != nil {
 				t.logger.WithError(err).Error("Failed to update stock position")
 			}
 		}
 		time.Sleep(1 * time.Second)
 		close(stopChan)
-		t.bot.Edit(msg, "✅ Exit posisi berhasil disimpan.")
+		t.bot.Edit(msg, tr.T("exit.saved"))
 		time.Sleep(1 * time.Second)
 		t.handleMyPositionWithEditMessage(newCtx, c, true)
 
 	}()
-	t.ResetUserState(userID)
+	t.ResetUserState(session)
 
 	return nil
 }
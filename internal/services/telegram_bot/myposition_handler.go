@@ -17,6 +17,7 @@ func (t *TelegramBotService) handleMyPosition(ctx context.Context, c telebot.Con
 
 func (t *TelegramBotService) handleMyPositionWithEditMessage(ctx context.Context, c telebot.Context, isEditMessage bool) error {
 	userID := c.Sender().ID
+	tr := t.translator(c)
 
 	monitoringParam := &models.StockPositionMonitoringQueryParam{
 		ShowNewest: utils.ToPointer(true),
@@ -24,11 +25,11 @@ func (t *TelegramBotService) handleMyPositionWithEditMessage(ctx context.Context
 
 	positions, err := t.stockService.GetStockPositionsTelegramUser(ctx, userID, monitoringParam)
 	if err != nil {
-		return c.Send(commonMessageInternalError)
+		return c.Send(tr.T("common.internal_error"))
 	}
 
 	if len(positions) == 0 {
-		return c.Send("❌ Tidak ada saham aktif yang kamu set position saat ini.")
+		return c.Send(tr.T("myposition.empty"))
 	}
 
 	stockCodes := []string{}
@@ -37,20 +38,18 @@ func (t *TelegramBotService) handleMyPositionWithEditMessage(ctx context.Context
 		stockCodes = append(stockCodes, position.StockCode)
 	}
 
-	lastMarketPriceMap, err := t.getLastMarketPrice(ctx, stockCodes)
+	lastMarketPriceMap, err := t.priceService.GetLastPrices(ctx, stockCodes)
 	if err != nil {
 		t.logger.WithError(err).Error("Failed to get last market prices")
-		return c.Send(commonMessageInternalError)
+		return c.Send(tr.T("common.internal_error"))
 	}
 
 	sb := strings.Builder{}
-	header := `📊 Posisi Saham yang Kamu Pantau Saat ini:`
-	sb.WriteString(header)
+	sb.WriteString(tr.T("myposition.list_header"))
 	sb.WriteString("\n")
-	body := t.FormatMyPositionListMessage(positions, lastMarketPriceMap)
+	body := t.FormatMyPositionListMessage(tr, positions, lastMarketPriceMap)
 	sb.WriteString(body)
-	footer := "\n👉 Tekan tombol di bawah untuk melihat detail lengkap atau mengelola posisi."
-	sb.WriteString(footer)
+	sb.WriteString(tr.T("myposition.list_footer"))
 	menu := &telebot.ReplyMarkup{}
 	rows := []telebot.Row{}
 	var tempRow []telebot.Btn
@@ -64,7 +63,7 @@ func (t *TelegramBotService) handleMyPositionWithEditMessage(ctx context.Context
 		}
 	}
 
-	btnDelete := menu.Data("🗑 Hapus Pesan", btnDeleteMessage.Unique)
+	btnDelete := menu.Data(tr.T(btnDeleteMessage.Text), btnDeleteMessage.Unique)
 
 	if len(tempRow) > 0 {
 		tempRow = append(tempRow, btnDelete)
@@ -85,9 +84,10 @@ func (t *TelegramBotService) handleMyPositionWithEditMessage(ctx context.Context
 
 func (t *TelegramBotService) handleBtnToDetailStockPosition(ctx context.Context, c telebot.Context) error {
 	userID := c.Sender().ID
+	tr := t.translator(c)
 	id, err := strconv.Atoi(c.Data())
 	if err != nil {
-		return c.Send(commonMessageInternalError)
+		return c.Send(tr.T("common.internal_error"))
 	}
 
 	position, err := t.stockService.GetStockPositionWithHistoryMonitoring(ctx, models.StockPositionQueryParam{
@@ -100,33 +100,30 @@ func (t *TelegramBotService) handleBtnToDetailStockPosition(ctx context.Context,
 		},
 	})
 	if err != nil {
-		return c.Send(commonMessageInternalError)
+		return c.Send(tr.T("common.internal_error"))
 	}
 
 	if position == nil {
-		return c.Send("❌ Tidak ada posisi yang ditemukan.")
+		return c.Send(tr.T("myposition.not_found"))
 	}
 
 	// Tombol analisa
 	menu := &telebot.ReplyMarkup{}
-	btn := menu.Data("🔍 Analisa", btnStockPositionMonitoring.Unique, position.StockCode)
-	btnManage := menu.Data(btnManageStockPosition.Text, btnManageStockPosition.Unique, strconv.FormatUint(uint64(position.ID), 10))
-	btnBack := menu.Data(btnBackStockPosition.Text, btnBackStockPosition.Unique)
-	btnNews := menu.Data(btnNewsStockPosition.Text, btnNewsStockPosition.Unique, position.StockCode)
+	btn := menu.Data(tr.T("button.analyze"), btnStockPositionMonitoring.Unique, position.StockCode)
+	btnManage := menu.Data(tr.T(btnManageStockPosition.Text), btnManageStockPosition.Unique, strconv.FormatUint(uint64(position.ID), 10))
+	btnBack := menu.Data(tr.T(btnBackStockPosition.Text), btnBackStockPosition.Unique)
+	btnNews := menu.Data(tr.T(btnNewsStockPosition.Text), btnNewsStockPosition.Unique, position.StockCode)
 
 	menu.Inline(
 		menu.Row(btn, btnManage),
 		menu.Row(btnNews, btnBack),
 	)
-	lastPrices, _ := t.getLastMarketPrice(ctx, []string{position.StockCode})
-	var marketPrice *models.RedisLastPrice
-	if len(lastPrices) > 0 {
-		if val, ok := lastPrices[position.StockCode]; ok {
-			marketPrice = &val
-		}
+	marketPrice, err := t.priceService.GetLastPrice(ctx, position.StockCode)
+	if err != nil {
+		t.logger.WithError(err).Error("Failed to get last market price")
 	}
 
-	return c.Edit(t.FormatMyStockPositionMessage(position, marketPrice), menu, telebot.ModeMarkdown)
+	return c.Edit(t.FormatMyStockPositionMessage(tr, position, marketPrice), menu, telebot.ModeMarkdown)
 }
 
 func (t *TelegramBotService) handleBtnBackStockPosition(ctx context.Context, c telebot.Context) error {
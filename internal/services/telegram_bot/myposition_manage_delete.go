@@ -2,15 +2,15 @@ package telegram_bot
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 
 	"gopkg.in/telebot.v3"
 )
 
 func (t *TelegramBotService) handleBtnDeleteStockPosition(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
 	c.Respond(&telebot.CallbackResponse{
-		Text:      "🔄 Menghapus....",
+		Text:      tr.T("myposition.deleting"),
 		ShowAlert: false,
 	})
 
@@ -18,12 +18,12 @@ func (t *TelegramBotService) handleBtnDeleteStockPosition(ctx context.Context, c
 
 	stockPositionIDInt, err := strconv.Atoi(stockPositionID)
 	if err != nil {
-		return c.Edit(fmt.Sprintf("❌ Gagal mengambil posisi untuk %s: %s", stockPositionID, err.Error()))
+		return c.Edit(tr.T("myposition.get_failed", stockPositionID, err.Error()))
 	}
 
 	if err = t.stockService.DeleteStockPositionTelegramUser(ctx, c.Sender().ID, uint(stockPositionIDInt)); err != nil {
-		return c.Edit(fmt.Sprintf("❌ Gagal menghapus posisi untuk %s: %s", stockPositionID, err.Error()))
+		return c.Edit(tr.T("myposition.delete_failed", stockPositionID, err.Error()))
 	}
 
-	return c.Edit("✅ Posisi saham berhasil dihapus.")
+	return c.Edit(tr.T("myposition.deleted"))
 }
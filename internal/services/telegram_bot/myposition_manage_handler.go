@@ -13,11 +13,12 @@ import (
 )
 
 func (t *TelegramBotService) handleBtnManageStockPosition(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
 	stockPositionID := c.Data()
 
 	stockPositionIDInt, err := strconv.Atoi(stockPositionID)
 	if err != nil {
-		return c.Edit(fmt.Sprintf("❌ Gagal mengambil posisi untuk %s: %s", stockPositionID, err.Error()))
+		return c.Edit(tr.T("myposition.get_failed", stockPositionID, err.Error()))
 	}
 
 	stockPosition, err := t.stockService.GetStockPosition(ctx, models.StockPositionQueryParam{
@@ -25,47 +26,44 @@ func (t *TelegramBotService) handleBtnManageStockPosition(ctx context.Context, c
 		IDs:         []uint{uint(stockPositionIDInt)},
 	})
 	if err != nil {
-		return c.Edit(fmt.Sprintf("❌ Gagal mengambil posisi untuk %s: %s", stockPositionID, err.Error()))
+		return c.Edit(tr.T("myposition.get_failed", stockPositionID, err.Error()))
 	}
 
 	if len(stockPosition) == 0 {
-		return c.Edit(fmt.Sprintf("❌ Tidak ditemukan posisi untuk %s", stockPositionID))
+		return c.Edit(tr.T("myposition.not_found_for", stockPositionID))
 	}
 
 	stockCode := stockPosition[0].StockCode
-	msgText := fmt.Sprintf(
-		"⚙️ Kelola Posisi Saham *%s*\n\nPilih aksi yang ingin kamu lakukan terhadap saham ini 👇\n\n",
-		stockCode,
-	)
+	msgText := tr.T("myposition.manage_title", stockCode)
 
 	// Buat keyboard
 	keyboard := &telebot.ReplyMarkup{}
 
 	// Tombol aksi utama
-	btnExit := keyboard.Data("🚪 Exit dari Posisi", btnExitStockPosition.Unique, fmt.Sprintf("%s|%d", stockCode, stockPositionIDInt))
-	btnDelete := keyboard.Data(btnDeleteStockPosition.Text, btnDeleteStockPosition.Unique, stockPositionID)
+	btnExit := keyboard.Data(tr.T("button.exit_position"), btnExitStockPosition.Unique, fmt.Sprintf("%s|%d", stockCode, stockPositionIDInt))
+	btnDelete := keyboard.Data(tr.T(btnDeleteStockPosition.Text), btnDeleteStockPosition.Unique, stockPositionID)
 
 	// Toggle Alert
 	var btnAlert telebot.Btn
 	isAlertOn := stockPosition[0].PriceAlert != nil && *stockPosition[0].PriceAlert
 	if isAlertOn {
-		btnAlert = keyboard.Data("🔕 Nonaktifkan Alert", btnUpdateAlertPrice.Unique, fmt.Sprintf("%s|false", stockPositionID))
+		btnAlert = keyboard.Data(tr.T("button.alert_off"), btnUpdateAlertPrice.Unique, fmt.Sprintf("%s|false", stockPositionID))
 	} else {
-		btnAlert = keyboard.Data("🔔 Aktifkan Alert", btnUpdateAlertPrice.Unique, fmt.Sprintf("%s|true", stockPositionID))
+		btnAlert = keyboard.Data(tr.T("button.alert_on"), btnUpdateAlertPrice.Unique, fmt.Sprintf("%s|true", stockPositionID))
 	}
 
 	// Toggle Monitoring
 	var btnMonitor telebot.Btn
 	isMonitoringOn := stockPosition[0].MonitorPosition != nil && *stockPosition[0].MonitorPosition
 	if isMonitoringOn {
-		btnMonitor = keyboard.Data("❌ Nonaktifkan Monitoring", btnUpdateAlertMonitor.Unique, fmt.Sprintf("%s|false", stockPositionID))
+		btnMonitor = keyboard.Data(tr.T("button.monitoring_off"), btnUpdateAlertMonitor.Unique, fmt.Sprintf("%s|false", stockPositionID))
 	} else {
-		btnMonitor = keyboard.Data("📡 Aktifkan Monitoring", btnUpdateAlertMonitor.Unique, fmt.Sprintf("%s|true", stockPositionID))
+		btnMonitor = keyboard.Data(tr.T("button.monitoring_on"), btnUpdateAlertMonitor.Unique, fmt.Sprintf("%s|true", stockPositionID))
 	}
 
 	// Tombol kembali
-	btnBack := keyboard.Data(btnBackActionStockPosition.Text, btnBackActionStockPosition.Unique, stockPositionID)
-	btnAdjustTarget := keyboard.Data(btnAdjustTargetPosition.Text, btnAdjustTargetPosition.Unique, stockPositionID)
+	btnBack := keyboard.Data(tr.T(btnBackActionStockPosition.Text), btnBackActionStockPosition.Unique, stockPositionID)
+	btnAdjustTarget := keyboard.Data(tr.T(btnAdjustTargetPosition.Text), btnAdjustTargetPosition.Unique, stockPositionID)
 
 	// Susun tombol: satu per baris
 	keyboard.Inline(
@@ -81,38 +79,34 @@ func (t *TelegramBotService) handleBtnManageStockPosition(ctx context.Context, c
 }
 
 func (t *TelegramBotService) handleBtnExitStockPosition(ctx context.Context, c telebot.Context) error {
-	userID := c.Sender().ID
+	tr := t.translator(c)
+	session := sessionKeyOf(c)
 	data := c.Data()
 
-	userState := t.userStates[userID]
+	userState := t.userStates[session]
 	if userState != StateIdle {
-		t.ResetUserState(userID)
-		return c.Send(commonMessageInternalError)
+		t.ResetUserState(session)
+		return c.Send(tr.T("common.internal_error"))
 	}
 
 	parts := strings.Split(data, "|")
 	if len(parts) != 2 {
-		return c.Edit(commonMessageInternalError, &telebot.ReplyMarkup{}, telebot.ModeMarkdown)
+		return c.Edit(tr.T("common.internal_error"), &telebot.ReplyMarkup{}, telebot.ModeMarkdown)
 	}
 
 	stockPositionIDInt, err := strconv.Atoi(parts[1])
 	if err != nil {
-		return c.Edit(commonMessageInternalError, &telebot.ReplyMarkup{}, telebot.ModeMarkdown)
+		return c.Edit(tr.T("common.internal_error"), &telebot.ReplyMarkup{}, telebot.ModeMarkdown)
 	}
 
-	msg := fmt.Sprintf(`🚀 Exit posisi saham *%s (1/2)*
-
-Masukkan *harga jual* kamu di bawah ini (dalam angka).  
-Contoh: 175.00
-
-`, parts[0])
+	msg := tr.T("exit.prompt_price", parts[0])
 
 	err = c.Edit(msg, &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
 	if err != nil {
-		return c.Edit(commonMessageInternalError, &telebot.ReplyMarkup{}, telebot.ModeMarkdown)
+		return c.Edit(tr.T("common.internal_error"), &telebot.ReplyMarkup{}, telebot.ModeMarkdown)
 	}
-	t.userStates[userID] = StateWaitingExitPositionInputExitPrice
-	t.userExitPositionData[userID] = &models.RequestExitPositionData{
+	t.userStates[session] = StateWaitingExitPositionInputExitPrice
+	t.userExitPositionData[session] = &models.RequestExitPositionData{
 		Symbol:          parts[0],
 		StockPositionID: uint(stockPositionIDInt),
 	}
@@ -121,33 +115,37 @@ Contoh: 175.00
 }
 
 func (t *TelegramBotService) handleBtnUpdateAlertPrice(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
 	data := c.Data()
 	parts := strings.Split(data, "|")
 	if len(parts) != 2 {
-		return c.Edit(commonMessageInternalError, &telebot.ReplyMarkup{}, telebot.ModeMarkdown)
+		return c.Edit(tr.T("common.internal_error"), &telebot.ReplyMarkup{}, telebot.ModeMarkdown)
 	}
 	stockPositionID := parts[0]
 	isAlertOn := parts[1]
 
 	stockPositionIDInt, err := strconv.Atoi(stockPositionID)
 	if err != nil {
-		return c.Edit(fmt.Sprintf("❌ Gagal mengambil posisi untuk %s: %s", stockPositionID, err.Error()))
+		return c.Edit(tr.T("myposition.get_failed", stockPositionID, err.Error()))
 	}
 
 	isAlertOnBool, err := strconv.ParseBool(isAlertOn)
 	if err != nil {
-		return c.Edit(fmt.Sprintf("❌ Gagal parse data untuk %s: %s", stockPositionID, err.Error()))
+		return c.Edit(tr.T("myposition.parse_failed", stockPositionID, err.Error()))
 	}
 
 	if err = t.stockService.UpdateStockPositionTelegramUser(ctx, c.Sender().ID, uint(stockPositionIDInt), &models.StockPositionUpdateRequest{
 		PriceAlert: &isAlertOnBool,
 	}); err != nil {
-		return c.Edit(fmt.Sprintf("❌ Gagal update status alert untuk %s: %s", stockPositionID, err.Error()))
+		if message, ok := quotaExceededMessage(tr, err); ok {
+			return c.Respond(&telebot.CallbackResponse{Text: message, ShowAlert: true})
+		}
+		return c.Edit(tr.T("myposition.update_alert_failed", stockPositionID, err.Error()))
 	}
 
-	isActive := "✅ Alert Price berhasil diaktifkan."
+	isActive := tr.T("myposition.alert_enabled")
 	if !isAlertOnBool {
-		isActive = "❌ Alert Price berhasil dinonaktifkan."
+		isActive = tr.T("myposition.alert_disabled")
 	}
 
 	c.Edit(isActive)
@@ -156,33 +154,37 @@ func (t *TelegramBotService) handleBtnUpdateAlertPrice(ctx context.Context, c te
 }
 
 func (t *TelegramBotService) handleBtnUpdateAlertMonitor(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
 	data := c.Data()
 	parts := strings.Split(data, "|")
 	if len(parts) != 2 {
-		return c.Edit(commonMessageInternalError, &telebot.ReplyMarkup{}, telebot.ModeMarkdown)
+		return c.Edit(tr.T("common.internal_error"), &telebot.ReplyMarkup{}, telebot.ModeMarkdown)
 	}
 	stockPositionID := parts[0]
 	isMonitorOn := parts[1]
 
 	stockPositionIDInt, err := strconv.Atoi(stockPositionID)
 	if err != nil {
-		return c.Edit(fmt.Sprintf("❌ Gagal mengambil posisi untuk %s: %s", stockPositionID, err.Error()))
+		return c.Edit(tr.T("myposition.get_failed", stockPositionID, err.Error()))
 	}
 
 	isMonitorOnBool, err := strconv.ParseBool(isMonitorOn)
 	if err != nil {
-		return c.Edit(fmt.Sprintf("❌ Gagal parse data untuk %s: %s", stockPositionID, err.Error()))
+		return c.Edit(tr.T("myposition.parse_failed", stockPositionID, err.Error()))
 	}
 
 	if err = t.stockService.UpdateStockPositionTelegramUser(ctx, c.Sender().ID, uint(stockPositionIDInt), &models.StockPositionUpdateRequest{
 		MonitorPosition: &isMonitorOnBool,
 	}); err != nil {
-		return c.Edit(fmt.Sprintf("❌ Gagal update status monitoring untuk %s: %s", stockPositionID, err.Error()))
+		if message, ok := quotaExceededMessage(tr, err); ok {
+			return c.Respond(&telebot.CallbackResponse{Text: message, ShowAlert: true})
+		}
+		return c.Edit(tr.T("myposition.update_monitoring_failed", stockPositionID, err.Error()))
 	}
 
-	isActive := "✅ Stock Monitoring berhasil diaktifkan."
+	isActive := tr.T("myposition.monitoring_enabled")
 	if !isMonitorOnBool {
-		isActive = "❌ Stock Monitoring berhasil dinonaktifkan."
+		isActive = tr.T("myposition.monitoring_disabled")
 	}
 
 	c.Edit(isActive)
@@ -196,6 +198,7 @@ func (t *TelegramBotService) handleBtnBackActionStockPosition(ctx context.Contex
 }
 
 func (t *TelegramBotService) handleBtnBackDetailStockPositionWithParam(ctx context.Context, c telebot.Context, symbol *string, stockPosisitionID *uint) error {
+	tr := t.translator(c)
 	senderID := c.Sender().ID
 
 	param := models.StockPositionQueryParam{
@@ -210,32 +213,29 @@ func (t *TelegramBotService) handleBtnBackDetailStockPositionWithParam(ctx conte
 	}
 	positions, err := t.stockService.GetStockPosition(ctx, param)
 	if err != nil {
-		return c.Send(commonMessageInternalError)
+		return c.Send(tr.T("common.internal_error"))
 	}
 
 	if len(positions) == 0 {
-		return c.Send("❌ Tidak ada posisi yang ditemukan.")
+		return c.Send(tr.T("myposition.not_found"))
 	}
 
 	// Tombol analisa
 	menu := &telebot.ReplyMarkup{}
-	btn := menu.Data("🔍 Analisa", btnStockPositionMonitoring.Unique, positions[0].StockCode)
-	btnManage := menu.Data(btnManageStockPosition.Text, btnManageStockPosition.Unique, strconv.FormatUint(uint64(positions[0].ID), 10))
-	btnBack := menu.Data(btnBackStockPosition.Text, btnBackStockPosition.Unique)
-	btnNews := menu.Data(btnNewsStockPosition.Text, btnNewsStockPosition.Unique, positions[0].StockCode)
+	btn := menu.Data(tr.T("button.analyze"), btnStockPositionMonitoring.Unique, positions[0].StockCode)
+	btnManage := menu.Data(tr.T(btnManageStockPosition.Text), btnManageStockPosition.Unique, strconv.FormatUint(uint64(positions[0].ID), 10))
+	btnBack := menu.Data(tr.T(btnBackStockPosition.Text), btnBackStockPosition.Unique)
+	btnNews := menu.Data(tr.T(btnNewsStockPosition.Text), btnNewsStockPosition.Unique, positions[0].StockCode)
 
 	menu.Inline(
 		menu.Row(btn, btnManage),
 		menu.Row(btnNews, btnBack),
 	)
 
-	lastPrices, _ := t.getLastMarketPrice(ctx, []string{positions[0].StockCode})
-	var marketPrice *models.RedisLastPrice
-	if len(lastPrices) > 0 {
-		if val, ok := lastPrices[positions[0].StockCode]; ok {
-			marketPrice = &val
-		}
+	marketPrice, err := t.priceService.GetLastPrice(ctx, positions[0].StockCode)
+	if err != nil {
+		t.logger.WithError(err).Error("Failed to get last market price")
 	}
 
-	return c.Edit(t.FormatMyStockPositionMessage(&positions[0], marketPrice), menu, telebot.ModeMarkdown)
+	return c.Edit(t.FormatMyStockPositionMessage(tr, &positions[0], marketPrice), menu, telebot.ModeMarkdown)
 }
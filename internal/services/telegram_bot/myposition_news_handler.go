@@ -2,12 +2,12 @@ package telegram_bot
 
 import (
 	"context"
-	"fmt"
 
 	"gopkg.in/telebot.v3"
 )
 
 func (t *TelegramBotService) handleBtnNewsStockPosition(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
 	stockCode := c.Data()
 	summary, err := t.stockService.GetLastStockNewsSummary(ctx, t.config.FeatureNewsMaxAgeInDays, stockCode)
 	if err != nil {
@@ -15,10 +15,10 @@ func (t *TelegramBotService) handleBtnNewsStockPosition(ctx context.Context, c t
 	}
 
 	if summary == nil {
-		t.telegramRateLimiter.Edit(ctx, c, c.Message(), fmt.Sprintf("Maaf, saat ini belum tersedia berita untuk saham %s coba lagi nanti.", stockCode), telebot.ModeMarkdown)
+		t.telegramRateLimiter.Edit(ctx, c, c.Message(), tr.T("news.summary_not_available", stockCode), telebot.ModeMarkdown)
 		return nil
 	}
-	msg := t.formatMessageNewsSummary(summary)
+	msg := t.formatMessageNewsSummary(tr, summary)
 	_, err = t.telegramRateLimiter.Edit(ctx, c, c.Message(), msg, telebot.ModeMarkdown)
 	if err != nil {
 		return err
@@ -11,11 +11,12 @@ import (
 )
 
 func (t *TelegramBotService) handleNews(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
 	menu := &telebot.ReplyMarkup{}
 
-	btnFind := menu.Data(btnActionNewsFind.Text, btnActionNewsFind.Unique)
-	btnTopNews := menu.Data(btnActionTopNews.Text, btnActionTopNews.Unique)
-	btnDeleteForCancel := menu.Data(btnCancelGeneral.Text, btnDeleteMessage.Unique)
+	btnFind := menu.Data(tr.T(btnActionNewsFind.Text), btnActionNewsFind.Unique)
+	btnTopNews := menu.Data(tr.T(btnActionTopNews.Text), btnActionTopNews.Unique)
+	btnDeleteForCancel := menu.Data(tr.T(btnCancelGeneral.Text), btnDeleteMessage.Unique)
 
 	menu.Inline(
 		menu.Row(btnFind),
@@ -23,49 +24,60 @@ func (t *TelegramBotService) handleNews(ctx context.Context, c telebot.Context)
 		menu.Row(btnDeleteForCancel),
 	)
 
-	t.telegramRateLimiter.Send(ctx, c, t.formatMessageMenuNews(), menu, telebot.ModeMarkdown)
+	t.telegramRateLimiter.Send(ctx, c, t.formatMessageMenuNews(tr), menu, telebot.ModeMarkdown)
 	return nil
 }
 
 func (t *TelegramBotService) handleBtnActionNewsFind(ctx context.Context, c telebot.Context) error {
-	t.ResetUserState(c.Sender().ID)
-	t.userStates[c.Sender().ID] = StateWaitingNewsFindSymbol
-	msg := `🔍 Silakan masukkan kode saham yang ingin kamu cari berita
-(contoh: BBRI, TLKM, ANTM)
-`
+	tr := t.translator(c)
+	session := sessionKeyOf(c)
+	t.ResetUserState(session)
+	t.userStates[session] = StateWaitingNewsFindSymbol
+	msg := tr.T("news.find_prompt")
 	_, err := t.telegramRateLimiter.Edit(ctx, c, c.Message(), msg, telebot.ModeMarkdown)
 	return err
 }
 
 func (t *TelegramBotService) handleNewsFindConversation(ctx context.Context, c telebot.Context) error {
-	userID := c.Sender().ID
-	state := t.userStates[userID]
+	tr := t.translator(c)
+	session := sessionKeyOf(c)
+	state := t.userStates[session]
 
 	switch state {
 	case StateWaitingNewsFindSymbol:
 		return t.handleNewsFind(ctx, c)
 	case StateWaitingNewsFindSendSummaryConfirmation:
-		return c.Send("👆 Silakan pilih salah satu opsi di atas, atau kirim /cancel untuk membatalkan.")
+		return c.Send(tr.T("common.choose_option"))
 	default:
 		return t.handleCancel(c)
 	}
 }
 
 func (t *TelegramBotService) handleNewsFind(ctx context.Context, c telebot.Context) error {
-	userID := c.Sender().ID
-	text := strings.ToUpper(c.Text())
-	state := t.userStates[userID]
-	var err error
+	session := sessionKeyOf(c)
+	state := t.userStates[session]
 
 	if state != StateWaitingNewsFindSymbol {
 		return t.handleCancel(c)
 	}
 
+	text, ok := t.resolveSymbolInput(ctx, c, c.Text())
+	if !ok {
+		return nil
+	}
+	return t.findNews(ctx, c, text)
+}
+
+func (t *TelegramBotService) findNews(ctx context.Context, c telebot.Context, text string) error {
+	tr := t.translator(c)
+	session := sessionKeyOf(c)
+	var err error
+
 	age := t.config.FeatureNewsMaxAgeInDays
 
 	defer func() {
 		if err != nil {
-			t.ResetUserState(userID)
+			t.ResetUserState(session)
 		}
 	}()
 
@@ -79,11 +91,11 @@ func (t *TelegramBotService) handleNewsFind(ctx context.Context, c telebot.Conte
 	}
 
 	if len(news) == 0 {
-		_, err = t.telegramRateLimiter.Send(ctx, c, "Maaf, tidak ada data berita ditemukan untuk kode saham tersebut.", telebot.ModeMarkdown)
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("news.not_found"), telebot.ModeMarkdown)
 		return err
 	}
 
-	msg := t.formatMessageNewsList(news, age)
+	msg := t.formatMessageNewsList(tr, news, age)
 	_, err = t.telegramRateLimiter.Send(ctx, c, msg, telebot.ModeMarkdown)
 	if err != nil {
 		return err
@@ -95,15 +107,15 @@ func (t *TelegramBotService) handleNewsFind(ctx context.Context, c telebot.Conte
 	}
 
 	if summary == nil {
-		t.ResetUserState(userID)
+		t.ResetUserState(session)
 		return nil
 	}
 
-	confirmSendSummaryMsg := fmt.Sprintf("📚 Saya telah menampilkan beberapa berita penting untuk saham %s. \n\nIngin melihat ringkasan analisis, sentimen, dan saran terkait saham ini?", text)
+	confirmSendSummaryMsg := tr.T("news.summary_confirm", text)
 
 	menu := &telebot.ReplyMarkup{}
-	btnConfirm := menu.Data("✅ Iya", btnNewsConfirmSendSummary.Unique, fmt.Sprintf("%s|%t", text, true))
-	btnReject := menu.Data("❌ Tidak", btnNewsConfirmSendSummary.Unique, fmt.Sprintf("%s|%t", text, false))
+	btnConfirm := menu.Data(tr.T("button.yes"), btnNewsConfirmSendSummary.Unique, fmt.Sprintf("%s|%t", text, true))
+	btnReject := menu.Data(tr.T("button.no"), btnNewsConfirmSendSummary.Unique, fmt.Sprintf("%s|%t", text, false))
 	menu.Inline(menu.Row(btnConfirm, btnReject))
 
 	time.Sleep(1 * time.Second)
@@ -112,18 +124,19 @@ func (t *TelegramBotService) handleNewsFind(ctx context.Context, c telebot.Conte
 		return err
 	}
 
-	t.userStates[userID] = StateWaitingNewsFindSendSummaryConfirmation
+	t.userStates[session] = StateWaitingNewsFindSendSummaryConfirmation
 	return nil
 }
 
 func (t *TelegramBotService) handleBtnNewsConfirmSendSummary(ctx context.Context, c telebot.Context) error {
-	userID := c.Sender().ID
-	state := t.userStates[userID]
+	tr := t.translator(c)
+	session := sessionKeyOf(c)
+	state := t.userStates[session]
 	if state != StateWaitingNewsFindSendSummaryConfirmation {
 		return t.handleCancel(c)
 	}
 
-	defer t.ResetUserState(userID)
+	defer t.ResetUserState(session)
 
 	data := strings.Split(c.Data(), "|")
 	if len(data) != 2 {
@@ -142,10 +155,10 @@ func (t *TelegramBotService) handleBtnNewsConfirmSendSummary(ctx context.Context
 	}
 
 	if summary == nil {
-		t.telegramRateLimiter.Edit(ctx, c, c.Message(), "Maaf, tidak ada data berita ditemukan untuk kode saham tersebut.", telebot.ModeMarkdown)
+		t.telegramRateLimiter.Edit(ctx, c, c.Message(), tr.T("news.not_found"), telebot.ModeMarkdown)
 		return nil
 	}
-	msg := t.formatMessageNewsSummary(summary)
+	msg := t.formatMessageNewsSummary(tr, summary)
 	_, err = t.telegramRateLimiter.Edit(ctx, c, c.Message(), msg, telebot.ModeMarkdown)
 	if err != nil {
 		return err
@@ -12,7 +12,7 @@ func (t *TelegramBotService) handleBtnActionTopNews(ctx context.Context, c teleb
 		return err
 	}
 
-	msg := t.formatMessageTopNewsList(news)
+	msg := t.formatMessageTopNewsList(t.translator(c), news)
 	_, err = t.telegramRateLimiter.Send(ctx, c, msg, telebot.ModeHTML)
 	if err != nil {
 		return err
@@ -0,0 +1,110 @@
+package telegram_bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang-swing-trading-signal/internal/i18n"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/quota"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+func (t *TelegramBotService) handleQuota(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	status, err := t.quotaService.GetStatus(ctx, c.Sender().ID)
+	if err != nil {
+		t.logger.Error("failed to get quota status", logrus.Fields{
+			"user_id": c.Sender().ID,
+			"error":   err,
+		})
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	_, err = t.telegramRateLimiter.Send(ctx, c, formatQuotaStatus(tr, status), telebot.ModeHTML)
+	return err
+}
+
+// editRequestAnalysisError mengganti pesan loading jika permintaan analisa on-demand gagal dikirim.
+func (t *TelegramBotService) editRequestAnalysisError(ctx context.Context, c telebot.Context, msg *telebot.Message, symbol string, err error) {
+	tr := t.translator(c)
+	message, ok := quotaExceededMessage(tr, err)
+	if !ok {
+		t.logger.WithError(err).WithField("symbol", symbol).Error("Failed to request analysis")
+		message = tr.T("analysis.failed", symbol)
+	}
+
+	if _, err := t.telegramRateLimiter.Edit(ctx, c, msg, message); err != nil {
+		t.logger.WithError(err).Error("Failed to edit message")
+	}
+}
+
+// quotaExceededMessage mengembalikan pesan untuk user jika err adalah kuota yang habis.
+func quotaExceededMessage(tr i18n.Translator, err error) (string, bool) {
+	var quotaErr *quota.QuotaExceededError
+	if !errors.As(err, &quotaErr) {
+		return "", false
+	}
+
+	message := tr.T("quota.exceeded", tr.T(quotaLabel(quotaErr.Kind)), planLabel(quotaErr.Plan), quotaErr.Limit)
+	if quotaErr.ResetAt != nil {
+		message += tr.T("quota.exceeded_reset", tr.DateTime(*quotaErr.ResetAt))
+	}
+	message += tr.T("quota.exceeded_footer")
+	return message, true
+}
+
+func formatQuotaStatus(tr i18n.Translator, status *models.QuotaStatus) string {
+	var sb strings.Builder
+	sb.WriteString(tr.T("quota.title", planLabel(status.Plan)))
+	if status.PlanExpiresAt != nil {
+		sb.WriteString(tr.T("quota.plan_expires", tr.DateTime(utils.TimeToWIB(*status.PlanExpiresAt))))
+	}
+	if status.Unlimited {
+		sb.WriteString(tr.T("quota.admin_unlimited"))
+	}
+	sb.WriteString("\n")
+
+	for _, item := range status.Items {
+		label := tr.T(quotaLabel(item.Kind))
+		sb.WriteString(fmt.Sprintf("• %s: ", strings.ToUpper(label[:1])+label[1:]))
+		if item.Unlimited() {
+			sb.WriteString(tr.T("quota.item_unlimited", item.Used))
+			continue
+		}
+		sb.WriteString(tr.T("quota.item_usage", item.Used, item.Limit, item.Remaining()))
+		if item.ResetAt != nil {
+			sb.WriteString(tr.T("quota.item_reset", tr.DateTime(*item.ResetAt)))
+		}
+	}
+	return sb.String()
+}
+
+// quotaLabel mengembalikan key katalog i18n untuk jenis kuota.
+func quotaLabel(kind string) string {
+	switch kind {
+	case models.QuotaAnalysis:
+		return "quota.label_analysis"
+	case models.QuotaActivePosition:
+		return "quota.label_active_position"
+	case models.QuotaAlert:
+		return "quota.label_alert"
+	case models.QuotaWatchlist:
+		return "quota.label_watchlist"
+	default:
+		return kind
+	}
+}
+
+func planLabel(plan models.Plan) string {
+	if plan == models.PlanPro {
+		return "Pro"
+	}
+	return "Free"
+}
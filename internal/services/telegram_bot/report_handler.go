@@ -9,6 +9,7 @@ import (
 )
 
 func (t *TelegramBotService) handleReport(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
 	telegramID := c.Sender().ID
 
 	param := models.StockPositionQueryParam{
@@ -19,7 +20,7 @@ func (t *TelegramBotService) handleReport(ctx context.Context, c telebot.Context
 	stockPositions, err := t.stockService.GetStockPosition(ctx, param)
 	if err != nil {
 		t.logger.WithError(err).Error("Failed to get stock positions for report")
-		_, errSend := t.telegramRateLimiter.Send(ctx, c, commonMessageInternalError)
+		_, errSend := t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
 		if errSend != nil {
 			t.logger.WithError(errSend).Error("Failed to send internal error message")
 		}
@@ -27,14 +28,14 @@ func (t *TelegramBotService) handleReport(ctx context.Context, c telebot.Context
 	}
 
 	if len(stockPositions) == 0 {
-		_, errSend := t.telegramRateLimiter.Send(ctx, c, t.formatMessageReportNotExits(), telebot.ModeMarkdown)
+		_, errSend := t.telegramRateLimiter.Send(ctx, c, t.formatMessageReportNotExits(tr), telebot.ModeMarkdown)
 		if errSend != nil {
 			t.logger.WithError(errSend).Error("Failed to send no exit positions message")
 		}
 		return errSend
 	}
 
-	reportMessage := t.formatMessageReport(stockPositions)
+	reportMessage := t.formatMessageReport(tr, stockPositions)
 	_, errSend := t.telegramRateLimiter.Send(ctx, c, reportMessage, telebot.ModeHTML)
 	if errSend != nil {
 		t.logger.WithError(errSend).Error("Failed to send report message")
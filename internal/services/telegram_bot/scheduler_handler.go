@@ -2,44 +2,47 @@ package telegram_bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"golang-swing-trading-signal/internal/i18n"
 	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/jobs"
 	"golang-swing-trading-signal/internal/utils"
+	"html"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/telebot.v3"
 )
 
 func (t *TelegramBotService) handleScheduler(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
 
-	jobs, err := t.jobService.Get(ctx, &models.GetJobParam{
-		IsActive: utils.ToPointer(true),
-	})
+	jobs, err := t.jobService.Get(ctx, &models.GetJobParam{})
 	if err != nil {
 		t.logger.Error("failed to get jobs", logrus.Fields{
 			"error": err,
 		})
-		_, err = t.telegramRateLimiter.Send(ctx, c, commonMessageInternalError)
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
 		return err
 	}
 
 	if len(jobs) == 0 {
-		_, err = t.telegramRateLimiter.Send(ctx, c, "Tidak ada job yang aktif.")
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("scheduler.no_jobs"))
 		return err
 	}
 
 	msg := strings.Builder{}
-	msg.WriteString("📋 Daftar Scheduler Aktif:\n\n")
-	msg.WriteString("Pilih job yang ingin kamu lihat:\n\n")
+	msg.WriteString(tr.T("scheduler.list_title"))
 	for idx, job := range jobs {
-		msg.WriteString(fmt.Sprintf("<b>%d. %s</b>\n", idx+1, job.Name))
+		msg.WriteString(fmt.Sprintf("<b>%d. %s %s</b>\n", idx+1, jobStatusIcon(job), job.Name))
 		msg.WriteString(fmt.Sprintf("  - %s\n", job.Description))
 		msg.WriteString("\n")
 	}
 	msg.WriteString("\n")
-	msg.WriteString("<i>👉 Tekan tombol di bawah untuk lihat detail dan jalankan manual</i>\n")
+	msg.WriteString(tr.T("scheduler.list_hint"))
 
 	menu := &telebot.ReplyMarkup{}
 	rows := []telebot.Row{}
@@ -48,7 +51,7 @@ func (t *TelegramBotService) handleScheduler(ctx context.Context, c telebot.Cont
 		btn := menu.Data(job.Name, btnDetailJob.Unique, fmt.Sprintf("%d", job.ID))
 		rows = append(rows, menu.Row(btn))
 	}
-	rows = append(rows, menu.Row(menu.Data(btnDeleteMessage.Text, btnDeleteMessage.Unique)))
+	rows = append(rows, menu.Row(menu.Data(tr.T(btnDeleteMessage.Text), btnDeleteMessage.Unique)))
 	menu.Inline(rows...)
 
 	msgExist := c.Message()
@@ -66,18 +69,22 @@ func (t *TelegramBotService) handleBtnActionBackToJobList(ctx context.Context, c
 }
 
 func (t *TelegramBotService) handleBtnDetailJob(ctx context.Context, c telebot.Context) error {
-	jobID := c.Data()
-	jobIDInt, err := strconv.Atoi(jobID)
+	tr := t.translator(c)
+	jobID, err := strconv.Atoi(c.Data())
 	if err != nil {
 		t.logger.Error("failed to convert job id to int", logrus.Fields{
 			"error": err,
 		})
-		_, err = t.telegramRateLimiter.Send(ctx, c, commonMessageInternalError)
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
 		return err
 	}
+	return t.showJobDetail(ctx, c, uint(jobID))
+}
 
+func (t *TelegramBotService) showJobDetail(ctx context.Context, c telebot.Context, jobID uint) error {
+	tr := t.translator(c)
 	jobs, err := t.jobService.Get(ctx, &models.GetJobParam{
-		IDs: []uint{uint(jobIDInt)},
+		IDs: []uint{jobID},
 		WithTaskHistory: &models.GetTaskExecutionHistoryParam{
 			Limit: utils.ToPointer(5),
 		},
@@ -86,12 +93,12 @@ func (t *TelegramBotService) handleBtnDetailJob(ctx context.Context, c telebot.C
 		t.logger.Error("failed to get job by id", logrus.Fields{
 			"error": err,
 		})
-		_, err = t.telegramRateLimiter.Send(ctx, c, commonMessageInternalError)
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
 		return err
 	}
 
 	if len(jobs) == 0 {
-		_, err = t.telegramRateLimiter.Send(ctx, c, "Job tidak ditemukan.")
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("scheduler.job_not_found"))
 		return err
 	}
 
@@ -101,49 +108,63 @@ func (t *TelegramBotService) handleBtnDetailJob(ctx context.Context, c telebot.C
 	msg.WriteString(fmt.Sprintf("%s\n\n", job.Name))
 	msg.WriteString(fmt.Sprintf("🔍 %s\n\n", job.Description))
 
-	msg.WriteString("📅 Jadwal: \n")
-	if job.Schedules[0].LastExecution.Valid {
-		msg.WriteString(fmt.Sprintf(" • Last Execution : %s\n", utils.PrettyDateWithIcon(utils.TimeToWIB(job.Schedules[0].LastExecution.Time))))
+	msg.WriteString(tr.T("scheduler.schedule_title"))
+	if len(job.Schedules) == 0 {
+		// stage pipeline tanpa schedule sendiri
+		msg.WriteString(tr.T("scheduler.pipeline_stage"))
 	} else {
-		msg.WriteString(" • Last Execution : Tidak ada\n")
-	}
-	if job.Schedules[0].NextExecution.Valid {
-		msg.WriteString(fmt.Sprintf(" • Next Execution : %s\n", utils.PrettyDateWithIcon(utils.TimeToWIB(job.Schedules[0].NextExecution.Time))))
-	} else {
-		msg.WriteString(" • Next Execution : Tidak ada\n")
+		schedule := job.Schedules[0]
+		if schedule.IsActive {
+			msg.WriteString(tr.T("scheduler.status_active"))
+		} else {
+			msg.WriteString(tr.T("scheduler.status_paused"))
+		}
+		msg.WriteString(fmt.Sprintf(" • Cron : <code>%s</code>\n", html.EscapeString(schedule.CronExpression)))
+		lastExecution := tr.T("scheduler.none")
+		if schedule.LastExecution.Valid {
+			lastExecution = tr.DateTime(utils.TimeToWIB(schedule.LastExecution.Time))
+		}
+		msg.WriteString(tr.T("scheduler.last_execution", lastExecution))
+		nextExecution := tr.T("scheduler.none")
+		if schedule.IsActive && schedule.NextExecution.Valid {
+			nextExecution = tr.DateTime(utils.TimeToWIB(schedule.NextExecution.Time))
+		}
+		msg.WriteString(tr.T("scheduler.next_execution", nextExecution))
 	}
-
 	msg.WriteString("\n")
-	msg.WriteString("📜 Riwayat Eksekusi Terakhir:\n")
+	msg.WriteString(tr.T("scheduler.recent_history"))
 	for idx, history := range job.Histories {
-
-		icon := "🟢"
-		if history.Status == models.StatusRunning {
-			icon = "🟡"
-		} else if history.Status == models.StatusCompleted {
-			icon = "🟢"
-		} else if history.Status == models.StatusFailed {
-			icon = "🔴"
-		} else if history.Status == models.StatusTimeout {
-			icon = "🟠"
-		}
-
 		if history.CreatedAt.IsZero() {
 			continue
 		}
-		if !history.CompletedAt.Valid {
-			msg.WriteString(fmt.Sprintf("%d. %s %s - %s\n", idx+1, icon, utils.TimeToWIB(history.CreatedAt).Format("01/02 15:04"), strings.ToUpper(string(history.Status))))
-			continue
-		}
-		duration := history.CompletedAt.Time.Sub(history.StartedAt)
-		msg.WriteString(fmt.Sprintf("%d. %s %s - %s (%.1fs)\n", idx+1, icon, utils.TimeToWIB(history.CreatedAt).Format("01/02 15:04"), strings.ToUpper(string(history.Status)), duration.Seconds()))
+		msg.WriteString(fmt.Sprintf("%d. %s\n", idx+1, formatHistoryLine(tr, history)))
 	}
 
 	menu := &telebot.ReplyMarkup{}
 	rows := []telebot.Row{}
-	btnBackJobList := menu.Data(btnActionBackToJobList.Text, btnActionBackToJobList.Unique)
-	btnRun := menu.Data(btnActionRunJob.Text, btnActionRunJob.Unique, fmt.Sprintf("%d", job.ID))
-	rows = append(rows, menu.Row(btnRun, btnBackJobList))
+	data := fmt.Sprintf("%d", job.ID)
+	btnHistory := menu.Data(tr.T(btnJobHistory.Text), btnJobHistory.Unique, fmt.Sprintf("%d|0", job.ID))
+	if len(job.Schedules) > 0 {
+		btnToggle := menu.Data(tr.T(btnActionPauseJob.Text), btnActionPauseJob.Unique, data)
+		if !job.Schedules[0].IsActive {
+			btnToggle = menu.Data(tr.T(btnActionResumeJob.Text), btnActionResumeJob.Unique, data)
+		}
+		rows = append(rows, menu.Row(menu.Data(tr.T(btnActionRunJob.Text), btnActionRunJob.Unique, data), btnToggle))
+		rows = append(rows, menu.Row(menu.Data(tr.T(btnActionEditJobCron.Text), btnActionEditJobCron.Unique, data), btnHistory))
+	} else {
+		rows = append(rows, menu.Row(btnHistory))
+	}
+	isPipeline, err := t.jobService.IsPipeline(ctx, job)
+	if err != nil {
+		t.logger.Error("failed to check job pipeline", logrus.Fields{
+			"job_id": job.ID,
+			"error":  err,
+		})
+	}
+	if isPipeline {
+		rows = append(rows, menu.Row(menu.Data(tr.T(btnJobPipeline.Text), btnJobPipeline.Unique, data)))
+	}
+	rows = append(rows, menu.Row(menu.Data(tr.T(btnActionBackToJobList.Text), btnActionBackToJobList.Unique)))
 	menu.Inline(rows...)
 
 	_, err = t.telegramRateLimiter.Edit(ctx, c, c.Message(), msg.String(), menu, telebot.ModeHTML)
@@ -151,13 +172,14 @@ func (t *TelegramBotService) handleBtnDetailJob(ctx context.Context, c telebot.C
 }
 
 func (t *TelegramBotService) handleBtnActionRunJob(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
 	jobID := c.Data()
 	jobIDInt, err := strconv.Atoi(jobID)
 	if err != nil {
 		t.logger.Error("failed to convert job id to int", logrus.Fields{
 			"error": err,
 		})
-		_, err = t.telegramRateLimiter.Send(ctx, c, commonMessageInternalError)
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
 		return err
 	}
 
@@ -168,26 +190,476 @@ func (t *TelegramBotService) handleBtnActionRunJob(ctx context.Context, c telebo
 		t.logger.Error("failed to get job by id", logrus.Fields{
 			"error": err,
 		})
-		_, err = t.telegramRateLimiter.Send(ctx, c, commonMessageInternalError)
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
 		return err
 	}
 
 	if len(job) == 0 {
-		_, err = t.telegramRateLimiter.Send(ctx, c, "Job tidak ditemukan.")
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("scheduler.job_not_found"))
 		return err
 	}
+	if len(job[0].Schedules) > 0 && !job[0].Schedules[0].IsActive {
+		// scheduler hanya meng-claim schedule aktif
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T("scheduler.job_paused_alert"), ShowAlert: true})
+	}
 
 	if err := t.jobService.RunJobTask(ctx, uint(job[0].ID)); err != nil {
 		t.logger.Error("failed to run job task", logrus.Fields{
 			"error": err,
 		})
-		_, err = t.telegramRateLimiter.Send(ctx, c, commonMessageInternalError)
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+	_, err = t.telegramRateLimiter.Edit(ctx, c, c.Message(), tr.T("scheduler.job_running", job[0].Name), telebot.ModeHTML)
+
+	return err
+}
+
+func (t *TelegramBotService) handleBtnActionPauseJob(ctx context.Context, c telebot.Context) error {
+	return t.setJobActive(ctx, c, false)
+}
+
+func (t *TelegramBotService) handleBtnActionResumeJob(ctx context.Context, c telebot.Context) error {
+	return t.setJobActive(ctx, c, true)
+}
+
+func (t *TelegramBotService) setJobActive(ctx context.Context, c telebot.Context, isActive bool) error {
+	tr := t.translator(c)
+	jobID, err := strconv.Atoi(c.Data())
+	if err != nil {
+		t.logger.Error("failed to convert job id to int", logrus.Fields{
+			"error": err,
+		})
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	if err := t.jobService.SetActive(ctx, uint(jobID), isActive); err != nil {
+		t.logger.Error("failed to set job active", logrus.Fields{
+			"job_id":    jobID,
+			"is_active": isActive,
+			"error":     err,
+		})
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	respond := tr.T("scheduler.job_paused")
+	if isActive {
+		respond = tr.T("scheduler.job_resumed")
+	}
+	_ = c.Respond(&telebot.CallbackResponse{Text: respond})
+	return t.showJobDetail(ctx, c, uint(jobID))
+}
+
+func (t *TelegramBotService) handleBtnActionEditJobCron(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	session := sessionKeyOf(c)
+	t.ResetUserState(session)
+
+	jobID, err := strconv.Atoi(c.Data())
+	if err != nil {
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	jobs, err := t.jobService.Get(ctx, &models.GetJobParam{IDs: []uint{uint(jobID)}})
+	if err != nil {
+		t.logger.Error("failed to get job by id", logrus.Fields{
+			"error": err,
+		})
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+	if len(jobs) == 0 || len(jobs[0].Schedules) == 0 {
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("scheduler.job_not_found"))
+		return err
+	}
+
+	t.mu.Lock()
+	t.userStates[session] = StateWaitingSchedulerCronExpression
+	t.userSchedulerCronData[session] = &models.RequestSchedulerCronData{
+		JobID:   jobs[0].ID,
+		JobName: jobs[0].Name,
+	}
+	t.mu.Unlock()
+
+	msg := tr.T("scheduler.edit_cron_prompt", jobs[0].Name, html.EscapeString(jobs[0].Schedules[0].CronExpression))
+
+	_, err = t.telegramRateLimiter.Edit(ctx, c, c.Message(), msg, telebot.ModeHTML)
+	return err
+}
+
+func (t *TelegramBotService) handleSchedulerCronConversation(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	session := sessionKeyOf(c)
+	state := t.userStates[session]
+
+	data, ok := t.userSchedulerCronData[session]
+	if !ok {
+		t.ResetUserState(session)
+		_, err := t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	switch state {
+	case StateWaitingSchedulerCronExpression:
+		expression := strings.TrimSpace(c.Text())
+		nextRuns, err := t.jobService.PreviewSchedule(expression, 5)
+		if err != nil {
+			_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("scheduler.invalid_cron", html.EscapeString(err.Error())), telebot.ModeHTML)
+			return err
+		}
+		data.CronExpression = expression
+		t.userStates[session] = StateWaitingSchedulerCronConfirm
+
+		msg := strings.Builder{}
+		msg.WriteString(tr.T("scheduler.confirm_cron", data.JobName, html.EscapeString(expression)))
+		msg.WriteString(tr.Plural("scheduler.next_runs", len(nextRuns)))
+		for idx, next := range nextRuns {
+			msg.WriteString(fmt.Sprintf("%d. %s\n", idx+1, tr.DateTime(utils.TimeToWIB(next))))
+		}
+		msg.WriteString(tr.T("scheduler.confirm_cron_hint"))
+
+		menu := &telebot.ReplyMarkup{}
+		menu.Inline(
+			menu.Row(menu.Data(tr.T(btnActionEditJobCronConfirm.Text), btnActionEditJobCronConfirm.Unique, fmt.Sprintf("%d", data.JobID))),
+			menu.Row(button(tr, btnCancelGeneral)),
+		)
+		_, err = t.telegramRateLimiter.Send(ctx, c, msg.String(), menu, telebot.ModeHTML)
+		return err
+	case StateWaitingSchedulerCronConfirm:
+		return c.Send(tr.T("common.choose_option"))
+	default:
+		t.ResetUserState(session)
+		return c.Send(tr.T("scheduler.state_not_found"))
+	}
+}
+
+func (t *TelegramBotService) handleBtnActionEditJobCronConfirm(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	session := sessionKeyOf(c)
+	data, ok := t.userSchedulerCronData[session]
+	defer t.ResetUserState(session)
+
+	if !ok || data.CronExpression == "" {
+		_, err := t.telegramRateLimiter.Edit(ctx, c, c.Message(), tr.T("scheduler.cron_session_expired"))
+		return err
+	}
+
+	if err := t.jobService.UpdateCronExpression(ctx, data.JobID, data.CronExpression); err != nil {
+		t.logger.Error("failed to update cron expression", logrus.Fields{
+			"job_id": data.JobID,
+			"cron":   data.CronExpression,
+			"error":  err,
+		})
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	_ = c.Respond(&telebot.CallbackResponse{Text: tr.T("scheduler.cron_saved")})
+	return t.showJobDetail(ctx, c, data.JobID)
+}
+
+const jobHistoryPageSize = 5
+
+func (t *TelegramBotService) handleBtnJobHistory(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	parts := strings.Split(c.Data(), "|")
+	if len(parts) != 2 {
+		_, err := t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+	jobID, errJob := strconv.Atoi(parts[0])
+	page, errPage := strconv.Atoi(parts[1])
+	if errJob != nil || errPage != nil || page < 0 {
+		_, err := t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	histories, total, err := t.jobService.GetHistories(ctx, &models.GetTaskExecutionHistoryParam{
+		JobID:  utils.ToPointer(uint(jobID)),
+		Limit:  utils.ToPointer(jobHistoryPageSize),
+		Offset: utils.ToPointer(page * jobHistoryPageSize),
+	})
+	if err != nil {
+		t.logger.Error("failed to get task execution histories", logrus.Fields{
+			"job_id": jobID,
+			"error":  err,
+		})
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	totalPages := int((total + jobHistoryPageSize - 1) / jobHistoryPageSize)
+	msg := strings.Builder{}
+	msg.WriteString(tr.T("scheduler.history_title", page+1, max(totalPages, 1), total))
+	if len(histories) == 0 {
+		msg.WriteString(tr.T("scheduler.history_empty"))
+	}
+
+	menu := &telebot.ReplyMarkup{}
+	rows := []telebot.Row{}
+	for idx, history := range histories {
+		number := page*jobHistoryPageSize + idx + 1
+		msg.WriteString(fmt.Sprintf("%d. %s\n", number, formatHistoryLine(tr, history)))
+		rows = append(rows, menu.Row(menu.Data(fmt.Sprintf("🔎 #%d", number), btnJobHistoryDetail.Unique, fmt.Sprintf("%d|%d", history.ID, page))))
+	}
+	msg.WriteString(tr.T("scheduler.history_hint"))
+
+	navigation := telebot.Row{}
+	if page > 0 {
+		navigation = append(navigation, menu.Data(tr.T("button.previous"), btnJobHistory.Unique, fmt.Sprintf("%d|%d", jobID, page-1)))
+	}
+	if page+1 < totalPages {
+		navigation = append(navigation, menu.Data(tr.T("button.next"), btnJobHistory.Unique, fmt.Sprintf("%d|%d", jobID, page+1)))
+	}
+	if len(navigation) > 0 {
+		rows = append(rows, navigation)
+	}
+	rows = append(rows, menu.Row(menu.Data(tr.T(btnActionBackToJobList.Text), btnDetailJob.Unique, fmt.Sprintf("%d", jobID))))
+	menu.Inline(rows...)
+
+	_, err = t.telegramRateLimiter.Edit(ctx, c, c.Message(), msg.String(), menu, telebot.ModeHTML)
+	return err
+}
+
+// maxHistoryOutputLength menjaga pesan detail riwayat di bawah batas 4096 karakter Telegram.
+const maxHistoryOutputLength = 1500
+
+func (t *TelegramBotService) handleBtnJobHistoryDetail(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	parts := strings.Split(c.Data(), "|")
+	if len(parts) != 2 {
+		_, err := t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+	historyID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+	page := parts[1]
+
+	history, err := t.jobService.GetHistory(ctx, uint(historyID))
+	if err != nil {
+		if errors.Is(err, jobs.ErrHistoryNotFound) {
+			_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("scheduler.history_not_found"))
+			return err
+		}
+		t.logger.Error("failed to get task execution history", logrus.Fields{
+			"history_id": historyID,
+			"error":      err,
+		})
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
 		return err
 	}
-	_, err = t.telegramRateLimiter.Edit(ctx, c, c.Message(), fmt.Sprintf(`🚀 Job “%s” sedang diproses secara manual.
 
-<i>Silakan cek status job melalui command /scheduler dan membuka kembali detail job ini nanti.</i>	
-`, job[0].Name), telebot.ModeHTML)
+	_, err = t.telegramRateLimiter.Edit(ctx, c, c.Message(), formatHistoryDetail(tr, history), historyDetailMenu(tr, history, page), telebot.ModeHTML)
+	return err
+}
 
+func (t *TelegramBotService) handleBtnActionCancelExecution(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	parts := strings.Split(c.Data(), "|")
+	if len(parts) != 2 {
+		_, err := t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+	historyID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	if err := t.jobService.CancelExecution(ctx, uint(historyID)); err != nil {
+		if errors.Is(err, jobs.ErrExecutionNotRunning) {
+			_ = c.Respond(&telebot.CallbackResponse{Text: tr.T("scheduler.execution_finished"), ShowAlert: true})
+		} else {
+			t.logger.Error("failed to cancel task execution", logrus.Fields{
+				"history_id": historyID,
+				"error":      err,
+			})
+			_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+			return err
+		}
+	} else {
+		_ = c.Respond(&telebot.CallbackResponse{Text: tr.T("scheduler.execution_cancelled")})
+	}
+
+	return t.handleBtnJobHistoryDetail(ctx, c)
+}
+
+func historyDetailMenu(tr i18n.Translator, history *models.TaskExecutionHistoryEntity, page string) *telebot.ReplyMarkup {
+	menu := &telebot.ReplyMarkup{}
+	rows := []telebot.Row{}
+	if history.Status == models.StatusRunning {
+		rows = append(rows, menu.Row(menu.Data(tr.T(btnActionCancelExecution.Text), btnActionCancelExecution.Unique, fmt.Sprintf("%d|%s", history.ID, page))))
+	}
+	rows = append(rows, menu.Row(menu.Data(tr.T(btnActionBackToJobList.Text), btnJobHistory.Unique, fmt.Sprintf("%d|%s", history.JobID, page))))
+	menu.Inline(rows...)
+	return menu
+}
+
+func formatHistoryDetail(tr i18n.Translator, history *models.TaskExecutionHistoryEntity) string {
+	msg := strings.Builder{}
+	msg.WriteString(tr.T("scheduler.execution_detail", history.ID))
+	msg.WriteString(fmt.Sprintf(" • Status : %s %s\n", historyStatusIcon(history.Status), strings.ToUpper(string(history.Status))))
+	msg.WriteString(tr.T("scheduler.execution_attempt", max(history.Attempt, 1)))
+	if history.Item != "" {
+		msg.WriteString(fmt.Sprintf(" • Item : %s\n", history.Item))
+	}
+	if history.PipelineRunID != nil {
+		msg.WriteString(fmt.Sprintf(" • Pipeline Run : #%d\n", *history.PipelineRunID))
+	}
+	msg.WriteString(tr.T("scheduler.execution_started", utils.TimeToWIB(history.StartedAt).Format("2006-01-02 15:04:05")))
+	if history.CompletedAt.Valid {
+		msg.WriteString(tr.T("scheduler.execution_completed", utils.TimeToWIB(history.CompletedAt.Time).Format("2006-01-02 15:04:05"), history.CompletedAt.Time.Sub(history.StartedAt).Seconds()))
+	} else {
+		msg.WriteString(tr.T("scheduler.execution_running", time.Since(history.StartedAt).Truncate(time.Second)))
+	}
+	if history.ExitCode.Valid {
+		msg.WriteString(fmt.Sprintf(" • Exit Code : %d\n", history.ExitCode.Int32))
+	}
+
+	if history.Output.Valid {
+		msg.WriteString(fmt.Sprintf("\n📤 Output:\n<pre>%s</pre>\n", html.EscapeString(truncateText(history.Output.String, maxHistoryOutputLength))))
+	}
+	if history.ErrorMessage.Valid {
+		msg.WriteString(fmt.Sprintf("\n❗ Error:\n<pre>%s</pre>\n", html.EscapeString(truncateText(history.ErrorMessage.String, maxHistoryOutputLength))))
+	}
+	if !history.Output.Valid && !history.ErrorMessage.Valid {
+		msg.WriteString(tr.T("scheduler.execution_no_output"))
+	}
+	return msg.String()
+}
+
+// truncateText memotong teks panjang dan menyimpan bagian akhirnya, karena error biasanya
+// ada di akhir output.
+func truncateText(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return "…" + string(runes[len(runes)-limit:])
+}
+
+func formatHistoryLine(tr i18n.Translator, history models.TaskExecutionHistoryEntity) string {
+	status := strings.ToUpper(string(history.Status))
+	if history.Attempt > 1 {
+		status = tr.T("scheduler.history_attempt", status, history.Attempt)
+	}
+	line := fmt.Sprintf("%s %s - %s", historyStatusIcon(history.Status), utils.TimeToWIB(history.CreatedAt).Format("01/02 15:04"), status)
+	if history.Item != "" {
+		line = fmt.Sprintf("%s %s [%s] - %s", historyStatusIcon(history.Status), utils.TimeToWIB(history.CreatedAt).Format("01/02 15:04"), history.Item, status)
+	}
+	if history.CompletedAt.Valid {
+		line += fmt.Sprintf(" (%.1fs)", history.CompletedAt.Time.Sub(history.StartedAt).Seconds())
+	}
+	return line
+}
+
+func historyStatusIcon(status models.TaskExecutionStatus) string {
+	switch status {
+	case models.StatusRunning:
+		return "🟡"
+	case models.StatusFailed:
+		return "🔴"
+	case models.StatusTimeout:
+		return "🟠"
+	case models.StatusCancelled:
+		return "⚪"
+	}
+	return "🟢"
+}
+
+func jobStatusIcon(job models.JobEntity) string {
+	if len(job.Schedules) > 0 && !job.Schedules[0].IsActive {
+		return "⏸"
+	}
+	return "▶️"
+}
+
+// jobPipelineRunLimit adalah jumlah pipeline run terakhir yang ditampilkan di /scheduler.
+const jobPipelineRunLimit = 3
+
+func (t *TelegramBotService) handleBtnJobPipeline(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	jobID, err := strconv.Atoi(c.Data())
+	if err != nil {
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	runs, err := t.jobService.GetPipelineRuns(ctx, &models.GetPipelineRunParam{
+		RootJobID: utils.ToPointer(uint(jobID)),
+		Limit:     utils.ToPointer(jobPipelineRunLimit),
+	})
+	if err != nil {
+		t.logger.Error("failed to get pipeline runs", logrus.Fields{
+			"job_id": jobID,
+			"error":  err,
+		})
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	msg := strings.Builder{}
+	msg.WriteString(tr.T("scheduler.pipeline_title"))
+	if len(runs) == 0 {
+		msg.WriteString(tr.T("scheduler.pipeline_empty"))
+	}
+	for _, run := range runs {
+		msg.WriteString(formatPipelineRun(tr, run))
+		msg.WriteString("\n")
+	}
+
+	menu := &telebot.ReplyMarkup{}
+	menu.Inline(
+		menu.Row(menu.Data(tr.T("button.refresh"), btnJobPipeline.Unique, c.Data())),
+		menu.Row(menu.Data(tr.T(btnActionBackToJobList.Text), btnDetailJob.Unique, c.Data())),
+	)
+	_, err = t.telegramRateLimiter.Edit(ctx, c, c.Message(), msg.String(), menu, telebot.ModeHTML)
 	return err
 }
+
+func formatPipelineRun(tr i18n.Translator, run models.PipelineRunSummary) string {
+	msg := strings.Builder{}
+	icon := map[models.PipelineRunStatus]string{
+		models.PipelineRunRunning:   "🟡",
+		models.PipelineRunCompleted: "🟢",
+		models.PipelineRunFailed:    "🔴",
+	}[run.Status]
+	msg.WriteString(fmt.Sprintf("<b>%s Run #%d</b> - %s - %s\n", icon, run.ID, utils.TimeToWIB(run.StartedAt).Format("01/02 15:04"), strings.ToUpper(string(run.Status))))
+	for idx, stage := range run.Stages {
+		line := fmt.Sprintf("  %d. %s %s", idx+1, pipelineStageIcon(stage.Status), html.EscapeString(stage.JobName))
+		if stage.Total > 1 {
+			line += fmt.Sprintf(" (%d/%d", stage.Completed, stage.Total)
+			if stage.Failed > 0 {
+				line += tr.T("scheduler.pipeline_stage_failed", stage.Failed)
+			}
+			line += ")"
+		}
+		msg.WriteString(line + "\n")
+		if stage.Status == models.PipelineStageFailed {
+			for _, stageErr := range stage.Errors {
+				msg.WriteString(fmt.Sprintf("     ↳ <i>%s</i>\n", html.EscapeString(truncateText(stageErr, 200))))
+			}
+		}
+	}
+	return msg.String()
+}
+
+func pipelineStageIcon(status string) string {
+	switch status {
+	case models.PipelineStageRunning:
+		return "🟡"
+	case models.PipelineStageCompleted:
+		return "🟢"
+	case models.PipelineStageFailed:
+		return "🔴"
+	case models.PipelineStageSkipped:
+		return "⏭"
+	}
+	return "⏳"
+}
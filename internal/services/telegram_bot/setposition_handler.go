@@ -2,180 +2,207 @@ package telegram_bot
 
 import (
 	"context"
-	"fmt"
 	"golang-swing-trading-signal/internal/models"
 	"strconv"
-	"strings"
 	"time"
 
 	"gopkg.in/telebot.v3"
 )
 
 func (t *TelegramBotService) handleSetPosition(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
 	userID := c.Sender().ID
-	t.userStates[userID] = StateWaitingSetPositionSymbol
+	session := sessionKeyOf(c)
+	if err := t.quotaService.CheckActivePositions(ctx, userID); err != nil {
+		if message, ok := quotaExceededMessage(tr, err); ok {
+			return c.Send(message)
+		}
+		t.logger.WithError(err).Error("Failed to check active position quota")
+		return c.Send(tr.T("common.internal_error"))
+	}
+
+	t.userStates[session] = StateWaitingSetPositionSymbol
 	reqData := &models.RequestSetPositionData{
 		UserTelegram: models.ToRequestUserTelegram(c.Sender()),
 	}
-	t.userPositionData[userID] = reqData
+	t.userPositionData[session] = reqData
 	t.logger.Infof("Starting /setposition for user %d", userID)
-	return c.Send("📈 Masukkan kode saham kamu (contoh: ANTM):", &telebot.SendOptions{})
+	return c.Send(tr.T("setposition.prompt_symbol"), &telebot.SendOptions{})
+}
+
+// saveSetPositionSymbol menyimpan kode saham yang sudah divalidasi lalu meminta harga beli.
+func (t *TelegramBotService) saveSetPositionSymbol(c telebot.Context, data *models.RequestSetPositionData, symbol string) error {
+	tr := t.translator(c)
+	data.Symbol = symbol
+	c.Send(tr.T("setposition.symbol_saved", data.Symbol), &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
+	t.userStates[sessionKeyOf(c)] = StateWaitingSetPositionBuyPrice
+	return c.Send(tr.T("setposition.prompt_buy_price"), &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
 }
 
 func (t *TelegramBotService) handleSetPositionConversation(ctx context.Context, c telebot.Context) error {
-	userID := c.Sender().ID
+	tr := t.translator(c)
+	session := sessionKeyOf(c)
 	text := c.Text()
-	state := t.userStates[userID] // We already know the state exists
+	state := t.userStates[session] // We already know the state exists
 
-	data, data_ok := t.userPositionData[userID]
+	data, data_ok := t.userPositionData[session]
 	if !data_ok {
 		// Should not happen, but as a safeguard
-		delete(t.userStates, userID)
-		return c.Send("Terjadi kesalahan internal (data posisi tidak ditemukan), silakan coba lagi dengan /setposition.")
+		delete(t.userStates, session)
+		return c.Send(tr.T("setposition.data_not_found"))
 	}
 
 	switch state {
 	case StateWaitingSetPositionSymbol:
-		data.Symbol = strings.ToUpper(text)
-		c.Send(fmt.Sprintf("👍 Oke, kode *%s* tercatat!", data.Symbol), &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
-		t.userStates[userID] = StateWaitingSetPositionBuyPrice
-		return c.Send("💰 Berapa harga belinya ? (contoh: 150.5)", &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
+		symbol, ok := t.resolveSymbolInput(ctx, c, text)
+		if !ok {
+			return nil
+		}
+		return t.saveSetPositionSymbol(c, data, symbol)
 
 	case StateWaitingSetPositionBuyPrice:
 		price, err := strconv.ParseFloat(text, 64)
 		if err != nil {
-			return c.Send("Format harga beli tidak valid. Silakan masukkan angka (contoh: 150.5).")
+			return c.Send(tr.T("setposition.invalid_buy_price"))
 		}
 		data.BuyPrice = price
-		t.userStates[userID] = StateWaitingSetPositionBuyDate
-		return c.Send("📅 Kapan tanggal belinya? (format: YYYY-MM-DD)", &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
+		t.userStates[session] = StateWaitingSetPositionBuyDate
+		return c.Send(tr.T("setposition.prompt_buy_date"), &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
 
 	case StateWaitingSetPositionBuyDate:
 		_, err := time.Parse("2006-01-02", text)
 		if err != nil {
-			return c.Send("Format tanggal tidak valid. Silakan gunakan format YYYY-MM-DD.")
+			return c.Send(tr.T("exit.invalid_date"))
 		}
 		data.BuyDate = text
-		t.userStates[userID] = StateWaitingSetPositionTakeProfit
-		return c.Send("🎯 Target take profit-nya di harga berapa? (contoh: 180.0)", &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
+		t.userStates[session] = StateWaitingSetPositionTakeProfit
+		return c.Send(tr.T("setposition.prompt_take_profit"), &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
 
 	case StateWaitingSetPositionTakeProfit:
 		price, err := strconv.ParseFloat(text, 64)
 		if err != nil {
-			return c.Send("Format harga take profit tidak valid. Silakan masukkan angka.")
+			return c.Send(tr.T("setposition.invalid_take_profit"))
 		}
 		data.TakeProfit = price
-		t.userStates[userID] = StateWaitingSetPositionStopLoss
-		return c.Send("📉 Stop loss-nya di harga berapa? (contoh: 140.0)", &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
+		t.userStates[session] = StateWaitingSetPositionStopLoss
+		return c.Send(tr.T("setposition.prompt_stop_loss"), &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
 
 	case StateWaitingSetPositionStopLoss:
 		price, err := strconv.ParseFloat(text, 64)
 		if err != nil {
-			return c.Send("Format harga stop loss tidak valid. Silakan masukkan angka.")
+			return c.Send(tr.T("setposition.invalid_stop_loss"))
 		}
 		data.StopLoss = price
-		t.userStates[userID] = StateWaitingSetPositionMaxHolding
-		return c.Send("⏳ Berapa maksimal hari mau di-hold? (contoh: 1) \n\n📌 *Note:* Isi angka dari *1* sampai *5* hari.", &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
+		t.userStates[session] = StateWaitingSetPositionMaxHolding
+		return c.Send(tr.T("setposition.prompt_max_holding"), &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
 
 	case StateWaitingSetPositionMaxHolding:
 		intVal, err := strconv.Atoi(text)
 		if err != nil || intVal <= 0 {
-			return c.Send("Format maksimal hari hold tidak valid. Silakan masukkan angka bulat positif.")
+			return c.Send(tr.T("setposition.invalid_max_holding"))
 		}
 		data.MaxHolding = intVal
-		t.userStates[userID] = StateWaitingSetPositionAlertPrice
+		t.userStates[session] = StateWaitingSetPositionAlertPrice
 
 		menu := &telebot.ReplyMarkup{}
 		menu.Inline(
-			menu.Row(btnSetPositionAlertPriceYes, btnSetPositionAlertPriceNo),
+			menu.Row(button(tr, btnSetPositionAlertPriceYes), button(tr, btnSetPositionAlertPriceNo)),
 		)
 
-		return c.Send("🚨 Aktifkan alert untuk data ini?\n\nNote: Sistem akan kirim pesan kalau harga mencapai take profit atau stop loss yang kamu tentukan.", menu)
+		return c.Send(tr.T("setposition.prompt_alert_price"), menu)
 
 	case StateWaitingSetPositionAlertPrice, StateWaitingSetPositionAlertMonitor:
-		return c.Send("👆 Silakan pilih salah satu opsi di atas, atau kirim /cancel untuk membatalkan.")
+		return c.Send(tr.T("common.choose_option"))
 	}
 	return nil
 }
 
 func (t *TelegramBotService) handleBtnSetPositionAlertPriceYes(ctx context.Context, c telebot.Context) error {
-	if t.userStates[c.Sender().ID] != StateWaitingSetPositionAlertPrice {
-		t.ResetUserState(c.Sender().ID)
-		return c.Send("❌ Terjadi kesalahan internal, silakan mulai lagi dengan /setposition.")
+	tr := t.translator(c)
+	session := sessionKeyOf(c)
+	if t.userStates[session] != StateWaitingSetPositionAlertPrice {
+		t.ResetUserState(session)
+		return c.Send(tr.T("setposition.internal_error"))
 	}
-	userID := c.Sender().ID
-	data := t.userPositionData[userID]
+	data := t.userPositionData[session]
 	data.AlertPrice = true
-	t.bot.Edit(c.Message(), "✅ Alert harga saham diaktifkan.", &telebot.SendOptions{
+	t.bot.Edit(c.Message(), tr.T("setposition.alert_price_on"), &telebot.SendOptions{
 		ParseMode: telebot.ModeMarkdown,
 	})
-	t.userStates[userID] = StateWaitingSetPositionAlertMonitor
+	t.userStates[session] = StateWaitingSetPositionAlertMonitor
 
 	menu := &telebot.ReplyMarkup{}
 	menu.Inline(
-		menu.Row(btnSetPositionAlertMonitorYes, btnSetPositionAlertMonitorNo),
+		menu.Row(button(tr, btnSetPositionAlertMonitorYes), button(tr, btnSetPositionAlertMonitorNo)),
 	)
-	return c.Send("🔎 Aktifkan monitoring alert?\n\nNote: Sistem akan menganalisis posisi ini dan kirim laporan singkat: apakah masih aman, rawan, atau mendekati batas hold/SL.", menu)
+	return c.Send(tr.T("setposition.prompt_alert_monitor"), menu)
 }
 
 func (t *TelegramBotService) handleBtnSetPositionAlertPriceNo(ctx context.Context, c telebot.Context) error {
-	if t.userStates[c.Sender().ID] != StateWaitingSetPositionAlertPrice {
-		t.ResetUserState(c.Sender().ID)
-		return c.Send("❌ Terjadi kesalahan internal, silakan mulai lagi dengan /setposition.")
+	tr := t.translator(c)
+	session := sessionKeyOf(c)
+	if t.userStates[session] != StateWaitingSetPositionAlertPrice {
+		t.ResetUserState(session)
+		return c.Send(tr.T("setposition.internal_error"))
 	}
-	userID := c.Sender().ID
-	data := t.userPositionData[userID]
+	data := t.userPositionData[session]
 	data.AlertPrice = false
-	t.bot.Edit(c.Message(), "❌ Alert harga saham tidak diaktifkan.", &telebot.SendOptions{
+	t.bot.Edit(c.Message(), tr.T("setposition.alert_price_off"), &telebot.SendOptions{
 		ParseMode: telebot.ModeMarkdown,
 	})
-	t.userStates[userID] = StateWaitingSetPositionAlertMonitor
+	t.userStates[session] = StateWaitingSetPositionAlertMonitor
 
 	menu := &telebot.ReplyMarkup{}
 	menu.Inline(
-		menu.Row(btnSetPositionAlertMonitorYes, btnSetPositionAlertMonitorNo),
+		menu.Row(button(tr, btnSetPositionAlertMonitorYes), button(tr, btnSetPositionAlertMonitorNo)),
 	)
-	return c.Send("🔎 Aktifkan monitoring alert?\n\nNote: Sistem akan menganalisis posisi ini dan kirim laporan singkat: apakah masih aman, rawan, atau mendekati batas hold/SL.", menu)
+	return c.Send(tr.T("setposition.prompt_alert_monitor"), menu)
 }
 
 func (t *TelegramBotService) handleSetPositionAlertMonitorYes(ctx context.Context, c telebot.Context) error {
-	if t.userStates[c.Sender().ID] != StateWaitingSetPositionAlertMonitor {
-		t.ResetUserState(c.Sender().ID)
-		return c.Send("❌ Terjadi kesalahan internal, silakan mulai lagi dengan /setposition.")
+	tr := t.translator(c)
+	session := sessionKeyOf(c)
+	if t.userStates[session] != StateWaitingSetPositionAlertMonitor {
+		t.ResetUserState(session)
+		return c.Send(tr.T("setposition.internal_error"))
 	}
-	userID := c.Sender().ID
-	data := t.userPositionData[userID]
+	data := t.userPositionData[session]
 	data.AlertMonitor = true
-	t.bot.Edit(c.Message(), "✅ Alert monitor diaktifkan.", &telebot.SendOptions{
+	t.bot.Edit(c.Message(), tr.T("setposition.alert_monitor_on"), &telebot.SendOptions{
 		ParseMode: telebot.ModeMarkdown,
 	})
 	return t.handleSetPositionFinish(ctx, c)
 }
 
 func (t *TelegramBotService) handleSetPositionAlertMonitorNo(ctx context.Context, c telebot.Context) error {
-	if t.userStates[c.Sender().ID] != StateWaitingSetPositionAlertMonitor {
-		t.ResetUserState(c.Sender().ID)
-		return c.Send("❌ Terjadi kesalahan internal, silakan mulai lagi dengan /setposition.")
+	tr := t.translator(c)
+	session := sessionKeyOf(c)
+	if t.userStates[session] != StateWaitingSetPositionAlertMonitor {
+		t.ResetUserState(session)
+		return c.Send(tr.T("setposition.internal_error"))
 	}
-	userID := c.Sender().ID
-	data := t.userPositionData[userID]
+	data := t.userPositionData[session]
 	data.AlertMonitor = false
-	t.bot.Edit(c.Message(), "❌ Alert monitor tidak diaktifkan.", &telebot.SendOptions{
+	t.bot.Edit(c.Message(), tr.T("setposition.alert_monitor_off"), &telebot.SendOptions{
 		ParseMode: telebot.ModeMarkdown,
 	})
 	return t.handleSetPositionFinish(ctx, c)
 }
 
 func (t *TelegramBotService) handleSetPositionFinish(ctx context.Context, c telebot.Context) error {
-	userID := c.Sender().ID
-	data := t.userPositionData[userID]
+	tr := t.translator(c)
+	session := sessionKeyOf(c)
+	data := t.userPositionData[session]
 
-	defer t.ResetUserState(userID)
+	defer t.ResetUserState(session)
 
 	if err := t.stockService.SetStockPosition(ctx, data); err != nil {
-		return c.Send("❌ Terjadi kesalahan internal, silakan mulai lagi dengan /setposition.")
+		if message, ok := quotaExceededMessage(tr, err); ok {
+			return c.Send(message)
+		}
+		return c.Send(tr.T("setposition.internal_error"))
 	}
 
-	return c.Send(t.FormatResultSetPositionMessage(data), &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
+	return c.Send(t.FormatResultSetPositionMessage(tr, data), &telebot.SendOptions{ParseMode: telebot.ModeMarkdown})
 
 }
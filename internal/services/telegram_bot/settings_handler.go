@@ -0,0 +1,206 @@
+package telegram_bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang-swing-trading-signal/internal/i18n"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/notification"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+func (t *TelegramBotService) handleSettings(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	preference, err := t.notificationService.GetPreference(ctx, c.Sender().ID)
+	if err != nil {
+		t.logger.Error("failed to get notification preference", logrus.Fields{
+			"error": err,
+		})
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	message, menu := settingsMenu(tr, preference)
+	_, err = t.telegramRateLimiter.Send(ctx, c, message, menu, telebot.ModeHTML)
+	return err
+}
+
+func (t *TelegramBotService) handleBtnSettingsToggle(ctx context.Context, c telebot.Context) error {
+	userID := c.Sender().ID
+	notificationType := c.Data()
+
+	preference, err := t.notificationService.GetPreference(ctx, userID)
+	if err != nil {
+		return t.respondSettingsError(c, err)
+	}
+
+	preference, err = t.notificationService.SetEnabled(ctx, userID, notificationType, !preference.Wants(notificationType))
+	if err != nil {
+		return t.respondSettingsError(c, err)
+	}
+	return t.editSettingsMenu(ctx, c, preference)
+}
+
+func (t *TelegramBotService) handleBtnSettingsMinConfidence(ctx context.Context, c telebot.Context) error {
+	userID := c.Sender().ID
+
+	preference, err := t.notificationService.GetPreference(ctx, userID)
+	if err != nil {
+		return t.respondSettingsError(c, err)
+	}
+
+	// tombol berputar ke pilihan minimum confidence berikutnya
+	next := notification.MinConfidenceOptions[0]
+	for _, option := range notification.MinConfidenceOptions {
+		if option > preference.MinConfidence {
+			next = option
+			break
+		}
+	}
+
+	preference, err = t.notificationService.SetMinConfidence(ctx, userID, next)
+	if err != nil {
+		return t.respondSettingsError(c, err)
+	}
+	return t.editSettingsMenu(ctx, c, preference)
+}
+
+func (t *TelegramBotService) handleBtnSettingsVerbosity(ctx context.Context, c telebot.Context) error {
+	userID := c.Sender().ID
+
+	preference, err := t.notificationService.GetPreference(ctx, userID)
+	if err != nil {
+		return t.respondSettingsError(c, err)
+	}
+
+	verbosity := models.VerbosityCompact
+	if preference.IsCompact() {
+		verbosity = models.VerbosityDetailed
+	}
+
+	preference, err = t.notificationService.SetVerbosity(ctx, userID, verbosity)
+	if err != nil {
+		return t.respondSettingsError(c, err)
+	}
+	return t.editSettingsMenu(ctx, c, preference)
+}
+
+func (t *TelegramBotService) handleBtnSettingsQuietHours(ctx context.Context, c telebot.Context) error {
+	session := sessionKeyOf(c)
+	t.ResetUserState(session)
+
+	t.mu.Lock()
+	t.userStates[session] = StateWaitingSettingsQuietHours
+	t.mu.Unlock()
+
+	_, err := t.telegramRateLimiter.Send(ctx, c, t.translator(c).T("settings.quiet_hours_prompt"), telebot.ModeHTML)
+	return err
+}
+
+func (t *TelegramBotService) handleSettingsQuietHoursConversation(ctx context.Context, c telebot.Context) error {
+	userID := c.Sender().ID
+	session := sessionKeyOf(c)
+	tr := t.translator(c)
+
+	if _, err := t.notificationService.SetQuietHours(ctx, userID, c.Text()); err != nil {
+		if errors.Is(err, notification.ErrInvalidQuietHours) {
+			_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("settings.quiet_hours_invalid"), telebot.ModeHTML)
+			return err
+		}
+		t.logger.Error("failed to update quiet hours", logrus.Fields{
+			"error": err,
+		})
+		t.ResetUserState(session)
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	t.ResetUserState(session)
+	return t.handleSettings(ctx, c)
+}
+
+func (t *TelegramBotService) respondSettingsError(c telebot.Context, err error) error {
+	t.logger.Error("failed to update notification preference", logrus.Fields{
+		"error": err,
+	})
+	return c.Respond(&telebot.CallbackResponse{Text: t.translator(c).T("common.internal_error"), ShowAlert: true})
+}
+
+func (t *TelegramBotService) editSettingsMenu(ctx context.Context, c telebot.Context, preference models.NotificationPreferenceEntity) error {
+	message, menu := settingsMenu(t.translator(c), preference)
+	_, err := t.telegramRateLimiter.Edit(ctx, c, c.Message(), message, menu, telebot.ModeHTML)
+	return err
+}
+
+func settingsMenu(tr i18n.Translator, preference models.NotificationPreferenceEntity) (string, *telebot.ReplyMarkup) {
+	sb := &strings.Builder{}
+	sb.WriteString(tr.T("settings.menu_title"))
+	menu := &telebot.ReplyMarkup{}
+	toggles := make([]telebot.Btn, 0, len(models.NotificationTypes))
+	for _, notificationType := range models.NotificationTypes {
+		icon := "❌"
+		if preference.Wants(notificationType) {
+			icon = "✅"
+		}
+		label := tr.T(notificationLabel(notificationType))
+		sb.WriteString(fmt.Sprintf("%s %s\n", icon, label))
+		toggles = append(toggles, menu.Data(fmt.Sprintf("%s %s", icon, label), btnSettingsToggle.Unique, notificationType))
+	}
+
+	quietHours := tr.T("settings.quiet_hours_off")
+	if preference.HasQuietHours() {
+		quietHours = fmt.Sprintf("%s - %s WIB", preference.QuietStart, preference.QuietEnd)
+	}
+	sb.WriteString(tr.T("settings.quiet_hours", quietHours))
+
+	minConfidence := tr.T("settings.min_confidence_all")
+	if preference.MinConfidence > 0 {
+		minConfidence = fmt.Sprintf("≥ %d%%", preference.MinConfidence)
+	}
+	verbosity := tr.T(verbosityLabel(preference.Verbosity))
+	sb.WriteString(tr.T("settings.min_confidence", minConfidence))
+	sb.WriteString(tr.T("settings.verbosity", verbosity))
+
+	rows := make([]telebot.Row, 0, len(toggles)/2+4)
+	for chunk := range slices.Chunk(toggles, 2) {
+		rows = append(rows, menu.Row(chunk...))
+	}
+	rows = append(rows,
+		menu.Row(button(tr, btnSettingsQuietHours)),
+		menu.Row(
+			menu.Data(fmt.Sprintf("🎯 Min Confidence: %s", minConfidence), btnSettingsMinConfidence.Unique),
+			menu.Data(fmt.Sprintf("📝 Format: %s", verbosity), btnSettingsVerbosity.Unique),
+		),
+		menu.Row(button(tr, btnDeleteMessage)),
+	)
+	menu.Inline(rows...)
+
+	return sb.String(), menu
+}
+
+// notificationLabel dan verbosityLabel mengembalikan key katalog i18n.
+func notificationLabel(notificationType string) string {
+	switch notificationType {
+	case models.NotificationTargetHit:
+		return "settings.label_target_hit"
+	case models.NotificationSignalChange:
+		return "settings.label_signal_change"
+	case models.NotificationDigest:
+		return "settings.label_digest"
+	default:
+		return notificationType
+	}
+}
+
+func verbosityLabel(verbosity string) string {
+	if verbosity == models.VerbosityCompact {
+		return "settings.verbosity_compact"
+	}
+	return "settings.verbosity_detailed"
+}
@@ -0,0 +1,284 @@
+package telegram_bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang-swing-trading-signal/internal/events"
+	"golang-swing-trading-signal/internal/i18n"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+	"golang-swing-trading-signal/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+const (
+	// hasil worker kadang belum terlihat di database saat event diterima, retry singkat
+	// sebelum menunggu reclaim
+	streamHandlerAttempts = 3
+	streamHandlerBackoff  = time.Second
+)
+
+// StartStreamConsumers menjalankan consumer untuk stream hasil analisa dari worker sehingga
+// user yang meminta analisa mendapat notifikasi saat hasilnya siap.
+func (t *TelegramBotService) StartStreamConsumers(ctx context.Context) {
+	subscriptions := []struct {
+		stream     string
+		legacyType string
+		handler    events.Handler
+	}{
+		{models.RedisStreamStockAnalyzerResult, events.TypeStockAnalyzerCompleted, t.handleStockAnalyzerResult},
+		{models.RedisStreamStockPositionMonitorResult, events.TypeStockPositionMonitorCompleted, t.handleStockPositionMonitorResult},
+	}
+
+	for _, subscription := range subscriptions {
+		stream := subscription.stream
+		consumer := events.NewSubscriber(t.redisClient, t.logger, redis.ConsumerConfig{
+			Stream:        stream,
+			Group:         t.streamConsumerConfig.Group,
+			Consumer:      t.streamConsumerConfig.ConsumerName,
+			MinIdle:       t.streamConsumerConfig.MinIdle,
+			MaxDeliveries: t.streamConsumerConfig.MaxDeliveries,
+		}, subscription.legacyType, subscription.handler,
+			events.Logging(t.logger),
+			t.eventMetrics.Middleware(),
+			events.Retry(streamHandlerAttempts, streamHandlerBackoff),
+		)
+
+		t.consumerWg.Add(1)
+		utils.SafeGo(func() {
+			defer t.consumerWg.Done()
+			if err := consumer.Run(ctx); err != nil {
+				t.logger.Error("failed to run stream consumer", logrus.Fields{
+					"stream": stream,
+					"error":  err,
+				})
+			}
+		})
+	}
+}
+
+// EventMetrics mengembalikan statistik event hasil worker yang sudah diproses.
+func (t *TelegramBotService) EventMetrics() map[string]events.EventStats {
+	return t.eventMetrics.Snapshot()
+}
+
+func (t *TelegramBotService) handleStockAnalyzerResult(ctx context.Context, envelope *events.Envelope) error {
+	var result models.StockAnalyzerResult
+	if err := envelope.DecodeInto(&result); err != nil {
+		return err
+	}
+	// signal yang dipakai ulang sudah pernah dibagikan ke grup saat pertama dibuat
+	if result.Error == "" && !result.Reused {
+		if err := t.notifyGroupsWatching(ctx, result.StockCode); err != nil {
+			return err
+		}
+	}
+
+	var analysis *models.IndividualAnalysisResponseMultiTimeframe
+	if result.Error == "" {
+		signals, err := t.stockService.GetLatestStockSignal(ctx, models.GetStockBuySignalParam{
+			After:     utils.TimeNowWIB().Add(-t.tradingConfig.GetLatestSignalBefore),
+			StockCode: result.StockCode,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get stock signal %s: %w", result.StockCode, err)
+		}
+		if len(signals) == 0 {
+			// hasil belum terlihat di database, pesan diproses ulang setelah reclaim
+			return fmt.Errorf("stock signal %s not found", result.StockCode)
+		}
+
+		analysis = &models.IndividualAnalysisResponseMultiTimeframe{}
+		if err := json.Unmarshal([]byte(signals[0].Data), analysis); err != nil {
+			return fmt.Errorf("failed to unmarshal analysis %s: %w", result.StockCode, err)
+		}
+	}
+
+	if result.NotifyUser && result.TelegramID != 0 {
+		if err := t.enqueueAnalysisResult(ctx, envelope, result.TelegramID, result.StockCode, analysis); err != nil {
+			return err
+		}
+	}
+	if result.Reused {
+		return nil
+	}
+
+	// user yang meminta analisa yang sama selama analisa berjalan ikut mendapat hasilnya
+	waiters, err := t.stockService.TakeStockAnalyzerWaiters(ctx, envelope.CorrelationID)
+	if err != nil {
+		return err
+	}
+	for _, telegramID := range waiters {
+		if result.NotifyUser && telegramID == result.TelegramID {
+			continue
+		}
+		if err := t.enqueueAnalysisResult(ctx, envelope, telegramID, result.StockCode, analysis); err != nil {
+			// penunggu sudah diambil dari redis, jangan ulang pesan hanya untuk satu user
+			t.logger.Error("failed to enqueue analysis result for waiter", logrus.Fields{
+				"telegram_id": telegramID,
+				"stock_code":  result.StockCode,
+				"error":       err,
+			})
+		}
+	}
+	return nil
+}
+
+// enqueueAnalysisResult mengirim hasil analisa ke user dalam bahasanya, atau pesan gagal jika
+// analysis nil.
+func (t *TelegramBotService) enqueueAnalysisResult(ctx context.Context, envelope *events.Envelope, telegramID int64, stockCode string, analysis *models.IndividualAnalysisResponseMultiTimeframe) error {
+	tr := t.translatorFor(ctx, telegramID)
+	text := tr.T("analysis.failed", stockCode)
+	if analysis != nil {
+		text = t.FormatAnalysisMessage(tr, analysis)
+	}
+	return t.enqueueStreamNotification(ctx, envelope, telegramID, text, time.Time{})
+}
+
+// notifyGroupsWatching mengirim sinyal BUY ke grup yang memantau saham tersebut. Hanya hasil
+// analisa saham yang dibagikan, posisi pribadi member tidak pernah dikirim ke grup.
+func (t *TelegramBotService) notifyGroupsWatching(ctx context.Context, stockCode string) error {
+	watching, err := t.groupService.GetGroupsWatching(ctx, stockCode)
+	if err != nil {
+		return err
+	}
+	if len(watching) == 0 {
+		return nil
+	}
+
+	signals, err := t.stockService.GetLatestStockSignal(ctx, models.GetStockBuySignalParam{
+		After:     utils.TimeNowWIB().Add(-t.tradingConfig.GetLatestSignalBefore),
+		StockCode: stockCode,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get stock signal %s: %w", stockCode, err)
+	}
+	if len(signals) == 0 {
+		return fmt.Errorf("stock signal %s not found", stockCode)
+	}
+	signal := signals[0]
+	if signal.Signal != "BUY" {
+		return nil
+	}
+
+	var analysis models.IndividualAnalysisResponseMultiTimeframe
+	if err := json.Unmarshal([]byte(signal.Data), &analysis); err != nil {
+		return fmt.Errorf("failed to unmarshal analysis %s: %w", stockCode, err)
+	}
+
+	for _, group := range watching {
+		tr := i18n.New(i18n.Resolve(group.Language))
+		// dedup per sinyal agar analisa ulang yang menghasilkan sinyal sama tidak dikirim dua kali
+		_, err := t.outboxService.Enqueue(ctx, models.TelegramOutboxMessage{
+			ChatID:    group.ChatID,
+			Text:      t.FormatAnalysisMessage(tr, &analysis),
+			ParseMode: telebot.ModeHTML,
+			DedupKey:  fmt.Sprintf("group_signal:%d:%d", signal.ID, group.ChatID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to enqueue group signal %s: %w", stockCode, err)
+		}
+	}
+	return nil
+}
+
+func (t *TelegramBotService) handleStockPositionMonitorResult(ctx context.Context, envelope *events.Envelope) error {
+	var result models.StockPositionMonitorResult
+	if err := envelope.DecodeInto(&result); err != nil {
+		return err
+	}
+	if !result.SendToTelegram || result.TelegramID == 0 {
+		return nil
+	}
+
+	tr := t.translatorFor(ctx, result.TelegramID)
+	text := tr.T("analysis.failed", result.StockCode)
+	notification := models.Notification{Type: models.NotificationSignalChange}
+	var monitoring *models.PositionMonitoringResponseMultiTimeframe
+	if result.Error == "" {
+		monitorings, err := t.stockService.GetLatestStockPositionMonitoring(ctx, models.GetStockPositionMonitoringParam{
+			TelegramID:      result.TelegramID,
+			StockPositionID: result.StockPositionID,
+			StockCode:       result.StockCode,
+			Limit:           1,
+			AfterTime:       utils.TimeNowWIB().Add(-t.tradingConfig.GetLatestSignalBefore),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get stock position monitoring %s: %w", result.StockCode, err)
+		}
+		if len(monitorings) == 0 {
+			return fmt.Errorf("stock position monitoring %s not found", result.StockCode)
+		}
+
+		monitoring = &models.PositionMonitoringResponseMultiTimeframe{}
+		if err := json.Unmarshal([]byte(monitorings[0].Data), monitoring); err != nil {
+			return fmt.Errorf("failed to unmarshal stock monitoring %s: %w", result.StockCode, err)
+		}
+		text = t.FormatPositionMonitoringMessage(tr, monitoring)
+		notification = monitoringNotification(monitoring)
+	}
+
+	// monitoring yang diminta langsung oleh user selalu dikirim
+	if !result.Scheduled {
+		return t.enqueueStreamNotification(ctx, envelope, result.TelegramID, text, time.Time{})
+	}
+
+	decision, err := t.notificationService.Decide(ctx, result.TelegramID, notification)
+	if err != nil {
+		return fmt.Errorf("failed to get notification preference %d: %w", result.TelegramID, err)
+	}
+	if !decision.Send {
+		t.logger.Info("position monitoring notification skipped by user preference", logrus.Fields{
+			"telegram_id": result.TelegramID,
+			"stock_code":  result.StockCode,
+			"type":        notification.Type,
+		})
+		return nil
+	}
+	if monitoring != nil && decision.Compact {
+		text = t.FormatPositionMonitoringCompactMessage(tr, monitoring)
+	}
+
+	// alert terjadwal saat bursa tutup ditahan sampai pre-opening berikutnya agar user masih
+	// sempat memasang order
+	sendAfter := decision.SendAfter
+	now := t.marketCalendar.Now()
+	if alertAt := t.marketCalendar.NextAlertTime(now); alertAt.After(now) && alertAt.After(sendAfter) {
+		sendAfter = alertAt
+	}
+
+	return t.enqueueStreamNotification(ctx, envelope, result.TelegramID, text, sendAfter)
+}
+
+// monitoringNotification mengelompokkan hasil monitoring: rekomendasi take profit / cut loss
+// dianggap notifikasi TP/SL, selain itu perubahan sinyal yang difilter minimum confidence.
+func monitoringNotification(monitoring *models.PositionMonitoringResponseMultiTimeframe) models.Notification {
+	switch monitoring.Action {
+	case "TAKE_PROFIT", "CUT_LOSS":
+		return models.Notification{Type: models.NotificationTargetHit}
+	default:
+		return models.Notification{
+			Type:       models.NotificationSignalChange,
+			Confidence: utils.ToPointer(monitoring.ConfidenceLevel),
+		}
+	}
+}
+
+// enqueueStreamNotification mengirim hasil lewat outbox. ID event dipakai sebagai dedup key
+// sehingga event yang diproses ulang tidak dikirim dua kali. sendAfter diisi untuk menunda
+// pengiriman sampai jam tenang user selesai.
+func (t *TelegramBotService) enqueueStreamNotification(ctx context.Context, envelope *events.Envelope, telegramID int64, text string, sendAfter time.Time) error {
+	_, err := t.outboxService.Enqueue(ctx, models.TelegramOutboxMessage{
+		ChatID:    telegramID,
+		Text:      text,
+		ParseMode: telebot.ModeHTML,
+		DedupKey:  fmt.Sprintf("event:%s:%d", envelope.ID, telegramID),
+		SendAfter: sendAfter,
+	})
+	return err
+}
@@ -0,0 +1,123 @@
+package telegram_bot
+
+import (
+	"context"
+	"errors"
+	"html"
+	"strings"
+
+	"golang-swing-trading-signal/internal/i18n"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/symbols"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+// resolveSymbolInput memvalidasi kode saham yang dikirim user. Jika tidak terdaftar, user diberi
+// saran saham yang mirip sebagai tombol dan state percakapan dibiarkan agar user bisa memilih
+// saran atau mengetik ulang kodenya.
+func (t *TelegramBotService) resolveSymbolInput(ctx context.Context, c telebot.Context, input string) (string, bool) {
+	tr := t.translator(c)
+
+	stock, err := t.symbolResolver.Resolve(ctx, input)
+	if err == nil {
+		return stock.Code, true
+	}
+
+	var unknown *symbols.UnknownSymbolError
+	switch {
+	case errors.As(err, &unknown):
+		_, err = t.telegramRateLimiter.Send(ctx, c, formatUnknownSymbol(tr, unknown), symbolSuggestionMenu(tr, unknown.Suggestions), telebot.ModeHTML)
+	case errors.Is(err, symbols.ErrInvalidSymbol):
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("symbol.invalid"))
+	default:
+		t.logger.Error("failed to resolve stock symbol", logrus.Fields{
+			"input": input,
+			"error": err,
+		})
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+	}
+	if err != nil {
+		t.logger.WithError(err).Error("Failed to send symbol validation message")
+	}
+	return "", false
+}
+
+// handleBtnSymbolSuggestion melanjutkan percakapan yang sedang menunggu kode saham dengan saran
+// yang dipilih user.
+func (t *TelegramBotService) handleBtnSymbolSuggestion(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	session := sessionKeyOf(c)
+	state := t.userStates[session]
+
+	if state != StateWaitingAnalyzeSymbol && state != StateWaitingNewsFindSymbol && state != StateWaitingSetPositionSymbol {
+		return c.Respond(&telebot.CallbackResponse{Text: tr.T("symbol.suggestion_expired"), ShowAlert: true})
+	}
+
+	// data callback bisa dimanipulasi, validasi ulang seperti input teks
+	symbol, ok := t.resolveSymbolInput(ctx, c, c.Data())
+	if !ok {
+		return c.Respond()
+	}
+
+	if err := c.Respond(); err != nil {
+		t.logger.Warn("failed to respond symbol suggestion callback", logrus.Fields{
+			"error": err,
+		})
+	}
+	if _, err := t.telegramRateLimiter.Edit(ctx, c, c.Message(), tr.T("symbol.selected", symbol), telebot.ModeHTML); err != nil {
+		t.logger.WithError(err).Error("Failed to edit symbol suggestion message")
+	}
+
+	switch state {
+	case StateWaitingAnalyzeSymbol:
+		return t.startGeneralAnalysis(ctx, c, symbol)
+	case StateWaitingNewsFindSymbol:
+		return t.findNews(ctx, c, symbol)
+	default:
+		data, ok := t.userPositionData[session]
+		if !ok {
+			t.ResetUserState(session)
+			return c.Send(tr.T("setposition.data_not_found"))
+		}
+		return t.saveSetPositionSymbol(c, data, symbol)
+	}
+}
+
+func formatUnknownSymbol(tr i18n.Translator, unknown *symbols.UnknownSymbolError) string {
+	input := html.EscapeString(unknown.Input)
+	if len(unknown.Suggestions) == 0 {
+		return tr.T("symbol.not_found", input)
+	}
+	return tr.T("symbol.not_found_suggestions", input)
+}
+
+func symbolSuggestionMenu(tr i18n.Translator, suggestions []models.StockEntity) *telebot.ReplyMarkup {
+	menu := &telebot.ReplyMarkup{}
+	rows := make([]telebot.Row, 0, len(suggestions)+1)
+	for _, stock := range suggestions {
+		rows = append(rows, menu.Row(menu.Data(formatSymbolSuggestion(stock), btnSymbolSuggestion.Unique, stock.Code)))
+	}
+	rows = append(rows, menu.Row(menu.Data(tr.T(btnCancelGeneral.Text), btnCancelGeneral.Unique)))
+	menu.Inline(rows...)
+	return menu
+}
+
+func formatSymbolSuggestion(stock models.StockEntity) string {
+	name := strings.TrimSpace(stock.Name)
+	if name == "" {
+		return stock.Code
+	}
+	return stock.Code + " · " + name
+}
+
+// formatSymbolSuggestionList dipakai di grup, saran ditampilkan sebagai teks karena percakapan
+// grup tidak menyimpan state yang bisa dilanjutkan tombol.
+func formatSymbolSuggestionList(suggestions []models.StockEntity) string {
+	codes := make([]string, 0, len(suggestions))
+	for _, stock := range suggestions {
+		codes = append(codes, "$"+stock.Code)
+	}
+	return strings.Join(codes, ", ")
+}
@@ -15,10 +15,22 @@ import (
 	"gopkg.in/telebot.v3"
 
 	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/events"
+	"golang-swing-trading-signal/internal/i18n"
 	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/digest"
+	"golang-swing-trading-signal/internal/services/groups"
 	"golang-swing-trading-signal/internal/services/jobs"
+	"golang-swing-trading-signal/internal/services/llm_usage"
+	"golang-swing-trading-signal/internal/services/market_calendar"
+	"golang-swing-trading-signal/internal/services/market_price"
+	"golang-swing-trading-signal/internal/services/notification"
+	"golang-swing-trading-signal/internal/services/outbox"
+	"golang-swing-trading-signal/internal/services/quota"
 	"golang-swing-trading-signal/internal/services/stocks"
+	"golang-swing-trading-signal/internal/services/symbols"
 	"golang-swing-trading-signal/internal/services/trading_analysis"
+	"golang-swing-trading-signal/internal/services/users"
 	"golang-swing-trading-signal/pkg/ratelimit"
 	"golang-swing-trading-signal/pkg/redis"
 )
@@ -61,26 +73,66 @@ const (
 	StateWaitingAdjustTargetPositionInputStopLossPrice = 51
 	StateWaitingAdjustTargetPositionMaxHoldingDays     = 52
 	StateWaitingAdjustTargetPositionConfirm            = 53
+
+	// /scheduler edit cron states
+	StateWaitingSchedulerCronExpression = 60
+	StateWaitingSchedulerCronConfirm    = 61
+
+	// /digest ubah jam pengiriman
+	StateWaitingDigestDeliveryTime = 70
+
+	// /settings ubah jam tenang
+	StateWaitingSettingsQuietHours = 80
 )
 
+// sessionKey memisahkan state percakapan per (chat, user) agar percakapan user yang sama
+// di chat pribadi dan di grup, atau beberapa user di grup yang sama, tidak saling menimpa.
+type sessionKey struct {
+	ChatID int64
+	UserID int64
+}
+
+func sessionKeyOf(c telebot.Context) sessionKey {
+	// update tanpa chat (misal inline query) memakai chat pribadi user
+	if c.Chat() == nil {
+		return sessionKey{ChatID: c.Sender().ID, UserID: c.Sender().ID}
+	}
+	return sessionKey{ChatID: c.Chat().ID, UserID: c.Sender().ID}
+}
+
 type TelegramBotService struct {
 	bot                          *telebot.Bot
 	telegramRateLimiter          *ratelimit.TelegramRateLimiter
 	config                       *config.TelegramConfig
+	streamConsumerConfig         *config.StreamConsumerConfig
 	tradingConfig                *config.TradingConfig
 	logger                       *logrus.Logger
 	analyzer                     *trading_analysis.Analyzer
 	stockService                 stocks.StockService
 	jobService                   jobs.JobService
 	redisClient                  *redis.Client
+	marketCalendar               *market_calendar.Calendar
+	priceService                 market_price.PriceService
+	usageService                 llm_usage.UsageService
+	outboxService                outbox.OutboxService
+	userService                  users.UserService
+	quotaService                 quota.QuotaService
+	digestService                digest.DigestService
+	notificationService          notification.NotificationService
+	groupService                 groups.GroupService
+	symbolResolver               symbols.SymbolResolver
 	router                       *gin.Engine
-	userStates                   map[int64]int                                     // UserID -> State
-	userPositionData             map[int64]*models.RequestSetPositionData          // UserID -> Data for /setposition
-	userAnalysisPositionData     map[int64]*models.RequestAnalysisPositionData     // UserID -> Data for /analyze
-	userExitPositionData         map[int64]*models.RequestExitPositionData         // UserID -> Data for /exitposition
-	userAdjustTargetPositionData map[int64]*models.RequestAdjustTargetPositionData // UserID -> Data for /adjusttargetposition
-	mu                           sync.Mutex                                        // Mutex for thread-safe operations
-	userCancelFuncs              map[int64]context.CancelFunc                      // key: telegram user ID atau chat ID
+	userStates                   map[sessionKey]int                                     // Session -> State
+	userPositionData             map[sessionKey]*models.RequestSetPositionData          // Session -> Data for /setposition
+	userAnalysisPositionData     map[sessionKey]*models.RequestAnalysisPositionData     // Session -> Data for /analyze
+	userExitPositionData         map[sessionKey]*models.RequestExitPositionData         // Session -> Data for /exitposition
+	userAdjustTargetPositionData map[sessionKey]*models.RequestAdjustTargetPositionData // Session -> Data for /adjusttargetposition
+	userSchedulerCronData        map[sessionKey]*models.RequestSchedulerCronData        // Session -> Data for /scheduler edit cron
+	userDigestType               map[sessionKey]string                                  // Session -> Jenis digest untuk /digest ubah jam
+	mu                           sync.Mutex                                             // Mutex for thread-safe operations
+	userCancelFuncs              map[sessionKey]context.CancelFunc                      // Session -> cancel proses yang sedang berjalan
+	consumerWg                   sync.WaitGroup
+	eventMetrics                 *events.Metrics
 	ctx                          context.Context
 }
 
@@ -88,6 +140,7 @@ func NewTelegramBotService(
 	cfg *config.TelegramConfig,
 	ctx context.Context,
 	tradingConfig *config.TradingConfig,
+	streamConsumerConfig *config.StreamConsumerConfig,
 	logger *logrus.Logger,
 	analyzer *trading_analysis.Analyzer,
 	stockService stocks.StockService,
@@ -95,6 +148,16 @@ func NewTelegramBotService(
 	redisClient *redis.Client,
 	bot *telebot.Bot,
 	telegramRateLimiter *ratelimit.TelegramRateLimiter,
+	marketCalendar *market_calendar.Calendar,
+	priceService market_price.PriceService,
+	usageService llm_usage.UsageService,
+	outboxService outbox.OutboxService,
+	userService users.UserService,
+	quotaService quota.QuotaService,
+	digestService digest.DigestService,
+	notificationService notification.NotificationService,
+	groupService groups.GroupService,
+	symbolResolver symbols.SymbolResolver,
 	router *gin.Engine) *TelegramBotService {
 
 	service := &TelegramBotService{
@@ -102,20 +165,34 @@ func NewTelegramBotService(
 		telegramRateLimiter:          telegramRateLimiter,
 		config:                       cfg,
 		tradingConfig:                tradingConfig,
+		streamConsumerConfig:         streamConsumerConfig,
 		logger:                       logger,
 		analyzer:                     analyzer,
 		stockService:                 stockService,
 		jobService:                   jobService,
 		redisClient:                  redisClient,
+		marketCalendar:               marketCalendar,
+		priceService:                 priceService,
+		usageService:                 usageService,
+		outboxService:                outboxService,
+		userService:                  userService,
+		quotaService:                 quotaService,
+		digestService:                digestService,
+		notificationService:          notificationService,
+		groupService:                 groupService,
+		symbolResolver:               symbolResolver,
 		router:                       router,
-		userStates:                   make(map[int64]int),
-		userPositionData:             make(map[int64]*models.RequestSetPositionData),
-		userAnalysisPositionData:     make(map[int64]*models.RequestAnalysisPositionData),
-		userExitPositionData:         make(map[int64]*models.RequestExitPositionData),
-		userAdjustTargetPositionData: make(map[int64]*models.RequestAdjustTargetPositionData),
+		userStates:                   make(map[sessionKey]int),
+		userPositionData:             make(map[sessionKey]*models.RequestSetPositionData),
+		userAnalysisPositionData:     make(map[sessionKey]*models.RequestAnalysisPositionData),
+		userExitPositionData:         make(map[sessionKey]*models.RequestExitPositionData),
+		userAdjustTargetPositionData: make(map[sessionKey]*models.RequestAdjustTargetPositionData),
+		userSchedulerCronData:        make(map[sessionKey]*models.RequestSchedulerCronData),
+		userDigestType:               make(map[sessionKey]string),
 		mu:                           sync.Mutex{},
-		userCancelFuncs:              make(map[int64]context.CancelFunc),
+		userCancelFuncs:              make(map[sessionKey]context.CancelFunc),
 		ctx:                          ctx,
+		eventMetrics:                 events.NewMetrics(),
 	}
 
 	// Register handlers
@@ -189,26 +266,29 @@ func (t *TelegramBotService) Stop() {
 		t.logger.Warn("Timeout while stopping bot, forcing shutdown")
 	}
 
+	t.consumerWg.Wait()
 	t.logger.Info("Telegram bot shutdown completed")
 }
 
 func (t *TelegramBotService) CleanUpUsersStates() {
-	t.userStates = make(map[int64]int)
-	t.userPositionData = make(map[int64]*models.RequestSetPositionData)
-	t.userAnalysisPositionData = make(map[int64]*models.RequestAnalysisPositionData)
+	t.userStates = make(map[sessionKey]int)
+	t.userPositionData = make(map[sessionKey]*models.RequestSetPositionData)
+	t.userAnalysisPositionData = make(map[sessionKey]*models.RequestAnalysisPositionData)
 }
 
-func (t *TelegramBotService) ResetUserState(userID int64) {
+func (t *TelegramBotService) ResetUserState(session sessionKey) {
 	t.mu.Lock()
-	delete(t.userStates, userID)
-	delete(t.userPositionData, userID)
-	delete(t.userAnalysisPositionData, userID)
-	delete(t.userExitPositionData, userID)
-	delete(t.userAdjustTargetPositionData, userID)
-
-	if cancel, exists := t.userCancelFuncs[userID]; exists {
+	delete(t.userStates, session)
+	delete(t.userPositionData, session)
+	delete(t.userAnalysisPositionData, session)
+	delete(t.userExitPositionData, session)
+	delete(t.userAdjustTargetPositionData, session)
+	delete(t.userSchedulerCronData, session)
+	delete(t.userDigestType, session)
+
+	if cancel, exists := t.userCancelFuncs[session]; exists {
 		cancel()
-		delete(t.userCancelFuncs, userID)
+		delete(t.userCancelFuncs, session)
 	}
 	t.mu.Unlock()
 }
@@ -224,17 +304,21 @@ func (t *TelegramBotService) SendPositionMonitoringNotification(position *models
 		return fmt.Errorf("invalid chat ID: %w", err)
 	}
 
-	message := t.FormatPositionMonitoringMessage(position)
+	// chat dari konfigurasi bukan user terdaftar, pakai bahasa default
+	message := t.FormatPositionMonitoringMessage(i18n.New(i18n.DefaultLocale), position)
 
-	_, err = t.bot.Send(&telebot.Chat{ID: chatID}, message, &telebot.SendOptions{
+	// dikirim lewat outbox agar tidak hilang saat kena flood wait atau proses restart
+	_, err = t.outboxService.Enqueue(t.ctx, models.TelegramOutboxMessage{
+		ChatID:    chatID,
+		Text:      message,
 		ParseMode: telebot.ModeHTML,
+		DedupKey:  fmt.Sprintf("position_monitoring:%s:%d", position.Symbol, position.AnalysisDate.Unix()),
 	})
-
 	if err != nil {
-		t.logger.WithError(err).Error("Failed to send position monitoring notification")
+		t.logger.WithError(err).Error("Failed to enqueue position monitoring notification")
 		return err
 	}
 
-	t.logger.WithField("symbol", position.Symbol).Info("Position monitoring notification sent")
+	t.logger.WithField("symbol", position.Symbol).Info("Position monitoring notification queued")
 	return nil
 }
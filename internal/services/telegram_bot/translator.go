@@ -0,0 +1,58 @@
+package telegram_bot
+
+import (
+	"context"
+	"golang-swing-trading-signal/internal/i18n"
+	"golang-swing-trading-signal/internal/services/users"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+const contextKeyTranslator = "translator"
+
+// translator mengembalikan translator sesuai bahasa user yang mengirim update. Hasilnya
+// disimpan di context telebot supaya akses user tidak di-resolve berulang kali.
+func (t *TelegramBotService) translator(c telebot.Context) i18n.Translator {
+	if tr, ok := c.Get(contextKeyTranslator).(i18n.Translator); ok {
+		return tr
+	}
+
+	if c.Sender() == nil {
+		return i18n.New(i18n.DefaultLocale)
+	}
+
+	access, err := t.userService.GetAccess(t.ctx, c.Sender().ID)
+	if err != nil {
+		t.logger.Warn("failed to get user access for translator", logrus.Fields{
+			"user_id": c.Sender().ID,
+			"error":   err,
+		})
+	}
+
+	tr := translatorForAccess(access, c.Sender().LanguageCode)
+	c.Set(contextKeyTranslator, tr)
+	return tr
+}
+
+// translatorFor dipakai untuk notifikasi background yang tidak punya context telebot.
+func (t *TelegramBotService) translatorFor(ctx context.Context, telegramID int64) i18n.Translator {
+	access, err := t.userService.GetAccess(ctx, telegramID)
+	if err != nil {
+		t.logger.Warn("failed to get user access for translator", logrus.Fields{
+			"user_id": telegramID,
+			"error":   err,
+		})
+	}
+	return translatorForAccess(access, "")
+}
+
+func translatorForAccess(access users.Access, languageCode string) i18n.Translator {
+	return i18n.New(i18n.Resolve(access.Language, languageCode, access.LanguageCode))
+}
+
+// button mengembalikan salinan tombol dengan label yang sudah diterjemahkan.
+func button(tr i18n.Translator, btn telebot.Btn) telebot.Btn {
+	btn.Text = tr.T(btn.Text)
+	return btn
+}
@@ -2,39 +2,52 @@ package telegram_bot
 
 import "gopkg.in/telebot.v3"
 
-// UI elements for telegram bot
+// UI elements for telegram bot. Text berisi key katalog i18n, gunakan button(tr, btn)
+// untuk mendapatkan tombol dengan label sesuai bahasa user.
 var (
-	btnSetPositionAlertPriceYes    telebot.Btn = telebot.Btn{Text: "✅ Ya", Unique: "btn_set_position_alert_price_yes", Data: "true"}
-	btnSetPositionAlertPriceNo     telebot.Btn = telebot.Btn{Text: "❌ Tidak", Unique: "btn_set_position_alert_price_no", Data: "false"}
-	btnSetPositionAlertMonitorYes  telebot.Btn = telebot.Btn{Text: "✅ Ya", Unique: "btn_set_position_alert_monitor_yes", Data: "true"}
-	btnSetPositionAlertMonitorNo   telebot.Btn = telebot.Btn{Text: "❌ Tidak", Unique: "btn_set_position_alert_monitor_no", Data: "false"}
+	btnSetPositionAlertPriceYes    telebot.Btn = telebot.Btn{Text: "button.yes", Unique: "btn_set_position_alert_price_yes", Data: "true"}
+	btnSetPositionAlertPriceNo     telebot.Btn = telebot.Btn{Text: "button.no", Unique: "btn_set_position_alert_price_no", Data: "false"}
+	btnSetPositionAlertMonitorYes  telebot.Btn = telebot.Btn{Text: "button.yes", Unique: "btn_set_position_alert_monitor_yes", Data: "true"}
+	btnSetPositionAlertMonitorNo   telebot.Btn = telebot.Btn{Text: "button.no", Unique: "btn_set_position_alert_monitor_no", Data: "false"}
 	btnStockPositionMonitoring     telebot.Btn = telebot.Btn{Unique: "btn_stock_position_monitoring"}
-	btnManageStockPosition         telebot.Btn = telebot.Btn{Text: "⚙️ Kelola", Unique: "btn_manage_stock_position"}
+	btnManageStockPosition         telebot.Btn = telebot.Btn{Text: "button.manage", Unique: "btn_manage_stock_position"}
 	btnToDetailStockPosition       telebot.Btn = telebot.Btn{Unique: "btn_list_stock_position"}
-	btnBackStockPosition           telebot.Btn = telebot.Btn{Text: "🔙 Kembali", Unique: "btn_back_stock_position"}
-	btnNewsStockPosition           telebot.Btn = telebot.Btn{Text: "📰 Berita", Unique: "btn_news_stock_position"}
-	btnBackActionStockPosition     telebot.Btn = telebot.Btn{Text: "🔙 Kembali", Unique: "btn_back_action_stock_position"}
-	btnBackDetailStockPosition     telebot.Btn = telebot.Btn{Text: "🔙 Kembali", Unique: "btn_back_detail_stock_position"}
-	btnDeleteMessage               telebot.Btn = telebot.Btn{Text: "🗑️ Hapus Pesan", Unique: "btn_delete_message"}
-	btnDeleteStockPosition         telebot.Btn = telebot.Btn{Text: "🗑️ Hapus Posisi", Unique: "btn_delete_stock_position"}
+	btnBackStockPosition           telebot.Btn = telebot.Btn{Text: "button.back", Unique: "btn_back_stock_position"}
+	btnNewsStockPosition           telebot.Btn = telebot.Btn{Text: "button.news", Unique: "btn_news_stock_position"}
+	btnBackActionStockPosition     telebot.Btn = telebot.Btn{Text: "button.back", Unique: "btn_back_action_stock_position"}
+	btnBackDetailStockPosition     telebot.Btn = telebot.Btn{Text: "button.back", Unique: "btn_back_detail_stock_position"}
+	btnDeleteMessage               telebot.Btn = telebot.Btn{Text: "button.delete_message", Unique: "btn_delete_message"}
+	btnDeleteStockPosition         telebot.Btn = telebot.Btn{Text: "button.delete_position", Unique: "btn_delete_stock_position"}
 	btnUpdateAlertPrice            telebot.Btn = telebot.Btn{Unique: "btn_update_alert_price"}
 	btnUpdateAlertMonitor          telebot.Btn = telebot.Btn{Unique: "btn_update_alert_monitor"}
 	btnExitStockPosition           telebot.Btn = telebot.Btn{Unique: "btn_exit_stock_position"}
-	btnSaveExitPosition            telebot.Btn = telebot.Btn{Text: "💾 Simpan", Unique: "btn_save_exit_position"}
-	btnCancelGeneral               telebot.Btn = telebot.Btn{Text: "❌ Batal", Unique: "btn_cancel_general"}
-	btnCancelBuyListAnalysis       telebot.Btn = telebot.Btn{Text: "⛔ Hentikan Analisis", Unique: "btn_cancel_buy_list_analysis"}
-	btnActionNewsFind              telebot.Btn = telebot.Btn{Text: "• Cari Berita", Unique: "btn_action_news_find"}
-	btnActionTopNews               telebot.Btn = telebot.Btn{Text: "• Top Berita Saham", Unique: "btn_action_top_news"}
+	btnSaveExitPosition            telebot.Btn = telebot.Btn{Text: "button.save", Unique: "btn_save_exit_position"}
+	btnCancelGeneral               telebot.Btn = telebot.Btn{Text: "button.cancel", Unique: "btn_cancel_general"}
+	btnCancelBuyListAnalysis       telebot.Btn = telebot.Btn{Text: "button.stop_analysis", Unique: "btn_cancel_buy_list_analysis"}
+	btnActionNewsFind              telebot.Btn = telebot.Btn{Text: "button.news_find", Unique: "btn_action_news_find"}
+	btnActionTopNews               telebot.Btn = telebot.Btn{Text: "button.top_news", Unique: "btn_action_top_news"}
 	btnNewsConfirmSendSummary      telebot.Btn = telebot.Btn{Unique: "btn_news_confirm_send_summary"}
-	btnAdjustTargetPosition        telebot.Btn = telebot.Btn{Text: "🎯 Atur Target", Unique: "btn_adjust_target_position"}
-	btnAdjustTargetPositionConfirm telebot.Btn = telebot.Btn{Text: "✅ Konfirmasi", Unique: "btn_adjust_target_position_confirm"}
+	btnAdjustTargetPosition        telebot.Btn = telebot.Btn{Text: "button.adjust_target", Unique: "btn_adjust_target_position"}
+	btnAdjustTargetPositionConfirm telebot.Btn = telebot.Btn{Text: "button.confirm", Unique: "btn_adjust_target_position_confirm"}
 	btnDetailJob                   telebot.Btn = telebot.Btn{Unique: "btn_detail_job"}
-	btnActionBackToJobList         telebot.Btn = telebot.Btn{Text: "🔙 Kembali", Unique: "btn_action_back_to_job_list"}
-	btnActionRunJob                telebot.Btn = telebot.Btn{Text: "🚀 Jalankan", Unique: "btn_action_run_job"}
-)
-
-var (
-	commonMessageInternalError  string = "❌ Terjadi kesalahan internal, silakan coba lagi."
-	messageLoadingAnalysis      string = "🔍 Menganalisis: $%s"
-	messageAnalysisNotAvailable string = "🔍Saat ini, data analisa untuk saham $%s belum tersedia.\n\nNamun jangan khawatir — proses analisa sedang kami mulai untuk mendapatkan insight terbaru. Kami akan segera memberitahumu begitu hasil analisa siap.\n\nMohon ditunggu sebentar, ya!"
+	btnActionBackToJobList         telebot.Btn = telebot.Btn{Text: "button.back", Unique: "btn_action_back_to_job_list"}
+	btnActionRunJob                telebot.Btn = telebot.Btn{Text: "button.run_job", Unique: "btn_action_run_job"}
+	btnActionPauseJob              telebot.Btn = telebot.Btn{Text: "button.pause_job", Unique: "btn_action_pause_job"}
+	btnActionResumeJob             telebot.Btn = telebot.Btn{Text: "button.resume_job", Unique: "btn_action_resume_job"}
+	btnActionEditJobCron           telebot.Btn = telebot.Btn{Text: "button.edit_cron", Unique: "btn_action_edit_job_cron"}
+	btnActionEditJobCronConfirm    telebot.Btn = telebot.Btn{Text: "button.save_cron", Unique: "btn_action_edit_job_cron_confirm"}
+	btnJobHistory                  telebot.Btn = telebot.Btn{Text: "button.job_history", Unique: "btn_job_history"}
+	btnJobHistoryDetail            telebot.Btn = telebot.Btn{Unique: "btn_job_history_detail"}
+	btnActionCancelExecution       telebot.Btn = telebot.Btn{Text: "button.cancel_execution", Unique: "btn_action_cancel_execution"}
+	btnJobPipeline                 telebot.Btn = telebot.Btn{Text: "button.pipeline", Unique: "btn_job_pipeline"}
+	btnDigestToggle                telebot.Btn = telebot.Btn{Unique: "btn_digest_toggle"}
+	btnDigestSetTime               telebot.Btn = telebot.Btn{Unique: "btn_digest_set_time"}
+	btnSettingsToggle              telebot.Btn = telebot.Btn{Unique: "btn_settings_toggle"}
+	btnSettingsQuietHours          telebot.Btn = telebot.Btn{Text: "button.settings_quiet_hours", Unique: "btn_settings_quiet_hours"}
+	btnSettingsMinConfidence       telebot.Btn = telebot.Btn{Unique: "btn_settings_min_confidence"}
+	btnSettingsVerbosity           telebot.Btn = telebot.Btn{Unique: "btn_settings_verbosity"}
+	btnLanguage                    telebot.Btn = telebot.Btn{Unique: "btn_language"}
+	btnGroupToggleSignal           telebot.Btn = telebot.Btn{Unique: "btn_group_toggle_signal"}
+	btnGroupToggleDigest           telebot.Btn = telebot.Btn{Unique: "btn_group_toggle_digest"}
+	btnSymbolSuggestion            telebot.Btn = telebot.Btn{Unique: "btn_symbol_suggestion"}
 )
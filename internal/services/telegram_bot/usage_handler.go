@@ -0,0 +1,23 @@
+package telegram_bot
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+func (t *TelegramBotService) handleUsage(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	report, err := t.usageService.GetReport(ctx)
+	if err != nil {
+		t.logger.Error("failed to get llm usage report", logrus.Fields{
+			"error": err,
+		})
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	_, err = t.telegramRateLimiter.Send(ctx, c, t.formatMessageUsageReport(tr, report), telebot.ModeHTML)
+	return err
+}
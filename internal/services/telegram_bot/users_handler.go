@@ -0,0 +1,299 @@
+package telegram_bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang-swing-trading-signal/internal/i18n"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/services/users"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/telebot.v3"
+)
+
+const (
+	maxShowUsers          = 20
+	defaultInviteMaxUses  = 1
+	defaultInviteValidFor = 7 * 24 * time.Hour
+)
+
+// handleStartRedeemInvite dipanggil dari /start <kode>. Mengembalikan role terbaru user.
+func (t *TelegramBotService) handleStartRedeemInvite(ctx context.Context, c telebot.Context, code string, current models.Role) (models.Role, error) {
+	tr := t.translator(c)
+	role, err := t.userService.RedeemInvite(ctx, c.Sender().ID, code)
+	if err != nil {
+		if errors.Is(err, users.ErrInvalidInviteCode) {
+			_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("users.invite_invalid"))
+			return current, err
+		}
+		t.logger.Error("failed to redeem invite code", logrus.Fields{
+			"user_id": c.Sender().ID,
+			"error":   err,
+		})
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return current, err
+	}
+
+	_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("users.invite_redeemed", roleLabel(role)), telebot.ModeHTML)
+	return role, err
+}
+
+func (t *TelegramBotService) handleUsers(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	list, err := t.userService.GetUsers(ctx, maxShowUsers)
+	if err != nil {
+		t.logger.Error("failed to get users", logrus.Fields{
+			"error": err,
+		})
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(tr.Plural("users.list_title", len(list)))
+	for _, user := range list {
+		sb.WriteString(formatUserLine(tr, &user))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(tr.T("users.list_footer"))
+
+	_, err = t.telegramRateLimiter.Send(ctx, c, sb.String(), telebot.ModeHTML)
+	return err
+}
+
+// handleInvite membuat kode undangan. Format: /invite [member|admin] [maks_pakai] [berlaku_hari],
+// maks_pakai 0 berarti tidak dibatasi dan berlaku_hari 0 berarti tidak kedaluwarsa.
+func (t *TelegramBotService) handleInvite(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	args := c.Args()
+	role := models.RoleMember
+	maxUses := defaultInviteMaxUses
+	validFor := defaultInviteValidFor
+
+	if len(args) > 0 {
+		role = models.Role(strings.ToLower(args[0]))
+	}
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 0 {
+			_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("users.invite_usage"), telebot.ModeHTML)
+			return err
+		}
+		maxUses = n
+	}
+	if len(args) > 2 {
+		days, err := strconv.Atoi(args[2])
+		if err != nil || days < 0 {
+			_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("users.invite_usage"), telebot.ModeHTML)
+			return err
+		}
+		validFor = time.Duration(days) * 24 * time.Hour
+	}
+
+	invite, err := t.userService.CreateInvite(ctx, c.Sender().ID, role, maxUses, validFor)
+	if err != nil {
+		if errors.Is(err, users.ErrInvalidRole) {
+			_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("users.invite_usage"), telebot.ModeHTML)
+			return err
+		}
+		t.logger.Error("failed to create invite code", logrus.Fields{
+			"error": err,
+		})
+		_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("common.internal_error"))
+		return err
+	}
+
+	_, err = t.telegramRateLimiter.Send(ctx, c, t.formatInvite(tr, invite), telebot.ModeHTML)
+	return err
+}
+
+func (t *TelegramBotService) handlePromote(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	return t.changeUserAccess(ctx, c, func(user *models.UserEntity) (string, error) {
+		role := user.Role.Promote()
+		if err := t.userService.SetRole(ctx, c.Sender().ID, user.TelegramID, role); err != nil {
+			return "", err
+		}
+		return tr.T("users.promoted", formatUserName(user), roleLabel(role)), nil
+	})
+}
+
+func (t *TelegramBotService) handleDemote(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	return t.changeUserAccess(ctx, c, func(user *models.UserEntity) (string, error) {
+		role := user.Role.Demote()
+		if err := t.userService.SetRole(ctx, c.Sender().ID, user.TelegramID, role); err != nil {
+			return "", err
+		}
+		return tr.T("users.demoted", formatUserName(user), roleLabel(role)), nil
+	})
+}
+
+func (t *TelegramBotService) handleBan(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	return t.changeUserAccess(ctx, c, func(user *models.UserEntity) (string, error) {
+		if err := t.userService.SetBanned(ctx, c.Sender().ID, user.TelegramID, true); err != nil {
+			return "", err
+		}
+		return tr.T("users.banned", formatUserName(user)), nil
+	})
+}
+
+func (t *TelegramBotService) handleUnban(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	return t.changeUserAccess(ctx, c, func(user *models.UserEntity) (string, error) {
+		if err := t.userService.SetBanned(ctx, c.Sender().ID, user.TelegramID, false); err != nil {
+			return "", err
+		}
+		return tr.T("users.unbanned", formatUserName(user)), nil
+	})
+}
+
+// handleSetPlan mengubah plan user. Format: /setplan <telegram_id|@username> <free|pro> [berlaku_hari]
+func (t *TelegramBotService) handleSetPlan(ctx context.Context, c telebot.Context) error {
+	tr := t.translator(c)
+	args := c.Args()
+	if len(args) < 2 {
+		_, err := t.telegramRateLimiter.Send(ctx, c, tr.T("users.setplan_usage"), telebot.ModeHTML)
+		return err
+	}
+
+	plan := models.Plan(strings.ToLower(args[1]))
+	var validFor time.Duration
+	if len(args) > 2 {
+		days, err := strconv.Atoi(args[2])
+		if err != nil || days < 0 {
+			_, err = t.telegramRateLimiter.Send(ctx, c, tr.T("users.setplan_usage"), telebot.ModeHTML)
+			return err
+		}
+		validFor = time.Duration(days) * 24 * time.Hour
+	}
+
+	user, err := t.userService.FindUser(ctx, args[0])
+	if err == nil {
+		err = t.userService.SetPlan(ctx, user.TelegramID, plan, validFor)
+	}
+
+	var message string
+	switch {
+	case err == nil:
+		message = tr.T("users.plan_updated", formatUserName(user), planLabel(plan))
+		if plan == models.PlanPro && validFor > 0 {
+			message += tr.Plural("users.plan_valid_days", int(validFor.Hours()/24))
+		}
+	case errors.Is(err, users.ErrInvalidPlan):
+		message = tr.T("users.setplan_usage")
+	case errors.Is(err, users.ErrUserNotFound):
+		message = tr.T("users.not_found")
+	default:
+		t.logger.Error("failed to set user plan", logrus.Fields{
+			"target": args[0],
+			"error":  err,
+		})
+		message = tr.T("common.internal_error")
+	}
+	_, err = t.telegramRateLimiter.Send(ctx, c, message, telebot.ModeHTML)
+	return err
+}
+
+// changeUserAccess mencari user target dari argumen perintah lalu menjalankan perubahan akses.
+func (t *TelegramBotService) changeUserAccess(ctx context.Context, c telebot.Context, apply func(user *models.UserEntity) (string, error)) error {
+	tr := t.translator(c)
+	args := c.Args()
+	if len(args) == 0 {
+		_, err := t.telegramRateLimiter.Send(ctx, c, tr.T("users.access_usage", c.Message().Text), telebot.ModeHTML)
+		return err
+	}
+
+	user, err := t.userService.FindUser(ctx, args[0])
+	if err == nil {
+		var message string
+		message, err = apply(user)
+		if err == nil {
+			_, err = t.telegramRateLimiter.Send(ctx, c, message, telebot.ModeHTML)
+			return err
+		}
+	}
+
+	var message string
+	switch {
+	case errors.Is(err, users.ErrUserNotFound):
+		message = tr.T("users.not_found")
+	case errors.Is(err, users.ErrCannotModifySelf):
+		message = tr.T("users.cannot_modify_self")
+	case errors.Is(err, users.ErrProtectedUser):
+		message = tr.T("users.protected")
+	case errors.Is(err, users.ErrRoleAlreadyApplied):
+		message = tr.T("users.role_already_applied")
+	default:
+		t.logger.Error("failed to change user access", logrus.Fields{
+			"target": args[0],
+			"error":  err,
+		})
+		message = tr.T("common.internal_error")
+	}
+	_, err = t.telegramRateLimiter.Send(ctx, c, message)
+	return err
+}
+
+func (t *TelegramBotService) formatInvite(tr i18n.Translator, invite *models.InviteCodeEntity) string {
+	var sb strings.Builder
+	sb.WriteString(tr.T("users.invite_created", invite.Code, roleLabel(invite.Role)))
+	if invite.MaxUses == 0 {
+		sb.WriteString(tr.T("users.invite_max_uses_unlimited"))
+	} else {
+		sb.WriteString(tr.T("users.invite_max_uses", invite.MaxUses))
+	}
+	if invite.ExpiresAt != nil {
+		sb.WriteString(tr.T("users.invite_expires", tr.DateTime(utils.TimeToWIB(*invite.ExpiresAt))))
+	}
+	sb.WriteString(tr.T("users.invite_share", invite.Code))
+	if t.bot.Me != nil && t.bot.Me.Username != "" {
+		sb.WriteString(tr.T("users.invite_share_link", t.bot.Me.Username, invite.Code))
+	}
+	return sb.String()
+}
+
+func formatUserLine(tr i18n.Translator, user *models.UserEntity) string {
+	line := fmt.Sprintf("%s %s <code>%d</code> - %s, %s", roleIcon(user.Role), formatUserName(user), user.TelegramID, roleLabel(user.Role), planLabel(user.ActivePlan(utils.TimeNowWIB())))
+	if user.IsBanned() {
+		line += tr.T("users.banned_suffix")
+	}
+	return line
+}
+
+func formatUserName(user *models.UserEntity) string {
+	if user.Username != "" {
+		return "@" + html.EscapeString(user.Username)
+	}
+	return html.EscapeString(strings.TrimSpace(user.FirstName + " " + user.LastName))
+}
+
+func roleLabel(role models.Role) string {
+	switch role {
+	case models.RoleAdmin:
+		return "Admin"
+	case models.RoleMember:
+		return "Member"
+	default:
+		return "Guest"
+	}
+}
+
+func roleIcon(role models.Role) string {
+	switch role {
+	case models.RoleAdmin:
+		return "👑"
+	case models.RoleMember:
+		return "👤"
+	default:
+		return "👻"
+	}
+}
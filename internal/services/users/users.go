@@ -0,0 +1,408 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/i18n"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/repository"
+	"golang-swing-trading-signal/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidInviteCode  = errors.New("invalid invite code")
+	ErrInvalidRole        = errors.New("invalid role")
+	ErrInvalidPlan        = errors.New("invalid plan")
+	ErrProtectedUser      = errors.New("user role is managed by config")
+	ErrCannotModifySelf   = errors.New("cannot modify own access")
+	ErrRoleAlreadyApplied = errors.New("role already applied")
+	ErrInvalidLanguage    = errors.New("invalid language")
+)
+
+const (
+	// roleCacheTTL membatasi berapa lama perubahan role dari replika lain baru terlihat.
+	roleCacheTTL     = time.Minute
+	inviteCodeLength = 8
+	// tanpa karakter yang mirip (0/O, 1/I/L) supaya kode mudah diketik ulang
+	inviteCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+)
+
+// Access adalah hak akses efektif user setelah digabung dengan konfigurasi admin & allowlist.
+// Language dan LanguageCode ikut di-cache untuk memilih bahasa pesan bot.
+type Access struct {
+	Role         models.Role
+	Banned       bool
+	Language     string
+	LanguageCode string
+}
+
+type UserService interface {
+	// Register membuat user jika belum ada dan mengembalikan user dengan role efektif.
+	Register(ctx context.Context, req *models.RequestUserTelegram) (*models.UserEntity, error)
+	GetAccess(ctx context.Context, telegramID int64) (Access, error)
+	RedeemInvite(ctx context.Context, telegramID int64, code string) (models.Role, error)
+	CreateInvite(ctx context.Context, createdBy int64, role models.Role, maxUses int, validFor time.Duration) (*models.InviteCodeEntity, error)
+	// FindUser mencari user berdasarkan telegram ID atau @username.
+	FindUser(ctx context.Context, query string) (*models.UserEntity, error)
+	GetUsers(ctx context.Context, limit int) ([]models.UserEntity, error)
+	SetRole(ctx context.Context, actorID, telegramID int64, role models.Role) error
+	SetBanned(ctx context.Context, actorID, telegramID int64, banned bool) error
+	// SetPlan mengubah plan user, validFor 0 berarti plan pro tidak kedaluwarsa.
+	SetPlan(ctx context.Context, telegramID int64, plan models.Plan, validFor time.Duration) error
+	// SetLanguage menyimpan bahasa pilihan user, string kosong kembali mengikuti bahasa Telegram.
+	SetLanguage(ctx context.Context, telegramID int64, language string) error
+}
+
+type cachedAccess struct {
+	access    Access
+	expiresAt time.Time
+}
+
+type userService struct {
+	cfg                  *config.TelegramConfig
+	logger               *logrus.Logger
+	userRepository       repository.UserRepository
+	inviteCodeRepository repository.InviteCodeRepository
+	unitOfWork           repository.UnitOfWork
+	now                  func() time.Time
+
+	mu    sync.Mutex
+	cache map[int64]cachedAccess
+}
+
+func NewUserService(cfg *config.TelegramConfig, logger *logrus.Logger, userRepository repository.UserRepository, inviteCodeRepository repository.InviteCodeRepository, unitOfWork repository.UnitOfWork) UserService {
+	return &userService{
+		cfg:                  cfg,
+		logger:               logger,
+		userRepository:       userRepository,
+		inviteCodeRepository: inviteCodeRepository,
+		unitOfWork:           unitOfWork,
+		now:                  utils.TimeNowWIB,
+		cache:                make(map[int64]cachedAccess),
+	}
+}
+
+// ResolveAccess menggabungkan data user di database dengan konfigurasi. Admin dari
+// TELEGRAM_ADMIN_IDS selalu admin dan tidak bisa diblokir, allowlist minimal member.
+// user nil berarti user belum pernah /start.
+func ResolveAccess(cfg *config.TelegramConfig, telegramID int64, user *models.UserEntity) Access {
+	if slices.Contains(cfg.AdminIDs, telegramID) {
+		access := Access{Role: models.RoleAdmin}
+		if user != nil {
+			access.Language, access.LanguageCode = user.Language, user.LanguageCode
+		}
+		return access
+	}
+
+	access := Access{Role: defaultRole(cfg)}
+	if user != nil {
+		access = Access{Role: user.Role, Banned: user.IsBanned(), Language: user.Language, LanguageCode: user.LanguageCode}
+		if !access.Role.Valid() {
+			access.Role = models.RoleGuest
+		}
+	}
+
+	if slices.Contains(cfg.AllowlistIDs, telegramID) && !access.Role.Allows(models.RoleMember) {
+		access.Role = models.RoleMember
+	}
+	return access
+}
+
+func defaultRole(cfg *config.TelegramConfig) models.Role {
+	role := models.Role(cfg.DefaultRole)
+	if role == models.RoleMember {
+		return role
+	}
+	// default role admin tidak diizinkan, admin harus lewat config atau promote
+	return models.RoleGuest
+}
+
+func (s *userService) Register(ctx context.Context, req *models.RequestUserTelegram) (*models.UserEntity, error) {
+	user, err := s.userRepository.GetUserByTelegramID(ctx, req.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user == nil {
+		user = req.ToUserEntity()
+		user.Role = ResolveAccess(s.cfg, req.ID, nil).Role
+		if err := s.userRepository.CreateUser(ctx, user); err != nil {
+			return nil, fmt.Errorf("failed to create user: %w", err)
+		}
+	}
+
+	access := ResolveAccess(s.cfg, req.ID, user)
+	user.Role = access.Role
+	s.setCache(req.ID, access)
+	return user, nil
+}
+
+func (s *userService) GetAccess(ctx context.Context, telegramID int64) (Access, error) {
+	if access, ok := s.getCache(telegramID); ok {
+		return access, nil
+	}
+
+	user, err := s.userRepository.GetUserByTelegramID(ctx, telegramID)
+	if err != nil {
+		return Access{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	access := ResolveAccess(s.cfg, telegramID, user)
+	s.setCache(telegramID, access)
+	return access, nil
+}
+
+func (s *userService) RedeemInvite(ctx context.Context, telegramID int64, code string) (models.Role, error) {
+	var role models.Role
+	err := s.unitOfWork.Run(func(opts ...utils.DBOption) error {
+		user, err := s.userRepository.GetUserByTelegramID(ctx, telegramID, opts...)
+		if err != nil {
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		if user == nil {
+			return ErrUserNotFound
+		}
+
+		invite, err := s.inviteCodeRepository.RedeemInviteCode(ctx, strings.ToUpper(strings.TrimSpace(code)), s.now(), opts...)
+		if err != nil {
+			return fmt.Errorf("failed to redeem invite code: %w", err)
+		}
+		if invite == nil {
+			return ErrInvalidInviteCode
+		}
+
+		// kode undangan tidak pernah menurunkan role yang sudah lebih tinggi
+		role = ResolveAccess(s.cfg, telegramID, user).Role
+		if role.Allows(invite.Role) {
+			return nil
+		}
+		role = invite.Role
+		if err := s.userRepository.UpdateUser(ctx, telegramID, map[string]any{"role": role}, opts...); err != nil {
+			return fmt.Errorf("failed to update user role: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	s.logger.Info("invite code redeemed", logrus.Fields{
+		"telegram_id": telegramID,
+		"role":        role,
+	})
+	s.invalidate(telegramID)
+	return role, nil
+}
+
+func (s *userService) CreateInvite(ctx context.Context, createdBy int64, role models.Role, maxUses int, validFor time.Duration) (*models.InviteCodeEntity, error) {
+	if role != models.RoleMember && role != models.RoleAdmin {
+		return nil, ErrInvalidRole
+	}
+
+	code, err := generateInviteCode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate invite code: %w", err)
+	}
+
+	invite := &models.InviteCodeEntity{
+		Code:      code,
+		Role:      role,
+		MaxUses:   maxUses,
+		CreatedBy: createdBy,
+	}
+	if validFor > 0 {
+		invite.ExpiresAt = utils.ToPointer(s.now().Add(validFor))
+	}
+
+	if err := s.inviteCodeRepository.CreateInviteCode(ctx, invite); err != nil {
+		return nil, fmt.Errorf("failed to create invite code: %w", err)
+	}
+	return invite, nil
+}
+
+func (s *userService) FindUser(ctx context.Context, query string) (*models.UserEntity, error) {
+	query = strings.TrimSpace(query)
+
+	var (
+		user *models.UserEntity
+		err  error
+	)
+	if telegramID, errParse := strconv.ParseInt(query, 10, 64); errParse == nil {
+		user, err = s.userRepository.GetUserByTelegramID(ctx, telegramID)
+	} else {
+		user, err = s.userRepository.GetUserByUsername(ctx, strings.TrimPrefix(query, "@"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	access := ResolveAccess(s.cfg, user.TelegramID, user)
+	user.Role = access.Role
+	return user, nil
+}
+
+func (s *userService) GetUsers(ctx context.Context, limit int) ([]models.UserEntity, error) {
+	users, err := s.userRepository.GetUsers(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+
+	for i := range users {
+		users[i].Role = ResolveAccess(s.cfg, users[i].TelegramID, &users[i]).Role
+	}
+	return users, nil
+}
+
+func (s *userService) SetRole(ctx context.Context, actorID, telegramID int64, role models.Role) error {
+	if !role.Valid() {
+		return ErrInvalidRole
+	}
+	user, err := s.guardTarget(ctx, actorID, telegramID)
+	if err != nil {
+		return err
+	}
+	if user.Role == role {
+		return ErrRoleAlreadyApplied
+	}
+
+	if err := s.userRepository.UpdateUser(ctx, telegramID, map[string]any{"role": role}); err != nil {
+		return fmt.Errorf("failed to update user role: %w", err)
+	}
+
+	s.invalidate(telegramID)
+	return nil
+}
+
+func (s *userService) SetBanned(ctx context.Context, actorID, telegramID int64, banned bool) error {
+	user, err := s.guardTarget(ctx, actorID, telegramID)
+	if err != nil {
+		return err
+	}
+	if user.IsBanned() == banned {
+		return ErrRoleAlreadyApplied
+	}
+
+	var bannedAt *time.Time
+	if banned {
+		bannedAt = utils.ToPointer(s.now())
+	}
+	if err := s.userRepository.UpdateUser(ctx, telegramID, map[string]any{"banned_at": bannedAt}); err != nil {
+		return fmt.Errorf("failed to update user banned status: %w", err)
+	}
+
+	s.invalidate(telegramID)
+	return nil
+}
+
+func (s *userService) SetPlan(ctx context.Context, telegramID int64, plan models.Plan, validFor time.Duration) error {
+	if !plan.Valid() {
+		return ErrInvalidPlan
+	}
+
+	user, err := s.userRepository.GetUserByTelegramID(ctx, telegramID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	var expiresAt *time.Time
+	if plan == models.PlanPro && validFor > 0 {
+		expiresAt = utils.ToPointer(s.now().Add(validFor))
+	}
+	if err := s.userRepository.UpdateUser(ctx, telegramID, map[string]any{"plan": plan, "plan_expires_at": expiresAt}); err != nil {
+		return fmt.Errorf("failed to update user plan: %w", err)
+	}
+	return nil
+}
+
+func (s *userService) SetLanguage(ctx context.Context, telegramID int64, language string) error {
+	if language != "" {
+		locale, ok := i18n.Parse(language)
+		if !ok {
+			return ErrInvalidLanguage
+		}
+		language = string(locale)
+	}
+
+	if err := s.userRepository.UpdateUser(ctx, telegramID, map[string]any{"language": language}); err != nil {
+		return fmt.Errorf("failed to update user language: %w", err)
+	}
+
+	s.invalidate(telegramID)
+	return nil
+}
+
+// guardTarget memastikan admin tidak mengubah aksesnya sendiri atau admin dari config.
+func (s *userService) guardTarget(ctx context.Context, actorID, telegramID int64) (*models.UserEntity, error) {
+	if actorID == telegramID {
+		return nil, ErrCannotModifySelf
+	}
+	if slices.Contains(s.cfg.AdminIDs, telegramID) {
+		return nil, ErrProtectedUser
+	}
+
+	user, err := s.userRepository.GetUserByTelegramID(ctx, telegramID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (s *userService) getCache(telegramID int64) (Access, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cached, ok := s.cache[telegramID]
+	if !ok || s.now().After(cached.expiresAt) {
+		return Access{}, false
+	}
+	return cached.access, true
+}
+
+func (s *userService) setCache(telegramID int64, access Access) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cache[telegramID] = cachedAccess{access: access, expiresAt: s.now().Add(roleCacheTTL)}
+}
+
+func (s *userService) invalidate(telegramID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.cache, telegramID)
+}
+
+func generateInviteCode() (string, error) {
+	var sb strings.Builder
+	max := big.NewInt(int64(len(inviteCodeAlphabet)))
+	for range inviteCodeLength {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(inviteCodeAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,80 @@
+package users
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang-swing-trading-signal/internal/config"
+	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
+)
+
+func TestResolveAccess(t *testing.T) {
+	cfg := &config.TelegramConfig{AdminIDs: []int64{1}, AllowlistIDs: []int64{2}}
+	bannedAt := utils.ToPointer(time.Now())
+
+	tests := []struct {
+		name       string
+		cfg        *config.TelegramConfig
+		telegramID int64
+		user       *models.UserEntity
+		want       Access
+	}{
+		{name: "config admin without user", cfg: cfg, telegramID: 1, want: Access{Role: models.RoleAdmin}},
+		{name: "config admin cannot be banned", cfg: cfg, telegramID: 1, user: &models.UserEntity{Role: models.RoleGuest, BannedAt: bannedAt}, want: Access{Role: models.RoleAdmin}},
+		{name: "allowlist without user", cfg: cfg, telegramID: 2, want: Access{Role: models.RoleMember}},
+		{name: "allowlist upgrades guest", cfg: cfg, telegramID: 2, user: &models.UserEntity{Role: models.RoleGuest}, want: Access{Role: models.RoleMember}},
+		{name: "allowlist keeps promoted admin", cfg: cfg, telegramID: 2, user: &models.UserEntity{Role: models.RoleAdmin}, want: Access{Role: models.RoleAdmin}},
+		{name: "unknown user gets default guest", cfg: cfg, telegramID: 3, want: Access{Role: models.RoleGuest}},
+		{name: "unknown user gets default member", cfg: &config.TelegramConfig{DefaultRole: "member"}, telegramID: 3, want: Access{Role: models.RoleMember}},
+		{name: "default admin is not allowed", cfg: &config.TelegramConfig{DefaultRole: "admin"}, telegramID: 3, want: Access{Role: models.RoleGuest}},
+		{name: "stored role", cfg: cfg, telegramID: 3, user: &models.UserEntity{Role: models.RoleMember}, want: Access{Role: models.RoleMember}},
+		{name: "invalid stored role falls back to guest", cfg: cfg, telegramID: 3, user: &models.UserEntity{Role: "owner"}, want: Access{Role: models.RoleGuest}},
+		{name: "banned user", cfg: cfg, telegramID: 3, user: &models.UserEntity{Role: models.RoleMember, BannedAt: bannedAt}, want: Access{Role: models.RoleMember, Banned: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveAccess(tt.cfg, tt.telegramID, tt.user); got != tt.want {
+				t.Errorf("ResolveAccess() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRole_Allows(t *testing.T) {
+	tests := []struct {
+		role     models.Role
+		required models.Role
+		want     bool
+	}{
+		{role: models.RoleAdmin, required: models.RoleMember, want: true},
+		{role: models.RoleMember, required: models.RoleMember, want: true},
+		{role: models.RoleMember, required: models.RoleAdmin, want: false},
+		{role: models.RoleGuest, required: models.RoleMember, want: false},
+		{role: models.RoleGuest, required: models.RoleGuest, want: true},
+		{role: "", required: models.RoleGuest, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.role)+"_"+string(tt.required), func(t *testing.T) {
+			if got := tt.role.Allows(tt.required); got != tt.want {
+				t.Errorf("Allows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateInviteCode(t *testing.T) {
+	code, err := generateInviteCode()
+	if err != nil {
+		t.Fatalf("generateInviteCode() error = %v", err)
+	}
+	if len(code) != inviteCodeLength {
+		t.Fatalf("len(code) = %d, want %d", len(code), inviteCodeLength)
+	}
+	for _, r := range code {
+		if !strings.ContainsRune(inviteCodeAlphabet, r) {
+			t.Errorf("code %q contains unexpected character %q", code, r)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package yahoo_finance
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,6 +13,7 @@ import (
 
 	"golang-swing-trading-signal/internal/config"
 	"golang-swing-trading-signal/internal/models"
+	"golang-swing-trading-signal/internal/utils"
 
 	"github.com/sirupsen/logrus"
 )
@@ -36,7 +38,7 @@ type OHLCDataWithInfo struct {
 	DataInfo models.DataInfo
 }
 
-func (c *Client) GetOHLCData(symbol string, period1, period2 int64, interval string) (*OHLCDataWithInfo, error) {
+func (c *Client) GetOHLCData(ctx context.Context, symbol string, period1, period2 int64, interval string) (*OHLCDataWithInfo, error) {
 	// Add .JK suffix for Indonesian stocks
 	indonesianSymbol := symbol + ".JK"
 
@@ -57,7 +59,7 @@ func (c *Client) GetOHLCData(symbol string, period1, period2 int64, interval str
 	})
 
 	// Create HTTP request
-	req, err := http.NewRequest("GET", requestURL, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -78,7 +80,7 @@ func (c *Client) GetOHLCData(symbol string, period1, period2 int64, interval str
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Yahoo Finance API returned status: %d", resp.StatusCode)
+		return nil, &utils.HTTPStatusError{Service: "Yahoo Finance API", StatusCode: resp.StatusCode}
 	}
 
 	// Read response body
@@ -180,7 +182,7 @@ func (c *Client) GetOHLCData(symbol string, period1, period2 int64, interval str
 }
 
 // GetRecentOHLCData gets the last 60 days of OHLC data
-func (c *Client) GetRecentOHLCData(symbol string, interval string, period string) (*OHLCDataWithInfo, error) {
+func (c *Client) GetRecentOHLCData(ctx context.Context, symbol string, interval string, period string) (*OHLCDataWithInfo, error) {
 	period1, period2 := c.MapPeriodeStringToUnix("2m")
 
 	if period != "" {
@@ -190,7 +192,7 @@ func (c *Client) GetRecentOHLCData(symbol string, interval string, period string
 	if interval == "" {
 		interval = "1d"
 	}
-	return c.GetOHLCData(symbol, period1, period2, interval)
+	return c.GetOHLCData(ctx, symbol, period1, period2, interval)
 }
 
 // MapPeriodeStringToUnix convert days to unix timestamp
@@ -223,8 +225,8 @@ func (c *Client) MapPeriodeStringToUnix(periode string) (int64, int64) {
 }
 
 // GetLatestOHLCData gets the most recent OHLC data
-func (c *Client) GetLatestOHLCData(symbol string) (*models.OHLCVData, error) {
-	ohlcvDataWithInfo, err := c.GetRecentOHLCData(symbol, "", "")
+func (c *Client) GetLatestOHLCData(ctx context.Context, symbol string) (*models.OHLCVData, error) {
+	ohlcvDataWithInfo, err := c.GetRecentOHLCData(ctx, symbol, "", "")
 	if err != nil {
 		return nil, err
 	}
@@ -113,3 +113,16 @@ func RemainingDays(maxHoldingDays int, buyTime time.Time) int {
 
 	return remaining
 }
+
+// ParseClock membaca jam dalam format H:MM, HH:MM atau HH.MM.
+func ParseClock(value string) (hour, minute int, ok bool) {
+	if _, err := fmt.Sscanf(value, "%d:%d", &hour, &minute); err != nil {
+		if _, err := fmt.Sscanf(value, "%d.%d", &hour, &minute); err != nil {
+			return 0, 0, false
+		}
+	}
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, 0, false
+	}
+	return hour, minute, true
+}
@@ -0,0 +1,18 @@
+package utils
+
+import "fmt"
+
+// HTTPStatusError dikembalikan client HTTP eksternal (Yahoo Finance, LLM) ketika response
+// bukan 200, supaya pemanggil bisa membedakan rate limit dan error server dari error lain.
+type HTTPStatusError struct {
+	Service    string
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPStatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("%s returned status code %d", e.Service, e.StatusCode)
+	}
+	return fmt.Sprintf("%s returned status code %d: %s", e.Service, e.StatusCode, e.Body)
+}
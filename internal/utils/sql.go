@@ -1,6 +1,10 @@
 package utils
 
-import "gorm.io/gorm"
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
 
 type DBOption func(*gorm.DB) *gorm.DB
 
@@ -23,6 +27,11 @@ func WithPreload(column string) DBOption {
 	}
 }
 
+// EscapeLike meng-escape karakter wildcard LIKE agar input user dicari apa adanya.
+func EscapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
 func WithWhere(query interface{}, args ...interface{}) DBOption {
 	return func(db *gorm.DB) *gorm.DB {
 		return db.Where(query, args...)
@@ -0,0 +1,23 @@
+package utils
+
+import "testing"
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "plain", value: "BBCA", want: "BBCA"},
+		{name: "percent", value: "10%", want: `10\%`},
+		{name: "underscore", value: "BB_A", want: `BB\_A`},
+		{name: "backslash", value: `a\b`, want: `a\\b`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EscapeLike(tt.value); got != tt.want {
+				t.Errorf("EscapeLike() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
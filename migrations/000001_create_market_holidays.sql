@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS market_holidays (
+    id SERIAL PRIMARY KEY,
+    date DATE NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
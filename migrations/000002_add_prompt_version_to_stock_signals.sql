@@ -0,0 +1,3 @@
+ALTER TABLE stock_signals ADD COLUMN IF NOT EXISTS prompt_version VARCHAR(50) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_stock_signals_prompt_version ON stock_signals (prompt_version, created_at);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS llm_usages (
+    id SERIAL PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    prompt_name VARCHAR(100),
+    prompt_version VARCHAR(50),
+    input_tokens INTEGER NOT NULL DEFAULT 0,
+    output_tokens INTEGER NOT NULL DEFAULT 0,
+    latency_ms BIGINT NOT NULL DEFAULT 0,
+    finish_reason VARCHAR(50),
+    error TEXT,
+    trigger_source VARCHAR(50),
+    telegram_id BIGINT,
+    job_id INTEGER,
+    cost NUMERIC(14, 6) NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_llm_usages_created_at ON llm_usages (created_at);
@@ -0,0 +1,3 @@
+ALTER TABLE stock_signals ADD COLUMN IF NOT EXISTS cache_key VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_stock_signals_cache_key ON stock_signals (cache_key, created_at DESC);
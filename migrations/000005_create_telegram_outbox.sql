@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS telegram_outbox (
+    id SERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL,
+    text TEXT NOT NULL,
+    parse_mode VARCHAR(20),
+    dedup_key VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    message_id INTEGER,
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_telegram_outbox_dedup_key ON telegram_outbox (dedup_key);
+CREATE INDEX IF NOT EXISTS idx_telegram_outbox_status_next_attempt_at ON telegram_outbox (status, next_attempt_at);
@@ -0,0 +1 @@
+ALTER TABLE task_execution_history ADD COLUMN IF NOT EXISTS attempt INT NOT NULL DEFAULT 1;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS job_dependencies (
+    id SERIAL PRIMARY KEY,
+    job_id INT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    depends_on_job_id INT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (job_id, depends_on_job_id),
+    CHECK (job_id <> depends_on_job_id)
+);
+
+CREATE TABLE IF NOT EXISTS pipeline_runs (
+    id SERIAL PRIMARY KEY,
+    root_job_id INT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    status VARCHAR(50) NOT NULL,
+    failed_job_id INT REFERENCES jobs(id) ON DELETE SET NULL,
+    error_message TEXT,
+    started_at TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pipeline_runs_root_job_id ON pipeline_runs (root_job_id, started_at DESC);
+
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS fan_out VARCHAR(50) NOT NULL DEFAULT '';
+
+-- stage pipeline yang tidak punya schedule sendiri dicatat tanpa schedule_id
+ALTER TABLE task_execution_history ALTER COLUMN schedule_id DROP NOT NULL;
+ALTER TABLE task_execution_history ADD COLUMN IF NOT EXISTS pipeline_run_id INT REFERENCES pipeline_runs(id) ON DELETE SET NULL;
+ALTER TABLE task_execution_history ADD COLUMN IF NOT EXISTS item VARCHAR(50) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_task_execution_history_pipeline_run_id ON task_execution_history (pipeline_run_id);
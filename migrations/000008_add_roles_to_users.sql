@@ -0,0 +1,19 @@
+-- User lama tetap bisa memakai bot seperti sebelumnya, user baru default guest.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'member';
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'guest';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS banned_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_username ON users (LOWER(username));
+
+CREATE TABLE IF NOT EXISTS invite_codes (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(32) NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    max_uses INTEGER NOT NULL DEFAULT 1,
+    used_count INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ,
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invite_codes_code ON invite_codes (code);
@@ -0,0 +1,14 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS plan VARCHAR(20) NOT NULL DEFAULT 'free';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS plan_expires_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS quota_usages (
+    id SERIAL PRIMARY KEY,
+    telegram_id BIGINT NOT NULL,
+    usage_date DATE NOT NULL,
+    kind VARCHAR(30) NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quota_usages_telegram_id_usage_date_kind ON quota_usages (telegram_id, usage_date, kind);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS digest_subscriptions (
+    id SERIAL PRIMARY KEY,
+    telegram_id BIGINT NOT NULL,
+    digest_type VARCHAR(30) NOT NULL,
+    delivery_time VARCHAR(5) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    last_sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_digest_subscriptions_telegram_id_digest_type ON digest_subscriptions (telegram_id, digest_type);
+CREATE INDEX IF NOT EXISTS idx_digest_subscriptions_enabled ON digest_subscriptions (enabled);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    telegram_id BIGINT PRIMARY KEY,
+    target_hit BOOLEAN NOT NULL DEFAULT TRUE,
+    signal_change BOOLEAN NOT NULL DEFAULT TRUE,
+    news BOOLEAN NOT NULL DEFAULT TRUE,
+    digest BOOLEAN NOT NULL DEFAULT TRUE,
+    quiet_start VARCHAR(5) NOT NULL DEFAULT '',
+    quiet_end VARCHAR(5) NOT NULL DEFAULT '',
+    min_confidence INTEGER NOT NULL DEFAULT 0,
+    verbosity VARCHAR(20) NOT NULL DEFAULT 'detailed',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
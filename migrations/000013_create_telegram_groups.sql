@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS telegram_groups (
+    chat_id BIGINT PRIMARY KEY,
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    added_by BIGINT NOT NULL,
+    language VARCHAR(10) NOT NULL DEFAULT '',
+    signal_alerts BOOLEAN NOT NULL DEFAULT TRUE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_telegram_groups_added_by ON telegram_groups (added_by);
+
+CREATE TABLE IF NOT EXISTS group_watchlists (
+    id SERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL REFERENCES telegram_groups (chat_id) ON DELETE CASCADE,
+    stock_code VARCHAR(20) NOT NULL,
+    added_by BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_group_watchlists_chat_id_stock_code ON group_watchlists (chat_id, stock_code);
+CREATE INDEX IF NOT EXISTS idx_group_watchlists_stock_code ON group_watchlists (stock_code);
@@ -0,0 +1,2 @@
+-- belum ada notifier berita otomatis, toggle alert berita dihapus dari /settings
+ALTER TABLE notification_preferences DROP COLUMN IF EXISTS news;
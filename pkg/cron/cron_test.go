@@ -0,0 +1,88 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"* * * FOO *",
+	}
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := Parse(expr); err == nil {
+				t.Errorf("Parse(%q) expected error", expr)
+			}
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*60*60)
+	// Kamis, 2 Januari 2025 09:30:15 WIB
+	from := time.Date(2025, 1, 2, 9, 30, 15, 0, wib)
+
+	tests := []struct {
+		name string
+		expr string
+		want time.Time
+	}{
+		{name: "every minute", expr: "* * * * *", want: time.Date(2025, 1, 2, 9, 31, 0, 0, wib)},
+		{name: "every 15 minutes", expr: "*/15 * * * *", want: time.Date(2025, 1, 2, 9, 45, 0, 0, wib)},
+		{name: "offset step", expr: "5/20 * * * *", want: time.Date(2025, 1, 2, 9, 45, 0, 0, wib)},
+		{name: "later today", expr: "0 16 * * *", want: time.Date(2025, 1, 2, 16, 0, 0, 0, wib)},
+		{name: "tomorrow", expr: "0 8 * * *", want: time.Date(2025, 1, 3, 8, 0, 0, 0, wib)},
+		{name: "weekdays market open", expr: "0 9 * * MON-FRI", want: time.Date(2025, 1, 3, 9, 0, 0, 0, wib)},
+		{name: "list of hours", expr: "0 9,12,15 * * *", want: time.Date(2025, 1, 2, 12, 0, 0, 0, wib)},
+		{name: "sunday as 7", expr: "0 0 * * 7", want: time.Date(2025, 1, 5, 0, 0, 0, 0, wib)},
+		{name: "month name", expr: "0 0 1 MAR *", want: time.Date(2025, 3, 1, 0, 0, 0, 0, wib)},
+		{name: "dom or dow", expr: "0 0 15 * MON", want: time.Date(2025, 1, 6, 0, 0, 0, 0, wib)},
+		{name: "leap day", expr: "0 0 29 2 *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, wib)},
+		{name: "daily descriptor", expr: "@daily", want: time.Date(2025, 1, 3, 0, 0, 0, 0, wib)},
+		{name: "hourly descriptor", expr: "@hourly", want: time.Date(2025, 1, 2, 10, 0, 0, 0, wib)},
+		{name: "never", expr: "0 0 30 2 *", want: time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.expr, err)
+			}
+			if got := schedule.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedule_NextN(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*60*60)
+	schedule, err := Parse("30 8 * * 1-5")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// Jumat sore, berikutnya Senin sampai Jumat minggu depan
+	got := schedule.NextN(time.Date(2025, 1, 3, 17, 0, 0, 0, wib), 5)
+	want := []int{6, 7, 8, 9, 10}
+	if len(got) != len(want) {
+		t.Fatalf("NextN() returned %d times, want %d", len(got), len(want))
+	}
+	for i, day := range want {
+		if got[i].Day() != day || got[i].Hour() != 8 || got[i].Minute() != 30 {
+			t.Errorf("NextN()[%d] = %s, want 2025-01-%02d 08:30", i, got[i], day)
+		}
+	}
+}
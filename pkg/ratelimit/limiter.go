@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+var ErrExceedsBurst = errors.New("requested tokens exceed limiter burst")
+
+// Limit adalah kuota Rate token per Period dengan kapasitas Burst. Burst 0 berarti sama
+// dengan Rate.
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+func PerSecond(rate int) Limit {
+	return Limit{Rate: rate, Period: time.Second}
+}
+
+func PerMinute(rate int) Limit {
+	return Limit{Rate: rate, Period: time.Minute}
+}
+
+func (l Limit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Rate
+}
+
+// interval adalah jarak waktu antar token (emission interval pada GCRA).
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Rate)
+}
+
+// Limiter membatasi pemakaian per key, misal "telegram:global" atau "telegram:user:<id>".
+// Implementasi memory hanya berlaku per proses, implementasi redis berlaku untuk semua
+// replica yang memakai redis yang sama.
+type Limiter interface {
+	// Wait memblokir sampai n token tersedia untuk key atau ctx selesai.
+	Wait(ctx context.Context, key string, limit Limit, n int) error
+	// Remaining mengembalikan perkiraan jumlah token yang tersedia untuk key saat ini.
+	Remaining(ctx context.Context, key string, limit Limit) (int, error)
+}
+
+// waitRetry memanggil try sampai diizinkan. try mengembalikan durasi tunggu sebelum boleh
+// mencoba lagi, 0 jika token berhasil diambil.
+func waitRetry(ctx context.Context, try func() (time.Duration, error)) error {
+	for {
+		retryAfter, err := try()
+		if err != nil {
+			return err
+		}
+		if retryAfter <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(retryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func validateRequest(limit Limit, n int) error {
+	if limit.Rate <= 0 || limit.Period <= 0 {
+		return fmt.Errorf("invalid limit: %d per %s", limit.Rate, limit.Period)
+	}
+	if n > limit.burst() {
+		return fmt.Errorf("%w: %d > %d", ErrExceedsBurst, n, limit.burst())
+	}
+	return nil
+}
+
+// FormatKey menggabungkan prefix dan ID, misal FormatKey("telegram:user", 123) -> "telegram:user:123".
+func FormatKey(prefix string, id int64) string {
+	return prefix + ":" + strconv.FormatInt(id, 10)
+}
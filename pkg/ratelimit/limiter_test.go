@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"golang-swing-trading-signal/pkg/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestMemoryLimiter_take(t *testing.T) {
+	now := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+	limit := PerSecond(2) // interval 500ms, burst 2
+
+	tests := []struct {
+		name      string
+		steps     []time.Duration // offset waktu tiap take dari now
+		n         int
+		wantWaits []time.Duration
+	}{
+		{name: "burst then wait", steps: []time.Duration{0, 0, 0}, n: 1, wantWaits: []time.Duration{0, 0, 500 * time.Millisecond}},
+		{name: "refill continuously", steps: []time.Duration{0, 0, 500 * time.Millisecond, 500 * time.Millisecond}, n: 1, wantWaits: []time.Duration{0, 0, 0, 500 * time.Millisecond}},
+		{name: "take multiple tokens", steps: []time.Duration{0, 0}, n: 2, wantWaits: []time.Duration{0, time.Second}},
+		{name: "idle fills bucket", steps: []time.Duration{0, 0, 10 * time.Second, 10 * time.Second}, n: 1, wantWaits: []time.Duration{0, 0, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewMemoryLimiter()
+			for i, step := range tt.steps {
+				limiter.now = func() time.Time { return now.Add(step) }
+				if got := limiter.take("key", limit, tt.n); got != tt.wantWaits[i] {
+					t.Errorf("take() #%d wait = %s, want %s", i, got, tt.wantWaits[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryLimiter_KeysAreIndependent(t *testing.T) {
+	limiter := NewMemoryLimiter()
+	limit := PerSecond(1)
+
+	if wait := limiter.take("telegram:user:1", limit, 1); wait != 0 {
+		t.Fatalf("first take wait = %s, want 0", wait)
+	}
+	if wait := limiter.take("telegram:user:2", limit, 1); wait != 0 {
+		t.Errorf("other key wait = %s, want 0", wait)
+	}
+	if wait := limiter.take("telegram:user:1", limit, 1); wait == 0 {
+		t.Error("same key should wait")
+	}
+
+	remaining, _ := limiter.Remaining(context.Background(), "telegram:user:1", limit)
+	if remaining != 0 {
+		t.Errorf("Remaining() = %d, want 0", remaining)
+	}
+}
+
+func TestMemoryLimiter_Wait(t *testing.T) {
+	limiter := NewMemoryLimiter()
+
+	if err := limiter.Wait(context.Background(), "key", PerSecond(1), 2); !errors.Is(err, ErrExceedsBurst) {
+		t.Errorf("Wait() error = %v, want ErrExceedsBurst", err)
+	}
+
+	limit := PerMinute(1)
+	if err := limiter.Wait(context.Background(), "key", limit, 1); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, "key", limit, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+// TestRedisLimiter membutuhkan redis, jalankan dengan REDIS_TEST_ADDR=localhost:6379.
+func TestRedisLimiter(t *testing.T) {
+	addr := os.Getenv("REDIS_TEST_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_TEST_ADDR not set")
+	}
+	var host string
+	var port int
+	if _, err := fmt.Sscanf(addr, "%[^:]:%d", &host, &port); err != nil {
+		t.Fatalf("invalid REDIS_TEST_ADDR: %v", err)
+	}
+	client, err := redis.NewClient(redis.Config{Host: host, Port: port})
+	if err != nil {
+		t.Fatalf("redis.NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	key := fmt.Sprintf("test:%d", time.Now().UnixNano())
+	limiter := NewRedisLimiter(client, logrus.New(), NewMemoryLimiter())
+	limit := PerMinute(2)
+
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(ctx, key, limit, 1); err != nil {
+			t.Fatalf("Wait() #%d error = %v", i, err)
+		}
+	}
+	if remaining, err := limiter.Remaining(ctx, key, limit); err != nil || remaining != 0 {
+		t.Errorf("Remaining() = %d, %v, want 0", remaining, err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(timeoutCtx, key, limit, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryLimiter menerapkan GCRA (generic cell rate algorithm) di memori proses. Dipakai
+// untuk development lokal dan sebagai fallback saat redis tidak bisa dihubungi.
+type MemoryLimiter struct {
+	mu  sync.Mutex
+	tat map[string]time.Time // theoretical arrival time per key
+	now func() time.Time
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		tat: make(map[string]time.Time),
+		now: time.Now,
+	}
+}
+
+func (m *MemoryLimiter) Wait(ctx context.Context, key string, limit Limit, n int) error {
+	if err := validateRequest(limit, n); err != nil {
+		return err
+	}
+	return waitRetry(ctx, func() (time.Duration, error) {
+		return m.take(key, limit, n), nil
+	})
+}
+
+func (m *MemoryLimiter) Remaining(ctx context.Context, key string, limit Limit) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	used := m.tat[key].Sub(m.now())
+	if used <= 0 {
+		return limit.burst(), nil
+	}
+	// token yang sedang terpakai dibulatkan ke atas
+	inUse := int((used + limit.interval() - 1) / limit.interval())
+	return max(limit.burst()-inUse, 0), nil
+}
+
+// DeleteExpired menghapus key yang bucket-nya sudah penuh kembali. Key tersebut setara dengan
+// key yang belum pernah dipakai sehingga aman dihapus.
+func (m *MemoryLimiter) DeleteExpired() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	for key, tat := range m.tat {
+		if !tat.After(now) {
+			delete(m.tat, key)
+		}
+	}
+}
+
+// take mengambil n token jika tersedia dan mengembalikan 0, atau mengembalikan durasi
+// tunggu sampai n token tersedia tanpa mengubah state.
+func (m *MemoryLimiter) take(key string, limit Limit, n int) time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	interval := limit.interval()
+
+	tat := m.tat[key]
+	if tat.Before(now) {
+		tat = now
+	}
+	newTat := tat.Add(interval * time.Duration(n))
+	allowAt := newTat.Add(-interval * time.Duration(limit.burst()))
+	if allowAt.After(now) {
+		return allowAt.Sub(now)
+	}
+
+	m.tat[key] = newTat
+	return 0
+}
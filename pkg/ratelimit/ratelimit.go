@@ -1,62 +1,306 @@
 package ratelimit
 
 import (
+	"container/list"
 	"context"
+	"fmt"
+	"math"
 	"sync"
 	"time"
 )
 
+// TokenRateLimiter membatasi jumlah token LLM per menit.
+type TokenRateLimiter interface {
+	Wait(ctx context.Context, tokens int) error
+	GetRemaining() int
+}
+
+// Priority menentukan lane antrian di TokenLimiter. Lane dengan nilai lebih kecil selalu
+// dilayani lebih dulu, di dalam satu lane urutannya FIFO.
+type Priority int
+
+const (
+	// PriorityInteractive untuk request yang ditunggu user (API, telegram). Ini nilai default.
+	PriorityInteractive Priority = iota
+	// PriorityBatch untuk job terjadwal yang boleh menunggu lebih lama.
+	PriorityBatch
+
+	priorityCount = int(PriorityBatch) + 1
+)
+
+type priorityKey struct{}
+
+// WithPriority menandai ctx dengan lane antrian yang dipakai TokenLimiter.Wait.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+func PriorityFromContext(ctx context.Context) Priority {
+	priority, ok := ctx.Value(priorityKey{}).(Priority)
+	if !ok || priority < PriorityInteractive || int(priority) >= priorityCount {
+		return PriorityInteractive
+	}
+	return priority
+}
+
+// tokenWaiter adalah satu pemanggil Wait yang sedang mengantri. ready ditutup saat token
+// sudah dialokasikan untuk waiter ini.
+type tokenWaiter struct {
+	tokens  int
+	ready   chan struct{}
+	granted bool
+}
+
+// TokenLimiter adalah TokenRateLimiter in-memory dengan refill kontinu (token bucket).
+// Pemanggil yang tidak langsung mendapat token mengantri per lane prioritas dan dibangunkan
+// oleh satu timer saat token untuk antrian terdepan cukup, tanpa polling.
 type TokenLimiter struct {
-	sync.Mutex
-	capacity     int           // max token per minute
-	remaining    int           // sisa token saat ini
-	refillPeriod time.Duration // biasanya 1 menit
-	lastRefill   time.Time
+	mu         sync.Mutex
+	capacity   float64 // max token per refillPeriod
+	available  float64 // sisa token saat ini
+	ratePerSec float64 // kecepatan refill
+	lastRefill time.Time
+	queues     [priorityCount]*list.List
+	timer      *time.Timer
+	now        func() time.Time
 }
 
 func NewTokenLimiter(tokensPerMinute int) *TokenLimiter {
-	return &TokenLimiter{
-		capacity:     tokensPerMinute,
-		remaining:    tokensPerMinute,
-		refillPeriod: time.Minute,
-		lastRefill:   time.Now(),
+	return newTokenLimiter(tokensPerMinute, time.Minute)
+}
+
+func newTokenLimiter(capacity int, refillPeriod time.Duration) *TokenLimiter {
+	l := &TokenLimiter{
+		capacity:   float64(capacity),
+		available:  float64(capacity),
+		ratePerSec: float64(capacity) / refillPeriod.Seconds(),
+		now:        time.Now,
 	}
+	l.lastRefill = l.now()
+	for i := range l.queues {
+		l.queues[i] = list.New()
+	}
+	return l
 }
 
+// Wait memblokir sampai tokens tersedia, ctx selesai, atau mengembalikan ErrExceedsBurst jika
+// tokens melebihi kapasitas sehingga tidak akan pernah bisa dipenuhi.
 func (l *TokenLimiter) Wait(ctx context.Context, tokens int) error {
-	for {
-		l.refill()
-
-		l.Lock()
-		if l.remaining >= tokens {
-			l.remaining -= tokens
-			l.Unlock()
-			return nil
-		}
-		l.Unlock()
+	if tokens <= 0 {
+		return nil
+	}
+	if float64(tokens) > l.capacity {
+		return fmt.Errorf("%w: %d > %.0f", ErrExceedsBurst, tokens, l.capacity)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(100 * time.Millisecond):
-			// Retry sebentar lagi
+	priority := PriorityFromContext(ctx)
+
+	l.mu.Lock()
+	l.refill()
+	// jalur cepat hanya jika tidak ada antrian di lane yang sama atau lebih prioritas,
+	// supaya pemanggil baru tidak menyalip yang sudah menunggu
+	if !l.hasWaitersUpTo(priority) && l.available >= float64(tokens) {
+		l.available -= float64(tokens)
+		l.mu.Unlock()
+		return nil
+	}
+
+	waiter := &tokenWaiter{tokens: tokens, ready: make(chan struct{})}
+	element := l.queues[priority].PushBack(waiter)
+	l.dispatch()
+	l.mu.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if waiter.granted {
+			// token sudah dialokasikan bersamaan dengan pembatalan, kembalikan ke bucket
+			l.available = math.Min(l.capacity, l.available+float64(tokens))
+		} else {
+			l.queues[priority].Remove(element)
 		}
+		// waiter di depan yang batal bisa membuka jalan untuk antrian berikutnya
+		l.dispatch()
+		return ctx.Err()
 	}
 }
 
+func (l *TokenLimiter) GetRemaining() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	return int(l.available)
+}
+
+// refill menambah token sesuai waktu yang berlalu sejak refill terakhir. Harus dipanggil
+// dengan mu terkunci.
 func (l *TokenLimiter) refill() {
-	l.Lock()
-	defer l.Unlock()
+	now := l.now()
+	elapsed := now.Sub(l.lastRefill)
+	if elapsed <= 0 {
+		return
+	}
+	l.available = math.Min(l.capacity, l.available+elapsed.Seconds()*l.ratePerSec)
+	l.lastRefill = now
+}
 
-	now := time.Now()
-	if now.Sub(l.lastRefill) >= l.refillPeriod {
-		l.remaining = l.capacity
-		l.lastRefill = now
+func (l *TokenLimiter) hasWaitersUpTo(priority Priority) bool {
+	for i := 0; i <= int(priority); i++ {
+		if l.queues[i].Len() > 0 {
+			return true
+		}
 	}
+	return false
 }
 
-func (l *TokenLimiter) GetRemaining() int {
-	l.Lock()
-	defer l.Unlock()
-	return l.remaining
+// dispatch membangunkan waiter terdepan selama token cukup, lane prioritas tertinggi dulu.
+// Jika waiter terdepan belum bisa dilayani, waiter di belakangnya (termasuk lane lebih
+// rendah) ikut menunggu dan timer dijadwalkan tepat saat token untuknya cukup. Harus
+// dipanggil dengan mu terkunci.
+func (l *TokenLimiter) dispatch() {
+	l.refill()
+	for _, queue := range l.queues {
+		for front := queue.Front(); front != nil; front = queue.Front() {
+			waiter := front.Value.(*tokenWaiter)
+			need := float64(waiter.tokens)
+			if l.available < need {
+				l.schedule(time.Duration((need - l.available) / l.ratePerSec * float64(time.Second)))
+				return
+			}
+			l.available -= need
+			waiter.granted = true
+			close(waiter.ready)
+			queue.Remove(front)
+		}
+	}
+}
+
+func (l *TokenLimiter) schedule(after time.Duration) {
+	// pembulatan float bisa membuat token kurang sedikit saat timer jalan, beri jeda minimal
+	if after < time.Millisecond {
+		after = time.Millisecond
+	}
+	if l.timer == nil {
+		l.timer = time.AfterFunc(after, l.onTimer)
+		return
+	}
+	l.timer.Reset(after)
+}
+
+func (l *TokenLimiter) onTimer() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.dispatch()
+}
+
+// keyedTokenLimiter adalah TokenRateLimiter di atas Limiter, sehingga kuota token bisa
+// dibagi antar replica lewat RedisLimiter. Di dalam satu proses pemanggil tetap dilayani per
+// lane prioritas dan FIFO seperti TokenLimiter: hanya pemegang giliran yang mengambil token
+// dari limiter, sisanya mengantri di turns.
+type keyedTokenLimiter struct {
+	limiter Limiter
+	key     string
+	limit   Limit
+	turns   *turnQueue
+}
+
+func NewKeyedTokenLimiter(limiter Limiter, key string, tokensPerMinute int) TokenRateLimiter {
+	return &keyedTokenLimiter{
+		limiter: limiter,
+		key:     key,
+		limit:   PerMinute(tokensPerMinute),
+		turns:   newTurnQueue(),
+	}
+}
+
+func (l *keyedTokenLimiter) Wait(ctx context.Context, tokens int) error {
+	if tokens <= 0 {
+		return nil
+	}
+	if err := l.turns.acquire(ctx, PriorityFromContext(ctx)); err != nil {
+		return err
+	}
+	defer l.turns.release()
+	return l.limiter.Wait(ctx, l.key, l.limit, tokens)
+}
+
+func (l *keyedTokenLimiter) GetRemaining() int {
+	remaining, err := l.limiter.Remaining(context.Background(), l.key, l.limit)
+	if err != nil {
+		return 0
+	}
+	return remaining
+}
+
+// turnQueue memberi giliran ke satu pemanggil pada satu waktu, lane prioritas tertinggi dulu
+// dan FIFO di dalam lane.
+type turnQueue struct {
+	mu     sync.Mutex
+	busy   bool
+	queues [priorityCount]*list.List
+}
+
+func newTurnQueue() *turnQueue {
+	q := &turnQueue{}
+	for i := range q.queues {
+		q.queues[i] = list.New()
+	}
+	return q
+}
+
+// acquire memblokir sampai pemanggil mendapat giliran atau ctx selesai. Giliran wajib
+// dikembalikan dengan release.
+func (q *turnQueue) acquire(ctx context.Context, priority Priority) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	if !q.busy {
+		q.busy = true
+		q.mu.Unlock()
+		return nil
+	}
+	waiter := &tokenWaiter{ready: make(chan struct{})}
+	element := q.queues[priority].PushBack(waiter)
+	q.mu.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		granted := waiter.granted
+		if !granted {
+			q.queues[priority].Remove(element)
+		}
+		q.mu.Unlock()
+		if granted {
+			// giliran sudah diberikan bersamaan dengan pembatalan, teruskan ke antrian berikutnya
+			q.release()
+		}
+		return ctx.Err()
+	}
+}
+
+// release menyerahkan giliran ke waiter terdepan, atau mengosongkan giliran jika tidak ada.
+func (q *turnQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, queue := range q.queues {
+		if front := queue.Front(); front != nil {
+			waiter := front.Value.(*tokenWaiter)
+			waiter.granted = true
+			close(waiter.ready)
+			queue.Remove(front)
+			return
+		}
+	}
+	q.busy = false
 }
@@ -0,0 +1,360 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTokenLimiter_refill(t *testing.T) {
+	now := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		used    int
+		elapsed time.Duration
+		want    int
+	}{
+		{name: "no time passed", used: 60, elapsed: 0, want: 0},
+		{name: "continuous refill", used: 60, elapsed: 10 * time.Second, want: 10},
+		{name: "half period", used: 60, elapsed: 30 * time.Second, want: 30},
+		{name: "capped at capacity", used: 10, elapsed: 5 * time.Minute, want: 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewTokenLimiter(60)
+			limiter.now = func() time.Time { return now }
+			limiter.lastRefill = now
+
+			if err := limiter.Wait(context.Background(), tt.used); err != nil {
+				t.Fatalf("Wait() error = %v", err)
+			}
+			limiter.now = func() time.Time { return now.Add(tt.elapsed) }
+			if got := limiter.GetRemaining(); got != tt.want {
+				t.Errorf("GetRemaining() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenLimiter_ExceedsCapacity(t *testing.T) {
+	limiter := NewTokenLimiter(100)
+	if err := limiter.Wait(context.Background(), 101); !errors.Is(err, ErrExceedsBurst) {
+		t.Errorf("Wait() error = %v, want ErrExceedsBurst", err)
+	}
+}
+
+// drain menghabiskan seluruh token supaya pemanggil berikutnya mengantri.
+func drain(t *testing.T, limiter *TokenLimiter, capacity int) {
+	t.Helper()
+	if err := limiter.Wait(context.Background(), capacity); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+}
+
+// waitQueued menunggu sampai jumlah waiter di semua lane mencapai n.
+func waitQueued(t *testing.T, limiter *TokenLimiter, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		limiter.mu.Lock()
+		queued := 0
+		for _, queue := range limiter.queues {
+			queued += queue.Len()
+		}
+		limiter.mu.Unlock()
+		if queued >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timeout waiting for %d queued waiters", n)
+}
+
+func TestTokenLimiter_FIFO(t *testing.T) {
+	limiter := newTokenLimiter(10, 100*time.Millisecond)
+	drain(t, limiter, 10)
+
+	var mu sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := limiter.Wait(context.Background(), 2); err != nil {
+				t.Errorf("Wait() error = %v", err)
+				return
+			}
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+		}(i)
+		// pastikan urutan masuk antrian sesuai i
+		waitQueued(t, limiter, i+1)
+	}
+	wg.Wait()
+
+	for i, got := range order {
+		if got != i {
+			t.Fatalf("order = %v, want FIFO", order)
+		}
+	}
+}
+
+func TestTokenLimiter_LargeRequestNotStarved(t *testing.T) {
+	limiter := newTokenLimiter(10, 100*time.Millisecond)
+	drain(t, limiter, 10)
+
+	large := make(chan struct{})
+	go func() {
+		if err := limiter.Wait(context.Background(), 10); err == nil {
+			close(large)
+		}
+	}()
+	waitQueued(t, limiter, 1)
+
+	// request kecil yang datang belakangan tidak boleh menyalip request besar
+	if err := limiter.Wait(context.Background(), 1); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	select {
+	case <-large:
+	default:
+		t.Error("small request overtook queued large request")
+	}
+}
+
+func TestTokenLimiter_Priority(t *testing.T) {
+	limiter := newTokenLimiter(10, 100*time.Millisecond)
+	drain(t, limiter, 10)
+
+	var mu sync.Mutex
+	var order []Priority
+	var wg sync.WaitGroup
+	wait := func(priority Priority) {
+		defer wg.Done()
+		if err := limiter.Wait(WithPriority(context.Background(), priority), 5); err != nil {
+			t.Errorf("Wait() error = %v", err)
+			return
+		}
+		mu.Lock()
+		order = append(order, priority)
+		mu.Unlock()
+	}
+
+	wg.Add(3)
+	go wait(PriorityBatch)
+	waitQueued(t, limiter, 1)
+	go wait(PriorityBatch)
+	waitQueued(t, limiter, 2)
+	go wait(PriorityInteractive)
+	wg.Wait()
+
+	if len(order) != 3 || order[0] != PriorityInteractive {
+		t.Errorf("order = %v, want interactive first", order)
+	}
+}
+
+func TestTokenLimiter_Cancel(t *testing.T) {
+	limiter := newTokenLimiter(10, 100*time.Millisecond)
+	drain(t, limiter, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- limiter.Wait(ctx, 10)
+	}()
+	waitQueued(t, limiter, 1)
+	cancel()
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Wait() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Wait() did not return after cancel")
+	}
+
+	// waiter yang batal dikeluarkan dari antrian dan tidak menghalangi pemanggil berikutnya
+	start := time.Now()
+	if err := limiter.Wait(context.Background(), 1); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Wait() after cancel took %s", elapsed)
+	}
+}
+
+func TestTokenLimiter_Throughput(t *testing.T) {
+	// 100 token per 100ms, 300 token diminta setelah bucket kosong butuh sekitar 300ms
+	limiter := newTokenLimiter(100, 100*time.Millisecond)
+	drain(t, limiter, 100)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := limiter.Wait(context.Background(), 10); err != nil {
+				t.Errorf("Wait() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	if elapsed < 250*time.Millisecond || elapsed > 600*time.Millisecond {
+		t.Errorf("elapsed = %s, want around 300ms", elapsed)
+	}
+}
+
+func BenchmarkTokenLimiter_Uncontended(b *testing.B) {
+	limiter := NewTokenLimiter(1 << 30)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = limiter.Wait(ctx, 1)
+	}
+}
+
+func BenchmarkTokenLimiter_Contended(b *testing.B) {
+	// kapasitas kecil sehingga sebagian besar pemanggil mengantri dan dibangunkan timer
+	limiter := newTokenLimiter(1000, 10*time.Millisecond)
+	ctx := context.Background()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = limiter.Wait(ctx, 1)
+		}
+	})
+}
+
+// blockingLimiter mencatat prioritas setiap Wait dan menahannya sampai release diisi,
+// menggantikan RedisLimiter di belakang keyedTokenLimiter.
+type blockingLimiter struct {
+	Limiter
+	mu      sync.Mutex
+	order   []Priority
+	entered chan struct{}
+	release chan struct{}
+}
+
+func newBlockingLimiter() *blockingLimiter {
+	return &blockingLimiter{
+		entered: make(chan struct{}, 10),
+		release: make(chan struct{}),
+	}
+}
+
+func (l *blockingLimiter) Wait(ctx context.Context, key string, limit Limit, n int) error {
+	l.mu.Lock()
+	l.order = append(l.order, PriorityFromContext(ctx))
+	l.mu.Unlock()
+	l.entered <- struct{}{}
+
+	select {
+	case <-l.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// waitTurns menunggu sampai jumlah waiter giliran di semua lane mencapai n.
+func waitTurns(t *testing.T, limiter TokenRateLimiter, n int) {
+	t.Helper()
+	turns := limiter.(*keyedTokenLimiter).turns
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		turns.mu.Lock()
+		queued := 0
+		for _, queue := range turns.queues {
+			queued += queue.Len()
+		}
+		turns.mu.Unlock()
+		if queued >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timeout waiting for %d queued turns", n)
+}
+
+func TestKeyedTokenLimiter_Priority(t *testing.T) {
+	backend := newBlockingLimiter()
+	limiter := NewKeyedTokenLimiter(backend, "gemini:tokens", 100)
+
+	var wg sync.WaitGroup
+	wait := func(priority Priority) {
+		defer wg.Done()
+		if err := limiter.Wait(WithPriority(context.Background(), priority), 5); err != nil {
+			t.Errorf("Wait() error = %v", err)
+		}
+	}
+
+	wg.Add(4)
+	go wait(PriorityBatch)
+	<-backend.entered
+	go wait(PriorityBatch)
+	waitTurns(t, limiter, 1)
+	go wait(PriorityBatch)
+	waitTurns(t, limiter, 2)
+	go wait(PriorityInteractive)
+	waitTurns(t, limiter, 3)
+
+	for i := 0; i < 4; i++ {
+		backend.release <- struct{}{}
+		if i < 3 {
+			<-backend.entered
+		}
+	}
+	wg.Wait()
+
+	want := []Priority{PriorityBatch, PriorityInteractive, PriorityBatch, PriorityBatch}
+	if len(backend.order) != len(want) {
+		t.Fatalf("order = %v, want %v", backend.order, want)
+	}
+	for i := range want {
+		if backend.order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", backend.order, want)
+		}
+	}
+}
+
+func TestKeyedTokenLimiter_Cancel(t *testing.T) {
+	backend := newBlockingLimiter()
+	limiter := NewKeyedTokenLimiter(backend, "gemini:tokens", 100)
+
+	first := make(chan error, 1)
+	go func() { first <- limiter.Wait(context.Background(), 5) }()
+	<-backend.entered
+
+	// waiter yang batal saat mengantri tidak boleh menahan giliran berikutnya
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error, 1)
+	go func() { cancelled <- limiter.Wait(ctx, 5) }()
+	waitTurns(t, limiter, 1)
+	cancel()
+	if err := <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() error = %v, want context.Canceled", err)
+	}
+
+	second := make(chan error, 1)
+	go func() { second <- limiter.Wait(context.Background(), 5) }()
+	waitTurns(t, limiter, 1)
+
+	backend.release <- struct{}{}
+	if err := <-first; err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	<-backend.entered
+	backend.release <- struct{}{}
+	if err := <-second; err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"golang-swing-trading-signal/pkg/redis"
+
+	goRedis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const redisKeyPrefix = "ratelimit:"
+
+// gcraScript mengambil n token dari key secara atomik. Semua waktu dalam mikrodetik dan
+// diambil dari jam redis sehingga konsisten antar replica.
+// ARGV: emission interval, burst, n. Return: 0 jika diizinkan, selain itu lama tunggu.
+var gcraScript = goRedis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+
+local new_tat = tat + interval * n
+local allow_at = new_tat - interval * burst
+if allow_at > now then
+	-- redis membulatkan angka Lua ke bawah, pastikan tidak pernah 0 saat ditolak
+	return math.max(math.ceil(allow_at - now), 1)
+end
+
+redis.call('SET', KEYS[1], new_tat, 'PX', math.ceil((new_tat - now) / 1000))
+return 0
+`)
+
+// gcraRemainingScript mengembalikan jumlah token yang tersedia tanpa mengubah state.
+var gcraRemainingScript = goRedis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat <= now then
+	return burst
+end
+return math.max(burst - math.ceil((tat - now) / interval), 0)
+`)
+
+// RedisLimiter menerapkan GCRA lewat script Lua di redis sehingga kuota dibagi oleh semua
+// replica. Jika redis error, request diteruskan ke fallback agar bot tetap berjalan.
+type RedisLimiter struct {
+	client   *redis.Client
+	log      *logrus.Logger
+	fallback Limiter
+}
+
+func NewRedisLimiter(client *redis.Client, log *logrus.Logger, fallback Limiter) *RedisLimiter {
+	return &RedisLimiter{
+		client:   client,
+		log:      log,
+		fallback: fallback,
+	}
+}
+
+func (r *RedisLimiter) Wait(ctx context.Context, key string, limit Limit, n int) error {
+	if err := validateRequest(limit, n); err != nil {
+		return err
+	}
+
+	args := []interface{}{intervalMicros(limit), limit.burst(), n}
+	err := waitRetry(ctx, func() (time.Duration, error) {
+		retryAfter, err := gcraScript.Run(ctx, r.client, []string{redisKeyPrefix + key}, args...).Int64()
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(retryAfter) * time.Microsecond, nil
+	})
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	r.log.Warn("redis rate limiter unavailable, using fallback limiter", logrus.Fields{
+		"key":   key,
+		"error": err,
+	})
+	return r.fallback.Wait(ctx, key, limit, n)
+}
+
+func (r *RedisLimiter) Remaining(ctx context.Context, key string, limit Limit) (int, error) {
+	args := []interface{}{intervalMicros(limit), limit.burst()}
+	remaining, err := gcraRemainingScript.Run(ctx, r.client, []string{redisKeyPrefix + key}, args...).Int64()
+	if err != nil {
+		return r.fallback.Remaining(ctx, key, limit)
+	}
+	return int(remaining), nil
+}
+
+// NewLimiter membuat limiter sesuai backend (memory atau redis, default memory).
+func NewLimiter(backend string, client *redis.Client, log *logrus.Logger) Limiter {
+	memory := NewMemoryLimiter()
+	if backend == BackendRedis && client != nil {
+		return NewRedisLimiter(client, log, memory)
+	}
+	return memory
+}
+
+// DeleteExpired membersihkan key pada fallback limiter.
+func (r *RedisLimiter) DeleteExpired() {
+	if expirer, ok := r.fallback.(interface{ DeleteExpired() }); ok {
+		expirer.DeleteExpired()
+	}
+}
+
+// intervalMicros mengembalikan emission interval dalam mikrodetik (pecahan diperbolehkan).
+func intervalMicros(limit Limit) float64 {
+	return float64(limit.interval()) / float64(time.Microsecond)
+}
@@ -8,42 +8,32 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
-	"golang.org/x/time/rate"
 	"gopkg.in/telebot.v3"
 )
 
-type userLimiterEntry struct {
-	limiter    *rate.Limiter
-	lastAccess time.Time
-}
-
-type messageLimiterEntry struct {
-	limiter    *rate.Limiter
-	lastAccess time.Time
-}
+const (
+	telegramGlobalKey = "telegram:global"
+	telegramUserKey   = "telegram:user"
+	telegramChatKey   = "telegram:chat"
+)
 
+// TelegramRateLimiter membatasi pengiriman pesan secara global, per user dan per chat. State
+// limit disimpan di Limiter sehingga bisa dibagi antar replica jika memakai RedisLimiter.
 type TelegramRateLimiter struct {
-	cfg             *config.TelegramConfig
-	log             *logrus.Logger
-	globalLimiter   *rate.Limiter
-	userLimiters    map[int64]*userLimiterEntry
-	messageLimiters map[int64]*messageLimiterEntry
-	bot             *telebot.Bot
-	mu              sync.Mutex
-	wg              sync.WaitGroup
+	cfg     *config.TelegramConfig
+	log     *logrus.Logger
+	limiter Limiter
+	bot     *telebot.Bot
+	wg      sync.WaitGroup
 }
 
-func NewTelegramRateLimiter(cfg *config.TelegramConfig, log *logrus.Logger, bot *telebot.Bot) *TelegramRateLimiter {
-
+func NewTelegramRateLimiter(cfg *config.TelegramConfig, log *logrus.Logger, bot *telebot.Bot, limiter Limiter) *TelegramRateLimiter {
 	return &TelegramRateLimiter{
-		cfg:             cfg,
-		log:             log,
-		bot:             bot,
-		globalLimiter:   rate.NewLimiter(rate.Limit(cfg.MaxGlobalRequestPerSecond), cfg.MaxGlobalRequestPerSecond), // 30 msg/sec globally
-		userLimiters:    make(map[int64]*userLimiterEntry),
-		messageLimiters: make(map[int64]*messageLimiterEntry),
-		mu:              sync.Mutex{},
-		wg:              sync.WaitGroup{},
+		cfg:     cfg,
+		log:     log,
+		bot:     bot,
+		limiter: limiter,
+		wg:      sync.WaitGroup{},
 	}
 }
 
@@ -51,7 +41,52 @@ func (t *TelegramRateLimiter) Send(ctx context.Context, c telebot.Context, what
 	if err := t.checkRateLimit(ctx, c); err != nil {
 		return nil, err
 	}
-	return t.bot.Send(c.Chat(), what, opts...)
+	return t.bot.Send(c.Chat(), what, replyInThread(c, opts)...)
+}
+
+// replyInThread membuat balasan di grup menjadi reply ke pesan pemicunya dan tetap berada di
+// topik (thread) yang sama, agar percakapan beberapa member di grup tidak tercampur.
+func replyInThread(c telebot.Context, opts []interface{}) []interface{} {
+	if c.Chat() == nil || c.Chat().Type == telebot.ChatPrivate || c.Message() == nil {
+		return opts
+	}
+
+	thread := &telebot.SendOptions{ThreadID: c.Message().ThreadID}
+	if c.Callback() == nil {
+		thread.ReplyTo = c.Message()
+		thread.AllowWithoutReply = true
+	}
+
+	result := make([]interface{}, 0, len(opts)+1)
+	found := false
+	for _, opt := range opts {
+		if sendOptions, ok := opt.(*telebot.SendOptions); ok && sendOptions != nil {
+			merged := *sendOptions
+			if merged.ReplyTo == nil {
+				merged.ReplyTo = thread.ReplyTo
+				merged.AllowWithoutReply = merged.AllowWithoutReply || thread.AllowWithoutReply
+			}
+			if merged.ThreadID == 0 {
+				merged.ThreadID = thread.ThreadID
+			}
+			opt, found = &merged, true
+		}
+		result = append(result, opt)
+	}
+	if !found {
+		// SendOptions harus di depan karena telebot menimpa seluruh opsi saat menemukan *SendOptions
+		result = append([]interface{}{thread}, result...)
+	}
+	return result
+}
+
+// SendToChat mengirim pesan ke chat tanpa telebot.Context (notifikasi dari worker), hanya
+// dibatasi limit per chat dan global.
+func (t *TelegramRateLimiter) SendToChat(ctx context.Context, chatID int64, what interface{}, opts ...interface{}) (*telebot.Message, error) {
+	if err := t.checkChatRateLimit(ctx, chatID); err != nil {
+		return nil, err
+	}
+	return t.bot.Send(&telebot.Chat{ID: chatID}, what, opts...)
 }
 
 func (t *TelegramRateLimiter) SendWithoutLimit(ctx context.Context, c telebot.Context, what interface{}, opts ...interface{}) (*telebot.Message, error) {
@@ -107,60 +142,31 @@ func (t *TelegramRateLimiter) Respond(ctx context.Context, c telebot.Context, re
 	return c.Respond(resp...)
 }
 
-func (r *TelegramRateLimiter) getUserLimiter(userID int64) *userLimiterEntry {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	if limiter, exists := r.userLimiters[userID]; exists {
-		limiter.lastAccess = time.Now()
-		return limiter
-	}
-
-	// Buat limiter baru untuk user ini: 1 msg/sec
-	limiter := rate.NewLimiter(rate.Limit(r.cfg.MaxUserRequestPerSecond), r.cfg.MaxUserRequestPerSecond)
-	r.userLimiters[userID] = &userLimiterEntry{
-		limiter:    limiter,
-		lastAccess: time.Now(),
-	}
-	return r.userLimiters[userID]
-}
-
-func (r *TelegramRateLimiter) getMessageLimiter(userID int64) *messageLimiterEntry {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	if limiter, exists := r.messageLimiters[userID]; exists {
-		limiter.lastAccess = time.Now()
-		return limiter
+func (r *TelegramRateLimiter) checkRateLimit(ctx context.Context, c telebot.Context) error {
+	if err := r.checkChatRateLimit(ctx, c.Chat().ID); err != nil {
+		return err
 	}
-
-	// Buat limiter baru untuk user ini: 1 msg/sec
-	limiter := rate.NewLimiter(rate.Limit(r.cfg.MaxEditMessagePerSecond), r.cfg.MaxEditMessagePerSecond)
-	r.messageLimiters[userID] = &messageLimiterEntry{
-		limiter:    limiter,
-		lastAccess: time.Now(),
+	userKey := FormatKey(telegramUserKey, c.Sender().ID)
+	if err := r.limiter.Wait(ctx, userKey, PerSecond(r.cfg.MaxUserRequestPerSecond), 1); err != nil {
+		r.log.WithError(err).Error("Failed to wait for user rate limit")
+		return err
 	}
-	return r.messageLimiters[userID]
+	return nil
 }
 
-func (r *TelegramRateLimiter) checkRateLimit(ctx context.Context, c telebot.Context) error {
-	userLimiter := r.getUserLimiter(c.Sender().ID)
-	messageLimiter := r.getMessageLimiter(c.Chat().ID)
-
-	if err := messageLimiter.limiter.Wait(ctx); err != nil {
+func (r *TelegramRateLimiter) checkChatRateLimit(ctx context.Context, chatID int64) error {
+	chatKey := FormatKey(telegramChatKey, chatID)
+	if err := r.limiter.Wait(ctx, chatKey, PerSecond(r.cfg.MaxEditMessagePerSecond), 1); err != nil {
 		r.log.WithError(err).Error("Failed to wait for message rate limit")
 		return err
 	}
-	if err := r.globalLimiter.Wait(ctx); err != nil {
+	if err := r.limiter.Wait(ctx, telegramGlobalKey, PerSecond(r.cfg.MaxGlobalRequestPerSecond), 1); err != nil {
 		r.log.WithError(err).Error("Failed to wait for global rate limit")
 		return err
 	}
-	if err := userLimiter.limiter.Wait(ctx); err != nil {
-		r.log.WithError(err).Error("Failed to wait for user rate limit")
-		return err
-	}
 	return nil
 }
+
 func (r *TelegramRateLimiter) StartCleanupExpired(ctx context.Context) {
 	r.wg.Add(1)
 	utils.SafeGo(func() {
@@ -174,14 +180,10 @@ func (r *TelegramRateLimiter) StartCleanupExpired(ctx context.Context) {
 				r.log.Info("Received signal to stop Telegram rate limiter cleanup expired")
 				return
 			case <-ticker.C:
-				r.mu.Lock()
-				now := time.Now()
-				for userID, entry := range r.userLimiters {
-					if now.Sub(entry.lastAccess) > r.cfg.RatelimitExpireDuration {
-						delete(r.userLimiters, userID)
-					}
+				// hanya limiter in-memory yang perlu dibersihkan, key redis punya TTL sendiri
+				if expirer, ok := r.limiter.(interface{ DeleteExpired() }); ok {
+					expirer.DeleteExpired()
 				}
-				r.mu.Unlock()
 			}
 		}
 	})
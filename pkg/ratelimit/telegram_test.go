@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"testing"
+
+	"gopkg.in/telebot.v3"
+)
+
+func TestReplyInThread(t *testing.T) {
+	bot, err := telebot.NewBot(telebot.Settings{Offline: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groupMessage := &telebot.Message{ID: 7, ThreadID: 3, Chat: &telebot.Chat{ID: -100, Type: telebot.ChatSuperGroup}}
+	privateMessage := &telebot.Message{ID: 7, Chat: &telebot.Chat{ID: 1, Type: telebot.ChatPrivate}}
+
+	tests := []struct {
+		name         string
+		update       telebot.Update
+		opts         []interface{}
+		wantReplyTo  bool
+		wantThreadID int
+		wantParse    telebot.ParseMode
+	}{
+		{
+			name:   "private chat unchanged",
+			update: telebot.Update{Message: privateMessage},
+			opts:   []interface{}{telebot.ModeHTML},
+		},
+		{
+			name:         "group message replies in thread",
+			update:       telebot.Update{Message: groupMessage},
+			opts:         []interface{}{telebot.ModeHTML},
+			wantReplyTo:  true,
+			wantThreadID: 3,
+			wantParse:    telebot.ModeHTML,
+		},
+		{
+			name:         "group keeps existing send options",
+			update:       telebot.Update{Message: groupMessage},
+			opts:         []interface{}{&telebot.SendOptions{ParseMode: telebot.ModeMarkdown}},
+			wantReplyTo:  true,
+			wantThreadID: 3,
+			wantParse:    telebot.ModeMarkdown,
+		},
+		{
+			name:         "group callback stays in thread without reply",
+			update:       telebot.Update{Callback: &telebot.Callback{Message: groupMessage}},
+			wantThreadID: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := replyInThread(bot.NewContext(tt.update), tt.opts)
+
+			var options *telebot.SendOptions
+			parseMode := telebot.ModeDefault
+			for _, opt := range got {
+				switch opt := opt.(type) {
+				case *telebot.SendOptions:
+					options = opt
+					parseMode = opt.ParseMode
+				case telebot.ParseMode:
+					parseMode = opt
+				}
+			}
+
+			if options == nil {
+				if tt.wantReplyTo || tt.wantThreadID != 0 {
+					t.Fatalf("replyInThread() returned no send options, want thread %d", tt.wantThreadID)
+				}
+				return
+			}
+			if (options.ReplyTo != nil) != tt.wantReplyTo {
+				t.Errorf("ReplyTo = %v, want reply %v", options.ReplyTo, tt.wantReplyTo)
+			}
+			if options.ThreadID != tt.wantThreadID {
+				t.Errorf("ThreadID = %d, want %d", options.ThreadID, tt.wantThreadID)
+			}
+			if parseMode != tt.wantParse {
+				t.Errorf("ParseMode = %q, want %q", parseMode, tt.wantParse)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultConsumerBatchSize     = 10
+	defaultConsumerBlock         = 5 * time.Second
+	defaultConsumerMinIdle       = time.Minute
+	defaultConsumerMaxDeliveries = 5
+)
+
+// MessageHandler memproses satu pesan stream. Pesan di-ack jika handler mengembalikan nil,
+// jika error pesan tetap pending dan akan diambil ulang lewat XAUTOCLAIM.
+type MessageHandler func(ctx context.Context, message redis.XMessage) error
+
+// ConsumerConfig mengatur consumer group untuk satu stream. Nilai nol memakai default.
+type ConsumerConfig struct {
+	Stream   string
+	Group    string
+	Consumer string
+	// BatchSize adalah jumlah maksimal pesan per XREADGROUP / XAUTOCLAIM.
+	BatchSize int64
+	// Block adalah lama XREADGROUP menunggu pesan baru.
+	Block time.Duration
+	// MinIdle adalah lama pesan pending tanpa ack sebelum diambil alih consumer lain.
+	MinIdle time.Duration
+	// MaxDeliveries adalah jumlah percobaan sebelum pesan dipindah ke DeadLetterStream.
+	MaxDeliveries int64
+	// DeadLetterStream default <Stream>.dlq.
+	DeadLetterStream string
+}
+
+// Consumer membaca stream lewat consumer group dengan ack, reclaim pesan pending milik
+// consumer yang mati (XAUTOCLAIM) dan dead-letter stream untuk pesan yang terus gagal.
+type Consumer struct {
+	client  *Client
+	log     *logrus.Logger
+	cfg     ConsumerConfig
+	handler MessageHandler
+}
+
+func NewConsumer(client *Client, log *logrus.Logger, cfg ConsumerConfig, handler MessageHandler) *Consumer {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultConsumerBatchSize
+	}
+	if cfg.Block <= 0 {
+		cfg.Block = defaultConsumerBlock
+	}
+	if cfg.MinIdle <= 0 {
+		cfg.MinIdle = defaultConsumerMinIdle
+	}
+	if cfg.MaxDeliveries <= 0 {
+		cfg.MaxDeliveries = defaultConsumerMaxDeliveries
+	}
+	if cfg.DeadLetterStream == "" {
+		cfg.DeadLetterStream = cfg.Stream + ".dlq"
+	}
+	return &Consumer{
+		client:  client,
+		log:     log,
+		cfg:     cfg,
+		handler: handler,
+	}
+}
+
+// Run membaca dan memproses pesan sampai ctx selesai.
+func (c *Consumer) Run(ctx context.Context) error {
+	if err := c.ensureGroup(ctx); err != nil {
+		return err
+	}
+
+	fields := logrus.Fields{"stream": c.cfg.Stream, "group": c.cfg.Group, "consumer": c.cfg.Consumer}
+	c.log.Info("redis stream consumer started", fields)
+
+	// pesan pending diperiksa setiap MinIdle, tidak perlu lebih sering karena belum ada
+	// pesan yang memenuhi syarat untuk diambil alih
+	nextReclaim := time.Now()
+	for ctx.Err() == nil {
+		if !time.Now().Before(nextReclaim) {
+			c.reclaim(ctx)
+			nextReclaim = time.Now().Add(c.cfg.MinIdle)
+		}
+
+		streams, err := c.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    c.cfg.Group,
+			Consumer: c.cfg.Consumer,
+			Streams:  []string{c.cfg.Stream, ">"},
+			Count:    c.cfg.BatchSize,
+			Block:    c.cfg.Block,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+				continue
+			}
+			c.log.Error("failed to read redis stream", logrus.Fields{
+				"stream": c.cfg.Stream,
+				"error":  err,
+			})
+			// jeda agar tidak membanjiri log saat redis tidak tersedia
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, message := range stream.Messages {
+				c.process(ctx, message)
+			}
+		}
+	}
+
+	c.log.Info("redis stream consumer stopped", fields)
+	return nil
+}
+
+func (c *Consumer) ensureGroup(ctx context.Context) error {
+	err := c.client.XGroupCreateMkStream(ctx, c.cfg.Stream, c.cfg.Group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group %s on %s: %w", c.cfg.Group, c.cfg.Stream, err)
+	}
+	return nil
+}
+
+// reclaim mengambil alih pesan yang terlalu lama pending (consumer mati atau handler gagal)
+// lalu memprosesnya ulang.
+func (c *Consumer) reclaim(ctx context.Context) {
+	start := "0-0"
+	for ctx.Err() == nil {
+		messages, next, err := c.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   c.cfg.Stream,
+			Group:    c.cfg.Group,
+			Consumer: c.cfg.Consumer,
+			MinIdle:  c.cfg.MinIdle,
+			Start:    start,
+			Count:    c.cfg.BatchSize,
+		}).Result()
+		if err != nil {
+			c.log.Error("failed to reclaim pending messages", logrus.Fields{
+				"stream": c.cfg.Stream,
+				"error":  err,
+			})
+			return
+		}
+
+		for _, message := range messages {
+			c.process(ctx, message)
+		}
+		if next == "0-0" {
+			return
+		}
+		start = next
+	}
+}
+
+func (c *Consumer) process(ctx context.Context, message redis.XMessage) {
+	err := c.handler(ctx, message)
+	if err == nil {
+		c.ack(ctx, message.ID)
+		return
+	}
+
+	fields := logrus.Fields{
+		"stream":     c.cfg.Stream,
+		"message_id": message.ID,
+		"error":      err,
+	}
+	deliveries, errPending := c.deliveries(ctx, message.ID)
+	if errPending != nil {
+		fields["pending_error"] = errPending
+		c.log.Error("failed to process stream message", fields)
+		return
+	}
+	fields["deliveries"] = deliveries
+	if deliveries < c.cfg.MaxDeliveries {
+		c.log.Warn("failed to process stream message, will retry", fields)
+		return
+	}
+
+	if err := c.deadLetter(ctx, message, err, deliveries); err != nil {
+		fields["dead_letter_error"] = err
+		c.log.Error("failed to move stream message to dead letter", fields)
+		return
+	}
+	c.log.Error("stream message moved to dead letter", fields)
+	c.ack(ctx, message.ID)
+}
+
+// deliveries mengembalikan berapa kali pesan sudah dikirim ke consumer (termasuk reclaim).
+func (c *Consumer) deliveries(ctx context.Context, id string) (int64, error) {
+	pending, err := c.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: c.cfg.Stream,
+		Group:  c.cfg.Group,
+		Start:  id,
+		End:    id,
+		Count:  1,
+	}).Result()
+	if err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, fmt.Errorf("message %s is not pending", id)
+	}
+	return pending[0].RetryCount, nil
+}
+
+func (c *Consumer) deadLetter(ctx context.Context, message redis.XMessage, cause error, deliveries int64) error {
+	values := make(map[string]interface{}, len(message.Values)+4)
+	for key, value := range message.Values {
+		values[key] = value
+	}
+	values["original_stream"] = c.cfg.Stream
+	values["original_id"] = message.ID
+	values["error"] = cause.Error()
+	values["deliveries"] = deliveries
+
+	return c.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: c.cfg.DeadLetterStream,
+		Values: values,
+	}).Err()
+}
+
+func (c *Consumer) ack(ctx context.Context, id string) {
+	if err := c.client.XAck(ctx, c.cfg.Stream, c.cfg.Group, id).Err(); err != nil {
+		c.log.Error("failed to ack stream message", logrus.Fields{
+			"stream":     c.cfg.Stream,
+			"message_id": id,
+			"error":      err,
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// newTestClient membutuhkan redis, jalankan dengan REDIS_TEST_ADDR=localhost:6379.
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	addr := os.Getenv("REDIS_TEST_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_TEST_ADDR not set")
+	}
+	var host string
+	var port int
+	if _, err := fmt.Sscanf(addr, "%[^:]:%d", &host, &port); err != nil {
+		t.Fatalf("invalid REDIS_TEST_ADDR: %v", err)
+	}
+	client, err := NewClient(Config{Host: host, Port: port})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestConsumer_AckAndDeadLetter(t *testing.T) {
+	client := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream := fmt.Sprintf("test.consumer.%d", time.Now().UnixNano())
+	t.Cleanup(func() { client.Del(context.Background(), stream, stream+".dlq") })
+
+	for _, payload := range []string{"ok", "fail"} {
+		if err := client.XAdd(ctx, &redis.XAddArgs{Stream: stream, Values: map[string]interface{}{"payload": payload}}).Err(); err != nil {
+			t.Fatalf("XAdd() error = %v", err)
+		}
+	}
+
+	processed := make(chan string, 10)
+	consumer := NewConsumer(client, logrus.New(), ConsumerConfig{
+		Stream:        stream,
+		Group:         "test",
+		Consumer:      "test-1",
+		Block:         100 * time.Millisecond,
+		MinIdle:       200 * time.Millisecond,
+		MaxDeliveries: 2,
+	}, func(ctx context.Context, message redis.XMessage) error {
+		payload := message.Values["payload"].(string)
+		processed <- payload
+		if payload == "fail" {
+			return errors.New("handler failed")
+		}
+		return nil
+	})
+
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = consumer.Run(runCtx)
+	}()
+
+	// "fail" dikirim dua kali (baca awal + reclaim) sebelum dipindah ke dead letter
+	deadline := time.After(5 * time.Second)
+	for {
+		length, err := client.XLen(ctx, stream+".dlq").Result()
+		if err != nil {
+			t.Fatalf("XLen() error = %v", err)
+		}
+		if length == 1 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("message was not moved to dead letter stream")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	stop()
+	<-done
+
+	pending, err := client.XPending(ctx, stream, "test").Result()
+	if err != nil {
+		t.Fatalf("XPending() error = %v", err)
+	}
+	if pending.Count != 0 {
+		t.Errorf("pending = %d, want 0", pending.Count)
+	}
+
+	counts := map[string]int{}
+	close(processed)
+	for payload := range processed {
+		counts[payload]++
+	}
+	if counts["ok"] != 1 || counts["fail"] != 2 {
+		t.Errorf("processed = %v, want ok once and fail twice", counts)
+	}
+}